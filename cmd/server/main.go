@@ -0,0 +1,442 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/ferdiebergado/fullstackgo/internal/config"
+	"github.com/ferdiebergado/fullstackgo/internal/db"
+	"github.com/ferdiebergado/fullstackgo/internal/http/handler"
+	"github.com/ferdiebergado/fullstackgo/internal/http/middleware"
+	"github.com/ferdiebergado/fullstackgo/internal/http/router"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/mail"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/oidc"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/password"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/ratelimit"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/validation"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(ctx, os.Args[2:], os.Stdout); err != nil {
+			slog.Error("migrate error", "error", err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "roles" {
+		if err := runRoles(ctx, os.Args[2:], os.Stdout); err != nil {
+			slog.Error("roles error", "error", err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if err := run(ctx); err != nil {
+		slog.Error("server error", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	conn, dialect, err := db.Open(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	slog.Info("connected to database", "dialect", dialect)
+
+	signer, err := newTokenSigner(cfg.Token)
+	if err != nil {
+		return err
+	}
+
+	userRepo := repo.NewUserRepo(conn)
+	sessionStore := repo.NewSessionStore(conn)
+	refreshTokenRepo := repo.NewRefreshTokenRepo(conn)
+	userTokenRepo := repo.NewUserTokenRepo(conn)
+	totpRepo := repo.NewTOTPRepo(conn)
+	recoveryCodeRepo := repo.NewRecoveryCodeRepo(conn)
+	mfaChallengeRepo := repo.NewMFAChallengeRepo(conn)
+	passkeyRepo := repo.NewPasskeyRepo(conn)
+	webAuthnChallengeRepo := repo.NewWebAuthnChallengeRepo(conn)
+	identityRepo := repo.NewIdentityRepo(conn)
+	oidcStateRepo := repo.NewOIDCStateRepo(conn)
+	magicLinkRepo := repo.NewMagicLinkRepo(conn)
+	roleRepo := repo.NewRoleRepo(conn)
+	organizationRepo := repo.NewOrganizationRepo(conn)
+
+	tokenCfg := service.TokenConfig{
+		AccessTTL:  cfg.Token.AccessTTL,
+		RefreshTTL: cfg.Token.RefreshTTL,
+	}
+	verificationCfg := service.VerificationConfig{
+		BaseURL:         cfg.Server.BaseURL,
+		TTL:             cfg.Account.VerificationTTL,
+		RequireVerified: cfg.Account.RequireVerifiedEmail,
+	}
+	resetCfg := service.PasswordResetConfig{
+		BaseURL: cfg.Server.BaseURL,
+		TTL:     cfg.Account.PasswordResetTTL,
+	}
+	magicLinkCfg := service.MagicLinkConfig{
+		BaseURL:    cfg.Server.BaseURL,
+		TTL:        cfg.Account.MagicLinkTTL,
+		SameDevice: cfg.Account.MagicLinkSameDevice,
+	}
+	invitationCfg := service.InvitationConfig{
+		BaseURL: cfg.Server.BaseURL,
+		TTL:     cfg.Account.InvitationTTL,
+	}
+	lockoutCfg := service.LockoutConfig{
+		AccountThreshold: cfg.Lockout.AccountThreshold,
+		IPThreshold:      cfg.Lockout.IPThreshold,
+		BaseDelay:        cfg.Lockout.BaseDelay,
+		MaxDelay:         cfg.Lockout.MaxDelay,
+		ResetAfter:       cfg.Lockout.ResetAfter,
+	}
+	mfaCfg := service.MFAConfig{
+		Issuer:        cfg.MFA.Issuer,
+		ChallengeTTL:  cfg.MFA.ChallengeTTL,
+		MaxAttempts:   cfg.MFA.MaxAttempts,
+		RecoveryCodes: cfg.MFA.RecoveryCodes,
+	}
+	passkeyCfg := service.PasskeyConfig{
+		RPID:                    cfg.WebAuthn.RPID,
+		RPName:                  cfg.WebAuthn.RPName,
+		Origins:                 cfg.WebAuthn.Origins,
+		ChallengeTTL:            cfg.WebAuthn.ChallengeTTL,
+		RequireUserVerification: cfg.WebAuthn.RequireUserVerification,
+	}
+	oidcCfg := service.OIDCConfig{
+		StateTTL: cfg.OIDC.StateTTL,
+	}
+	loginAttempts, err := newLoginAttemptStore(conn, cfg.Lockout)
+	if err != nil {
+		return err
+	}
+	mailer := newMailer(cfg.Mail)
+	// Emails sent after their request returned are delivered before exiting.
+	var background sync.WaitGroup
+	defer background.Wait()
+	authOpts := []service.AuthOption{
+		service.WithBackground(func(task func()) {
+			background.Add(1)
+			go func() {
+				defer background.Done()
+				task()
+			}()
+		}),
+		service.WithTokens(refreshTokenRepo, signer, tokenCfg),
+		service.WithSessions(sessionStore),
+		service.WithEmailVerification(userTokenRepo, mailer, verificationCfg),
+		service.WithPasswordReset(userTokenRepo, mailer, resetCfg),
+		service.WithMagicLinks(magicLinkRepo, mailer, magicLinkCfg),
+		service.WithLockout(loginAttempts, lockoutCfg),
+		service.WithMFA(totpRepo, recoveryCodeRepo, mfaChallengeRepo, mfaCfg),
+		service.WithPasskeys(passkeyRepo, webAuthnChallengeRepo, passkeyCfg),
+		service.WithOIDC(identityRepo, oidcStateRepo, newOIDCProviders(cfg.OIDC), oidcCfg),
+	}
+	var breaches password.BreachChecker
+	if cfg.Password.BreachDir != "" {
+		breaches = password.NewRangeChecker(&password.DirRangeSource{Dir: cfg.Password.BreachDir})
+		authOpts = append(authOpts, service.WithBreachCheck(breaches))
+	}
+	hasher, err := newHasher(cfg.Hasher)
+	if err != nil {
+		return err
+	}
+	expvar.Publish("hasher", expvar.Func(func() any { return hasher.Stats() }))
+	authService := service.NewAuthService(userRepo, hasher, authOpts...)
+	sessionService := service.NewSessionService(sessionStore, cfg.Session.TTL)
+
+	cookie := handler.SessionCookie{
+		Name:   cfg.Session.CookieName,
+		Secure: cfg.Session.CookieSecure,
+	}
+	policy := password.Policy{
+		MinLength:  cfg.Password.MinLength,
+		MaxLength:  cfg.Password.MaxLength,
+		MinEntropy: float64(cfg.Password.MinEntropy),
+	}
+	validate := validation.Instance(validation.WithPasswordPolicy(policy))
+	authHandler := handler.NewAuthHandler(authService, sessionService, validate, cookie)
+	authMiddleware := middleware.NewAuth(sessionService, authService, userRepo, cookie)
+	roleHandler := handler.NewRoleHandler(service.NewRoleService(roleRepo, userRepo), validate)
+	organizationService := service.NewOrganizationService(organizationRepo)
+	authorizer := service.NewAuthorizer(roleRepo)
+	membershipService := service.NewMembershipService(repo.NewMembershipRepo(conn), repo.NewInvitationRepo(conn),
+		roleRepo, userRepo, authorizer, hasher, breaches, mailer, invitationCfg)
+	organizationHandler := handler.NewOrganizationHandler(organizationService, membershipService, validate)
+
+	var oauthHandler handler.OAuthHandler
+	if cfg.OAuth.Enabled {
+		oauthSigner, err := newOAuthSigner(cfg.OAuth)
+		if err != nil {
+			return err
+		}
+
+		oauthService := service.NewOAuthService(userRepo, repo.NewOAuthClientRepo(conn), repo.NewOAuthCodeRepo(conn),
+			repo.NewOAuthTokenRepo(conn), oauthSigner, service.OAuthConfig{
+				AuthorizeURL:   cfg.OAuth.AuthorizeURL,
+				CodeTTL:        cfg.OAuth.CodeTTL,
+				AccessTokenTTL: cfg.OAuth.AccessTokenTTL,
+			})
+		oauthHandler = handler.NewOAuthHandler(oauthService, validate, cfg.OAuth.RegistrationToken)
+	}
+
+	var rateLimiter *middleware.RateLimiter
+	if cfg.RateLimit.Enabled {
+		rateLimiter = middleware.NewRateLimiter(ratelimit.NewMemoryStore())
+	}
+
+	srv := &http.Server{
+		Addr: cfg.Server.Addr,
+		Handler: middleware.ClientIP(cfg.Server.TrustProxy)(router.New(router.Handlers{
+			Auth:           authHandler,
+			AuthMiddleware: authMiddleware,
+			OAuth:          oauthHandler,
+			Roles:          roleHandler,
+			Authorizer:     authorizer,
+			Organizations:  organizationHandler,
+			Tenants:        organizationService,
+			RateLimiter:    rateLimiter,
+			RateLimits: router.RateLimits{
+				API:         ratelimit.Limit{Requests: cfg.RateLimit.APIRequests, Period: cfg.RateLimit.APIPeriod},
+				Credentials: ratelimit.Limit{Requests: cfg.RateLimit.CredentialRequests, Period: cfg.RateLimit.CredentialPeriod},
+			},
+		})),
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+
+	return serve(ctx, srv, cfg.Server)
+}
+
+func newLoginAttemptStore(conn *sql.DB, cfg config.LockoutConfig) (repo.LoginAttemptStore, error) {
+	switch cfg.Store {
+	case "database":
+		return repo.NewLoginAttemptStore(conn), nil
+	case "memory":
+		return repo.NewMemoryLoginAttemptStore(cfg.ResetAfter), nil
+	default:
+		return nil, fmt.Errorf("unknown lockout store %q", cfg.Store)
+	}
+}
+
+// newHasher builds the password hasher: Argon2id for new hashes, accepting
+// imported bcrypt and PBKDF2 hashes, bounded by a pool so that concurrent
+// hashes cannot exhaust memory.
+func newHasher(cfg config.HasherConfig) (*security.PooledHasher, error) {
+	ints := []struct {
+		name string
+		val  int
+		max  int
+	}{
+		{"memory", cfg.Memory, math.MaxUint32},
+		{"iterations", cfg.Iterations, math.MaxUint32},
+		{"parallelism", cfg.Parallelism, math.MaxUint8},
+		{"salt length", cfg.SaltLength, math.MaxUint32},
+		{"key length", cfg.KeyLength, math.MaxUint32},
+	}
+
+	for _, i := range ints {
+		if i.val < 0 || i.val > i.max {
+			return nil, fmt.Errorf("%w: %s out of range", security.ErrInvalidArgon2Params, i.name)
+		}
+	}
+
+	params := security.Argon2Params{
+		Memory:      uint32(cfg.Memory),
+		Iterations:  uint32(cfg.Iterations),
+		Parallelism: uint8(cfg.Parallelism),
+		SaltLength:  uint32(cfg.SaltLength),
+		KeyLength:   uint32(cfg.KeyLength),
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	peppers := cfg.Peppers
+	if cfg.PeppersFile != "" {
+		data, err := os.ReadFile(cfg.PeppersFile)
+		if err != nil {
+			return nil, fmt.Errorf("read password peppers: %w", err)
+		}
+		peppers = string(data)
+	}
+
+	parsed, err := security.ParsePeppers(peppers)
+	if err != nil {
+		return nil, fmt.Errorf("parse password peppers: %w", err)
+	}
+
+	if len(parsed) == 0 {
+		slog.Warn("no password pepper configured")
+	}
+
+	hasher := security.NewMultiHasher(&security.Argon2Hasher{Params: params, Peppers: parsed})
+
+	return security.NewPooledHasher(hasher, security.PoolConfig{
+		MaxConcurrent: int64(cfg.MaxConcurrent),
+		MaxQueued:     int64(cfg.MaxQueued),
+	})
+}
+
+// newMailer delivers over SMTP when a host is configured. Otherwise messages
+// are written to files so that links can be followed during development.
+func newMailer(cfg config.MailConfig) mail.Mailer {
+	if cfg.SMTPHost == "" {
+		slog.Warn("no SMTP host configured, writing emails to files", "dir", cfg.Dir)
+		return mail.NewFileMailer(cfg.Dir, cfg.From)
+	}
+
+	return mail.NewSMTPMailer(mail.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.From,
+	})
+}
+
+// newOIDCProviders returns the configured OpenID providers by name. Their
+// metadata is discovered on first use.
+func newOIDCProviders(cfg config.OIDCConfig) map[string]*oidc.Provider {
+	providers := make(map[string]*oidc.Provider, len(cfg.Providers))
+	for _, p := range cfg.Providers {
+		providers[p.Name] = oidc.NewProvider(oidc.Config{
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		})
+
+		slog.Info("oidc provider configured", "provider", p.Name, "issuer", p.Issuer)
+	}
+
+	return providers
+}
+
+// newTokenSigner builds the access token signer from the configured key. Without
+// one, an ephemeral Ed25519 key is generated and tokens do not survive restarts.
+func newTokenSigner(cfg config.TokenConfig) (security.TokenSigner, error) {
+	switch {
+	case cfg.PrivateKeyFile != "":
+		data, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read token private key: %w", err)
+		}
+
+		key, err := security.ParseEd25519PrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse token private key: %w", err)
+		}
+
+		return security.NewEdDSASigner(key, cfg.Issuer), nil
+	case cfg.Secret != "":
+		signer, err := security.NewHS256Signer([]byte(cfg.Secret), cfg.Issuer)
+		if err != nil {
+			return nil, fmt.Errorf("create token signer: %w", err)
+		}
+
+		return signer, nil
+	default:
+		slog.Warn("no token signing key configured, using an ephemeral key")
+
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("generate token signing key: %w", err)
+		}
+
+		return security.NewEdDSASigner(key, cfg.Issuer), nil
+	}
+}
+
+// newOAuthSigner builds the signer of tokens issued to OAuth clients. Without a
+// key file, an ephemeral Ed25519 key is generated and tokens do not survive
+// restarts.
+func newOAuthSigner(cfg config.OAuthConfig) (*security.JWTSigner, error) {
+	if cfg.SigningKeyFile == "" {
+		slog.Warn("no oauth signing key configured, using an ephemeral key")
+
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("generate oauth signing key: %w", err)
+		}
+
+		return security.NewEdDSASigner(key, cfg.Issuer), nil
+	}
+
+	data, err := os.ReadFile(cfg.SigningKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read oauth signing key: %w", err)
+	}
+
+	key, err := security.ParseEd25519PrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse oauth signing key: %w", err)
+	}
+
+	return security.NewEdDSASigner(key, cfg.Issuer), nil
+}
+
+// serve runs the server until ctx is cancelled, then shuts it down gracefully.
+func serve(ctx context.Context, srv *http.Server, cfg config.ServerConfig) error {
+	serverErr := make(chan error, 1)
+
+	go func() {
+		slog.Info("server listening", "addr", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("listen and serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown server: %w", err)
+	}
+
+	return nil
+}
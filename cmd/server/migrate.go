@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/config"
+	"github.com/ferdiebergado/fullstackgo/internal/db"
+)
+
+var errUsage = errors.New("usage: server migrate up|down [steps]|status|new <name>")
+
+// runMigrate implements the migrate subcommand.
+func runMigrate(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dir := flags.String("dir", filepath.Join("internal", "db", db.MigrationsDir),
+		"directory where new migrations are created")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	if args[0] == "new" {
+		if len(args) != 2 {
+			return errUsage
+		}
+
+		now := time.Now()
+		for _, dialect := range []db.Dialect{db.Postgres, db.SQLite} {
+			up, down, err := db.CreateMigration(filepath.Join(*dir, string(dialect)), args[1], now)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(out, "created %s\ncreated %s\n", up, down)
+		}
+
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	conn, dialect, err := db.Open(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	migrator := db.NewMigrator(conn, dialect, dialect.Migrations())
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		printMigrations(out, "applied", applied)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return errUsage
+			}
+		}
+
+		reverted, err := migrator.Down(ctx, steps)
+		printMigrations(out, "reverted", reverted)
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		printStatus(out, statuses)
+		return nil
+	default:
+		return errUsage
+	}
+}
+
+func printMigrations(out io.Writer, verb string, migrations []db.Migration) {
+	if len(migrations) == 0 {
+		fmt.Fprintf(out, "no migrations %s\n", verb)
+		return
+	}
+
+	for _, m := range migrations {
+		fmt.Fprintf(out, "%s %d_%s\n", verb, m.Version, m.Name)
+	}
+}
+
+func printStatus(out io.Writer, statuses []db.MigrationStatus) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+
+	for _, s := range statuses {
+		appliedAt := "pending"
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+	}
+
+	w.Flush()
+}
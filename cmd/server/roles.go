@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ferdiebergado/fullstackgo/internal/config"
+	"github.com/ferdiebergado/fullstackgo/internal/db"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+var errRolesUsage = errors.New("usage: server roles assign|unassign <email> <role>")
+
+// runRoles implements the roles subcommand, which assigns roles from the
+// command line. It is how the first admin is made.
+func runRoles(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) != 3 || (args[0] != "assign" && args[0] != "unassign") {
+		return errRolesUsage
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	conn, _, err := db.Open(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	users := repo.NewUserRepo(conn)
+	user, err := users.FindUserByEmail(ctx, args[1])
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.ErrUserNotFound
+		}
+
+		return fmt.Errorf("find user by email: %w", err)
+	}
+
+	roles := service.NewRoleService(repo.NewRoleRepo(conn), users)
+
+	var assigned *model.UserRoles
+	if args[0] == "assign" {
+		assigned, err = roles.AssignRole(ctx, user.ID, args[2])
+	} else {
+		assigned, err = roles.UnassignRole(ctx, user.ID, args[2])
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "%s has roles: %s\n", user.Email, strings.Join(assigned.Roles, ", "))
+	return nil
+}
@@ -0,0 +1,607 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/password"
+)
+
+var ErrMissingEnv = errors.New("missing required environment variable")
+
+type Config struct {
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Session   SessionConfig
+	Token     TokenConfig
+	Mail      MailConfig
+	Account   AccountConfig
+	Lockout   LockoutConfig
+	RateLimit RateLimitConfig
+	Hasher    HasherConfig
+	Password  PasswordConfig
+	MFA       MFAConfig
+	WebAuthn  WebAuthnConfig
+	OIDC      OIDCConfig
+	OAuth     OAuthConfig
+}
+
+type ServerConfig struct {
+	Addr            string
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	ShutdownTimeout time.Duration
+	// BaseURL is the public address of the app used in emailed links.
+	BaseURL string
+	// TrustProxy takes client IPs from X-Forwarded-For. Only enable it
+	// behind a reverse proxy that sets the header.
+	TrustProxy bool
+}
+
+type DatabaseConfig struct {
+	URL             string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+type SessionConfig struct {
+	CookieName   string
+	CookieSecure bool
+	TTL          time.Duration
+}
+
+// TokenConfig configures bearer access tokens. EdDSA is used when
+// PrivateKeyFile is set, otherwise HS256 with Secret.
+type TokenConfig struct {
+	Issuer         string
+	Secret         string
+	PrivateKeyFile string
+	AccessTTL      time.Duration
+	RefreshTTL     time.Duration
+}
+
+// MailConfig configures outgoing email. Messages are delivered over SMTP
+// when SMTPHost is set, otherwise they are written to files in Dir.
+type MailConfig struct {
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+	Dir          string
+}
+
+// AccountConfig configures the emailed links used to verify addresses, reset
+// passwords, sign in without a password and join organizations.
+// MagicLinkSameDevice only lets a sign-in link be opened in the browser that
+// asked for it.
+type AccountConfig struct {
+	RequireVerifiedEmail bool
+	VerificationTTL      time.Duration
+	PasswordResetTTL     time.Duration
+	MagicLinkTTL         time.Duration
+	MagicLinkSameDevice  bool
+	InvitationTTL        time.Duration
+}
+
+// LockoutConfig throttles failed sign-ins. Store is "database" to share
+// counts between instances, or "memory" for a single node.
+type LockoutConfig struct {
+	Store            string
+	AccountThreshold int
+	IPThreshold      int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	ResetAfter       time.Duration
+}
+
+// RateLimitConfig throttles API requests. A limit with zero requests is
+// disabled.
+type RateLimitConfig struct {
+	Enabled            bool
+	APIRequests        int
+	APIPeriod          time.Duration
+	CredentialRequests int
+	CredentialPeriod   time.Duration
+}
+
+// HasherConfig sets the Argon2 cost of new password hashes and bounds
+// concurrent hashing. Operations beyond MaxConcurrent wait in a queue of at
+// most MaxQueued. Memory is in KiB, and hashing can hold up to
+// MaxConcurrent × Memory of it at once.
+type HasherConfig struct {
+	Memory        int
+	Iterations    int
+	Parallelism   int
+	SaltLength    int
+	KeyLength     int
+	MaxConcurrent int
+	MaxQueued     int
+	// Peppers lists <id>:<base64 key> entries, current first. PeppersFile,
+	// if set, is read instead.
+	Peppers     string
+	PeppersFile string
+}
+
+// PasswordConfig sets the policy for new passwords. MinEntropy is in bits.
+// Passwords are also checked against the breach corpus in BreachDir, if set.
+type PasswordConfig struct {
+	MinLength  int
+	MaxLength  int
+	MinEntropy int
+	BreachDir  string
+}
+
+// MFAConfig configures two-factor authentication. Issuer is the name shown
+// in authenticator apps. A sign-in awaiting its code expires after
+// ChallengeTTL or MaxAttempts wrong codes.
+type MFAConfig struct {
+	Issuer        string
+	ChallengeTTL  time.Duration
+	MaxAttempts   int
+	RecoveryCodes int
+}
+
+// WebAuthnConfig configures passkeys. RPID is the domain passkeys are
+// scoped to and Origins the pages allowed to use them; both default to those
+// of the server's BaseURL.
+type WebAuthnConfig struct {
+	RPID                    string
+	RPName                  string
+	Origins                 []string
+	ChallengeTTL            time.Duration
+	RequireUserVerification bool
+}
+
+// OIDCConfig configures sign-in with OpenID providers. Providers are named
+// in OIDC_PROVIDERS and each is configured by variables prefixed with its
+// upper-cased name, such as OIDC_GOOGLE_ISSUER.
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig
+	StateTTL  time.Duration
+}
+
+// OIDCProviderConfig registers the app with one OpenID provider. Name is
+// used in the sign-in URLs. RedirectURL defaults to the page
+// /signin/oidc/<name>/callback of the app.
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// OAuthConfig configures the OAuth2 authorization server, which is off
+// unless Enabled. Issuer defaults to the server's BaseURL and AuthorizeURL,
+// the consent page clients send users to, to its page /oauth/authorize.
+// Tokens are signed with the Ed25519 key in SigningKeyFile, or with an
+// ephemeral key when it is unset. Clients register with RegistrationToken,
+// and cannot when it is empty.
+type OAuthConfig struct {
+	Enabled           bool
+	Issuer            string
+	AuthorizeURL      string
+	SigningKeyFile    string
+	RegistrationToken string
+	CodeTTL           time.Duration
+	AccessTokenTTL    time.Duration
+}
+
+// Default values used when the corresponding environment variable is not set.
+const (
+	defaultAddr            = ":8888"
+	defaultReadTimeout     = 10 * time.Second
+	defaultWriteTimeout    = 10 * time.Second
+	defaultIdleTimeout     = 60 * time.Second
+	defaultShutdownTimeout = 10 * time.Second
+	defaultMaxOpenConns    = 25
+	defaultMaxIdleConns    = 25
+	defaultConnMaxLifetime = 5 * time.Minute
+	defaultSessionCookie   = "session"
+	defaultSessionTTL      = 24 * time.Hour
+	defaultTokenIssuer     = "fullstackgo"
+	defaultAccessTTL       = 15 * time.Minute
+	defaultRefreshTTL      = 30 * 24 * time.Hour
+	defaultBaseURL         = "http://localhost:8888"
+	defaultSMTPPort        = 587
+	defaultMailFrom        = "no-reply@localhost"
+	defaultMailDir         = "tmp/mail"
+	defaultVerifyTTL       = 24 * time.Hour
+	defaultResetTTL        = time.Hour
+	defaultMagicLinkTTL    = 15 * time.Minute
+	defaultInvitationTTL   = 7 * 24 * time.Hour
+	defaultLockoutStore    = "database"
+	defaultAccountLockout  = 5
+	defaultIPLockout       = 50
+	defaultLockoutDelay    = 30 * time.Second
+	defaultLockoutMaxDelay = time.Hour
+	defaultLockoutReset    = 24 * time.Hour
+	defaultAPIRequests     = 300
+	defaultCredentialLimit = 10
+	defaultRateLimitPeriod = time.Minute
+	defaultArgon2Memory    = 64 * 1024
+	defaultArgon2Passes    = 3
+	defaultArgon2Threads   = 2
+	defaultArgon2SaltLen   = 16
+	defaultArgon2KeyLen    = 32
+	defaultHasherWorkers   = 4
+	defaultHasherQueue     = 64
+	defaultMFAIssuer       = "fullstackgo"
+	defaultMFAChallengeTTL = 5 * time.Minute
+	defaultMFAMaxAttempts  = 5
+	defaultRecoveryCodes   = 10
+	defaultWebAuthnRPName  = "fullstackgo"
+	defaultWebAuthnTTL     = 5 * time.Minute
+	defaultOIDCStateTTL    = 10 * time.Minute
+	defaultOAuthCodeTTL    = time.Minute
+	defaultOAuthTokenTTL   = time.Hour
+)
+
+// Load reads the configuration from the environment.
+func Load() (*Config, error) {
+	dbURL, err := requireEnv("DATABASE_URL")
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{
+		Server: ServerConfig{
+			Addr:            getEnv("SERVER_ADDR", defaultAddr),
+			BaseURL:         getEnv("APP_BASE_URL", defaultBaseURL),
+			ReadTimeout:     defaultReadTimeout,
+			WriteTimeout:    defaultWriteTimeout,
+			IdleTimeout:     defaultIdleTimeout,
+			ShutdownTimeout: defaultShutdownTimeout,
+		},
+		Database: DatabaseConfig{
+			URL:             dbURL,
+			MaxOpenConns:    defaultMaxOpenConns,
+			MaxIdleConns:    defaultMaxIdleConns,
+			ConnMaxLifetime: defaultConnMaxLifetime,
+		},
+		Session: SessionConfig{
+			CookieName:   getEnv("SESSION_COOKIE_NAME", defaultSessionCookie),
+			CookieSecure: true,
+			TTL:          defaultSessionTTL,
+		},
+		Token: TokenConfig{
+			Issuer:         getEnv("TOKEN_ISSUER", defaultTokenIssuer),
+			Secret:         os.Getenv("TOKEN_SECRET"),
+			PrivateKeyFile: os.Getenv("TOKEN_PRIVATE_KEY_FILE"),
+			AccessTTL:      defaultAccessTTL,
+			RefreshTTL:     defaultRefreshTTL,
+		},
+		Mail: MailConfig{
+			SMTPHost:     os.Getenv("SMTP_HOST"),
+			SMTPPort:     defaultSMTPPort,
+			SMTPUsername: os.Getenv("SMTP_USERNAME"),
+			SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+			From:         getEnv("MAIL_FROM", defaultMailFrom),
+			Dir:          getEnv("MAIL_DIR", defaultMailDir),
+		},
+		Account: AccountConfig{
+			VerificationTTL:  defaultVerifyTTL,
+			PasswordResetTTL: defaultResetTTL,
+			MagicLinkTTL:     defaultMagicLinkTTL,
+			InvitationTTL:    defaultInvitationTTL,
+		},
+		Lockout: LockoutConfig{
+			Store:            getEnv("LOCKOUT_STORE", defaultLockoutStore),
+			AccountThreshold: defaultAccountLockout,
+			IPThreshold:      defaultIPLockout,
+			BaseDelay:        defaultLockoutDelay,
+			MaxDelay:         defaultLockoutMaxDelay,
+			ResetAfter:       defaultLockoutReset,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:            true,
+			APIRequests:        defaultAPIRequests,
+			APIPeriod:          defaultRateLimitPeriod,
+			CredentialRequests: defaultCredentialLimit,
+			CredentialPeriod:   defaultRateLimitPeriod,
+		},
+		Hasher: HasherConfig{
+			Memory:        defaultArgon2Memory,
+			Iterations:    defaultArgon2Passes,
+			Parallelism:   defaultArgon2Threads,
+			SaltLength:    defaultArgon2SaltLen,
+			KeyLength:     defaultArgon2KeyLen,
+			MaxConcurrent: defaultHasherWorkers,
+			MaxQueued:     defaultHasherQueue,
+			Peppers:       os.Getenv("PASSWORD_PEPPERS"),
+			PeppersFile:   os.Getenv("PASSWORD_PEPPERS_FILE"),
+		},
+		Password: PasswordConfig{
+			MinLength:  password.DefaultMinLength,
+			MaxLength:  password.DefaultMaxLength,
+			MinEntropy: password.DefaultMinEntropy,
+			BreachDir:  os.Getenv("PASSWORD_BREACH_DIR"),
+		},
+		MFA: MFAConfig{
+			Issuer:        getEnv("MFA_ISSUER", defaultMFAIssuer),
+			ChallengeTTL:  defaultMFAChallengeTTL,
+			MaxAttempts:   defaultMFAMaxAttempts,
+			RecoveryCodes: defaultRecoveryCodes,
+		},
+		WebAuthn: WebAuthnConfig{
+			RPID:         os.Getenv("WEBAUTHN_RP_ID"),
+			RPName:       getEnv("WEBAUTHN_RP_NAME", defaultWebAuthnRPName),
+			Origins:      splitList(os.Getenv("WEBAUTHN_ORIGINS")),
+			ChallengeTTL: defaultWebAuthnTTL,
+		},
+		OIDC: OIDCConfig{
+			StateTTL: defaultOIDCStateTTL,
+		},
+		OAuth: OAuthConfig{
+			Issuer:            os.Getenv("OAUTH_ISSUER"),
+			AuthorizeURL:      os.Getenv("OAUTH_AUTHORIZE_URL"),
+			SigningKeyFile:    os.Getenv("OAUTH_SIGNING_KEY_FILE"),
+			RegistrationToken: os.Getenv("OAUTH_REGISTRATION_TOKEN"),
+			CodeTTL:           defaultOAuthCodeTTL,
+			AccessTokenTTL:    defaultOAuthTokenTTL,
+		},
+	}
+
+	if err := cfg.WebAuthn.applyBaseURL(cfg.Server.BaseURL); err != nil {
+		return nil, err
+	}
+
+	providers, err := loadOIDCProviders(cfg.Server.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	cfg.OIDC.Providers = providers
+
+	if cfg.OAuth.Issuer == "" {
+		cfg.OAuth.Issuer = cfg.Server.BaseURL
+	}
+	if cfg.OAuth.AuthorizeURL == "" {
+		cfg.OAuth.AuthorizeURL = strings.TrimSuffix(cfg.Server.BaseURL, "/") + "/oauth/authorize"
+	}
+
+	durations := []struct {
+		key  string
+		dest *time.Duration
+	}{
+		{"SERVER_READ_TIMEOUT", &cfg.Server.ReadTimeout},
+		{"SERVER_WRITE_TIMEOUT", &cfg.Server.WriteTimeout},
+		{"SERVER_IDLE_TIMEOUT", &cfg.Server.IdleTimeout},
+		{"SERVER_SHUTDOWN_TIMEOUT", &cfg.Server.ShutdownTimeout},
+		{"DB_CONN_MAX_LIFETIME", &cfg.Database.ConnMaxLifetime},
+		{"SESSION_TTL", &cfg.Session.TTL},
+		{"ACCESS_TOKEN_TTL", &cfg.Token.AccessTTL},
+		{"REFRESH_TOKEN_TTL", &cfg.Token.RefreshTTL},
+		{"EMAIL_VERIFICATION_TTL", &cfg.Account.VerificationTTL},
+		{"PASSWORD_RESET_TTL", &cfg.Account.PasswordResetTTL},
+		{"MAGIC_LINK_TTL", &cfg.Account.MagicLinkTTL},
+		{"INVITATION_TTL", &cfg.Account.InvitationTTL},
+		{"LOCKOUT_BASE_DELAY", &cfg.Lockout.BaseDelay},
+		{"LOCKOUT_MAX_DELAY", &cfg.Lockout.MaxDelay},
+		{"LOCKOUT_RESET_AFTER", &cfg.Lockout.ResetAfter},
+		{"RATE_LIMIT_API_PERIOD", &cfg.RateLimit.APIPeriod},
+		{"RATE_LIMIT_CREDENTIAL_PERIOD", &cfg.RateLimit.CredentialPeriod},
+		{"MFA_CHALLENGE_TTL", &cfg.MFA.ChallengeTTL},
+		{"WEBAUTHN_CHALLENGE_TTL", &cfg.WebAuthn.ChallengeTTL},
+		{"OIDC_STATE_TTL", &cfg.OIDC.StateTTL},
+		{"OAUTH_CODE_TTL", &cfg.OAuth.CodeTTL},
+		{"OAUTH_ACCESS_TOKEN_TTL", &cfg.OAuth.AccessTokenTTL},
+	}
+
+	for _, d := range durations {
+		if err := parseDuration(d.key, d.dest); err != nil {
+			return nil, err
+		}
+	}
+
+	ints := []struct {
+		key  string
+		dest *int
+	}{
+		{"DB_MAX_OPEN_CONNS", &cfg.Database.MaxOpenConns},
+		{"DB_MAX_IDLE_CONNS", &cfg.Database.MaxIdleConns},
+		{"SMTP_PORT", &cfg.Mail.SMTPPort},
+		{"LOCKOUT_ACCOUNT_THRESHOLD", &cfg.Lockout.AccountThreshold},
+		{"LOCKOUT_IP_THRESHOLD", &cfg.Lockout.IPThreshold},
+		{"RATE_LIMIT_API_REQUESTS", &cfg.RateLimit.APIRequests},
+		{"RATE_LIMIT_CREDENTIAL_REQUESTS", &cfg.RateLimit.CredentialRequests},
+		{"ARGON2_MEMORY", &cfg.Hasher.Memory},
+		{"ARGON2_ITERATIONS", &cfg.Hasher.Iterations},
+		{"ARGON2_PARALLELISM", &cfg.Hasher.Parallelism},
+		{"ARGON2_SALT_LENGTH", &cfg.Hasher.SaltLength},
+		{"ARGON2_KEY_LENGTH", &cfg.Hasher.KeyLength},
+		{"HASHER_MAX_CONCURRENT", &cfg.Hasher.MaxConcurrent},
+		{"HASHER_MAX_QUEUED", &cfg.Hasher.MaxQueued},
+		{"PASSWORD_MIN_LENGTH", &cfg.Password.MinLength},
+		{"PASSWORD_MAX_LENGTH", &cfg.Password.MaxLength},
+		{"PASSWORD_MIN_ENTROPY", &cfg.Password.MinEntropy},
+		{"MFA_MAX_ATTEMPTS", &cfg.MFA.MaxAttempts},
+		{"MFA_RECOVERY_CODES", &cfg.MFA.RecoveryCodes},
+	}
+
+	for _, i := range ints {
+		if err := parseInt(i.key, i.dest); err != nil {
+			return nil, err
+		}
+	}
+
+	bools := []struct {
+		key  string
+		dest *bool
+	}{
+		{"SESSION_COOKIE_SECURE", &cfg.Session.CookieSecure},
+		{"REQUIRE_EMAIL_VERIFICATION", &cfg.Account.RequireVerifiedEmail},
+		{"MAGIC_LINK_SAME_DEVICE", &cfg.Account.MagicLinkSameDevice},
+		{"TRUST_PROXY", &cfg.Server.TrustProxy},
+		{"RATE_LIMIT_ENABLED", &cfg.RateLimit.Enabled},
+		{"WEBAUTHN_REQUIRE_USER_VERIFICATION", &cfg.WebAuthn.RequireUserVerification},
+		{"OAUTH_ENABLED", &cfg.OAuth.Enabled},
+	}
+
+	for _, b := range bools {
+		if err := parseBool(b.key, b.dest); err != nil {
+			return nil, err
+		}
+	}
+
+	// Tokens issued to OAuth clients must not pass for the app's own access
+	// tokens, which carry no scope.
+	if cfg.OAuth.Enabled && cfg.OAuth.Issuer == cfg.Token.Issuer {
+		return nil, errors.New("OAUTH_ISSUER must differ from TOKEN_ISSUER")
+	}
+
+	return cfg, nil
+}
+
+// applyBaseURL fills in the relying party ID and origin left unset from the
+// public address of the app.
+func (c *WebAuthnConfig) applyBaseURL(baseURL string) error {
+	if c.RPID != "" && len(c.Origins) != 0 {
+		return nil
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("parse APP_BASE_URL: invalid URL %q", baseURL)
+	}
+
+	if c.RPID == "" {
+		c.RPID = u.Hostname()
+	}
+
+	if len(c.Origins) == 0 {
+		c.Origins = []string{u.Scheme + "://" + u.Host}
+	}
+
+	return nil
+}
+
+// loadOIDCProviders reads the providers named in OIDC_PROVIDERS. Each needs
+// an issuer and a client ID.
+func loadOIDCProviders(baseURL string) ([]OIDCProviderConfig, error) {
+	var providers []OIDCProviderConfig
+	for _, name := range splitList(os.Getenv("OIDC_PROVIDERS")) {
+		if !validProviderName(name) {
+			return nil, fmt.Errorf("parse OIDC_PROVIDERS: invalid provider name %q", name)
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		provider := OIDCProviderConfig{
+			Name:         name,
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL: getEnv(prefix+"REDIRECT_URL",
+				strings.TrimSuffix(baseURL, "/")+"/signin/oidc/"+name+"/callback"),
+		}
+
+		if scopes := os.Getenv(prefix + "SCOPES"); scopes != "" {
+			provider.Scopes = strings.Fields(scopes)
+		}
+
+		var err error
+		if provider.Issuer, err = requireEnv(prefix + "ISSUER"); err != nil {
+			return nil, err
+		}
+
+		if provider.ClientID, err = requireEnv(prefix + "CLIENT_ID"); err != nil {
+			return nil, err
+		}
+
+		providers = append(providers, provider)
+	}
+
+	return providers, nil
+}
+
+// validProviderName reports whether name is lower-case letters, digits and
+// hyphens, so that it fits in a URL path and an environment variable.
+func validProviderName(name string) bool {
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+
+	return name != ""
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func getEnv(key, fallback string) string {
+	if val, ok := os.LookupEnv(key); ok && val != "" {
+		return val
+	}
+
+	return fallback
+}
+
+func requireEnv(key string) (string, error) {
+	val, ok := os.LookupEnv(key)
+	if !ok || val == "" {
+		return "", fmt.Errorf("%w: %s", ErrMissingEnv, key)
+	}
+
+	return val, nil
+}
+
+func parseDuration(key string, dest *time.Duration) error {
+	val, ok := os.LookupEnv(key)
+	if !ok || val == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", key, err)
+	}
+
+	*dest = d
+	return nil
+}
+
+func parseInt(key string, dest *int) error {
+	val, ok := os.LookupEnv(key)
+	if !ok || val == "" {
+		return nil
+	}
+
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", key, err)
+	}
+
+	*dest = i
+	return nil
+}
+
+func parseBool(key string, dest *bool) error {
+	val, ok := os.LookupEnv(key)
+	if !ok || val == "" {
+		return nil
+	}
+
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", key, err)
+	}
+
+	*dest = b
+	return nil
+}
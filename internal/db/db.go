@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"strings"
+
+	"github.com/ferdiebergado/fullstackgo/internal/config"
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the pgx driver
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Dialect identifies a supported database engine.
+//
+// Repository queries are written in the subset of SQL both engines accept:
+// $n placeholders, RETURNING clauses, ON CONFLICT upserts and timestamps
+// passed in as parameters. What differs between the engines, such as schema
+// definitions, migration locking and error codes, is handled here.
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+// pgUniqueViolation is the SQLSTATE raised on unique constraint violations.
+const pgUniqueViolation = "23505"
+
+// sqlitePragmas are applied to every SQLite connection.
+const sqlitePragmas = "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+
+var ErrUnsupportedDSN = errors.New("unsupported database url")
+
+// DriverName returns the database/sql driver registered for the dialect.
+func (d Dialect) DriverName() string {
+	if d == SQLite {
+		return "sqlite"
+	}
+
+	return "pgx"
+}
+
+// Migrations returns the embedded migrations written for the dialect.
+func (d Dialect) Migrations() fs.FS {
+	sub, err := fs.Sub(EmbeddedMigrations(), string(d))
+	if err != nil {
+		// The dialect names are valid paths by construction.
+		panic(err)
+	}
+
+	return sub
+}
+
+// ParseDSN selects the dialect from the scheme of a database URL and returns
+// the data source name to hand to its driver. SQLite URLs take the form
+// sqlite:///path/to/file.db, sqlite://relative.db or sqlite://:memory:.
+func ParseDSN(dsn string) (Dialect, string, error) {
+	scheme, rest, ok := strings.Cut(dsn, "://")
+	if !ok {
+		return "", "", fmt.Errorf("%w: missing scheme", ErrUnsupportedDSN)
+	}
+
+	switch scheme {
+	case "postgres", "postgresql":
+		return Postgres, dsn, nil
+	case "sqlite", "sqlite3":
+		path, query, _ := strings.Cut(rest, "?")
+		if path == "" {
+			return "", "", fmt.Errorf("%w: missing sqlite path", ErrUnsupportedDSN)
+		}
+
+		params, err := url.ParseQuery(query)
+		if err != nil {
+			return "", "", fmt.Errorf("%w: %w", ErrUnsupportedDSN, err)
+		}
+
+		source := "file:" + path + "?" + sqlitePragmas
+		if enc := params.Encode(); enc != "" {
+			source += "&" + enc
+		}
+
+		return SQLite, source, nil
+	default:
+		return "", "", fmt.Errorf("%w: unknown scheme %q", ErrUnsupportedDSN, scheme)
+	}
+}
+
+// Open connects to the database at cfg.URL and verifies the connection.
+func Open(ctx context.Context, cfg config.DatabaseConfig) (*sql.DB, Dialect, error) {
+	dialect, dsn, err := ParseDSN(cfg.URL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	conn, err := sql.Open(dialect.DriverName(), dsn)
+	if err != nil {
+		return nil, "", fmt.Errorf("open database: %w", err)
+	}
+
+	if dialect == SQLite {
+		// SQLite allows a single writer, and every connection to an in-memory
+		// database opens a new, empty one.
+		conn.SetMaxOpenConns(1)
+	} else {
+		conn.SetMaxOpenConns(cfg.MaxOpenConns)
+		conn.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	conn.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return nil, "", fmt.Errorf("ping database: %w", err)
+	}
+
+	return conn, dialect, nil
+}
+
+// IsUniqueViolation reports whether err was caused by a unique or primary key
+// constraint on either engine.
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgUniqueViolation
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code()
+		return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	}
+
+	return false
+}
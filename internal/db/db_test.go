@@ -0,0 +1,104 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/config"
+	"github.com/ferdiebergado/fullstackgo/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		dsn     string
+		dialect db.Dialect
+		source  string
+	}{
+		{"postgres scheme", "postgres://u:p@localhost:5432/app", db.Postgres, "postgres://u:p@localhost:5432/app"},
+		{"postgresql scheme", "postgresql://localhost/app", db.Postgres, "postgresql://localhost/app"},
+		{"sqlite absolute path", "sqlite:///var/lib/app.db", db.SQLite,
+			"file:/var/lib/app.db?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"},
+		{"sqlite in memory", "sqlite://:memory:", db.SQLite,
+			"file::memory:?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"},
+		{"sqlite with params", "sqlite://app.db?mode=ro", db.SQLite,
+			"file:app.db?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&mode=ro"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect, source, err := db.ParseDSN(tt.dsn)
+
+			assert.NoError(t, err, "parse should not return an error")
+			assert.Equal(t, tt.dialect, dialect, "dialect should match")
+			assert.Equal(t, tt.source, source, "data source should match")
+		})
+	}
+}
+
+func TestParseDSN_Unsupported(t *testing.T) {
+	for _, dsn := range []string{"mysql://localhost/app", "app.db", "sqlite://"} {
+		t.Run(dsn, func(t *testing.T) {
+			_, _, err := db.ParseDSN(dsn)
+			assert.ErrorIs(t, err, db.ErrUnsupportedDSN, "errors should match")
+		})
+	}
+}
+
+func TestOpen_SQLiteMigrateUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	conn, dialect, err := db.Open(ctx, config.DatabaseConfig{URL: "sqlite://:memory:"})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	assert.Equal(t, db.SQLite, dialect, "dialect should match")
+
+	migrator := db.NewMigrator(conn, dialect, dialect.Migrations())
+
+	applied, err := migrator.Up(ctx)
+	assert.NoError(t, err, "up should not return an error")
+	assert.NotEmpty(t, applied, "migrations should be applied")
+
+	applied, err = migrator.Up(ctx)
+	assert.NoError(t, err, "up should not return an error")
+	assert.Empty(t, applied, "migrations should only be applied once")
+
+	statuses, err := migrator.Status(ctx)
+	assert.NoError(t, err, "status should not return an error")
+	for _, s := range statuses {
+		if assert.NotNil(t, s.AppliedAt, "%d_%s should be applied", s.Version, s.Name) {
+			assert.WithinDuration(t, time.Now(), *s.AppliedAt, time.Minute, "applied_at should be recent")
+		}
+	}
+
+	reverted, err := migrator.Down(ctx, len(statuses))
+	assert.NoError(t, err, "down should not return an error")
+	assert.Len(t, reverted, len(statuses), "all migrations should be reverted")
+}
+
+func TestIsUniqueViolation_SQLite(t *testing.T) {
+	ctx := context.Background()
+	conn, _, err := db.Open(ctx, config.DatabaseConfig{URL: "sqlite://:memory:"})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	if _, err := conn.ExecContext(ctx, "CREATE TABLE t (v TEXT UNIQUE)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	_, err = conn.ExecContext(ctx, "INSERT INTO t (v) VALUES ($1)", "a")
+	assert.NoError(t, err, "first insert should succeed")
+
+	_, err = conn.ExecContext(ctx, "INSERT INTO t (v) VALUES ($1)", "a")
+	assert.True(t, db.IsUniqueViolation(err), "duplicate insert should be a unique violation")
+
+	assert.False(t, db.IsUniqueViolation(errors.New("other")), "other errors should not be unique violations")
+	assert.False(t, db.IsUniqueViolation(nil), "nil should not be a unique violation")
+}
@@ -0,0 +1,87 @@
+// Package dbtest provides migrated databases for integration tests.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/config"
+	"github.com/ferdiebergado/fullstackgo/internal/db"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+)
+
+// EnvDatabaseURL names the variable holding a PostgreSQL server URL to run
+// the integration tests against. Without it, tests use in-memory SQLite.
+const EnvDatabaseURL = "TEST_DATABASE_URL"
+
+// dbNameLength is the number of random bytes in a temporary database name.
+const dbNameLength = 8
+
+// New returns a freshly migrated database that is discarded when the test ends.
+//
+// Each call gets its own in-memory SQLite database, or, when TEST_DATABASE_URL
+// points at a PostgreSQL server, its own temporary database on that server.
+func New(t testing.TB) (*sql.DB, db.Dialect) {
+	t.Helper()
+
+	dsn := "sqlite://:memory:"
+	if serverURL := os.Getenv(EnvDatabaseURL); serverURL != "" {
+		dsn = createPostgresDB(t, serverURL)
+	}
+
+	ctx := context.Background()
+	conn, dialect, err := db.Open(ctx, config.DatabaseConfig{URL: dsn, MaxOpenConns: 4, MaxIdleConns: 4})
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	if _, err := db.NewMigrator(conn, dialect, dialect.Migrations()).Up(ctx); err != nil {
+		t.Fatalf("migrate test database: %v", err)
+	}
+
+	return conn, dialect
+}
+
+// createPostgresDB creates a temporary database on the server and returns its URL.
+func createPostgresDB(t testing.TB, serverURL string) string {
+	t.Helper()
+
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		t.Fatalf("parse %s: %v", EnvDatabaseURL, err)
+	}
+
+	suffix, err := security.GenerateRandomBytes(dbNameLength)
+	if err != nil {
+		t.Fatalf("generate database name: %v", err)
+	}
+	name := fmt.Sprintf("test_%x", suffix)
+
+	ctx := context.Background()
+	admin, _, err := db.Open(ctx, config.DatabaseConfig{URL: serverURL, MaxOpenConns: 1, MaxIdleConns: 1})
+	if err != nil {
+		t.Fatalf("connect to test server: %v", err)
+	}
+
+	if _, err := admin.ExecContext(ctx, "CREATE DATABASE "+name); err != nil {
+		admin.Close()
+		t.Fatalf("create test database: %v", err)
+	}
+
+	// Registered before the caller's cleanup, so it runs after the test's
+	// connections have been closed.
+	t.Cleanup(func() {
+		defer admin.Close()
+		if _, err := admin.ExecContext(ctx, "DROP DATABASE IF EXISTS "+name+" WITH (FORCE)"); err != nil {
+			t.Errorf("drop test database: %v", err)
+		}
+	})
+
+	u.Path = "/" + name
+	return u.String()
+}
@@ -0,0 +1,355 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed migrations/*/*.sql
+var migrations embed.FS
+
+// MigrationsDir is the source directory of the embedded migrations, relative
+// to this package.
+const MigrationsDir = "migrations"
+
+// migrationLockID identifies the advisory lock held while migrating.
+const migrationLockID int64 = 7_245_130_911
+
+var (
+	ErrInvalidMigration = errors.New("invalid migration")
+	ErrNoDownMigration  = errors.New("migration has no down script")
+)
+
+// migrationFile matches names like 20250201000001_create_users.up.sql.
+var migrationFile = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator applies versioned migrations and records them in schema_migrations.
+type Migrator struct {
+	db      *sql.DB
+	dialect Dialect
+	fsys    fs.FS
+}
+
+func NewMigrator(db *sql.DB, dialect Dialect, fsys fs.FS) *Migrator {
+	return &Migrator{
+		db:      db,
+		dialect: dialect,
+		fsys:    fsys,
+	}
+}
+
+// EmbeddedMigrations returns the SQL migrations shipped with the application,
+// one directory per dialect. Use Dialect.Migrations for a single dialect.
+func EmbeddedMigrations() fs.FS {
+	sub, err := fs.Sub(migrations, MigrationsDir)
+	if err != nil {
+		// MigrationsDir is a valid path by construction.
+		panic(err)
+	}
+
+	return sub
+}
+
+// LoadMigrations reads the migrations in the root of fsys, ordered by version.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: unexpected file %s", ErrInvalidMigration, entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidMigration, entry.Name(), err)
+		}
+
+		contents, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("%w: version %d is used by %s and %s", ErrInvalidMigration, version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(contents)
+		} else {
+			m.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("%w: %d_%s has no up script", ErrInvalidMigration, m.Version, m.Name)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// CreateMigration writes empty up and down scripts for a new migration to dir,
+// versioned with the given time, and returns their paths.
+func CreateMigration(dir, name string, now time.Time) (string, string, error) {
+	base := fmt.Sprintf("%s_%s", now.UTC().Format("20060102150405"), name)
+	if !migrationFile.MatchString(base + ".up.sql") {
+		return "", "", fmt.Errorf("%w: name must be lowercase letters, digits and underscores", ErrInvalidMigration)
+	}
+
+	up := filepath.Join(dir, base+".up.sql")
+	down := filepath.Join(dir, base+".down.sql")
+
+	for _, path := range []string{up, down} {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err != nil {
+			return "", "", fmt.Errorf("create migration: %w", err)
+		}
+
+		if err := f.Close(); err != nil {
+			return "", "", fmt.Errorf("create migration: %w", err)
+		}
+	}
+
+	return up, down, nil
+}
+
+const CreateMigrationsTableQuery = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)
+`
+
+const AcquireMigrationLockQuery = `SELECT pg_advisory_lock($1)`
+
+const ReleaseMigrationLockQuery = `SELECT pg_advisory_unlock($1)`
+
+const AppliedMigrationsQuery = `
+SELECT version, applied_at
+FROM schema_migrations
+ORDER BY version
+`
+
+const InsertMigrationQuery = `
+INSERT INTO schema_migrations (version, name)
+VALUES ($1, $2)
+`
+
+const DeleteMigrationQuery = `
+DELETE FROM schema_migrations
+WHERE version = $1
+`
+
+// Up applies all pending migrations and returns the ones it applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		migrations, done, err := m.load(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range migrations {
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+
+			if err := m.apply(ctx, conn, mig.Up, InsertMigrationQuery, mig.Version, mig.Name); err != nil {
+				return fmt.Errorf("apply migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+
+			applied = append(applied, mig)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down rolls back the latest steps applied migrations and returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		migrations, done, err := m.load(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			mig := migrations[i]
+			if _, ok := done[mig.Version]; !ok {
+				continue
+			}
+
+			if mig.Down == "" {
+				return fmt.Errorf("%w: %d_%s", ErrNoDownMigration, mig.Version, mig.Name)
+			}
+
+			if err := m.apply(ctx, conn, mig.Down, DeleteMigrationQuery, mig.Version); err != nil {
+				return fmt.Errorf("revert migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+
+			reverted = append(reverted, mig)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status reports every known migration and when it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		migrations, done, err := m.load(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		statuses = make([]MigrationStatus, 0, len(migrations))
+		for _, mig := range migrations {
+			status := MigrationStatus{Migration: mig}
+			if appliedAt, ok := done[mig.Version]; ok {
+				status.AppliedAt = &appliedAt
+			}
+
+			statuses = append(statuses, status)
+		}
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+// withLock runs fn on a dedicated connection while holding the migration
+// advisory lock so that concurrent instances migrate one at a time. SQLite has
+// no advisory locks; its database-wide write lock serializes the migration
+// transactions and the schema_migrations primary key rejects a second apply.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if m.dialect == Postgres {
+		if _, err := conn.ExecContext(ctx, AcquireMigrationLockQuery, migrationLockID); err != nil {
+			return fmt.Errorf("acquire migration lock: %w", err)
+		}
+
+		defer func() {
+			// Use a fresh context so the lock is released even if ctx was cancelled.
+			if _, unlockErr := conn.ExecContext(context.Background(), ReleaseMigrationLockQuery, migrationLockID); unlockErr != nil {
+				err = errors.Join(err, fmt.Errorf("release migration lock: %w", unlockErr))
+			}
+		}()
+	}
+
+	if _, err := conn.ExecContext(ctx, CreateMigrationsTableQuery); err != nil {
+		return fmt.Errorf("create migrations table: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// load returns the available migrations and the applied versions.
+func (m *Migrator) load(ctx context.Context, conn *sql.Conn) ([]Migration, map[int64]time.Time, error) {
+	migrations, err := LoadMigrations(m.fsys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, AppliedMigrationsQuery)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	done := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, nil, fmt.Errorf("scan applied migration: %w", err)
+		}
+
+		done[version] = appliedAt
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("query applied migrations: %w", err)
+	}
+
+	return migrations, done, nil
+}
+
+// apply runs a migration script and its bookkeeping query in one transaction.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, script, query string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("execute script: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("record migration: %w", err)
+	}
+
+	return tx.Commit()
+}
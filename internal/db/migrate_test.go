@@ -0,0 +1,194 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+const lockID int64 = 7_245_130_911
+
+var sqlmockOpts = sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual)
+
+var testMigrations = fstest.MapFS{
+	"1_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id INT)")},
+	"1_create_a.down.sql": {Data: []byte("DROP TABLE a")},
+	"2_create_b.up.sql":   {Data: []byte("CREATE TABLE b (id INT)")},
+	"2_create_b.down.sql": {Data: []byte("DROP TABLE b")},
+}
+
+func TestLoadMigrations_Sorted(t *testing.T) {
+	migrations, err := db.LoadMigrations(testMigrations)
+
+	assert.NoError(t, err, "load should not return an error")
+	if assert.Len(t, migrations, 2, "all migrations should be loaded") {
+		assert.Equal(t, int64(1), migrations[0].Version, "versions should be ascending")
+		assert.Equal(t, "create_a", migrations[0].Name, "name should match")
+		assert.Equal(t, "CREATE TABLE a (id INT)", migrations[0].Up, "up script should match")
+		assert.Equal(t, "DROP TABLE a", migrations[0].Down, "down script should match")
+		assert.Equal(t, int64(2), migrations[1].Version, "versions should be ascending")
+	}
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"unexpected file", fstest.MapFS{"README.md": {}}},
+		{"missing up script", fstest.MapFS{"1_a.down.sql": {Data: []byte("DROP TABLE a")}}},
+		{"duplicate version", fstest.MapFS{
+			"1_a.up.sql": {Data: []byte("SELECT 1")},
+			"1_b.up.sql": {Data: []byte("SELECT 1")},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.LoadMigrations(tt.fsys)
+			assert.ErrorIs(t, err, db.ErrInvalidMigration, "errors should match")
+		})
+	}
+}
+
+func TestEmbeddedMigrations_Load(t *testing.T) {
+	var versions [][]int64
+
+	for _, dialect := range []db.Dialect{db.Postgres, db.SQLite} {
+		migrations, err := db.LoadMigrations(dialect.Migrations())
+
+		assert.NoError(t, err, "%s migrations should be valid", dialect)
+		assert.NotEmpty(t, migrations, "%s migrations should not be empty", dialect)
+
+		dialectVersions := make([]int64, 0, len(migrations))
+		for _, m := range migrations {
+			assert.NotEmpty(t, m.Down, "migration %s/%d_%s should have a down script", dialect, m.Version, m.Name)
+			dialectVersions = append(dialectVersions, m.Version)
+		}
+
+		versions = append(versions, dialectVersions)
+	}
+
+	assert.Equal(t, versions[0], versions[1], "every dialect should have the same migrations")
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC)
+
+	up, down, err := db.CreateMigration(dir, "add_widgets", now)
+
+	assert.NoError(t, err, "create should not return an error")
+	assert.Equal(t, filepath.Join(dir, "20250203040506_add_widgets.up.sql"), up, "up path should match")
+	assert.Equal(t, filepath.Join(dir, "20250203040506_add_widgets.down.sql"), down, "down path should match")
+	assert.FileExists(t, up, "up script should be created")
+	assert.FileExists(t, down, "down script should be created")
+
+	_, _, err = db.CreateMigration(dir, "add_widgets", now)
+	assert.ErrorIs(t, err, os.ErrExist, "existing migrations should not be overwritten")
+
+	_, _, err = db.CreateMigration(dir, "Add Widgets", now)
+	assert.ErrorIs(t, err, db.ErrInvalidMigration, "invalid names should be rejected")
+}
+
+func TestMigrator_Up_AppliesPending(t *testing.T) {
+	mock, migrator := setupMockMigrator(t)
+	expectLock(mock)
+	mock.ExpectQuery(db.AppliedMigrationsQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE b (id INT)").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(db.InsertMigrationQuery).WithArgs(int64(2), "create_b").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	applied, err := migrator.Up(context.Background())
+
+	assert.NoError(t, err, "up should not return an error")
+	if assert.Len(t, applied, 1, "only pending migrations should be applied") {
+		assert.Equal(t, int64(2), applied[0].Version, "version should match")
+	}
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestMigrator_Up_RollsBackFailedMigration(t *testing.T) {
+	mock, migrator := setupMockMigrator(t)
+	expectLock(mock)
+	mock.ExpectQuery(db.AppliedMigrationsQuery).WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE a (id INT)").WillReturnError(errors.New("syntax error"))
+	mock.ExpectRollback()
+	expectUnlock(mock)
+
+	applied, err := migrator.Up(context.Background())
+
+	assert.Error(t, err, "up should return an error")
+	assert.Empty(t, applied, "no migrations should be applied")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestMigrator_Down_RevertsLatest(t *testing.T) {
+	mock, migrator := setupMockMigrator(t)
+	now := time.Now()
+	expectLock(mock)
+	mock.ExpectQuery(db.AppliedMigrationsQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, now).AddRow(2, now))
+	mock.ExpectBegin()
+	mock.ExpectExec("DROP TABLE b").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(db.DeleteMigrationQuery).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	reverted, err := migrator.Down(context.Background(), 1)
+
+	assert.NoError(t, err, "down should not return an error")
+	if assert.Len(t, reverted, 1, "one migration should be reverted") {
+		assert.Equal(t, int64(2), reverted[0].Version, "the latest migration should be reverted")
+	}
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestMigrator_Status(t *testing.T) {
+	mock, migrator := setupMockMigrator(t)
+	now := time.Now()
+	expectLock(mock)
+	mock.ExpectQuery(db.AppliedMigrationsQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, now))
+	expectUnlock(mock)
+
+	statuses, err := migrator.Status(context.Background())
+
+	assert.NoError(t, err, "status should not return an error")
+	if assert.Len(t, statuses, 2, "all migrations should be reported") {
+		assert.NotNil(t, statuses[0].AppliedAt, "applied migration should have a timestamp")
+		assert.Nil(t, statuses[1].AppliedAt, "pending migration should not have a timestamp")
+	}
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockMigrator(t *testing.T) (sqlmock.Sqlmock, *db.Migrator) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, db.NewMigrator(mockDB, db.Postgres, testMigrations)
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(db.AcquireMigrationLockQuery).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(db.CreateMigrationsTableQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(db.ReleaseMigrationLockQuery).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+}
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS roles (
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    role TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    action TEXT NOT NULL,
+    resource TEXT NOT NULL,
+    PRIMARY KEY (role, action, resource)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS user_roles_role_idx ON user_roles (role);
+
+INSERT INTO roles (name, description) VALUES ('admin', 'Full access to every resource');
+INSERT INTO permissions (role, action, resource) VALUES ('admin', '*', '*');
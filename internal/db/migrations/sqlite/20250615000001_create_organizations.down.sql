@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS invitations;
+DROP TABLE IF EXISTS memberships;
+DROP TABLE IF EXISTS organizations;
+DELETE FROM roles WHERE name IN ('owner', 'member');
+ALTER TABLE roles DROP COLUMN scope;
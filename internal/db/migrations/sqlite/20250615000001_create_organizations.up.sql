@@ -0,0 +1,39 @@
+ALTER TABLE roles ADD COLUMN scope TEXT NOT NULL DEFAULT 'global'
+    CHECK (scope IN ('global', 'organization'));
+
+CREATE TABLE IF NOT EXISTS organizations (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS memberships (
+    organization_id TEXT NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role TEXT NOT NULL REFERENCES roles (name),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS memberships_user_id_idx ON memberships (user_id);
+
+CREATE TABLE IF NOT EXISTS invitations (
+    id TEXT PRIMARY KEY,
+    organization_id TEXT NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    role TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS invitations_organization_id_email_idx ON invitations (organization_id, email);
+
+INSERT INTO roles (name, description, scope) VALUES
+    ('owner', 'Manages an organization, its members and invitations', 'organization'),
+    ('member', 'Reads an organization and its members', 'organization');
+INSERT INTO permissions (role, action, resource) VALUES
+    ('owner', '*', 'organization'),
+    ('owner', '*', 'members'),
+    ('owner', '*', 'invitations'),
+    ('member', 'read', 'organization'),
+    ('member', 'read', 'members');
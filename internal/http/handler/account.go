@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+func (h *authHandler) HandleChangePassword(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.UserFromContext(r.Context())
+	if !ok {
+		Unauthorized(w, "Authentication required.")
+		return
+	}
+
+	var params model.ChangePasswordParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	if err := h.service.ChangePassword(r.Context(), user, params); err != nil {
+		if errors.Is(err, service.ErrPasswordMismatch) {
+			currentPasswordMismatch(w)
+			return
+		}
+
+		if errors.Is(err, service.ErrPasswordBreached) {
+			passwordBreached(w)
+			return
+		}
+
+		serviceError(w, err)
+		return
+	}
+
+	// Changing the password ended every session, so start a new one for this client.
+	token, session, err := h.sessions.CreateSession(r.Context(), user.ID)
+	if err != nil {
+		serverError(w)
+		return
+	}
+
+	h.cookie.set(w, token, session.ExpiresAt)
+
+	res := APIResponse{
+		Message: "Password changed.",
+		Data:    session,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *authHandler) HandleChangeEmail(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.UserFromContext(r.Context())
+	if !ok {
+		Unauthorized(w, "Authentication required.")
+		return
+	}
+
+	var params model.ChangeEmailParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	updated, err := h.service.ChangeEmail(r.Context(), user, params)
+	if err != nil {
+		if errors.Is(err, service.ErrPasswordMismatch) {
+			currentPasswordMismatch(w)
+			return
+		}
+
+		if errors.Is(err, service.ErrEmailTaken) {
+			res := APIResponse{
+				Message: "Invalid input!",
+				Errors: []map[string]string{
+					{"email": err.Error()},
+				},
+			}
+
+			responseJSON(w, http.StatusUnprocessableEntity, res)
+			return
+		}
+
+		serviceError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Email changed.",
+		Data:    updated,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func currentPasswordMismatch(w http.ResponseWriter) {
+	res := APIResponse{
+		Message: "Invalid input!",
+		Errors: []map[string]string{
+			{"current_password": "current password is incorrect"},
+		},
+	}
+
+	responseJSON(w, http.StatusUnprocessableEntity, res)
+}
@@ -0,0 +1,98 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	changePasswordURL = "/api/me/password"
+	changeEmailURL    = "/api/me/email"
+	newEmail          = "new@example.com"
+)
+
+func TestAuthHandler_HandleChangePassword_Success(t *testing.T) {
+	user := &model.User{ID: testID, Email: testEmail}
+	params := model.ChangePasswordParams{CurrentPassword: testPassword, Password: "new", PasswordConfirm: "new"}
+	req := newJSONRequest(t, http.MethodPost, changePasswordURL, params)
+	req = req.WithContext(service.ContextWithUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	mockService, mockSessions, mockValidator, authHandler := setupMockService(t)
+	mockValidator.EXPECT().Struct(params).Return(nil)
+	mockService.EXPECT().ChangePassword(req.Context(), user, params).Return(nil)
+	mockSessions.EXPECT().CreateSession(req.Context(), testID).Return(testToken, &model.Session{
+		UserID:    testID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+
+	authHandler.HandleChangePassword(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+
+	cookie := findCookie(rr.Result().Cookies(), testCookieName)
+	if assert.NotNil(t, cookie, "a new session cookie should be set") {
+		assert.Equal(t, testToken, cookie.Value, "session token should match")
+	}
+}
+
+func TestAuthHandler_HandleChangePassword_WrongPassword(t *testing.T) {
+	user := &model.User{ID: testID, Email: testEmail}
+	params := model.ChangePasswordParams{CurrentPassword: "wrong", Password: "new", PasswordConfirm: "new"}
+	req := newJSONRequest(t, http.MethodPost, changePasswordURL, params)
+	req = req.WithContext(service.ContextWithUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	mockService, mockSessions, mockValidator, authHandler := setupMockService(t)
+	mockValidator.EXPECT().Struct(params).Return(nil)
+	mockService.EXPECT().ChangePassword(req.Context(), user, params).Return(service.ErrPasswordMismatch)
+	mockSessions.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+	authHandler.HandleChangePassword(rr, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, "Response status code should match")
+}
+
+func TestAuthHandler_HandleChangePassword_Unauthenticated(t *testing.T) {
+	req := newJSONRequest(t, http.MethodPost, changePasswordURL, model.ChangePasswordParams{})
+	rr := httptest.NewRecorder()
+
+	_, _, _, authHandler := setupMockService(t)
+	authHandler.HandleChangePassword(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code, "Response status code should match")
+}
+
+func TestAuthHandler_HandleChangeEmail(t *testing.T) {
+	tests := []struct {
+		name   string
+		user   *model.User
+		err    error
+		status int
+	}{
+		{"email should be changed", &model.User{ID: testID, Email: newEmail}, nil, http.StatusOK},
+		{"wrong password should be rejected", nil, service.ErrPasswordMismatch, http.StatusUnprocessableEntity},
+		{"taken email should be rejected", nil, service.ErrEmailTaken, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &model.User{ID: testID, Email: testEmail}
+			params := model.ChangeEmailParams{CurrentPassword: testPassword, Email: newEmail}
+			req := newJSONRequest(t, http.MethodPost, changeEmailURL, params)
+			req = req.WithContext(service.ContextWithUser(req.Context(), user))
+			rr := httptest.NewRecorder()
+
+			mockService, _, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(params).Return(nil)
+			mockService.EXPECT().ChangeEmail(req.Context(), user, params).Return(tt.user, tt.err)
+
+			authHandler.HandleChangeEmail(rr, req)
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -56,10 +60,67 @@ func responseValError(w http.ResponseWriter, valErrs *validator.ValidationErrors
 	responseJSON(w, http.StatusUnprocessableEntity, res)
 }
 
+func Unauthorized(w http.ResponseWriter, msg string) {
+	res := APIResponse{
+		Message: msg,
+	}
+
+	responseJSON(w, http.StatusUnauthorized, res)
+}
+
+func Forbidden(w http.ResponseWriter, msg string) {
+	res := APIResponse{
+		Message: msg,
+	}
+
+	responseJSON(w, http.StatusForbidden, res)
+}
+
+func NotFound(w http.ResponseWriter, msg string) {
+	res := APIResponse{
+		Message: msg,
+	}
+
+	responseJSON(w, http.StatusNotFound, res)
+}
+
+// TooManyRequests responds with 429 and a Retry-After header rounded up to
+// whole seconds.
+func TooManyRequests(w http.ResponseWriter, msg string, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+
+	res := APIResponse{
+		Message: msg,
+	}
+
+	responseJSON(w, http.StatusTooManyRequests, res)
+}
+
 func serverError(w http.ResponseWriter) {
 	http.Error(w, "An error occurred.", http.StatusInternalServerError)
 }
 
+// serviceBusyRetryAfter is the delay suggested to clients when the password
+// hasher is saturated.
+const serviceBusyRetryAfter = 5 * time.Second
+
+// serviceError responds to an unexpected service error: 503 when the password
+// hasher is saturated, 500 otherwise.
+func serviceError(w http.ResponseWriter, err error) {
+	if errors.Is(err, security.ErrHasherBusy) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(serviceBusyRetryAfter.Seconds())))
+
+		res := APIResponse{
+			Message: "Server is busy. Try again later.",
+		}
+
+		responseJSON(w, http.StatusServiceUnavailable, res)
+		return
+	}
+
+	serverError(w)
+}
+
 func handleValidationError(w http.ResponseWriter, err error) bool {
 	var valErrs *validator.ValidationErrors
 	if errors.As(err, &valErrs) {
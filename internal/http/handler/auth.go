@@ -1,3 +1,4 @@
+//go:generate mockgen -destination=mocks/auth_handler_mock.go -package=mocks . AuthHandler
 package handler
 
 import (
@@ -12,19 +13,52 @@ import (
 type AuthHandler interface {
 	HandleUserSignUp(w http.ResponseWriter, r *http.Request)
 	HandleUserSignIn(w http.ResponseWriter, r *http.Request)
+	HandleUserSignOut(w http.ResponseWriter, r *http.Request)
+	HandleCurrentUser(w http.ResponseWriter, r *http.Request)
+	HandleTokenSignIn(w http.ResponseWriter, r *http.Request)
+	HandleTokenRefresh(w http.ResponseWriter, r *http.Request)
+	HandleTokenRevoke(w http.ResponseWriter, r *http.Request)
+	HandleVerifyEmail(w http.ResponseWriter, r *http.Request)
+	HandleResendVerification(w http.ResponseWriter, r *http.Request)
+	HandleForgotPassword(w http.ResponseWriter, r *http.Request)
+	HandleResetPassword(w http.ResponseWriter, r *http.Request)
+	HandleChangePassword(w http.ResponseWriter, r *http.Request)
+	HandleChangeEmail(w http.ResponseWriter, r *http.Request)
+	HandleMFASignIn(w http.ResponseWriter, r *http.Request)
+	HandleMFATokenSignIn(w http.ResponseWriter, r *http.Request)
+	HandleEnrollTOTP(w http.ResponseWriter, r *http.Request)
+	HandleConfirmTOTP(w http.ResponseWriter, r *http.Request)
+	HandleDisableTOTP(w http.ResponseWriter, r *http.Request)
+	HandleRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request)
+	HandlePasskeySignInOptions(w http.ResponseWriter, r *http.Request)
+	HandlePasskeySignIn(w http.ResponseWriter, r *http.Request)
+	HandlePasskeyRegistrationOptions(w http.ResponseWriter, r *http.Request)
+	HandleRegisterPasskey(w http.ResponseWriter, r *http.Request)
+	HandleListPasskeys(w http.ResponseWriter, r *http.Request)
+	HandleDeletePasskey(w http.ResponseWriter, r *http.Request)
+	HandleOIDCSignIn(w http.ResponseWriter, r *http.Request)
+	HandleOIDCCallback(w http.ResponseWriter, r *http.Request)
+	HandleListIdentities(w http.ResponseWriter, r *http.Request)
+	HandleRequestMagicLink(w http.ResponseWriter, r *http.Request)
+	HandleMagicLinkSignIn(w http.ResponseWriter, r *http.Request)
 }
 
 type authHandler struct {
 	service   service.AuthService
+	sessions  service.SessionService
 	validator validation.Validator
+	cookie    SessionCookie
 }
 
 var _ AuthHandler = (*authHandler)(nil)
 
-func NewAuthHandler(authService service.AuthService, validator validation.Validator) AuthHandler {
+func NewAuthHandler(authService service.AuthService, sessionService service.SessionService,
+	validator validation.Validator, cookie SessionCookie) AuthHandler {
 	return &authHandler{
 		service:   authService,
+		sessions:  sessionService,
 		validator: validator,
+		cookie:    cookie,
 	}
 }
 
@@ -55,7 +89,12 @@ func (h *authHandler) HandleUserSignUp(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		serverError(w)
+		if errors.Is(err, service.ErrPasswordBreached) {
+			passwordBreached(w)
+			return
+		}
+
+		serviceError(w, err)
 		return
 	}
 
@@ -75,15 +114,71 @@ func (h *authHandler) HandleUserSignIn(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	_, err := h.service.SignInUser(r.Context(), params)
+	userID, err := h.service.SignInUser(r.Context(), params)
+	if err != nil {
+		if mfaRequired(w, err) {
+			return
+		}
+
+		if errors.Is(err, service.ErrUserNotFound) || errors.Is(err, service.ErrPasswordMismatch) {
+			Unauthorized(w, "Invalid email or password.")
+			return
+		}
+
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			emailNotVerified(w)
+			return
+		}
+
+		var lockErr *service.LockoutError
+		if errors.As(err, &lockErr) {
+			TooManyRequests(w, "Too many failed sign-in attempts. Try again later.", lockErr.RetryAfter)
+			return
+		}
+
+		serviceError(w, err)
+		return
+	}
+
+	token, session, err := h.sessions.CreateSession(r.Context(), userID)
 	if err != nil {
 		serverError(w)
 		return
 	}
 
+	h.cookie.set(w, token, session.ExpiresAt)
+
 	res := APIResponse{
 		Message: "Signin successful.",
+		Data:    session,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *authHandler) HandleUserSignOut(w http.ResponseWriter, r *http.Request) {
+	if token := h.cookie.Token(r); token != "" {
+		if err := h.sessions.RevokeSession(r.Context(), token); err != nil {
+			serverError(w)
+			return
+		}
+	}
+
+	h.cookie.clear(w)
+
+	res := APIResponse{
+		Message: "Signout successful.",
 	}
 
 	responseJSON(w, http.StatusOK, res)
 }
+
+func (h *authHandler) HandleCurrentUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.UserFromContext(r.Context())
+	if !ok {
+		Unauthorized(w, "Authentication required.")
+		return
+	}
+
+	responseJSON(w, http.StatusOK, user)
+}
@@ -25,12 +25,15 @@ const (
 	contentType = "application/json"
 	signUpURL   = "/api/signup"
 	signInURL   = "/api/signin"
+	signOutURL  = "/api/signout"
 )
 
 const (
-	testID       = "1"
-	testEmail    = "abc@example.com"
-	testPassword = "hashed"
+	testID         = "1"
+	testEmail      = "abc@example.com"
+	testPassword   = "hashed"
+	testToken      = "token"
+	testCookieName = "session"
 )
 
 func TestAuthHandler_HandleUserSignUp_Success(t *testing.T) {
@@ -49,7 +52,7 @@ func TestAuthHandler_HandleUserSignUp_Success(t *testing.T) {
 	req.Header.Set("Content-Type", contentType)
 	rr := httptest.NewRecorder()
 
-	mockService, mockValidator, authHandler := setupMockService(t)
+	mockService, _, mockValidator, authHandler := setupMockService(t)
 	mockValidator.EXPECT().Struct(params).Return(nil)
 	mockService.EXPECT().SignUpUser(req.Context(), params).DoAndReturn(
 		func(_ context.Context, params model.UserSignUpParams) (*model.User, error) {
@@ -97,7 +100,7 @@ func TestAuthHandler_HandleUserSignUp_DuplicateUser(t *testing.T) {
 	req.Header.Set("Content-Type", contentType)
 	rr := httptest.NewRecorder()
 
-	mockService, mockValidator, authHandler := setupMockService(t)
+	mockService, _, mockValidator, authHandler := setupMockService(t)
 	mockValidator.EXPECT().Struct(params).Return(nil)
 	mockService.EXPECT().SignUpUser(req.Context(), params).Return(nil, service.ErrEmailTaken)
 
@@ -118,7 +121,7 @@ func TestAuthHandler_HandleUserSignUp_DuplicateUser(t *testing.T) {
 }
 
 func TestAuthHandler_HandleUserSignUp_InvalidInput(t *testing.T) {
-	mockService, mockValidator, authHandler := setupMockService(t)
+	mockService, _, mockValidator, authHandler := setupMockService(t)
 
 	tests := []struct {
 		name  string
@@ -201,13 +204,25 @@ func TestAuthHandler_HandleUserSignIn_Success(t *testing.T) {
 	req.Header.Set("Content-Type", contentType)
 	rr := httptest.NewRecorder()
 
-	mockService, mockValidator, authHandler := setupMockService(t)
+	mockService, mockSessions, mockValidator, authHandler := setupMockService(t)
 	mockService.EXPECT().SignInUser(req.Context(), params).Return(testID, nil)
 	mockValidator.EXPECT().Struct(params).Return(nil)
+	mockSessions.EXPECT().CreateSession(req.Context(), testID).Return(testToken, &model.Session{
+		UserID:    testID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
 
 	authHandler.HandleUserSignIn(rr, req)
 	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
 
+	cookie := findCookie(rr.Result().Cookies(), testCookieName)
+	if assert.NotNil(t, cookie, "session cookie should be set") {
+		assert.Equal(t, testToken, cookie.Value, "session token should match")
+		assert.True(t, cookie.HttpOnly, "session cookie should be HttpOnly")
+		assert.True(t, cookie.Secure, "session cookie should be Secure")
+		assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite, "session cookie SameSite should match")
+	}
+
 	actualContentType := rr.Header().Get("Content-Type")
 	assert.Equal(t, contentType, actualContentType, "Content-Type header should match")
 
@@ -219,12 +234,102 @@ func TestAuthHandler_HandleUserSignIn_Success(t *testing.T) {
 	assert.Equal(t, "Signin successful.", res.Message, "Message should match")
 }
 
-func setupMockService(t *testing.T) (*mocks.MockAuthService, *validationMocks.MockValidator, handler.AuthHandler) {
+func TestAuthHandler_HandleUserSignIn_InvalidCredentials(t *testing.T) {
+	for _, svcErr := range []error{service.ErrUserNotFound, service.ErrPasswordMismatch} {
+		t.Run(svcErr.Error(), func(t *testing.T) {
+			params := model.UserSignInParams{
+				Email:    testEmail,
+				Password: testPassword,
+			}
+
+			jsonParams, err := json.Marshal(params)
+			if err != nil {
+				t.Fatalf("json.Marshal: %v, err: %v", params, err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, signInURL, bytes.NewBuffer(jsonParams))
+			req.Header.Set("Content-Type", contentType)
+			rr := httptest.NewRecorder()
+
+			mockService, mockSessions, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(params).Return(nil)
+			mockService.EXPECT().SignInUser(req.Context(), params).Return("", svcErr)
+			mockSessions.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+			authHandler.HandleUserSignIn(rr, req)
+			assert.Equal(t, http.StatusUnauthorized, rr.Code, "Response status code should match")
+			assert.Nil(t, findCookie(rr.Result().Cookies(), testCookieName), "session cookie should not be set")
+		})
+	}
+}
+
+func TestAuthHandler_HandleUserSignOut(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, signOutURL, nil)
+	req.AddCookie(&http.Cookie{Name: testCookieName, Value: testToken})
+	rr := httptest.NewRecorder()
+
+	_, mockSessions, _, authHandler := setupMockService(t)
+	mockSessions.EXPECT().RevokeSession(req.Context(), testToken).Return(nil)
+
+	authHandler.HandleUserSignOut(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+
+	cookie := findCookie(rr.Result().Cookies(), testCookieName)
+	if assert.NotNil(t, cookie, "session cookie should be cleared") {
+		assert.Empty(t, cookie.Value, "session cookie should be empty")
+		assert.Negative(t, cookie.MaxAge, "session cookie should expire")
+	}
+}
+
+func TestAuthHandler_HandleUserSignOut_NoCookie(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, signOutURL, nil)
+	rr := httptest.NewRecorder()
+
+	_, mockSessions, _, authHandler := setupMockService(t)
+	mockSessions.EXPECT().RevokeSession(gomock.Any(), gomock.Any()).Times(0)
+
+	authHandler.HandleUserSignOut(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+}
+
+func setupMockService(t *testing.T) (*mocks.MockAuthService, *mocks.MockSessionService,
+	*validationMocks.MockValidator, handler.AuthHandler) {
 	t.Helper()
 	ctrl := gomock.NewController(t)
 	mockService := mocks.NewMockAuthService(ctrl)
+	mockSessions := mocks.NewMockSessionService(ctrl)
 	mockValidator := validationMocks.NewMockValidator(ctrl)
-	authHandler := handler.NewAuthHandler(mockService, mockValidator)
+	cookie := handler.SessionCookie{Name: testCookieName, Secure: true}
+	authHandler := handler.NewAuthHandler(mockService, mockSessions, mockValidator, cookie)
+
+	return mockService, mockSessions, mockValidator, authHandler
+}
+
+func findCookie(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, c := range cookies {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func TestAuthHandler_HandleCurrentUser(t *testing.T) {
+	user := &model.User{ID: testID, Email: testEmail}
+	req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	req = req.WithContext(service.ContextWithUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	_, _, _, authHandler := setupMockService(t)
+	authHandler.HandleCurrentUser(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+
+	var got model.User
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
 
-	return mockService, mockValidator, authHandler
+	assert.Equal(t, testID, got.ID, "ID should match")
+	assert.Equal(t, testEmail, got.Email, "email should match")
 }
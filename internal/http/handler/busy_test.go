@@ -0,0 +1,58 @@
+package handler_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthHandler_HasherBusy(t *testing.T) {
+	busyErr := fmt.Errorf("hash password: %w", security.ErrHasherBusy)
+
+	t.Run("signup should be unavailable", func(t *testing.T) {
+		params := model.UserSignUpParams{Email: testEmail, Password: testPassword, PasswordConfirm: testPassword}
+		req := newJSONRequest(t, http.MethodPost, signUpURL, params)
+		rr := httptest.NewRecorder()
+
+		mockService, _, mockValidator, authHandler := setupMockService(t)
+		mockValidator.EXPECT().Struct(params).Return(nil)
+		mockService.EXPECT().SignUpUser(req.Context(), params).Return(nil, busyErr)
+
+		authHandler.HandleUserSignUp(rr, req)
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code, "Response status code should match")
+		assert.Equal(t, "5", rr.Header().Get("Retry-After"), "Retry-After should be set")
+	})
+
+	t.Run("signin should be unavailable", func(t *testing.T) {
+		params := model.UserSignInParams{Email: testEmail, Password: testPassword}
+		req := newJSONRequest(t, http.MethodPost, signInURL, params)
+		rr := httptest.NewRecorder()
+
+		mockService, _, mockValidator, authHandler := setupMockService(t)
+		mockValidator.EXPECT().Struct(params).Return(nil)
+		mockService.EXPECT().SignInUser(req.Context(), params).Return("", busyErr)
+
+		authHandler.HandleUserSignIn(rr, req)
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code, "Response status code should match")
+	})
+
+	t.Run("other errors should not be reported as busy", func(t *testing.T) {
+		params := model.UserSignInParams{Email: testEmail, Password: testPassword}
+		req := newJSONRequest(t, http.MethodPost, signInURL, params)
+		rr := httptest.NewRecorder()
+
+		mockService, _, mockValidator, authHandler := setupMockService(t)
+		mockValidator.EXPECT().Struct(params).Return(nil)
+		mockService.EXPECT().SignInUser(req.Context(), params).Return("", errors.New("db down"))
+
+		authHandler.HandleUserSignIn(rr, req)
+		assert.Equal(t, http.StatusInternalServerError, rr.Code, "Response status code should match")
+		assert.Empty(t, rr.Header().Get("Retry-After"), "Retry-After should not be set")
+	})
+}
@@ -0,0 +1,45 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuthHandler_SignIn_Locked(t *testing.T) {
+	params := model.UserSignInParams{Email: testEmail, Password: testPassword}
+	lockErr := &service.LockoutError{RetryAfter: 90*time.Second + time.Millisecond}
+
+	t.Run("session signin should be throttled", func(t *testing.T) {
+		req := newJSONRequest(t, http.MethodPost, "/api/signin", params)
+		rr := httptest.NewRecorder()
+
+		mockService, mockSessions, mockValidator, authHandler := setupMockService(t)
+		mockValidator.EXPECT().Struct(params).Return(nil)
+		mockService.EXPECT().SignInUser(req.Context(), params).Return("", lockErr)
+		mockSessions.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+		authHandler.HandleUserSignIn(rr, req)
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code, "Response status code should match")
+		assert.Equal(t, "91", rr.Header().Get("Retry-After"), "Retry-After should be rounded up")
+	})
+
+	t.Run("token signin should be throttled", func(t *testing.T) {
+		req := newJSONRequest(t, http.MethodPost, tokenURL, params)
+		rr := httptest.NewRecorder()
+
+		mockService, _, mockValidator, authHandler := setupMockService(t)
+		mockValidator.EXPECT().Struct(params).Return(nil)
+		mockService.EXPECT().SignInWithToken(req.Context(), params).Return(nil, lockErr)
+
+		authHandler.HandleTokenSignIn(rr, req)
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code, "Response status code should match")
+		assert.Equal(t, "91", rr.Header().Get("Retry-After"), "Retry-After should be set")
+	})
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+const (
+	// magicLinkCookieName holds the nonce that binds a magic link to the
+	// browser that asked for it, when links are bound to a device.
+	magicLinkCookieName = "magic_link_nonce"
+	magicLinkCookiePath = "/api/signin/magic-link"
+)
+
+func (h *authHandler) HandleRequestMagicLink(w http.ResponseWriter, r *http.Request) {
+	var params model.EmailParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	nonce, err := h.service.RequestMagicLink(r.Context(), params.Email)
+	if err != nil {
+		if errors.Is(err, service.ErrMagicLinksDisabled) {
+			http.NotFound(w, r)
+			return
+		}
+
+		serverError(w)
+		return
+	}
+
+	if nonce != "" {
+		h.setMagicLinkNonce(w, nonce)
+	}
+
+	// The response is the same whether or not the email is registered.
+	res := APIResponse{
+		Message: "If the email belongs to an account, a sign-in link has been sent.",
+	}
+
+	responseJSON(w, http.StatusAccepted, res)
+}
+
+func (h *authHandler) HandleMagicLinkSignIn(w http.ResponseWriter, r *http.Request) {
+	var params model.MagicLinkSignInParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	if cookie, err := r.Cookie(magicLinkCookieName); err == nil {
+		params.Nonce = cookie.Value
+	}
+
+	userID, err := h.service.SignInWithMagicLink(r.Context(), params)
+	if err != nil {
+		if mfaRequired(w, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, service.ErrMagicLinksDisabled):
+			http.NotFound(w, r)
+		case errors.Is(err, service.ErrInvalidToken):
+			Unauthorized(w, "Sign-in link is invalid or expired. Request a new one from this browser.")
+		default:
+			serviceError(w, err)
+		}
+		return
+	}
+
+	h.setMagicLinkNonce(w, "")
+
+	token, session, err := h.sessions.CreateSession(r.Context(), userID)
+	if err != nil {
+		serverError(w)
+		return
+	}
+
+	h.cookie.set(w, token, session.ExpiresAt)
+
+	res := APIResponse{
+		Message: "Signin successful.",
+		Data:    session,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+// setMagicLinkNonce sets the nonce cookie, or clears it when nonce is empty.
+// It lasts for the browser session, as the link itself expires on the
+// server.
+func (h *authHandler) setMagicLinkNonce(w http.ResponseWriter, nonce string) {
+	maxAge := 0
+	if nonce == "" {
+		maxAge = -1
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     magicLinkCookieName,
+		Value:    nonce,
+		Path:     magicLinkCookiePath,
+		MaxAge:   maxAge,
+		Secure:   h.cookie.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
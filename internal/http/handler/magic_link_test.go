@@ -0,0 +1,110 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	magicLinkURL       = "/api/signin/magic-link"
+	magicLinkVerifyURL = "/api/signin/magic-link/verify"
+	magicLinkCookie    = "magic_link_nonce"
+	testNonce          = "nonce"
+)
+
+func TestAuthHandler_HandleRequestMagicLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		nonce  string
+		cookie bool
+	}{
+		{"links bound to the device should set the nonce cookie", testNonce, true},
+		{"links for any device should not set a cookie", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := model.EmailParams{Email: testEmail}
+			req := newJSONRequest(t, http.MethodPost, magicLinkURL, params)
+			rr := httptest.NewRecorder()
+
+			mockService, _, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(params).Return(nil)
+			mockService.EXPECT().RequestMagicLink(req.Context(), testEmail).Return(tt.nonce, nil)
+
+			authHandler.HandleRequestMagicLink(rr, req)
+
+			assert.Equal(t, http.StatusAccepted, rr.Code, "Response status code should match")
+			cookie := findCookie(rr.Result().Cookies(), magicLinkCookie)
+			if !tt.cookie {
+				assert.Nil(t, cookie, "no nonce cookie should be set")
+				return
+			}
+			if assert.NotNil(t, cookie, "a nonce cookie should be set") {
+				assert.Equal(t, testNonce, cookie.Value, "nonce should match")
+				assert.True(t, cookie.HttpOnly, "nonce cookie should be HttpOnly")
+			}
+		})
+	}
+}
+
+func TestAuthHandler_HandleMagicLinkSignIn_Success(t *testing.T) {
+	req := newJSONRequest(t, http.MethodPost, magicLinkVerifyURL, map[string]string{"token": "token"})
+	req.AddCookie(&http.Cookie{Name: magicLinkCookie, Value: testNonce})
+	rr := httptest.NewRecorder()
+	params := model.MagicLinkSignInParams{Token: "token", Nonce: testNonce}
+
+	mockService, mockSessions, mockValidator, authHandler := setupMockService(t)
+	mockValidator.EXPECT().Struct(model.MagicLinkSignInParams{Token: "token"}).Return(nil)
+	mockService.EXPECT().SignInWithMagicLink(req.Context(), params).Return(testID, nil)
+	mockSessions.EXPECT().CreateSession(req.Context(), testID).Return(testToken, &model.Session{
+		UserID:    testID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+
+	authHandler.HandleMagicLinkSignIn(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+
+	cookies := rr.Result().Cookies()
+	if cookie := findCookie(cookies, testCookieName); assert.NotNil(t, cookie, "a session cookie should be set") {
+		assert.Equal(t, testToken, cookie.Value, "session token should match")
+	}
+	if cookie := findCookie(cookies, magicLinkCookie); assert.NotNil(t, cookie, "the nonce cookie should be cleared") {
+		assert.Negative(t, cookie.MaxAge, "nonce cookie should expire")
+	}
+}
+
+func TestAuthHandler_HandleMagicLinkSignIn_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"invalid link should be unauthorized", service.ErrInvalidToken, http.StatusUnauthorized},
+		{"disabled magic links should not be found", service.ErrMagicLinksDisabled, http.StatusNotFound},
+		{"second factor should be required", &service.MFARequiredError{}, http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newJSONRequest(t, http.MethodPost, magicLinkVerifyURL, map[string]string{"token": "token"})
+			rr := httptest.NewRecorder()
+
+			mockService, mockSessions, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(gomock.Any()).Return(nil)
+			mockService.EXPECT().SignInWithMagicLink(req.Context(), model.MagicLinkSignInParams{Token: "token"}).
+				Return("", tt.err)
+			mockSessions.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+			authHandler.HandleMagicLinkSignIn(rr, req)
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
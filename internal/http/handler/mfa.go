@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+func (h *authHandler) HandleMFASignIn(w http.ResponseWriter, r *http.Request) {
+	var params model.MFASignInParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	userID, err := h.service.CompleteMFASignIn(r.Context(), params)
+	if err != nil {
+		mfaSignInError(w, err)
+		return
+	}
+
+	token, session, err := h.sessions.CreateSession(r.Context(), userID)
+	if err != nil {
+		serverError(w)
+		return
+	}
+
+	h.cookie.set(w, token, session.ExpiresAt)
+
+	res := APIResponse{
+		Message: "Signin successful.",
+		Data:    session,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *authHandler) HandleMFATokenSignIn(w http.ResponseWriter, r *http.Request) {
+	var params model.MFASignInParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	tokens, err := h.service.CompleteMFATokenSignIn(r.Context(), params)
+	if err != nil {
+		mfaSignInError(w, err)
+		return
+	}
+
+	responseJSON(w, http.StatusOK, tokens)
+}
+
+func (h *authHandler) HandleEnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.UserFromContext(r.Context())
+	if !ok {
+		Unauthorized(w, "Authentication required.")
+		return
+	}
+
+	var params model.CurrentPasswordParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	enrollment, err := h.service.EnrollTOTP(r.Context(), user, params)
+	if err != nil {
+		if errors.Is(err, service.ErrPasswordMismatch) {
+			currentPasswordMismatch(w)
+			return
+		}
+
+		if errors.Is(err, service.ErrMFAAlreadyEnabled) {
+			mfaConflict(w, err)
+			return
+		}
+
+		serviceError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Scan the QR code with your authenticator app, then confirm with a code.",
+		Data:    enrollment,
+	}
+
+	responseJSON(w, http.StatusCreated, res)
+}
+
+func (h *authHandler) HandleConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.UserFromContext(r.Context())
+	if !ok {
+		Unauthorized(w, "Authentication required.")
+		return
+	}
+
+	var params model.TOTPCodeParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	codes, err := h.service.ConfirmTOTP(r.Context(), user, params)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMFACode) {
+			invalidMFACode(w)
+			return
+		}
+
+		if errors.Is(err, service.ErrMFAAlreadyEnabled) || errors.Is(err, service.ErrMFANotEnrolled) {
+			mfaConflict(w, err)
+			return
+		}
+
+		serviceError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Two-factor authentication enabled. Store the recovery codes somewhere safe.",
+		Data:    codes,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *authHandler) HandleDisableTOTP(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.UserFromContext(r.Context())
+	if !ok {
+		Unauthorized(w, "Authentication required.")
+		return
+	}
+
+	var params model.MFACodeParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	if err := h.service.DisableTOTP(r.Context(), user, params); err != nil {
+		mfaChangeError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Two-factor authentication disabled.",
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *authHandler) HandleRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.UserFromContext(r.Context())
+	if !ok {
+		Unauthorized(w, "Authentication required.")
+		return
+	}
+
+	var params model.MFACodeParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(r.Context(), user, params)
+	if err != nil {
+		mfaChangeError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Recovery codes replaced. Store them somewhere safe.",
+		Data:    codes,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+// mfaRequired responds with the challenge of a sign-in that needs a second
+// factor, and reports whether err was one.
+func mfaRequired(w http.ResponseWriter, err error) bool {
+	var mfaErr *service.MFARequiredError
+	if !errors.As(err, &mfaErr) {
+		return false
+	}
+
+	res := APIResponse{
+		Message: "Two-factor authentication required.",
+		Data:    mfaErr.Challenge,
+	}
+
+	responseJSON(w, http.StatusAccepted, res)
+	return true
+}
+
+func mfaSignInError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrInvalidToken) {
+		Unauthorized(w, "Sign-in expired. Sign in again.")
+		return
+	}
+
+	if errors.Is(err, service.ErrInvalidMFACode) {
+		Unauthorized(w, "Invalid authentication code.")
+		return
+	}
+
+	var lockErr *service.LockoutError
+	if errors.As(err, &lockErr) {
+		TooManyRequests(w, "Too many failed sign-in attempts. Try again later.", lockErr.RetryAfter)
+		return
+	}
+
+	serviceError(w, err)
+}
+
+func mfaChangeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrPasswordMismatch) {
+		currentPasswordMismatch(w)
+		return
+	}
+
+	if errors.Is(err, service.ErrInvalidMFACode) {
+		invalidMFACode(w)
+		return
+	}
+
+	if errors.Is(err, service.ErrMFANotEnrolled) {
+		mfaConflict(w, err)
+		return
+	}
+
+	serviceError(w, err)
+}
+
+func invalidMFACode(w http.ResponseWriter) {
+	res := APIResponse{
+		Message: "Invalid input!",
+		Errors: []map[string]string{
+			{"code": service.ErrInvalidMFACode.Error()},
+		},
+	}
+
+	responseJSON(w, http.StatusUnprocessableEntity, res)
+}
+
+func mfaConflict(w http.ResponseWriter, err error) {
+	res := APIResponse{
+		Message: err.Error(),
+	}
+
+	responseJSON(w, http.StatusConflict, res)
+}
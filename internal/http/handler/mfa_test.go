@@ -0,0 +1,259 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	mfaSignInURL     = "/api/signin/mfa"
+	enrollTOTPURL    = "/api/me/mfa/totp"
+	confirmTOTPURL   = "/api/me/mfa/totp/confirm"
+	disableTOTPURL   = "/api/me/mfa/totp/disable"
+	recoveryCodesURL = "/api/me/mfa/recovery-codes"
+	testMFAToken     = "mfatoken"
+	testMFACode      = "123456"
+)
+
+func TestAuthHandler_SignIn_MFARequired(t *testing.T) {
+	params := model.UserSignInParams{Email: testEmail, Password: testPassword}
+	pending := model.PendingMFA{Token: testMFAToken, ExpiresAt: time.Now().Add(5 * time.Minute).UTC()}
+	mfaErr := &service.MFARequiredError{Challenge: pending}
+
+	t.Run("session signin should return a challenge", func(t *testing.T) {
+		req := newJSONRequest(t, http.MethodPost, "/api/signin", params)
+		rr := httptest.NewRecorder()
+
+		mockService, mockSessions, mockValidator, authHandler := setupMockService(t)
+		mockValidator.EXPECT().Struct(params).Return(nil)
+		mockService.EXPECT().SignInUser(req.Context(), params).Return("", mfaErr)
+		mockSessions.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+		authHandler.HandleUserSignIn(rr, req)
+		assert.Equal(t, http.StatusAccepted, rr.Code, "Response status code should match")
+		assert.Nil(t, findCookie(rr.Result().Cookies(), testCookieName), "no session cookie should be set")
+
+		var res struct {
+			Data model.PendingMFA `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&res), "response should be JSON")
+		assert.Equal(t, testMFAToken, res.Data.Token, "challenge token should match")
+	})
+
+	t.Run("token signin should return a challenge", func(t *testing.T) {
+		req := newJSONRequest(t, http.MethodPost, tokenURL, params)
+		rr := httptest.NewRecorder()
+
+		mockService, _, mockValidator, authHandler := setupMockService(t)
+		mockValidator.EXPECT().Struct(params).Return(nil)
+		mockService.EXPECT().SignInWithToken(req.Context(), params).Return(nil, mfaErr)
+
+		authHandler.HandleTokenSignIn(rr, req)
+		assert.Equal(t, http.StatusAccepted, rr.Code, "Response status code should match")
+	})
+}
+
+func TestAuthHandler_HandleMFASignIn_Success(t *testing.T) {
+	params := model.MFASignInParams{Token: testMFAToken, Code: testMFACode}
+	req := newJSONRequest(t, http.MethodPost, mfaSignInURL, params)
+	rr := httptest.NewRecorder()
+
+	mockService, mockSessions, mockValidator, authHandler := setupMockService(t)
+	mockValidator.EXPECT().Struct(params).Return(nil)
+	mockService.EXPECT().CompleteMFASignIn(req.Context(), params).Return(testID, nil)
+	mockSessions.EXPECT().CreateSession(req.Context(), testID).Return(testToken, &model.Session{
+		UserID:    testID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+
+	authHandler.HandleMFASignIn(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+
+	cookie := findCookie(rr.Result().Cookies(), testCookieName)
+	if assert.NotNil(t, cookie, "a session cookie should be set") {
+		assert.Equal(t, testToken, cookie.Value, "session token should match")
+	}
+}
+
+func TestAuthHandler_HandleMFASignIn_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"wrong code should be rejected", service.ErrInvalidMFACode, http.StatusUnauthorized},
+		{"expired challenge should be rejected", service.ErrInvalidToken, http.StatusUnauthorized},
+		{"locked account should be throttled", &service.LockoutError{RetryAfter: time.Minute}, http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := model.MFASignInParams{Token: testMFAToken, Code: testMFACode}
+			req := newJSONRequest(t, http.MethodPost, mfaSignInURL, params)
+			rr := httptest.NewRecorder()
+
+			mockService, mockSessions, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(params).Return(nil)
+			mockService.EXPECT().CompleteMFASignIn(req.Context(), params).Return("", tt.err)
+			mockSessions.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+			authHandler.HandleMFASignIn(rr, req)
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func TestAuthHandler_HandleMFATokenSignIn(t *testing.T) {
+	params := model.MFASignInParams{Token: testMFAToken, Code: testMFACode}
+	req := newJSONRequest(t, http.MethodPost, "/api/token/mfa", params)
+	rr := httptest.NewRecorder()
+
+	mockService, _, mockValidator, authHandler := setupMockService(t)
+	mockValidator.EXPECT().Struct(params).Return(nil)
+	mockService.EXPECT().CompleteMFATokenSignIn(req.Context(), params).Return(&model.TokenPair{
+		AccessToken: "access", TokenType: service.TokenTypeBearer, RefreshToken: "refresh",
+	}, nil)
+
+	authHandler.HandleMFATokenSignIn(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+}
+
+func TestAuthHandler_HandleEnrollTOTP(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"enrollment should start", nil, http.StatusCreated},
+		{"wrong password should be rejected", service.ErrPasswordMismatch, http.StatusUnprocessableEntity},
+		{"enabled mfa should conflict", service.ErrMFAAlreadyEnabled, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &model.User{ID: testID, Email: testEmail}
+			params := model.CurrentPasswordParams{CurrentPassword: testPassword}
+			req := newJSONRequest(t, http.MethodPost, enrollTOTPURL, params)
+			req = req.WithContext(service.ContextWithUser(req.Context(), user))
+			rr := httptest.NewRecorder()
+
+			var enrollment *model.TOTPEnrollment
+			if tt.err == nil {
+				enrollment = &model.TOTPEnrollment{Secret: "SECRET", URI: "otpauth://totp/x", QRCode: []byte("png")}
+			}
+
+			mockService, _, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(params).Return(nil)
+			mockService.EXPECT().EnrollTOTP(req.Context(), user, params).Return(enrollment, tt.err)
+
+			authHandler.HandleEnrollTOTP(rr, req)
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func TestAuthHandler_HandleConfirmTOTP(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"mfa should be enabled", nil, http.StatusOK},
+		{"wrong code should be rejected", service.ErrInvalidMFACode, http.StatusUnprocessableEntity},
+		{"missing enrollment should conflict", service.ErrMFANotEnrolled, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &model.User{ID: testID, Email: testEmail}
+			params := model.TOTPCodeParams{Code: testMFACode}
+			req := newJSONRequest(t, http.MethodPost, confirmTOTPURL, params)
+			req = req.WithContext(service.ContextWithUser(req.Context(), user))
+			rr := httptest.NewRecorder()
+
+			var codes *model.RecoveryCodes
+			if tt.err == nil {
+				codes = &model.RecoveryCodes{Codes: []string{"abcde-fghij"}}
+			}
+
+			mockService, _, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(params).Return(nil)
+			mockService.EXPECT().ConfirmTOTP(req.Context(), user, params).Return(codes, tt.err)
+
+			authHandler.HandleConfirmTOTP(rr, req)
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func TestAuthHandler_HandleDisableTOTP(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"mfa should be disabled", nil, http.StatusOK},
+		{"wrong password should be rejected", service.ErrPasswordMismatch, http.StatusUnprocessableEntity},
+		{"wrong code should be rejected", service.ErrInvalidMFACode, http.StatusUnprocessableEntity},
+		{"disabled mfa should conflict", service.ErrMFANotEnrolled, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &model.User{ID: testID, Email: testEmail}
+			params := model.MFACodeParams{CurrentPassword: testPassword, Code: testMFACode}
+			req := newJSONRequest(t, http.MethodPost, disableTOTPURL, params)
+			req = req.WithContext(service.ContextWithUser(req.Context(), user))
+			rr := httptest.NewRecorder()
+
+			mockService, _, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(params).Return(nil)
+			mockService.EXPECT().DisableTOTP(req.Context(), user, params).Return(tt.err)
+
+			authHandler.HandleDisableTOTP(rr, req)
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func TestAuthHandler_HandleRegenerateRecoveryCodes(t *testing.T) {
+	user := &model.User{ID: testID, Email: testEmail}
+	params := model.MFACodeParams{CurrentPassword: testPassword, Code: testMFACode}
+	req := newJSONRequest(t, http.MethodPost, recoveryCodesURL, params)
+	req = req.WithContext(service.ContextWithUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	mockService, _, mockValidator, authHandler := setupMockService(t)
+	mockValidator.EXPECT().Struct(params).Return(nil)
+	mockService.EXPECT().RegenerateRecoveryCodes(req.Context(), user, params).
+		Return(&model.RecoveryCodes{Codes: []string{"abcde-fghij"}}, nil)
+
+	authHandler.HandleRegenerateRecoveryCodes(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+}
+
+func TestAuthHandler_MFA_Unauthenticated(t *testing.T) {
+	_, _, _, authHandler := setupMockService(t)
+	handlers := map[string]http.HandlerFunc{
+		enrollTOTPURL:    authHandler.HandleEnrollTOTP,
+		confirmTOTPURL:   authHandler.HandleConfirmTOTP,
+		disableTOTPURL:   authHandler.HandleDisableTOTP,
+		recoveryCodesURL: authHandler.HandleRegenerateRecoveryCodes,
+	}
+
+	for url, h := range handlers {
+		req := httptest.NewRequest(http.MethodPost, url, nil)
+		rr := httptest.NewRecorder()
+
+		h(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code, "Response status code should match for %s", url)
+	}
+}
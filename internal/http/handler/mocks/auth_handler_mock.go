@@ -0,0 +1,401 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/http/handler (interfaces: AuthHandler)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/auth_handler_mock.go -package=mocks . AuthHandler
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	http "net/http"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuthHandler is a mock of AuthHandler interface.
+type MockAuthHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthHandlerMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthHandlerMockRecorder is the mock recorder for MockAuthHandler.
+type MockAuthHandlerMockRecorder struct {
+	mock *MockAuthHandler
+}
+
+// NewMockAuthHandler creates a new mock instance.
+func NewMockAuthHandler(ctrl *gomock.Controller) *MockAuthHandler {
+	mock := &MockAuthHandler{ctrl: ctrl}
+	mock.recorder = &MockAuthHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthHandler) EXPECT() *MockAuthHandlerMockRecorder {
+	return m.recorder
+}
+
+// HandleChangeEmail mocks base method.
+func (m *MockAuthHandler) HandleChangeEmail(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleChangeEmail", w, r)
+}
+
+// HandleChangeEmail indicates an expected call of HandleChangeEmail.
+func (mr *MockAuthHandlerMockRecorder) HandleChangeEmail(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleChangeEmail", reflect.TypeOf((*MockAuthHandler)(nil).HandleChangeEmail), w, r)
+}
+
+// HandleChangePassword mocks base method.
+func (m *MockAuthHandler) HandleChangePassword(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleChangePassword", w, r)
+}
+
+// HandleChangePassword indicates an expected call of HandleChangePassword.
+func (mr *MockAuthHandlerMockRecorder) HandleChangePassword(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleChangePassword", reflect.TypeOf((*MockAuthHandler)(nil).HandleChangePassword), w, r)
+}
+
+// HandleConfirmTOTP mocks base method.
+func (m *MockAuthHandler) HandleConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleConfirmTOTP", w, r)
+}
+
+// HandleConfirmTOTP indicates an expected call of HandleConfirmTOTP.
+func (mr *MockAuthHandlerMockRecorder) HandleConfirmTOTP(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleConfirmTOTP", reflect.TypeOf((*MockAuthHandler)(nil).HandleConfirmTOTP), w, r)
+}
+
+// HandleCurrentUser mocks base method.
+func (m *MockAuthHandler) HandleCurrentUser(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleCurrentUser", w, r)
+}
+
+// HandleCurrentUser indicates an expected call of HandleCurrentUser.
+func (mr *MockAuthHandlerMockRecorder) HandleCurrentUser(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleCurrentUser", reflect.TypeOf((*MockAuthHandler)(nil).HandleCurrentUser), w, r)
+}
+
+// HandleDeletePasskey mocks base method.
+func (m *MockAuthHandler) HandleDeletePasskey(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleDeletePasskey", w, r)
+}
+
+// HandleDeletePasskey indicates an expected call of HandleDeletePasskey.
+func (mr *MockAuthHandlerMockRecorder) HandleDeletePasskey(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleDeletePasskey", reflect.TypeOf((*MockAuthHandler)(nil).HandleDeletePasskey), w, r)
+}
+
+// HandleDisableTOTP mocks base method.
+func (m *MockAuthHandler) HandleDisableTOTP(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleDisableTOTP", w, r)
+}
+
+// HandleDisableTOTP indicates an expected call of HandleDisableTOTP.
+func (mr *MockAuthHandlerMockRecorder) HandleDisableTOTP(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleDisableTOTP", reflect.TypeOf((*MockAuthHandler)(nil).HandleDisableTOTP), w, r)
+}
+
+// HandleEnrollTOTP mocks base method.
+func (m *MockAuthHandler) HandleEnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleEnrollTOTP", w, r)
+}
+
+// HandleEnrollTOTP indicates an expected call of HandleEnrollTOTP.
+func (mr *MockAuthHandlerMockRecorder) HandleEnrollTOTP(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleEnrollTOTP", reflect.TypeOf((*MockAuthHandler)(nil).HandleEnrollTOTP), w, r)
+}
+
+// HandleForgotPassword mocks base method.
+func (m *MockAuthHandler) HandleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleForgotPassword", w, r)
+}
+
+// HandleForgotPassword indicates an expected call of HandleForgotPassword.
+func (mr *MockAuthHandlerMockRecorder) HandleForgotPassword(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleForgotPassword", reflect.TypeOf((*MockAuthHandler)(nil).HandleForgotPassword), w, r)
+}
+
+// HandleListIdentities mocks base method.
+func (m *MockAuthHandler) HandleListIdentities(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleListIdentities", w, r)
+}
+
+// HandleListIdentities indicates an expected call of HandleListIdentities.
+func (mr *MockAuthHandlerMockRecorder) HandleListIdentities(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleListIdentities", reflect.TypeOf((*MockAuthHandler)(nil).HandleListIdentities), w, r)
+}
+
+// HandleListPasskeys mocks base method.
+func (m *MockAuthHandler) HandleListPasskeys(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleListPasskeys", w, r)
+}
+
+// HandleListPasskeys indicates an expected call of HandleListPasskeys.
+func (mr *MockAuthHandlerMockRecorder) HandleListPasskeys(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleListPasskeys", reflect.TypeOf((*MockAuthHandler)(nil).HandleListPasskeys), w, r)
+}
+
+// HandleMFASignIn mocks base method.
+func (m *MockAuthHandler) HandleMFASignIn(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleMFASignIn", w, r)
+}
+
+// HandleMFASignIn indicates an expected call of HandleMFASignIn.
+func (mr *MockAuthHandlerMockRecorder) HandleMFASignIn(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleMFASignIn", reflect.TypeOf((*MockAuthHandler)(nil).HandleMFASignIn), w, r)
+}
+
+// HandleMFATokenSignIn mocks base method.
+func (m *MockAuthHandler) HandleMFATokenSignIn(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleMFATokenSignIn", w, r)
+}
+
+// HandleMFATokenSignIn indicates an expected call of HandleMFATokenSignIn.
+func (mr *MockAuthHandlerMockRecorder) HandleMFATokenSignIn(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleMFATokenSignIn", reflect.TypeOf((*MockAuthHandler)(nil).HandleMFATokenSignIn), w, r)
+}
+
+// HandleMagicLinkSignIn mocks base method.
+func (m *MockAuthHandler) HandleMagicLinkSignIn(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleMagicLinkSignIn", w, r)
+}
+
+// HandleMagicLinkSignIn indicates an expected call of HandleMagicLinkSignIn.
+func (mr *MockAuthHandlerMockRecorder) HandleMagicLinkSignIn(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleMagicLinkSignIn", reflect.TypeOf((*MockAuthHandler)(nil).HandleMagicLinkSignIn), w, r)
+}
+
+// HandleOIDCCallback mocks base method.
+func (m *MockAuthHandler) HandleOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleOIDCCallback", w, r)
+}
+
+// HandleOIDCCallback indicates an expected call of HandleOIDCCallback.
+func (mr *MockAuthHandlerMockRecorder) HandleOIDCCallback(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleOIDCCallback", reflect.TypeOf((*MockAuthHandler)(nil).HandleOIDCCallback), w, r)
+}
+
+// HandleOIDCSignIn mocks base method.
+func (m *MockAuthHandler) HandleOIDCSignIn(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleOIDCSignIn", w, r)
+}
+
+// HandleOIDCSignIn indicates an expected call of HandleOIDCSignIn.
+func (mr *MockAuthHandlerMockRecorder) HandleOIDCSignIn(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleOIDCSignIn", reflect.TypeOf((*MockAuthHandler)(nil).HandleOIDCSignIn), w, r)
+}
+
+// HandlePasskeyRegistrationOptions mocks base method.
+func (m *MockAuthHandler) HandlePasskeyRegistrationOptions(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandlePasskeyRegistrationOptions", w, r)
+}
+
+// HandlePasskeyRegistrationOptions indicates an expected call of HandlePasskeyRegistrationOptions.
+func (mr *MockAuthHandlerMockRecorder) HandlePasskeyRegistrationOptions(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandlePasskeyRegistrationOptions", reflect.TypeOf((*MockAuthHandler)(nil).HandlePasskeyRegistrationOptions), w, r)
+}
+
+// HandlePasskeySignIn mocks base method.
+func (m *MockAuthHandler) HandlePasskeySignIn(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandlePasskeySignIn", w, r)
+}
+
+// HandlePasskeySignIn indicates an expected call of HandlePasskeySignIn.
+func (mr *MockAuthHandlerMockRecorder) HandlePasskeySignIn(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandlePasskeySignIn", reflect.TypeOf((*MockAuthHandler)(nil).HandlePasskeySignIn), w, r)
+}
+
+// HandlePasskeySignInOptions mocks base method.
+func (m *MockAuthHandler) HandlePasskeySignInOptions(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandlePasskeySignInOptions", w, r)
+}
+
+// HandlePasskeySignInOptions indicates an expected call of HandlePasskeySignInOptions.
+func (mr *MockAuthHandlerMockRecorder) HandlePasskeySignInOptions(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandlePasskeySignInOptions", reflect.TypeOf((*MockAuthHandler)(nil).HandlePasskeySignInOptions), w, r)
+}
+
+// HandleRegenerateRecoveryCodes mocks base method.
+func (m *MockAuthHandler) HandleRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleRegenerateRecoveryCodes", w, r)
+}
+
+// HandleRegenerateRecoveryCodes indicates an expected call of HandleRegenerateRecoveryCodes.
+func (mr *MockAuthHandlerMockRecorder) HandleRegenerateRecoveryCodes(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleRegenerateRecoveryCodes", reflect.TypeOf((*MockAuthHandler)(nil).HandleRegenerateRecoveryCodes), w, r)
+}
+
+// HandleRegisterPasskey mocks base method.
+func (m *MockAuthHandler) HandleRegisterPasskey(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleRegisterPasskey", w, r)
+}
+
+// HandleRegisterPasskey indicates an expected call of HandleRegisterPasskey.
+func (mr *MockAuthHandlerMockRecorder) HandleRegisterPasskey(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleRegisterPasskey", reflect.TypeOf((*MockAuthHandler)(nil).HandleRegisterPasskey), w, r)
+}
+
+// HandleRequestMagicLink mocks base method.
+func (m *MockAuthHandler) HandleRequestMagicLink(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleRequestMagicLink", w, r)
+}
+
+// HandleRequestMagicLink indicates an expected call of HandleRequestMagicLink.
+func (mr *MockAuthHandlerMockRecorder) HandleRequestMagicLink(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleRequestMagicLink", reflect.TypeOf((*MockAuthHandler)(nil).HandleRequestMagicLink), w, r)
+}
+
+// HandleResendVerification mocks base method.
+func (m *MockAuthHandler) HandleResendVerification(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleResendVerification", w, r)
+}
+
+// HandleResendVerification indicates an expected call of HandleResendVerification.
+func (mr *MockAuthHandlerMockRecorder) HandleResendVerification(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleResendVerification", reflect.TypeOf((*MockAuthHandler)(nil).HandleResendVerification), w, r)
+}
+
+// HandleResetPassword mocks base method.
+func (m *MockAuthHandler) HandleResetPassword(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleResetPassword", w, r)
+}
+
+// HandleResetPassword indicates an expected call of HandleResetPassword.
+func (mr *MockAuthHandlerMockRecorder) HandleResetPassword(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleResetPassword", reflect.TypeOf((*MockAuthHandler)(nil).HandleResetPassword), w, r)
+}
+
+// HandleTokenRefresh mocks base method.
+func (m *MockAuthHandler) HandleTokenRefresh(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleTokenRefresh", w, r)
+}
+
+// HandleTokenRefresh indicates an expected call of HandleTokenRefresh.
+func (mr *MockAuthHandlerMockRecorder) HandleTokenRefresh(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleTokenRefresh", reflect.TypeOf((*MockAuthHandler)(nil).HandleTokenRefresh), w, r)
+}
+
+// HandleTokenRevoke mocks base method.
+func (m *MockAuthHandler) HandleTokenRevoke(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleTokenRevoke", w, r)
+}
+
+// HandleTokenRevoke indicates an expected call of HandleTokenRevoke.
+func (mr *MockAuthHandlerMockRecorder) HandleTokenRevoke(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleTokenRevoke", reflect.TypeOf((*MockAuthHandler)(nil).HandleTokenRevoke), w, r)
+}
+
+// HandleTokenSignIn mocks base method.
+func (m *MockAuthHandler) HandleTokenSignIn(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleTokenSignIn", w, r)
+}
+
+// HandleTokenSignIn indicates an expected call of HandleTokenSignIn.
+func (mr *MockAuthHandlerMockRecorder) HandleTokenSignIn(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleTokenSignIn", reflect.TypeOf((*MockAuthHandler)(nil).HandleTokenSignIn), w, r)
+}
+
+// HandleUserSignIn mocks base method.
+func (m *MockAuthHandler) HandleUserSignIn(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleUserSignIn", w, r)
+}
+
+// HandleUserSignIn indicates an expected call of HandleUserSignIn.
+func (mr *MockAuthHandlerMockRecorder) HandleUserSignIn(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleUserSignIn", reflect.TypeOf((*MockAuthHandler)(nil).HandleUserSignIn), w, r)
+}
+
+// HandleUserSignOut mocks base method.
+func (m *MockAuthHandler) HandleUserSignOut(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleUserSignOut", w, r)
+}
+
+// HandleUserSignOut indicates an expected call of HandleUserSignOut.
+func (mr *MockAuthHandlerMockRecorder) HandleUserSignOut(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleUserSignOut", reflect.TypeOf((*MockAuthHandler)(nil).HandleUserSignOut), w, r)
+}
+
+// HandleUserSignUp mocks base method.
+func (m *MockAuthHandler) HandleUserSignUp(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleUserSignUp", w, r)
+}
+
+// HandleUserSignUp indicates an expected call of HandleUserSignUp.
+func (mr *MockAuthHandlerMockRecorder) HandleUserSignUp(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleUserSignUp", reflect.TypeOf((*MockAuthHandler)(nil).HandleUserSignUp), w, r)
+}
+
+// HandleVerifyEmail mocks base method.
+func (m *MockAuthHandler) HandleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleVerifyEmail", w, r)
+}
+
+// HandleVerifyEmail indicates an expected call of HandleVerifyEmail.
+func (mr *MockAuthHandlerMockRecorder) HandleVerifyEmail(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleVerifyEmail", reflect.TypeOf((*MockAuthHandler)(nil).HandleVerifyEmail), w, r)
+}
@@ -0,0 +1,137 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/http/handler (interfaces: OAuthHandler)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/oauth_handler_mock.go -package=mocks . OAuthHandler
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	http "net/http"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOAuthHandler is a mock of OAuthHandler interface.
+type MockOAuthHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthHandlerMockRecorder
+	isgomock struct{}
+}
+
+// MockOAuthHandlerMockRecorder is the mock recorder for MockOAuthHandler.
+type MockOAuthHandlerMockRecorder struct {
+	mock *MockOAuthHandler
+}
+
+// NewMockOAuthHandler creates a new mock instance.
+func NewMockOAuthHandler(ctrl *gomock.Controller) *MockOAuthHandler {
+	mock := &MockOAuthHandler{ctrl: ctrl}
+	mock.recorder = &MockOAuthHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthHandler) EXPECT() *MockOAuthHandlerMockRecorder {
+	return m.recorder
+}
+
+// HandleAuthorize mocks base method.
+func (m *MockOAuthHandler) HandleAuthorize(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleAuthorize", w, r)
+}
+
+// HandleAuthorize indicates an expected call of HandleAuthorize.
+func (mr *MockOAuthHandlerMockRecorder) HandleAuthorize(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleAuthorize", reflect.TypeOf((*MockOAuthHandler)(nil).HandleAuthorize), w, r)
+}
+
+// HandleDecide mocks base method.
+func (m *MockOAuthHandler) HandleDecide(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleDecide", w, r)
+}
+
+// HandleDecide indicates an expected call of HandleDecide.
+func (mr *MockOAuthHandlerMockRecorder) HandleDecide(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleDecide", reflect.TypeOf((*MockOAuthHandler)(nil).HandleDecide), w, r)
+}
+
+// HandleIntrospect mocks base method.
+func (m *MockOAuthHandler) HandleIntrospect(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleIntrospect", w, r)
+}
+
+// HandleIntrospect indicates an expected call of HandleIntrospect.
+func (mr *MockOAuthHandlerMockRecorder) HandleIntrospect(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleIntrospect", reflect.TypeOf((*MockOAuthHandler)(nil).HandleIntrospect), w, r)
+}
+
+// HandleJWKS mocks base method.
+func (m *MockOAuthHandler) HandleJWKS(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleJWKS", w, r)
+}
+
+// HandleJWKS indicates an expected call of HandleJWKS.
+func (mr *MockOAuthHandlerMockRecorder) HandleJWKS(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleJWKS", reflect.TypeOf((*MockOAuthHandler)(nil).HandleJWKS), w, r)
+}
+
+// HandleMetadata mocks base method.
+func (m *MockOAuthHandler) HandleMetadata(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleMetadata", w, r)
+}
+
+// HandleMetadata indicates an expected call of HandleMetadata.
+func (mr *MockOAuthHandlerMockRecorder) HandleMetadata(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleMetadata", reflect.TypeOf((*MockOAuthHandler)(nil).HandleMetadata), w, r)
+}
+
+// HandleRegisterClient mocks base method.
+func (m *MockOAuthHandler) HandleRegisterClient(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleRegisterClient", w, r)
+}
+
+// HandleRegisterClient indicates an expected call of HandleRegisterClient.
+func (mr *MockOAuthHandlerMockRecorder) HandleRegisterClient(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleRegisterClient", reflect.TypeOf((*MockOAuthHandler)(nil).HandleRegisterClient), w, r)
+}
+
+// HandleRevoke mocks base method.
+func (m *MockOAuthHandler) HandleRevoke(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleRevoke", w, r)
+}
+
+// HandleRevoke indicates an expected call of HandleRevoke.
+func (mr *MockOAuthHandlerMockRecorder) HandleRevoke(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleRevoke", reflect.TypeOf((*MockOAuthHandler)(nil).HandleRevoke), w, r)
+}
+
+// HandleToken mocks base method.
+func (m *MockOAuthHandler) HandleToken(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleToken", w, r)
+}
+
+// HandleToken indicates an expected call of HandleToken.
+func (mr *MockOAuthHandlerMockRecorder) HandleToken(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleToken", reflect.TypeOf((*MockOAuthHandler)(nil).HandleToken), w, r)
+}
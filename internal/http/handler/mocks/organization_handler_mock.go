@@ -0,0 +1,137 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/http/handler (interfaces: OrganizationHandler)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/organization_handler_mock.go -package=mocks . OrganizationHandler
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	http "net/http"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOrganizationHandler is a mock of OrganizationHandler interface.
+type MockOrganizationHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrganizationHandlerMockRecorder
+	isgomock struct{}
+}
+
+// MockOrganizationHandlerMockRecorder is the mock recorder for MockOrganizationHandler.
+type MockOrganizationHandlerMockRecorder struct {
+	mock *MockOrganizationHandler
+}
+
+// NewMockOrganizationHandler creates a new mock instance.
+func NewMockOrganizationHandler(ctrl *gomock.Controller) *MockOrganizationHandler {
+	mock := &MockOrganizationHandler{ctrl: ctrl}
+	mock.recorder = &MockOrganizationHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrganizationHandler) EXPECT() *MockOrganizationHandlerMockRecorder {
+	return m.recorder
+}
+
+// HandleAcceptInvitation mocks base method.
+func (m *MockOrganizationHandler) HandleAcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleAcceptInvitation", w, r)
+}
+
+// HandleAcceptInvitation indicates an expected call of HandleAcceptInvitation.
+func (mr *MockOrganizationHandlerMockRecorder) HandleAcceptInvitation(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleAcceptInvitation", reflect.TypeOf((*MockOrganizationHandler)(nil).HandleAcceptInvitation), w, r)
+}
+
+// HandleCreateOrganization mocks base method.
+func (m *MockOrganizationHandler) HandleCreateOrganization(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleCreateOrganization", w, r)
+}
+
+// HandleCreateOrganization indicates an expected call of HandleCreateOrganization.
+func (mr *MockOrganizationHandlerMockRecorder) HandleCreateOrganization(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleCreateOrganization", reflect.TypeOf((*MockOrganizationHandler)(nil).HandleCreateOrganization), w, r)
+}
+
+// HandleGetOrganization mocks base method.
+func (m *MockOrganizationHandler) HandleGetOrganization(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleGetOrganization", w, r)
+}
+
+// HandleGetOrganization indicates an expected call of HandleGetOrganization.
+func (mr *MockOrganizationHandlerMockRecorder) HandleGetOrganization(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleGetOrganization", reflect.TypeOf((*MockOrganizationHandler)(nil).HandleGetOrganization), w, r)
+}
+
+// HandleInvite mocks base method.
+func (m *MockOrganizationHandler) HandleInvite(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleInvite", w, r)
+}
+
+// HandleInvite indicates an expected call of HandleInvite.
+func (mr *MockOrganizationHandlerMockRecorder) HandleInvite(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleInvite", reflect.TypeOf((*MockOrganizationHandler)(nil).HandleInvite), w, r)
+}
+
+// HandleListMembers mocks base method.
+func (m *MockOrganizationHandler) HandleListMembers(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleListMembers", w, r)
+}
+
+// HandleListMembers indicates an expected call of HandleListMembers.
+func (mr *MockOrganizationHandlerMockRecorder) HandleListMembers(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleListMembers", reflect.TypeOf((*MockOrganizationHandler)(nil).HandleListMembers), w, r)
+}
+
+// HandleListOrganizations mocks base method.
+func (m *MockOrganizationHandler) HandleListOrganizations(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleListOrganizations", w, r)
+}
+
+// HandleListOrganizations indicates an expected call of HandleListOrganizations.
+func (mr *MockOrganizationHandlerMockRecorder) HandleListOrganizations(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleListOrganizations", reflect.TypeOf((*MockOrganizationHandler)(nil).HandleListOrganizations), w, r)
+}
+
+// HandleRemoveMember mocks base method.
+func (m *MockOrganizationHandler) HandleRemoveMember(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleRemoveMember", w, r)
+}
+
+// HandleRemoveMember indicates an expected call of HandleRemoveMember.
+func (mr *MockOrganizationHandlerMockRecorder) HandleRemoveMember(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleRemoveMember", reflect.TypeOf((*MockOrganizationHandler)(nil).HandleRemoveMember), w, r)
+}
+
+// HandleUpdateMember mocks base method.
+func (m *MockOrganizationHandler) HandleUpdateMember(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleUpdateMember", w, r)
+}
+
+// HandleUpdateMember indicates an expected call of HandleUpdateMember.
+func (mr *MockOrganizationHandlerMockRecorder) HandleUpdateMember(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleUpdateMember", reflect.TypeOf((*MockOrganizationHandler)(nil).HandleUpdateMember), w, r)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/http/handler (interfaces: RoleHandler)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/role_handler_mock.go -package=mocks . RoleHandler
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	http "net/http"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRoleHandler is a mock of RoleHandler interface.
+type MockRoleHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleHandlerMockRecorder
+	isgomock struct{}
+}
+
+// MockRoleHandlerMockRecorder is the mock recorder for MockRoleHandler.
+type MockRoleHandlerMockRecorder struct {
+	mock *MockRoleHandler
+}
+
+// NewMockRoleHandler creates a new mock instance.
+func NewMockRoleHandler(ctrl *gomock.Controller) *MockRoleHandler {
+	mock := &MockRoleHandler{ctrl: ctrl}
+	mock.recorder = &MockRoleHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleHandler) EXPECT() *MockRoleHandlerMockRecorder {
+	return m.recorder
+}
+
+// HandleAssignRole mocks base method.
+func (m *MockRoleHandler) HandleAssignRole(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleAssignRole", w, r)
+}
+
+// HandleAssignRole indicates an expected call of HandleAssignRole.
+func (mr *MockRoleHandlerMockRecorder) HandleAssignRole(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleAssignRole", reflect.TypeOf((*MockRoleHandler)(nil).HandleAssignRole), w, r)
+}
+
+// HandleCreateRole mocks base method.
+func (m *MockRoleHandler) HandleCreateRole(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleCreateRole", w, r)
+}
+
+// HandleCreateRole indicates an expected call of HandleCreateRole.
+func (mr *MockRoleHandlerMockRecorder) HandleCreateRole(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleCreateRole", reflect.TypeOf((*MockRoleHandler)(nil).HandleCreateRole), w, r)
+}
+
+// HandleListRoles mocks base method.
+func (m *MockRoleHandler) HandleListRoles(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleListRoles", w, r)
+}
+
+// HandleListRoles indicates an expected call of HandleListRoles.
+func (mr *MockRoleHandlerMockRecorder) HandleListRoles(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleListRoles", reflect.TypeOf((*MockRoleHandler)(nil).HandleListRoles), w, r)
+}
+
+// HandleListUserRoles mocks base method.
+func (m *MockRoleHandler) HandleListUserRoles(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleListUserRoles", w, r)
+}
+
+// HandleListUserRoles indicates an expected call of HandleListUserRoles.
+func (mr *MockRoleHandlerMockRecorder) HandleListUserRoles(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleListUserRoles", reflect.TypeOf((*MockRoleHandler)(nil).HandleListUserRoles), w, r)
+}
+
+// HandleUnassignRole mocks base method.
+func (m *MockRoleHandler) HandleUnassignRole(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleUnassignRole", w, r)
+}
+
+// HandleUnassignRole indicates an expected call of HandleUnassignRole.
+func (mr *MockRoleHandlerMockRecorder) HandleUnassignRole(w, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleUnassignRole", reflect.TypeOf((*MockRoleHandler)(nil).HandleUnassignRole), w, r)
+}
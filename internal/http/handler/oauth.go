@@ -0,0 +1,300 @@
+//go:generate mockgen -destination=mocks/oauth_handler_mock.go -package=mocks . OAuthHandler
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/validation"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+// OAuthHandler serves the authorization server. The endpoints called by
+// clients answer in the formats of the OAuth RFCs, while the consent
+// endpoints are part of the app's API.
+type OAuthHandler interface {
+	HandleMetadata(w http.ResponseWriter, r *http.Request)
+	HandleJWKS(w http.ResponseWriter, r *http.Request)
+	HandleRegisterClient(w http.ResponseWriter, r *http.Request)
+	HandleAuthorize(w http.ResponseWriter, r *http.Request)
+	HandleDecide(w http.ResponseWriter, r *http.Request)
+	HandleToken(w http.ResponseWriter, r *http.Request)
+	HandleIntrospect(w http.ResponseWriter, r *http.Request)
+	HandleRevoke(w http.ResponseWriter, r *http.Request)
+}
+
+type oauthHandler struct {
+	service           service.OAuthService
+	validator         validation.Validator
+	registrationToken string
+}
+
+var _ OAuthHandler = (*oauthHandler)(nil)
+
+// NewOAuthHandler returns the handler of the authorization server. Clients
+// register with registrationToken as a bearer token; registration is
+// disabled when it is empty.
+func NewOAuthHandler(oauthService service.OAuthService, validator validation.Validator,
+	registrationToken string) OAuthHandler {
+	return &oauthHandler{
+		service:           oauthService,
+		validator:         validator,
+		registrationToken: registrationToken,
+	}
+}
+
+func (h *oauthHandler) HandleMetadata(w http.ResponseWriter, _ *http.Request) {
+	meta := h.service.Metadata()
+	if h.registrationToken == "" {
+		meta.RegistrationEndpoint = ""
+	}
+
+	responseJSON(w, http.StatusOK, meta)
+}
+
+func (h *oauthHandler) HandleJWKS(w http.ResponseWriter, _ *http.Request) {
+	responseJSON(w, http.StatusOK, h.service.JWKS())
+}
+
+func (h *oauthHandler) HandleRegisterClient(w http.ResponseWriter, r *http.Request) {
+	if h.registrationToken == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.registrationToken)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		oauthResponseError(w, &service.OAuthError{Code: service.OAuthInvalidToken})
+		return
+	}
+
+	// Unknown client metadata must be ignored (RFC 7591 section 2), so the
+	// body is not decoded with DecodeJSON.
+	var params model.OAuthClientParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		oauthResponseError(w, &service.OAuthError{Code: service.OAuthInvalidClientMetadata, Description: err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		oauthResponseError(w, &service.OAuthError{Code: service.OAuthInvalidClientMetadata, Description: err.Error()})
+		return
+	}
+
+	reg, err := h.service.RegisterClient(r.Context(), params)
+	if err != nil {
+		oauthResponseError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	responseJSON(w, http.StatusCreated, reg)
+}
+
+// HandleAuthorize validates the authorization request the consent page was
+// opened with, and describes it for the user to approve.
+func (h *oauthHandler) HandleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	params := model.OAuthAuthorizeParams{
+		ResponseType:        query.Get("response_type"),
+		ClientID:            query.Get("client_id"),
+		RedirectURI:         query.Get("redirect_uri"),
+		Scope:               query.Get("scope"),
+		State:               query.Get("state"),
+		CodeChallenge:       query.Get("code_challenge"),
+		CodeChallengeMethod: query.Get("code_challenge_method"),
+	}
+
+	consent, err := h.service.Authorize(r.Context(), params)
+	if err != nil {
+		authorizationError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Authorization request is valid.",
+		Data:    consent,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+// HandleDecide records whether the signed-in user approved the request, and
+// returns where to send the user back to the client.
+func (h *oauthHandler) HandleDecide(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.UserFromContext(r.Context())
+	if !ok {
+		Unauthorized(w, "Authentication required.")
+		return
+	}
+
+	var params model.OAuthDecisionParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	redirect, err := h.service.Decide(r.Context(), user, params)
+	if err != nil {
+		authorizationError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Return to the application.",
+		Data:    redirect,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *oauthHandler) HandleToken(w http.ResponseWriter, r *http.Request) {
+	creds, err := clientCredentials(r)
+	if err != nil {
+		oauthResponseError(w, err)
+		return
+	}
+
+	params := model.OAuthTokenParams{
+		GrantType:    r.PostForm.Get("grant_type"),
+		Code:         r.PostForm.Get("code"),
+		RedirectURI:  r.PostForm.Get("redirect_uri"),
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+		Scope:        r.PostForm.Get("scope"),
+	}
+
+	res, err := h.service.Token(r.Context(), creds, params)
+	if err != nil {
+		oauthResponseError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *oauthHandler) HandleIntrospect(w http.ResponseWriter, r *http.Request) {
+	creds, err := clientCredentials(r)
+	if err != nil {
+		oauthResponseError(w, err)
+		return
+	}
+
+	token := r.PostForm.Get("token")
+	if token == "" {
+		oauthResponseError(w, &service.OAuthError{Code: service.OAuthInvalidRequest, Description: "token is required"})
+		return
+	}
+
+	res, err := h.service.Introspect(r.Context(), creds, token)
+	if err != nil {
+		oauthResponseError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *oauthHandler) HandleRevoke(w http.ResponseWriter, r *http.Request) {
+	creds, err := clientCredentials(r)
+	if err != nil {
+		oauthResponseError(w, err)
+		return
+	}
+
+	token := r.PostForm.Get("token")
+	if token == "" {
+		oauthResponseError(w, &service.OAuthError{Code: service.OAuthInvalidRequest, Description: "token is required"})
+		return
+	}
+
+	if err := h.service.Revoke(r.Context(), creds, token); err != nil {
+		oauthResponseError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// clientCredentials parses the form body of a client request and returns the
+// credentials of the client, sent with HTTP Basic authentication or in the
+// body (RFC 6749 section 2.3.1), but not both.
+func clientCredentials(r *http.Request) (model.OAuthClientCredentials, error) {
+	var creds model.OAuthClientCredentials
+
+	if err := r.ParseForm(); err != nil {
+		return creds, &service.OAuthError{Code: service.OAuthInvalidRequest, Description: "invalid form body"}
+	}
+
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		creds.ID = r.PostForm.Get("client_id")
+		creds.Secret = r.PostForm.Get("client_secret")
+		return creds, nil
+	}
+
+	if r.PostForm.Has("client_secret") {
+		return creds, &service.OAuthError{
+			Code:        service.OAuthInvalidRequest,
+			Description: "only one client authentication method may be used",
+		}
+	}
+
+	var err error
+	if creds.ID, err = url.QueryUnescape(id); err != nil {
+		return creds, &service.OAuthError{Code: service.OAuthInvalidClient}
+	}
+	if creds.Secret, err = url.QueryUnescape(secret); err != nil {
+		return creds, &service.OAuthError{Code: service.OAuthInvalidClient}
+	}
+
+	return creds, nil
+}
+
+// oauthResponseError responds to a client with an OAuth error, or with 500
+// for unexpected errors. Failed client authentication is 401, other errors
+// 400.
+func oauthResponseError(w http.ResponseWriter, err error) {
+	var oauthErr *service.OAuthError
+	if !errors.As(err, &oauthErr) {
+		serviceError(w, err)
+		return
+	}
+
+	status := http.StatusBadRequest
+	switch oauthErr.Code {
+	case service.OAuthInvalidClient:
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+		status = http.StatusUnauthorized
+	case service.OAuthInvalidToken:
+		status = http.StatusUnauthorized
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	responseJSON(w, status, oauthErr)
+}
+
+// authorizationError responds to the consent page with an invalid
+// authorization request. The error carries the redirect URI to send the user
+// back to the client with, unless the client could not be trusted with it.
+func authorizationError(w http.ResponseWriter, err error) {
+	var oauthErr *service.OAuthError
+	if !errors.As(err, &oauthErr) {
+		serviceError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Invalid authorization request.",
+		Data:    oauthErr,
+	}
+
+	responseJSON(w, http.StatusBadRequest, res)
+}
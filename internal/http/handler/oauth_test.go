@@ -0,0 +1,280 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/http/handler"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/ferdiebergado/fullstackgo/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	validationMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/validation/mocks"
+)
+
+const (
+	testRegistrationToken = "registration-token"
+	testOAuthClientID     = "client id"
+	testOAuthSecret       = "secret"
+)
+
+func newFormRequest(path string, form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestOAuthHandler_HandleToken_BasicAuth(t *testing.T) {
+	req := newFormRequest("/oauth/token", url.Values{
+		"grant_type":    {service.GrantAuthorizationCode},
+		"code":          {"code"},
+		"redirect_uri":  {"https://client.example.com/callback"},
+		"code_verifier": {"verifier"},
+	})
+	req.SetBasicAuth(url.QueryEscape(testOAuthClientID), url.QueryEscape(testOAuthSecret))
+	rr := httptest.NewRecorder()
+
+	mockService, _, oauthHandler := setupMockOAuthService(t)
+	mockService.EXPECT().Token(req.Context(),
+		model.OAuthClientCredentials{ID: testOAuthClientID, Secret: testOAuthSecret},
+		model.OAuthTokenParams{
+			GrantType:    service.GrantAuthorizationCode,
+			Code:         "code",
+			RedirectURI:  "https://client.example.com/callback",
+			CodeVerifier: "verifier",
+		}).Return(&model.OAuthTokenResponse{AccessToken: testToken, TokenType: "Bearer", ExpiresIn: 3600}, nil)
+
+	oauthHandler.HandleToken(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"), "tokens should not be cached")
+	assert.Contains(t, rr.Body.String(), `"access_token":"`+testToken+`"`, "access token should be returned")
+}
+
+func TestOAuthHandler_HandleToken_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"failed client authentication should be unauthorized",
+			&service.OAuthError{Code: service.OAuthInvalidClient}, http.StatusUnauthorized, service.OAuthInvalidClient},
+		{"invalid grant should be a bad request",
+			&service.OAuthError{Code: service.OAuthInvalidGrant}, http.StatusBadRequest, service.OAuthInvalidGrant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newFormRequest("/oauth/token", url.Values{
+				"grant_type": {service.GrantClientCredentials},
+				"client_id":  {testOAuthClientID},
+			})
+			rr := httptest.NewRecorder()
+
+			mockService, _, oauthHandler := setupMockOAuthService(t)
+			mockService.EXPECT().Token(req.Context(), model.OAuthClientCredentials{ID: testOAuthClientID}, gomock.Any()).
+				Return(nil, tt.err)
+
+			oauthHandler.HandleToken(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+			assert.Contains(t, rr.Body.String(), `"error":"`+tt.code+`"`, "error code should match")
+		})
+	}
+}
+
+func TestOAuthHandler_HandleToken_TwoAuthMethods(t *testing.T) {
+	req := newFormRequest("/oauth/token", url.Values{
+		"grant_type":    {service.GrantClientCredentials},
+		"client_secret": {testOAuthSecret},
+	})
+	req.SetBasicAuth(testOAuthClientID, testOAuthSecret)
+	rr := httptest.NewRecorder()
+
+	mockService, _, oauthHandler := setupMockOAuthService(t)
+	mockService.EXPECT().Token(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	oauthHandler.HandleToken(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code, "Response status code should match")
+	assert.Contains(t, rr.Body.String(), `"error":"invalid_request"`, "error code should match")
+}
+
+func TestOAuthHandler_HandleIntrospect(t *testing.T) {
+	req := newFormRequest("/oauth/introspect", url.Values{"token": {testToken}})
+	req.SetBasicAuth(testOAuthClientID, testOAuthSecret)
+	rr := httptest.NewRecorder()
+
+	mockService, _, oauthHandler := setupMockOAuthService(t)
+	mockService.EXPECT().Introspect(req.Context(), gomock.Any(), testToken).
+		Return(&model.OAuthIntrospection{Active: false}, nil)
+
+	oauthHandler.HandleIntrospect(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+	assert.JSONEq(t, `{"active":false}`, rr.Body.String(), "inactive tokens should only be reported inactive")
+}
+
+func TestOAuthHandler_HandleRevoke_MissingToken(t *testing.T) {
+	req := newFormRequest("/oauth/revoke", url.Values{"client_id": {testOAuthClientID}})
+	rr := httptest.NewRecorder()
+
+	mockService, _, oauthHandler := setupMockOAuthService(t)
+	mockService.EXPECT().Revoke(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	oauthHandler.HandleRevoke(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code, "Response status code should match")
+}
+
+func TestOAuthHandler_HandleRegisterClient(t *testing.T) {
+	body := `{"client_name":"Example","redirect_uris":["https://client.example.com/callback"],` +
+		`"logo_uri":"https://client.example.com/logo.png"}`
+	params := model.OAuthClientParams{
+		Name:         "Example",
+		RedirectURIs: []string{"https://client.example.com/callback"},
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		status int
+	}{
+		{"registration should be disabled without a token", "", "Bearer " + testRegistrationToken, http.StatusNotFound},
+		{"wrong token should be unauthorized", testRegistrationToken, "Bearer other", http.StatusUnauthorized},
+		{"missing token should be unauthorized", testRegistrationToken, "", http.StatusUnauthorized},
+		{"client should be registered, ignoring unknown metadata", testRegistrationToken,
+			"Bearer " + testRegistrationToken, http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/oauth/register", strings.NewReader(body))
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+
+			ctrl := gomock.NewController(t)
+			mockService := mocks.NewMockOAuthService(ctrl)
+			mockValidator := validationMocks.NewMockValidator(ctrl)
+			oauthHandler := handler.NewOAuthHandler(mockService, mockValidator, tt.token)
+
+			if tt.status == http.StatusCreated {
+				mockValidator.EXPECT().Struct(params).Return(nil)
+				mockService.EXPECT().RegisterClient(req.Context(), params).
+					Return(&model.OAuthClientRegistration{ClientID: "id", Name: "Example"}, nil)
+			}
+
+			oauthHandler.HandleRegisterClient(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func TestOAuthHandler_HandleMetadata_RegistrationDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockOAuthService(ctrl)
+	oauthHandler := handler.NewOAuthHandler(mockService, validationMocks.NewMockValidator(ctrl), "")
+	rr := httptest.NewRecorder()
+
+	mockService.EXPECT().Metadata().Return(model.OAuthMetadata{
+		Issuer:               "https://auth.example.com",
+		RegistrationEndpoint: "https://auth.example.com/oauth/register",
+	})
+
+	oauthHandler.HandleMetadata(rr, httptest.NewRequest(http.MethodGet, "/.well-known/oauth-authorization-server", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+	assert.NotContains(t, rr.Body.String(), "registration_endpoint", "disabled registration should not be advertised")
+}
+
+func TestOAuthHandler_HandleAuthorize(t *testing.T) {
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {testOAuthClientID},
+		"redirect_uri":          {"https://client.example.com/callback"},
+		"state":                 {"xyz"},
+		"code_challenge":        {"challenge"},
+		"code_challenge_method": {"S256"},
+	}
+	params := model.OAuthAuthorizeParams{
+		ResponseType:        "code",
+		ClientID:            testOAuthClientID,
+		RedirectURI:         "https://client.example.com/callback",
+		State:               "xyz",
+		CodeChallenge:       "challenge",
+		CodeChallengeMethod: "S256",
+	}
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		body   string
+	}{
+		{"valid request should be described", nil, http.StatusOK, `"client_name":"Example"`},
+		{"invalid request should return the redirect to the client",
+			&service.OAuthError{Code: service.OAuthInvalidScope, RedirectURI: "https://client.example.com/callback?error=x"},
+			http.StatusBadRequest, `"redirect_uri":"https://client.example.com/callback?error=x"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/oauth/authorize?"+query.Encode(), nil)
+			rr := httptest.NewRecorder()
+
+			mockService, _, oauthHandler := setupMockOAuthService(t)
+			var consent *model.OAuthConsent
+			if tt.err == nil {
+				consent = &model.OAuthConsent{ClientID: testOAuthClientID, ClientName: "Example"}
+			}
+			mockService.EXPECT().Authorize(req.Context(), params).Return(consent, tt.err)
+
+			oauthHandler.HandleAuthorize(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+			assert.Contains(t, rr.Body.String(), tt.body, "response body should match")
+		})
+	}
+}
+
+func TestOAuthHandler_HandleDecide(t *testing.T) {
+	params := model.OAuthDecisionParams{
+		OAuthAuthorizeParams: model.OAuthAuthorizeParams{ClientID: testOAuthClientID},
+		Approve:              true,
+	}
+	user := &model.User{ID: testID}
+	req := newJSONRequest(t, http.MethodPost, "/api/oauth/authorize", params)
+	req = req.WithContext(service.ContextWithUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	mockService, _, oauthHandler := setupMockOAuthService(t)
+	mockService.EXPECT().Decide(req.Context(), user, params).
+		Return(&model.OAuthRedirect{RedirectURI: "https://client.example.com/callback?code=c"}, nil)
+
+	oauthHandler.HandleDecide(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+	assert.Contains(t, rr.Body.String(), `"redirect_uri":"https://client.example.com/callback?code=c"`,
+		"redirect should be returned")
+}
+
+func setupMockOAuthService(t *testing.T) (*mocks.MockOAuthService, *validationMocks.MockValidator,
+	handler.OAuthHandler) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockOAuthService(ctrl)
+	mockValidator := validationMocks.NewMockValidator(ctrl)
+	oauthHandler := handler.NewOAuthHandler(mockService, mockValidator, testRegistrationToken)
+
+	return mockService, mockValidator, oauthHandler
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+const (
+	// oidcStateCookieName ties the provider's callback to the browser that
+	// started the sign-in, so that nobody can sign a victim in to their own
+	// account.
+	oidcStateCookieName = "oidc_state"
+	oidcStateCookiePath = "/api/signin/oidc"
+)
+
+func (h *authHandler) HandleOIDCSignIn(w http.ResponseWriter, r *http.Request) {
+	auth, err := h.service.BeginOIDCSignIn(r.Context(), r.PathValue("provider"))
+	if err != nil {
+		oidcError(w, err)
+		return
+	}
+
+	h.setOIDCState(w, auth.State)
+
+	res := APIResponse{
+		Message: "Continue at the identity provider.",
+		Data:    auth,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *authHandler) HandleOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	var params model.OIDCCallbackParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	cookie, err := r.Cookie(oidcStateCookieName)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(params.State)) != 1 {
+		Unauthorized(w, "Sign-in expired. Sign in again.")
+		return
+	}
+
+	h.setOIDCState(w, "")
+
+	userID, err := h.service.FinishOIDCSignIn(r.Context(), r.PathValue("provider"), params)
+	if err != nil {
+		if mfaRequired(w, err) {
+			return
+		}
+
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			emailNotVerified(w)
+			return
+		}
+
+		oidcError(w, err)
+		return
+	}
+
+	token, session, err := h.sessions.CreateSession(r.Context(), userID)
+	if err != nil {
+		serverError(w)
+		return
+	}
+
+	h.cookie.set(w, token, session.ExpiresAt)
+
+	res := APIResponse{
+		Message: "Signin successful.",
+		Data:    session,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *authHandler) HandleListIdentities(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.UserFromContext(r.Context())
+	if !ok {
+		Unauthorized(w, "Authentication required.")
+		return
+	}
+
+	identities, err := h.service.ListIdentities(r.Context(), user)
+	if err != nil {
+		serviceError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Linked accounts retrieved.",
+		Data:    identities,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+// setOIDCState sets the state cookie, or clears it when state is empty. It
+// lasts for the browser session, as the state itself expires on the server.
+func (h *authHandler) setOIDCState(w http.ResponseWriter, state string) {
+	maxAge := 0
+	if state == "" {
+		maxAge = -1
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookieName,
+		Value:    state,
+		Path:     oidcStateCookiePath,
+		MaxAge:   maxAge,
+		Secure:   h.cookie.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func oidcError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrUnknownProvider) || errors.Is(err, service.ErrOIDCDisabled):
+		responseJSON(w, http.StatusNotFound, APIResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrInvalidOIDCSignIn):
+		Unauthorized(w, "Sign-in with the identity provider failed. Try again.")
+	case errors.Is(err, service.ErrProviderEmailNotVerified):
+		responseJSON(w, http.StatusForbidden, APIResponse{
+			Message: "Verify your email with the identity provider, then try again.",
+		})
+	case errors.Is(err, service.ErrIdentityNotLinkable):
+		responseJSON(w, http.StatusConflict, APIResponse{
+			Message: "An account with this email exists. Sign in with its password and verify its email to link it.",
+		})
+	case errors.Is(err, service.ErrProviderUnavailable):
+		responseJSON(w, http.StatusBadGateway, APIResponse{
+			Message: "The identity provider is unavailable. Try again later.",
+		})
+	default:
+		serviceError(w, err)
+	}
+}
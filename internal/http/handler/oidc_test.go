@@ -0,0 +1,159 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	oidcSignInURL   = "/api/signin/oidc/google"
+	oidcCallbackURL = "/api/signin/oidc/google/callback"
+	testOIDCState   = "state"
+	oidcStateCookie = "oidc_state"
+)
+
+var testOIDCCallback = model.OIDCCallbackParams{State: testOIDCState, Code: "code"}
+
+func newOIDCCallbackRequest(t *testing.T, state string) *http.Request {
+	t.Helper()
+	req := newJSONRequest(t, http.MethodPost, oidcCallbackURL, testOIDCCallback)
+	req.SetPathValue("provider", "google")
+	if state != "" {
+		req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: state})
+	}
+	return req
+}
+
+func TestAuthHandler_HandleOIDCSignIn_Success(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, oidcSignInURL, nil)
+	req.SetPathValue("provider", "google")
+	rr := httptest.NewRecorder()
+
+	mockService, _, _, authHandler := setupMockService(t)
+	mockService.EXPECT().BeginOIDCSignIn(req.Context(), "google").Return(&model.OIDCAuthorization{
+		URL:   "https://idp.example.com/authorize?state=" + testOIDCState,
+		State: testOIDCState,
+	}, nil)
+
+	authHandler.HandleOIDCSignIn(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+	assert.Contains(t, rr.Body.String(), `"authorization_url":"https://idp.example.com/authorize`,
+		"authorization URL should be returned")
+
+	cookie := findCookie(rr.Result().Cookies(), oidcStateCookie)
+	if assert.NotNil(t, cookie, "a state cookie should be set") {
+		assert.Equal(t, testOIDCState, cookie.Value, "state should match")
+		assert.True(t, cookie.HttpOnly, "state cookie should be HttpOnly")
+	}
+}
+
+func TestAuthHandler_HandleOIDCSignIn_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"unknown provider should not be found", service.ErrUnknownProvider, http.StatusNotFound},
+		{"unreachable provider should be a bad gateway",
+			fmt.Errorf("%w: timeout", service.ErrProviderUnavailable), http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, oidcSignInURL, nil)
+			rr := httptest.NewRecorder()
+
+			mockService, _, _, authHandler := setupMockService(t)
+			mockService.EXPECT().BeginOIDCSignIn(req.Context(), gomock.Any()).Return(nil, tt.err)
+
+			authHandler.HandleOIDCSignIn(rr, req)
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+			assert.Nil(t, findCookie(rr.Result().Cookies(), oidcStateCookie), "no state cookie should be set")
+		})
+	}
+}
+
+func TestAuthHandler_HandleOIDCCallback_Success(t *testing.T) {
+	req := newOIDCCallbackRequest(t, testOIDCState)
+	rr := httptest.NewRecorder()
+
+	mockService, mockSessions, mockValidator, authHandler := setupMockService(t)
+	mockValidator.EXPECT().Struct(testOIDCCallback).Return(nil)
+	mockService.EXPECT().FinishOIDCSignIn(req.Context(), "google", testOIDCCallback).Return(testID, nil)
+	mockSessions.EXPECT().CreateSession(req.Context(), testID).Return(testToken, &model.Session{
+		UserID:    testID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+
+	authHandler.HandleOIDCCallback(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+
+	cookies := rr.Result().Cookies()
+	if cookie := findCookie(cookies, testCookieName); assert.NotNil(t, cookie, "a session cookie should be set") {
+		assert.Equal(t, testToken, cookie.Value, "session token should match")
+	}
+	if cookie := findCookie(cookies, oidcStateCookie); assert.NotNil(t, cookie, "the state cookie should be cleared") {
+		assert.Negative(t, cookie.MaxAge, "state cookie should expire")
+	}
+}
+
+func TestAuthHandler_HandleOIDCCallback_StateMismatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		state string
+	}{
+		{"missing state cookie", ""},
+		{"state of another browser", "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" should be rejected", func(t *testing.T) {
+			req := newOIDCCallbackRequest(t, tt.state)
+			rr := httptest.NewRecorder()
+
+			mockService, _, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(gomock.Any()).Return(nil)
+			mockService.EXPECT().FinishOIDCSignIn(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			authHandler.HandleOIDCCallback(rr, req)
+			assert.Equal(t, http.StatusUnauthorized, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func TestAuthHandler_HandleOIDCCallback_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"failed sign-in should be unauthorized", service.ErrInvalidOIDCSignIn, http.StatusUnauthorized},
+		{"unverified provider email should be forbidden", service.ErrProviderEmailNotVerified, http.StatusForbidden},
+		{"unlinkable account should conflict", service.ErrIdentityNotLinkable, http.StatusConflict},
+		{"unverified email should be forbidden", service.ErrEmailNotVerified, http.StatusForbidden},
+		{"second factor should be required", &service.MFARequiredError{}, http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newOIDCCallbackRequest(t, testOIDCState)
+			rr := httptest.NewRecorder()
+
+			mockService, mockSessions, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(gomock.Any()).Return(nil)
+			mockService.EXPECT().FinishOIDCSignIn(req.Context(), "google", testOIDCCallback).Return("", tt.err)
+			mockSessions.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+			authHandler.HandleOIDCCallback(rr, req)
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
@@ -0,0 +1,260 @@
+//go:generate mockgen -destination=mocks/organization_handler_mock.go -package=mocks . OrganizationHandler
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/validation"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+// OrganizationHandler serves the organizations API. The router scopes the
+// routes under an organization to it with middleware.RequireOrganization and
+// guards them with permissions on its resources.
+type OrganizationHandler interface {
+	HandleCreateOrganization(w http.ResponseWriter, r *http.Request)
+	HandleListOrganizations(w http.ResponseWriter, r *http.Request)
+	HandleGetOrganization(w http.ResponseWriter, r *http.Request)
+	HandleListMembers(w http.ResponseWriter, r *http.Request)
+	HandleUpdateMember(w http.ResponseWriter, r *http.Request)
+	HandleRemoveMember(w http.ResponseWriter, r *http.Request)
+	HandleInvite(w http.ResponseWriter, r *http.Request)
+	HandleAcceptInvitation(w http.ResponseWriter, r *http.Request)
+}
+
+type organizationHandler struct {
+	orgs        service.OrganizationService
+	memberships service.MembershipService
+	validator   validation.Validator
+}
+
+var _ OrganizationHandler = (*organizationHandler)(nil)
+
+func NewOrganizationHandler(orgs service.OrganizationService, memberships service.MembershipService,
+	validator validation.Validator) OrganizationHandler {
+	return &organizationHandler{
+		orgs:        orgs,
+		memberships: memberships,
+		validator:   validator,
+	}
+}
+
+func (h *organizationHandler) HandleCreateOrganization(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.UserFromContext(r.Context())
+	if !ok {
+		Unauthorized(w, "Authentication required.")
+		return
+	}
+
+	var params model.OrganizationParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	org, err := h.orgs.CreateOrganization(r.Context(), user, params)
+	if err != nil {
+		serviceError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Organization created.",
+		Data:    org,
+	}
+
+	responseJSON(w, http.StatusCreated, res)
+}
+
+func (h *organizationHandler) HandleListOrganizations(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.UserFromContext(r.Context())
+	if !ok {
+		Unauthorized(w, "Authentication required.")
+		return
+	}
+
+	orgs, err := h.orgs.ListOrganizations(r.Context(), user)
+	if err != nil {
+		serviceError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Organizations retrieved.",
+		Data:    orgs,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *organizationHandler) HandleGetOrganization(w http.ResponseWriter, r *http.Request) {
+	org, ok := service.OrganizationFromContext(r.Context())
+	if !ok {
+		serviceError(w, service.ErrNoOrganization)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Organization retrieved.",
+		Data:    org,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *organizationHandler) HandleListMembers(w http.ResponseWriter, r *http.Request) {
+	members, err := h.memberships.ListMembers(r.Context())
+	if err != nil {
+		serviceError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Members retrieved.",
+		Data:    members,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *organizationHandler) HandleUpdateMember(w http.ResponseWriter, r *http.Request) {
+	var params model.MembershipParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	member, err := h.memberships.UpdateMemberRole(r.Context(), r.PathValue("id"), params)
+	if err != nil {
+		membershipError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Member updated.",
+		Data:    member,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *organizationHandler) HandleRemoveMember(w http.ResponseWriter, r *http.Request) {
+	if err := h.memberships.RemoveMember(r.Context(), r.PathValue("id")); err != nil {
+		membershipError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *organizationHandler) HandleInvite(w http.ResponseWriter, r *http.Request) {
+	var params model.InvitationParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	inv, err := h.memberships.Invite(r.Context(), params)
+	if err != nil {
+		membershipError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Invitation sent.",
+		Data:    inv,
+	}
+
+	responseJSON(w, http.StatusCreated, res)
+}
+
+func (h *organizationHandler) HandleAcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	var params model.AcceptInvitationParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	member, err := h.memberships.AcceptInvitation(r.Context(), params)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidToken):
+			responseJSON(w, http.StatusBadRequest, APIResponse{Message: "Invalid or expired invitation link."})
+		case errors.Is(err, service.ErrPasswordRequired):
+			responseJSON(w, http.StatusUnprocessableEntity, APIResponse{
+				Message: "Invalid input!",
+				Errors: []map[string]string{
+					{"password": "choose a password to create your account"},
+				},
+			})
+		case errors.Is(err, service.ErrPasswordBreached):
+			passwordBreached(w)
+		case errors.Is(err, service.ErrIdentityNotLinkable):
+			responseJSON(w, http.StatusConflict, APIResponse{
+				Message: "An account with this email exists. Verify its email, then accept the invitation again.",
+			})
+		case errors.Is(err, service.ErrEmailTaken):
+			responseJSON(w, http.StatusConflict, APIResponse{
+				Message: "An account with this email was just created. Accept the invitation again.",
+			})
+		default:
+			serviceError(w, err)
+		}
+		return
+	}
+
+	res := APIResponse{
+		Message: "Invitation accepted.",
+		Data:    member,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func membershipError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrMemberNotFound):
+		responseJSON(w, http.StatusNotFound, APIResponse{Message: "Member not found."})
+	case errors.Is(err, service.ErrRoleNotFound), errors.Is(err, service.ErrRoleScope):
+		responseJSON(w, http.StatusUnprocessableEntity, APIResponse{
+			Message: "Invalid input!",
+			Errors: []map[string]string{
+				{"role": err.Error()},
+			},
+		})
+	case errors.Is(err, service.ErrRoleNotGrantable):
+		Forbidden(w, "You cannot give or take away a role with permissions you do not have.")
+	case errors.Is(err, service.ErrAlreadyMember):
+		responseJSON(w, http.StatusConflict, APIResponse{Message: "This user is already a member."})
+	case errors.Is(err, service.ErrLastOwner):
+		responseJSON(w, http.StatusConflict, APIResponse{Message: "The organization must keep an owner."})
+	default:
+		serviceError(w, err)
+	}
+}
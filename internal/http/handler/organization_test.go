@@ -0,0 +1,176 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/http/handler"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/ferdiebergado/fullstackgo/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	validationMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/validation/mocks"
+)
+
+const testOrgID = "6f1c2b9e-4d5a-4e7b-9c3d-2a1b0c9d8e7f"
+
+type organizationMocks struct {
+	orgs        *mocks.MockOrganizationService
+	memberships *mocks.MockMembershipService
+	validator   *validationMocks.MockValidator
+}
+
+func TestOrganizationHandler_HandleCreateOrganization(t *testing.T) {
+	params := model.OrganizationParams{Name: "Acme"}
+	user := &model.User{ID: testID, Email: testEmail}
+	req := newJSONRequest(t, http.MethodPost, "/api/orgs", params)
+	req = req.WithContext(service.ContextWithUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	m, orgHandler := setupMockOrganizationService(t)
+	m.validator.EXPECT().Struct(params).Return(nil)
+	m.orgs.EXPECT().CreateOrganization(req.Context(), user, params).
+		Return(&model.Organization{ID: testOrgID, Name: "Acme"}, nil)
+
+	orgHandler.HandleCreateOrganization(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code, "Response status code should match")
+	assert.Contains(t, rr.Body.String(), testOrgID, "the organization should be returned")
+}
+
+func TestOrganizationHandler_HandleGetOrganization(t *testing.T) {
+	org := &model.Organization{ID: testOrgID, Name: "Acme"}
+	req := httptest.NewRequest(http.MethodGet, "/api/orgs/"+testOrgID, nil)
+	req = req.WithContext(service.ContextWithOrganization(req.Context(), org))
+	rr := httptest.NewRecorder()
+
+	_, orgHandler := setupMockOrganizationService(t)
+
+	orgHandler.HandleGetOrganization(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+	assert.Contains(t, rr.Body.String(), `"name":"Acme"`, "the scoped organization should be returned")
+}
+
+func TestOrganizationHandler_HandleUpdateMember(t *testing.T) {
+	params := model.MembershipParams{Role: service.RoleMember}
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		body   string
+	}{
+		{"member should be updated", nil, http.StatusOK, `"role":"member"`},
+		{"unknown member should not be found", service.ErrMemberNotFound, http.StatusNotFound, "Member not found."},
+		{"unknown role should be invalid", service.ErrRoleNotFound, http.StatusUnprocessableEntity, `"role"`},
+		{"global role should be invalid", service.ErrRoleScope, http.StatusUnprocessableEntity, `"role"`},
+		{"role beyond the user's permissions should be forbidden", service.ErrRoleNotGrantable, http.StatusForbidden,
+			"permissions you do not have"},
+		{"last owner should conflict", service.ErrLastOwner, http.StatusConflict, "must keep an owner"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newJSONRequest(t, http.MethodPut, "/api/orgs/"+testOrgID+"/members/"+testID, params)
+			req.SetPathValue("id", testID)
+			rr := httptest.NewRecorder()
+
+			m, orgHandler := setupMockOrganizationService(t)
+			m.validator.EXPECT().Struct(params).Return(nil)
+			var member *model.Membership
+			if tt.err == nil {
+				member = &model.Membership{OrganizationID: testOrgID, UserID: testID, Role: service.RoleMember}
+			}
+			m.memberships.EXPECT().UpdateMemberRole(req.Context(), testID, params).Return(member, tt.err)
+
+			orgHandler.HandleUpdateMember(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+			assert.Contains(t, rr.Body.String(), tt.body, "response body should match")
+		})
+	}
+}
+
+func TestOrganizationHandler_HandleInvite(t *testing.T) {
+	params := model.InvitationParams{Email: testEmail, Role: service.RoleMember}
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"invitation should be sent", nil, http.StatusCreated},
+		{"existing member should conflict", service.ErrAlreadyMember, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newJSONRequest(t, http.MethodPost, "/api/orgs/"+testOrgID+"/invitations", params)
+			rr := httptest.NewRecorder()
+
+			m, orgHandler := setupMockOrganizationService(t)
+			m.validator.EXPECT().Struct(params).Return(nil)
+			var inv *model.Invitation
+			if tt.err == nil {
+				inv = &model.Invitation{ID: "hash", OrganizationID: testOrgID, Email: testEmail, Role: service.RoleMember}
+			}
+			m.memberships.EXPECT().Invite(req.Context(), params).Return(inv, tt.err)
+
+			orgHandler.HandleInvite(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+			assert.NotContains(t, rr.Body.String(), "hash", "the token hash should not be returned")
+		})
+	}
+}
+
+func TestOrganizationHandler_HandleAcceptInvitation(t *testing.T) {
+	params := model.AcceptInvitationParams{Token: testToken}
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"invitation should be accepted", nil, http.StatusOK},
+		{"invalid invitation should be rejected", service.ErrInvalidToken, http.StatusBadRequest},
+		{"new user without a password should be invalid", service.ErrPasswordRequired,
+			http.StatusUnprocessableEntity},
+		{"unverified account should conflict", service.ErrIdentityNotLinkable, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newJSONRequest(t, http.MethodPost, "/api/invitations/accept", params)
+			rr := httptest.NewRecorder()
+
+			m, orgHandler := setupMockOrganizationService(t)
+			m.validator.EXPECT().Struct(params).Return(nil)
+			var member *model.Membership
+			if tt.err == nil {
+				member = &model.Membership{OrganizationID: testOrgID, UserID: testID, Role: service.RoleMember}
+			}
+			m.memberships.EXPECT().AcceptInvitation(req.Context(), params).Return(member, tt.err)
+
+			orgHandler.HandleAcceptInvitation(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func setupMockOrganizationService(t *testing.T) (*organizationMocks, handler.OrganizationHandler) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := &organizationMocks{
+		orgs:        mocks.NewMockOrganizationService(ctrl),
+		memberships: mocks.NewMockMembershipService(ctrl),
+		validator:   validationMocks.NewMockValidator(ctrl),
+	}
+
+	return m, handler.NewOrganizationHandler(m.orgs, m.memberships, m.validator)
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+func (h *authHandler) HandlePasskeySignInOptions(w http.ResponseWriter, r *http.Request) {
+	opts, err := h.service.BeginPasskeySignIn(r.Context())
+	if err != nil {
+		serviceError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Sign in with a passkey.",
+		Data:    opts,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *authHandler) HandlePasskeySignIn(w http.ResponseWriter, r *http.Request) {
+	var params model.PasskeySignInParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	userID, err := h.service.FinishPasskeySignIn(r.Context(), params)
+	if err != nil {
+		if mfaRequired(w, err) {
+			return
+		}
+
+		if errors.Is(err, service.ErrInvalidPasskey) {
+			Unauthorized(w, "Passkey was not recognized. Try again or sign in with your password.")
+			return
+		}
+
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			emailNotVerified(w)
+			return
+		}
+
+		serviceError(w, err)
+		return
+	}
+
+	token, session, err := h.sessions.CreateSession(r.Context(), userID)
+	if err != nil {
+		serverError(w)
+		return
+	}
+
+	h.cookie.set(w, token, session.ExpiresAt)
+
+	res := APIResponse{
+		Message: "Signin successful.",
+		Data:    session,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *authHandler) HandlePasskeyRegistrationOptions(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.UserFromContext(r.Context())
+	if !ok {
+		Unauthorized(w, "Authentication required.")
+		return
+	}
+
+	var params model.CurrentPasswordParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	opts, err := h.service.BeginPasskeyRegistration(r.Context(), user, params)
+	if err != nil {
+		if errors.Is(err, service.ErrPasswordMismatch) {
+			currentPasswordMismatch(w)
+			return
+		}
+
+		serviceError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Create a passkey with your device.",
+		Data:    opts,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *authHandler) HandleRegisterPasskey(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.UserFromContext(r.Context())
+	if !ok {
+		Unauthorized(w, "Authentication required.")
+		return
+	}
+
+	var params model.PasskeyRegistrationParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	passkey, err := h.service.FinishPasskeyRegistration(r.Context(), user, params)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPasskey) {
+			res := APIResponse{
+				Message: "Invalid input!",
+				Errors: []map[string]string{
+					{"credential": service.ErrInvalidPasskey.Error()},
+				},
+			}
+
+			responseJSON(w, http.StatusUnprocessableEntity, res)
+			return
+		}
+
+		if errors.Is(err, service.ErrPasskeyRegistered) {
+			res := APIResponse{
+				Message: err.Error(),
+			}
+
+			responseJSON(w, http.StatusConflict, res)
+			return
+		}
+
+		serviceError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Passkey added.",
+		Data:    passkey,
+	}
+
+	responseJSON(w, http.StatusCreated, res)
+}
+
+func (h *authHandler) HandleListPasskeys(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.UserFromContext(r.Context())
+	if !ok {
+		Unauthorized(w, "Authentication required.")
+		return
+	}
+
+	passkeys, err := h.service.ListPasskeys(r.Context(), user)
+	if err != nil {
+		serviceError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Passkeys retrieved.",
+		Data:    passkeys,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *authHandler) HandleDeletePasskey(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.UserFromContext(r.Context())
+	if !ok {
+		Unauthorized(w, "Authentication required.")
+		return
+	}
+
+	if err := h.service.DeletePasskey(r.Context(), user, r.PathValue("id")); err != nil {
+		if errors.Is(err, service.ErrPasskeyNotFound) {
+			res := APIResponse{
+				Message: err.Error(),
+			}
+
+			responseJSON(w, http.StatusNotFound, res)
+			return
+		}
+
+		serviceError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Passkey removed.",
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,199 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/webauthn"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	passkeySignInURL  = "/api/signin/passkey"
+	passkeyOptionsURL = "/api/me/passkeys/options"
+	passkeysURL       = "/api/me/passkeys"
+)
+
+// testAssertionJSON is shaped like PublicKeyCredential.toJSON() in browsers,
+// including the fields the server does not use.
+const testAssertionJSON = `{"credential": {
+	"id": "Y3JlZA", "rawId": "Y3JlZA", "type": "public-key",
+	"authenticatorAttachment": "platform", "clientExtensionResults": {},
+	"response": {"clientDataJSON": "e30", "authenticatorData": "YXV0aA", "signature": "c2ln", "userHandle": "MQ"}
+}}`
+
+func newPasskeySignInRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodPost, passkeySignInURL, strings.NewReader(testAssertionJSON))
+	req.Header.Set("Content-Type", contentType)
+	return req
+}
+
+func TestAuthHandler_HandlePasskeySignIn_Success(t *testing.T) {
+	req := newPasskeySignInRequest()
+	rr := httptest.NewRecorder()
+
+	mockService, mockSessions, mockValidator, authHandler := setupMockService(t)
+	mockValidator.EXPECT().Struct(gomock.Any()).Return(nil)
+	mockService.EXPECT().FinishPasskeySignIn(req.Context(), gomock.Any()).
+		DoAndReturn(func(_ any, params model.PasskeySignInParams) (string, error) {
+			assert.Equal(t, []byte("cred"), []byte(params.Credential.RawID), "raw ID should be decoded")
+			assert.Equal(t, []byte("1"), []byte(params.Credential.Response.UserHandle), "user handle should be decoded")
+			return testID, nil
+		})
+	mockSessions.EXPECT().CreateSession(req.Context(), testID).Return(testToken, &model.Session{
+		UserID:    testID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+
+	authHandler.HandlePasskeySignIn(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+
+	cookie := findCookie(rr.Result().Cookies(), testCookieName)
+	if assert.NotNil(t, cookie, "a session cookie should be set") {
+		assert.Equal(t, testToken, cookie.Value, "session token should match")
+	}
+}
+
+func TestAuthHandler_HandlePasskeySignIn_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"unrecognized passkey should be rejected", service.ErrInvalidPasskey, http.StatusUnauthorized},
+		{"unverified email should be rejected", service.ErrEmailNotVerified, http.StatusForbidden},
+		{"second factor should be required", &service.MFARequiredError{}, http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newPasskeySignInRequest()
+			rr := httptest.NewRecorder()
+
+			mockService, mockSessions, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(gomock.Any()).Return(nil)
+			mockService.EXPECT().FinishPasskeySignIn(req.Context(), gomock.Any()).Return("", tt.err)
+			mockSessions.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+			authHandler.HandlePasskeySignIn(rr, req)
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func TestAuthHandler_HandlePasskeySignInOptions(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, passkeySignInURL+"/options", nil)
+	rr := httptest.NewRecorder()
+
+	mockService, _, _, authHandler := setupMockService(t)
+	mockService.EXPECT().BeginPasskeySignIn(req.Context()).Return(&webauthn.RequestOptions{
+		Challenge: []byte("challenge"),
+		RPID:      "example.com",
+	}, nil)
+
+	authHandler.HandlePasskeySignInOptions(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+	assert.Contains(t, rr.Body.String(), `"challenge":"Y2hhbGxlbmdl"`, "challenge should be base64url encoded")
+}
+
+func TestAuthHandler_HandlePasskeyRegistrationOptions_WrongPassword(t *testing.T) {
+	user := &model.User{ID: testID, Email: testEmail}
+	params := model.CurrentPasswordParams{CurrentPassword: "wrong"}
+	req := newJSONRequest(t, http.MethodPost, passkeyOptionsURL, params)
+	req = req.WithContext(service.ContextWithUser(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	mockService, _, mockValidator, authHandler := setupMockService(t)
+	mockValidator.EXPECT().Struct(params).Return(nil)
+	mockService.EXPECT().BeginPasskeyRegistration(req.Context(), user, params).Return(nil, service.ErrPasswordMismatch)
+
+	authHandler.HandlePasskeyRegistrationOptions(rr, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, "Response status code should match")
+}
+
+func TestAuthHandler_HandleRegisterPasskey(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"passkey should be added", nil, http.StatusCreated},
+		{"invalid credential should be rejected", service.ErrInvalidPasskey, http.StatusUnprocessableEntity},
+		{"registered passkey should conflict", service.ErrPasskeyRegistered, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &model.User{ID: testID, Email: testEmail}
+			params := model.PasskeyRegistrationParams{Name: "Laptop", Credential: &webauthn.AttestationResponse{
+				ID: "Y3JlZA", RawID: []byte("cred"), Type: webauthn.CredentialTypePublicKey,
+			}}
+			req := newJSONRequest(t, http.MethodPost, passkeysURL, params)
+			req = req.WithContext(service.ContextWithUser(req.Context(), user))
+			rr := httptest.NewRecorder()
+
+			var passkey *model.Passkey
+			if tt.err == nil {
+				passkey = &model.Passkey{ID: "Y3JlZA", Name: "Laptop"}
+			}
+
+			mockService, _, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(gomock.Any()).Return(nil)
+			mockService.EXPECT().FinishPasskeyRegistration(req.Context(), user, gomock.Any()).Return(passkey, tt.err)
+
+			authHandler.HandleRegisterPasskey(rr, req)
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func TestAuthHandler_HandleDeletePasskey(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"passkey should be removed", nil, http.StatusOK},
+		{"unknown passkey should not be found", service.ErrPasskeyNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &model.User{ID: testID, Email: testEmail}
+			req := httptest.NewRequest(http.MethodDelete, passkeysURL+"/Y3JlZA", nil)
+			req.SetPathValue("id", "Y3JlZA")
+			req = req.WithContext(service.ContextWithUser(req.Context(), user))
+			rr := httptest.NewRecorder()
+
+			mockService, _, _, authHandler := setupMockService(t)
+			mockService.EXPECT().DeletePasskey(req.Context(), user, "Y3JlZA").Return(tt.err)
+
+			authHandler.HandleDeletePasskey(rr, req)
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func TestAuthHandler_Passkeys_Unauthenticated(t *testing.T) {
+	_, _, _, authHandler := setupMockService(t)
+	handlers := map[string]http.HandlerFunc{
+		"registration options": authHandler.HandlePasskeyRegistrationOptions,
+		"registration":         authHandler.HandleRegisterPasskey,
+		"list":                 authHandler.HandleListPasskeys,
+		"delete":               authHandler.HandleDeletePasskey,
+	}
+
+	for name, h := range handlers {
+		req := httptest.NewRequest(http.MethodPost, passkeysURL, nil)
+		rr := httptest.NewRecorder()
+
+		h(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code, "Response status code should match for %s", name)
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+func (h *authHandler) HandleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var params model.EmailParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	if err := h.service.ForgotPassword(r.Context(), params.Email); err != nil {
+		if errors.Is(err, service.ErrPasswordResetDisabled) {
+			http.NotFound(w, r)
+			return
+		}
+
+		serverError(w)
+		return
+	}
+
+	// The response is the same whether or not the email is registered.
+	res := APIResponse{
+		Message: "If the email belongs to an account, a password reset link has been sent.",
+	}
+
+	responseJSON(w, http.StatusAccepted, res)
+}
+
+func (h *authHandler) HandleResetPassword(w http.ResponseWriter, r *http.Request) {
+	var params model.ResetPasswordParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	if err := h.service.ResetPassword(r.Context(), params); err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			res := APIResponse{
+				Message: "Invalid or expired password reset link.",
+			}
+
+			responseJSON(w, http.StatusBadRequest, res)
+			return
+		}
+
+		if errors.Is(err, service.ErrPasswordResetDisabled) {
+			http.NotFound(w, r)
+			return
+		}
+
+		if errors.Is(err, service.ErrPasswordBreached) {
+			passwordBreached(w)
+			return
+		}
+
+		serviceError(w, err)
+		return
+	}
+
+	// Every session was revoked, including the one in this browser.
+	h.cookie.clear(w)
+
+	res := APIResponse{
+		Message: "Password has been reset.",
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func passwordBreached(w http.ResponseWriter) {
+	res := APIResponse{
+		Message: "Invalid input!",
+		Errors: []map[string]string{
+			{"password": "password has appeared in a data breach, choose another"},
+		},
+	}
+
+	responseJSON(w, http.StatusUnprocessableEntity, res)
+}
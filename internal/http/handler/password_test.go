@@ -0,0 +1,93 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	forgotPasswordURL = "/api/password/forgot"
+	resetPasswordURL  = "/api/password/reset"
+)
+
+func TestAuthHandler_HandleForgotPassword(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"request should be accepted", nil, http.StatusAccepted},
+		{"disabled reset should not be found", service.ErrPasswordResetDisabled, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := model.EmailParams{Email: testEmail}
+			req := newJSONRequest(t, http.MethodPost, forgotPasswordURL, params)
+			rr := httptest.NewRecorder()
+
+			mockService, _, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(params).Return(nil)
+			mockService.EXPECT().ForgotPassword(req.Context(), testEmail).Return(tt.err)
+
+			authHandler.HandleForgotPassword(rr, req)
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func TestAuthHandler_HandleResetPassword(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"valid token should reset the password", nil, http.StatusOK},
+		{"invalid token should be rejected", service.ErrInvalidToken, http.StatusBadRequest},
+		{"breached password should be rejected", service.ErrPasswordBreached, http.StatusUnprocessableEntity},
+		{"service errors should be reported", errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := model.ResetPasswordParams{Token: testToken, Password: testPassword, PasswordConfirm: testPassword}
+			req := newJSONRequest(t, http.MethodPost, resetPasswordURL, params)
+			rr := httptest.NewRecorder()
+
+			mockService, _, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(params).Return(nil)
+			mockService.EXPECT().ResetPassword(req.Context(), params).Return(tt.err)
+
+			authHandler.HandleResetPassword(rr, req)
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+
+			if tt.err == nil {
+				cookie := findCookie(rr.Result().Cookies(), testCookieName)
+				if assert.NotNil(t, cookie, "session cookie should be cleared") {
+					assert.Negative(t, cookie.MaxAge, "cookie should expire")
+				}
+			}
+		})
+	}
+}
+
+func TestAuthHandler_HandleUserSignUp_Breached(t *testing.T) {
+	params := model.UserSignUpParams{Email: testEmail, Password: testPassword, PasswordConfirm: testPassword}
+	req := newJSONRequest(t, http.MethodPost, signUpURL, params)
+	rr := httptest.NewRecorder()
+
+	mockService, _, mockValidator, authHandler := setupMockService(t)
+	mockValidator.EXPECT().Struct(params).Return(nil)
+	mockService.EXPECT().SignUpUser(req.Context(), params).Return(nil, service.ErrPasswordBreached)
+
+	authHandler.HandleUserSignUp(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, "Response status code should match")
+	assert.Contains(t, rr.Body.String(), `"password"`, "the password field should be reported")
+}
@@ -0,0 +1,145 @@
+//go:generate mockgen -destination=mocks/role_handler_mock.go -package=mocks . RoleHandler
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/validation"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+// RoleHandler serves the admin API for roles. The router guards it with
+// permissions on the roles resource.
+type RoleHandler interface {
+	HandleListRoles(w http.ResponseWriter, r *http.Request)
+	HandleCreateRole(w http.ResponseWriter, r *http.Request)
+	HandleListUserRoles(w http.ResponseWriter, r *http.Request)
+	HandleAssignRole(w http.ResponseWriter, r *http.Request)
+	HandleUnassignRole(w http.ResponseWriter, r *http.Request)
+}
+
+type roleHandler struct {
+	service   service.RoleService
+	validator validation.Validator
+}
+
+var _ RoleHandler = (*roleHandler)(nil)
+
+func NewRoleHandler(roleService service.RoleService, validator validation.Validator) RoleHandler {
+	return &roleHandler{
+		service:   roleService,
+		validator: validator,
+	}
+}
+
+func (h *roleHandler) HandleListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.service.ListRoles(r.Context())
+	if err != nil {
+		serviceError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Roles retrieved.",
+		Data:    roles,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *roleHandler) HandleCreateRole(w http.ResponseWriter, r *http.Request) {
+	var params model.RoleParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	role, err := h.service.CreateRole(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, service.ErrRoleExists) {
+			responseJSON(w, http.StatusConflict, APIResponse{Message: "A role with this name already exists."})
+			return
+		}
+
+		serviceError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Role created.",
+		Data:    role,
+	}
+
+	responseJSON(w, http.StatusCreated, res)
+}
+
+func (h *roleHandler) HandleListUserRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.service.ListUserRoles(r.Context(), r.PathValue("id"))
+	if err != nil {
+		roleError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Roles retrieved.",
+		Data:    roles,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *roleHandler) HandleAssignRole(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.service.AssignRole(r.Context(), r.PathValue("id"), r.PathValue("role"))
+	if err != nil {
+		roleError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Role assigned.",
+		Data:    roles,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *roleHandler) HandleUnassignRole(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.service.UnassignRole(r.Context(), r.PathValue("id"), r.PathValue("role"))
+	if err != nil {
+		roleError(w, err)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Role unassigned.",
+		Data:    roles,
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func roleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		responseJSON(w, http.StatusNotFound, APIResponse{Message: "User not found."})
+	case errors.Is(err, service.ErrRoleNotFound):
+		responseJSON(w, http.StatusNotFound, APIResponse{Message: "Role not found."})
+	case errors.Is(err, service.ErrRoleScope):
+		responseJSON(w, http.StatusUnprocessableEntity, APIResponse{
+			Message: "Invalid input!",
+			Errors: []map[string]string{
+				{"role": err.Error()},
+			},
+		})
+	default:
+		serviceError(w, err)
+	}
+}
@@ -0,0 +1,114 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/http/handler"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/ferdiebergado/fullstackgo/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	validationMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/validation/mocks"
+)
+
+const testRole = "editor"
+
+func TestRoleHandler_HandleCreateRole(t *testing.T) {
+	params := model.RoleParams{
+		Name:        testRole,
+		Permissions: []model.Permission{{Action: "write", Resource: "posts"}},
+	}
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"role should be created", nil, http.StatusCreated},
+		{"existing role should conflict", service.ErrRoleExists, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newJSONRequest(t, http.MethodPost, "/api/admin/roles", params)
+			rr := httptest.NewRecorder()
+
+			mockService, mockValidator, roleHandler := setupMockRoleService(t)
+			mockValidator.EXPECT().Struct(params).Return(nil)
+			var role *model.Role
+			if tt.err == nil {
+				role = &model.Role{Name: testRole, Permissions: params.Permissions}
+			}
+			mockService.EXPECT().CreateRole(req.Context(), params).Return(role, tt.err)
+
+			roleHandler.HandleCreateRole(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func TestRoleHandler_HandleAssignRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		body   string
+	}{
+		{"role should be assigned", nil, http.StatusOK, `"roles":["editor"]`},
+		{"unknown user should not be found", service.ErrUserNotFound, http.StatusNotFound, "User not found."},
+		{"unknown role should not be found", service.ErrRoleNotFound, http.StatusNotFound, "Role not found."},
+		{"organization role should be invalid", service.ErrRoleScope, http.StatusUnprocessableEntity, `"role"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/api/admin/users/"+testID+"/roles/"+testRole, nil)
+			req.SetPathValue("id", testID)
+			req.SetPathValue("role", testRole)
+			rr := httptest.NewRecorder()
+
+			mockService, _, roleHandler := setupMockRoleService(t)
+			var roles *model.UserRoles
+			if tt.err == nil {
+				roles = &model.UserRoles{UserID: testID, Roles: []string{testRole}}
+			}
+			mockService.EXPECT().AssignRole(req.Context(), testID, testRole).Return(roles, tt.err)
+
+			roleHandler.HandleAssignRole(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+			assert.Contains(t, rr.Body.String(), tt.body, "response body should match")
+		})
+	}
+}
+
+func TestRoleHandler_HandleUnassignRole(t *testing.T) {
+	req := httptest.NewRequest(http.MethodDelete, "/api/admin/users/"+testID+"/roles/"+testRole, nil)
+	req.SetPathValue("id", testID)
+	req.SetPathValue("role", testRole)
+	rr := httptest.NewRecorder()
+
+	mockService, _, roleHandler := setupMockRoleService(t)
+	mockService.EXPECT().UnassignRole(req.Context(), testID, testRole).
+		Return(&model.UserRoles{UserID: testID, Roles: []string{}}, nil)
+
+	roleHandler.HandleUnassignRole(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+	assert.Contains(t, rr.Body.String(), `"roles":[]`, "remaining roles should be returned")
+}
+
+func setupMockRoleService(t *testing.T) (*mocks.MockRoleService, *validationMocks.MockValidator, handler.RoleHandler) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockRoleService(ctrl)
+	mockValidator := validationMocks.NewMockValidator(ctrl)
+	roleHandler := handler.NewRoleHandler(mockService, mockValidator)
+
+	return mockService, mockValidator, roleHandler
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultSessionCookieName is the cookie used when none is configured.
+const DefaultSessionCookieName = "session"
+
+// SessionCookie holds the attributes of the cookie that carries the session token.
+type SessionCookie struct {
+	Name   string
+	Secure bool
+}
+
+func (c SessionCookie) name() string {
+	if c.Name == "" {
+		return DefaultSessionCookieName
+	}
+
+	return c.Name
+}
+
+// Token returns the session token sent with the request, if any.
+func (c SessionCookie) Token(r *http.Request) string {
+	cookie, err := r.Cookie(c.name())
+	if err != nil {
+		return ""
+	}
+
+	return cookie.Value
+}
+
+func (c SessionCookie) set(w http.ResponseWriter, token string, expiresAt time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     c.name(),
+		Value:    token,
+		Path:     "/",
+		Expires:  expiresAt,
+		MaxAge:   int(time.Until(expiresAt).Seconds()),
+		Secure:   c.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (c SessionCookie) clear(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     c.name(),
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   c.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
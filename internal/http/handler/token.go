@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+func (h *authHandler) HandleTokenSignIn(w http.ResponseWriter, r *http.Request) {
+	var params model.UserSignInParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	tokens, err := h.service.SignInWithToken(r.Context(), params)
+	if err != nil {
+		if mfaRequired(w, err) {
+			return
+		}
+
+		if errors.Is(err, service.ErrUserNotFound) || errors.Is(err, service.ErrPasswordMismatch) {
+			Unauthorized(w, "Invalid email or password.")
+			return
+		}
+
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			emailNotVerified(w)
+			return
+		}
+
+		var lockErr *service.LockoutError
+		if errors.As(err, &lockErr) {
+			TooManyRequests(w, "Too many failed sign-in attempts. Try again later.", lockErr.RetryAfter)
+			return
+		}
+
+		serviceError(w, err)
+		return
+	}
+
+	responseJSON(w, http.StatusOK, tokens)
+}
+
+func (h *authHandler) HandleTokenRefresh(w http.ResponseWriter, r *http.Request) {
+	var params model.RefreshTokenParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	tokens, err := h.service.RefreshTokens(r.Context(), params.RefreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+			Unauthorized(w, "Invalid refresh token.")
+			return
+		}
+
+		serverError(w)
+		return
+	}
+
+	responseJSON(w, http.StatusOK, tokens)
+}
+
+func (h *authHandler) HandleTokenRevoke(w http.ResponseWriter, r *http.Request) {
+	var params model.RefreshTokenParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	// Unknown tokens are reported as revoked so that callers cannot probe for valid ones.
+	if err := h.service.RevokeRefreshToken(r.Context(), params.RefreshToken); err != nil &&
+		!errors.Is(err, service.ErrInvalidToken) {
+		serverError(w)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Token revoked.",
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
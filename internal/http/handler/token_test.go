@@ -0,0 +1,146 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/http/handler"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	tokenURL        = "/api/token"
+	tokenRefreshURL = "/api/token/refresh"
+	tokenRevokeURL  = "/api/token/revoke"
+	testRefresh     = "refresh"
+)
+
+func TestAuthHandler_HandleTokenSignIn_Success(t *testing.T) {
+	params := model.UserSignInParams{Email: testEmail, Password: testPassword}
+	req := newJSONRequest(t, http.MethodPost, tokenURL, params)
+	rr := httptest.NewRecorder()
+
+	mockService, _, mockValidator, authHandler := setupMockService(t)
+	mockValidator.EXPECT().Struct(params).Return(nil)
+	mockService.EXPECT().SignInWithToken(req.Context(), params).Return(&model.TokenPair{
+		AccessToken:  testToken,
+		TokenType:    service.TokenTypeBearer,
+		ExpiresIn:    900,
+		RefreshToken: testRefresh,
+	}, nil)
+
+	authHandler.HandleTokenSignIn(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+
+	var pair model.TokenPair
+	if err := json.NewDecoder(rr.Body).Decode(&pair); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+
+	assert.Equal(t, testToken, pair.AccessToken, "access token should match")
+	assert.Equal(t, testRefresh, pair.RefreshToken, "refresh token should match")
+	assert.Equal(t, service.TokenTypeBearer, pair.TokenType, "token type should match")
+}
+
+func TestAuthHandler_HandleTokenSignIn_InvalidCredentials(t *testing.T) {
+	params := model.UserSignInParams{Email: testEmail, Password: testPassword}
+	req := newJSONRequest(t, http.MethodPost, tokenURL, params)
+	rr := httptest.NewRecorder()
+
+	mockService, _, mockValidator, authHandler := setupMockService(t)
+	mockValidator.EXPECT().Struct(params).Return(nil)
+	mockService.EXPECT().SignInWithToken(req.Context(), params).Return(nil, service.ErrPasswordMismatch)
+
+	authHandler.HandleTokenSignIn(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code, "Response status code should match")
+}
+
+func TestAuthHandler_HandleTokenRefresh(t *testing.T) {
+	tests := []struct {
+		name   string
+		pair   *model.TokenPair
+		err    error
+		status int
+	}{
+		{"valid token should be rotated", &model.TokenPair{AccessToken: testToken, RefreshToken: testRefresh}, nil, http.StatusOK},
+		{"invalid token should be rejected", nil, service.ErrInvalidToken, http.StatusUnauthorized},
+		{"reused token should be rejected", nil, service.ErrRefreshTokenReused, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := model.RefreshTokenParams{RefreshToken: testRefresh}
+			req := newJSONRequest(t, http.MethodPost, tokenRefreshURL, params)
+			rr := httptest.NewRecorder()
+
+			mockService, _, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(params).Return(nil)
+			mockService.EXPECT().RefreshTokens(req.Context(), testRefresh).Return(tt.pair, tt.err)
+
+			authHandler.HandleTokenRefresh(rr, req)
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func TestAuthHandler_HandleTokenRevoke(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"known token should be revoked", nil},
+		{"unknown token should look revoked", service.ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := model.RefreshTokenParams{RefreshToken: testRefresh}
+			req := newJSONRequest(t, http.MethodPost, tokenRevokeURL, params)
+			rr := httptest.NewRecorder()
+
+			mockService, _, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(params).Return(nil)
+			mockService.EXPECT().RevokeRefreshToken(req.Context(), testRefresh).Return(tt.err)
+
+			authHandler.HandleTokenRevoke(rr, req)
+			assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+
+			var res handler.APIResponse
+			if err := json.NewDecoder(rr.Body).Decode(&res); err != nil {
+				t.Fatalf("decode json: %v", err)
+			}
+
+			assert.Equal(t, "Token revoked.", res.Message, "Message should match")
+		})
+	}
+}
+
+func TestAuthHandler_HandleTokenRefresh_MalformedBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, tokenRefreshURL, bytes.NewBufferString(`{"unknown": 1}`))
+	rr := httptest.NewRecorder()
+
+	mockService, _, mockValidator, authHandler := setupMockService(t)
+	mockValidator.EXPECT().Struct(gomock.Any()).Times(0)
+	mockService.EXPECT().RefreshTokens(gomock.Any(), gomock.Any()).Times(0)
+
+	authHandler.HandleTokenRefresh(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code, "Response status code should match")
+}
+
+func newJSONRequest(t *testing.T, method, url string, body any) *http.Request {
+	t.Helper()
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v, err: %v", body, err)
+	}
+
+	req := httptest.NewRequest(method, url, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", contentType)
+	return req
+}
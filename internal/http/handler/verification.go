@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+func (h *authHandler) HandleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var params model.VerifyEmailParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	if err := h.service.VerifyEmail(r.Context(), params.Token); err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			res := APIResponse{
+				Message: "Invalid or expired verification link.",
+			}
+
+			responseJSON(w, http.StatusBadRequest, res)
+			return
+		}
+
+		if errors.Is(err, service.ErrVerificationDisabled) {
+			http.NotFound(w, r)
+			return
+		}
+
+		serverError(w)
+		return
+	}
+
+	res := APIResponse{
+		Message: "Email verified.",
+	}
+
+	responseJSON(w, http.StatusOK, res)
+}
+
+func (h *authHandler) HandleResendVerification(w http.ResponseWriter, r *http.Request) {
+	var params model.EmailParams
+	if err := DecodeJSON(r, &params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		if handleValidationError(w, err) {
+			return
+		}
+	}
+
+	if err := h.service.ResendVerification(r.Context(), params.Email); err != nil {
+		if errors.Is(err, service.ErrVerificationDisabled) {
+			http.NotFound(w, r)
+			return
+		}
+
+		serverError(w)
+		return
+	}
+
+	// The response is the same whether or not the email is registered.
+	res := APIResponse{
+		Message: "If the email belongs to an unverified account, a verification link has been sent.",
+	}
+
+	responseJSON(w, http.StatusAccepted, res)
+}
+
+func emailNotVerified(w http.ResponseWriter) {
+	res := APIResponse{
+		Message: "Email address has not been verified.",
+	}
+
+	responseJSON(w, http.StatusForbidden, res)
+}
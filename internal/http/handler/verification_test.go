@@ -0,0 +1,86 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	verifyEmailURL        = "/api/verify-email"
+	resendVerificationURL = "/api/verify-email/resend"
+)
+
+func TestAuthHandler_HandleVerifyEmail(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"valid token should verify the email", nil, http.StatusOK},
+		{"invalid token should be rejected", service.ErrInvalidToken, http.StatusBadRequest},
+		{"disabled verification should not be found", service.ErrVerificationDisabled, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := model.VerifyEmailParams{Token: testToken}
+			req := newJSONRequest(t, http.MethodPost, verifyEmailURL, params)
+			rr := httptest.NewRecorder()
+
+			mockService, _, mockValidator, authHandler := setupMockService(t)
+			mockValidator.EXPECT().Struct(params).Return(nil)
+			mockService.EXPECT().VerifyEmail(req.Context(), testToken).Return(tt.err)
+
+			authHandler.HandleVerifyEmail(rr, req)
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func TestAuthHandler_HandleResendVerification(t *testing.T) {
+	params := model.EmailParams{Email: testEmail}
+	req := newJSONRequest(t, http.MethodPost, resendVerificationURL, params)
+	rr := httptest.NewRecorder()
+
+	mockService, _, mockValidator, authHandler := setupMockService(t)
+	mockValidator.EXPECT().Struct(params).Return(nil)
+	mockService.EXPECT().ResendVerification(req.Context(), testEmail).Return(nil)
+
+	authHandler.HandleResendVerification(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code, "Response status code should match")
+}
+
+func TestAuthHandler_SignIn_EmailNotVerified(t *testing.T) {
+	params := model.UserSignInParams{Email: testEmail, Password: testPassword}
+
+	t.Run("session signin should be forbidden", func(t *testing.T) {
+		req := newJSONRequest(t, http.MethodPost, "/api/signin", params)
+		rr := httptest.NewRecorder()
+
+		mockService, mockSessions, mockValidator, authHandler := setupMockService(t)
+		mockValidator.EXPECT().Struct(params).Return(nil)
+		mockService.EXPECT().SignInUser(req.Context(), params).Return("", service.ErrEmailNotVerified)
+		mockSessions.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+		authHandler.HandleUserSignIn(rr, req)
+		assert.Equal(t, http.StatusForbidden, rr.Code, "Response status code should match")
+	})
+
+	t.Run("token signin should be forbidden", func(t *testing.T) {
+		req := newJSONRequest(t, http.MethodPost, tokenURL, params)
+		rr := httptest.NewRecorder()
+
+		mockService, _, mockValidator, authHandler := setupMockService(t)
+		mockValidator.EXPECT().Struct(params).Return(nil)
+		mockService.EXPECT().SignInWithToken(req.Context(), params).Return(nil, service.ErrEmailNotVerified)
+
+		authHandler.HandleTokenSignIn(rr, req)
+		assert.Equal(t, http.StatusForbidden, rr.Code, "Response status code should match")
+	})
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/ferdiebergado/fullstackgo/internal/http/handler"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+// AccessTokenVerifier resolves the user a signed access token was issued to.
+type AccessTokenVerifier interface {
+	VerifyAccessToken(ctx context.Context, accessToken string) (string, error)
+}
+
+// Auth resolves the caller of a request from its session cookie or bearer token.
+type Auth struct {
+	sessions service.SessionService
+	tokens   AccessTokenVerifier
+	users    repo.UserRepo
+	cookie   handler.SessionCookie
+}
+
+// NewAuth returns the authentication middleware. tokens may be nil, in which
+// case bearer tokens are only accepted as session tokens.
+func NewAuth(sessions service.SessionService, tokens AccessTokenVerifier, users repo.UserRepo,
+	cookie handler.SessionCookie) *Auth {
+	return &Auth{
+		sessions: sessions,
+		tokens:   tokens,
+		users:    users,
+		cookie:   cookie,
+	}
+}
+
+// LoadUser stores the authenticated user in the request context when the
+// request carries a valid credential. Requests without one pass through
+// unchanged; use RequireAuth to reject them.
+func (a *Auth) LoadUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		token := bearerToken(r)
+		if a.tokens != nil && isJWT(token) {
+			userID, err := a.tokens.VerifyAccessToken(ctx, token)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(a.withUser(ctx, userID)))
+			return
+		}
+
+		if token == "" {
+			token = a.cookie.Token(r)
+		}
+
+		if token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		session, err := a.sessions.ValidateSession(ctx, token)
+		if err != nil {
+			if !errors.Is(err, service.ErrInvalidSession) {
+				slog.Error("validate session", "error", err)
+			}
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx = service.ContextWithSession(ctx, session)
+		next.ServeHTTP(w, r.WithContext(a.withUser(ctx, session.UserID)))
+	})
+}
+
+// withUser loads the user and stores it in ctx, with a cache for the
+// permissions checked during the request. ctx is returned unchanged if the
+// user cannot be loaded.
+func (a *Auth) withUser(ctx context.Context, userID string) context.Context {
+	user, err := a.users.FindUserByID(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Error("find authenticated user", "error", err)
+		}
+
+		return ctx
+	}
+
+	return service.ContextWithPermissionCache(service.ContextWithUser(ctx, user))
+}
+
+// RequireAuth responds with 401 unless LoadUser has authenticated the request.
+func RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := service.UserFromContext(r.Context()); !ok {
+			handler.Unauthorized(w, "Authentication required.")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func bearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+
+	header := r.Header.Get("Authorization")
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return ""
+	}
+
+	return strings.TrimSpace(header[len(prefix):])
+}
+
+// isJWT reports whether token has the three dot-separated segments of a JWT.
+// Opaque session tokens are URL-safe base64 and never contain dots.
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
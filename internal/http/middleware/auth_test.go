@@ -0,0 +1,214 @@
+package middleware_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/http/handler"
+	"github.com/ferdiebergado/fullstackgo/internal/http/middleware"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+	svcMocks "github.com/ferdiebergado/fullstackgo/internal/service/mocks"
+)
+
+const (
+	testID         = "1"
+	testEmail      = "abc@example.com"
+	testToken      = "token"
+	testCookieName = "session"
+	testJWT        = "header.payload.signature"
+)
+
+func TestAuth_LoadUser_FromCookie(t *testing.T) {
+	mockSessions, _, mockUsers, auth := setupAuthMocks(t)
+	session := &model.Session{UserID: testID, ExpiresAt: time.Now().Add(time.Hour)}
+	mockSessions.EXPECT().ValidateSession(gomock.Any(), testToken).Return(session, nil)
+	mockUsers.EXPECT().FindUserByID(gomock.Any(), testID).Return(&model.User{ID: testID, Email: testEmail}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: testCookieName, Value: testToken})
+
+	var gotUser *model.User
+	var gotSession *model.Session
+	next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		gotUser, _ = service.UserFromContext(r.Context())
+		gotSession, _ = service.SessionFromContext(r.Context())
+	})
+
+	auth.LoadUser(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	if assert.NotNil(t, gotUser, "user should be stored in the context") {
+		assert.Equal(t, testID, gotUser.ID, "ID should match")
+		assert.Equal(t, testEmail, gotUser.Email, "email should match")
+	}
+	assert.Equal(t, session, gotSession, "session should be stored in the context")
+}
+
+func TestAuth_LoadUser_FromBearerToken(t *testing.T) {
+	mockSessions, _, mockUsers, auth := setupAuthMocks(t)
+	mockSessions.EXPECT().ValidateSession(gomock.Any(), testToken).
+		Return(&model.Session{UserID: testID, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	mockUsers.EXPECT().FindUserByID(gomock.Any(), testID).Return(&model.User{ID: testID}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+
+	var ok bool
+	next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		_, ok = service.UserFromContext(r.Context())
+	})
+
+	auth.LoadUser(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.True(t, ok, "user should be stored in the context")
+}
+
+func TestAuth_LoadUser_Unauthenticated(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		setup func(s *svcMocks.MockSessionService, u *repoMocks.MockUserRepo)
+	}{
+		{"no credentials", "", func(s *svcMocks.MockSessionService, _ *repoMocks.MockUserRepo) {
+			s.EXPECT().ValidateSession(gomock.Any(), gomock.Any()).Times(0)
+		}},
+		{"invalid session", testToken, func(s *svcMocks.MockSessionService, u *repoMocks.MockUserRepo) {
+			s.EXPECT().ValidateSession(gomock.Any(), testToken).Return(nil, service.ErrInvalidSession)
+			u.EXPECT().FindUserByID(gomock.Any(), gomock.Any()).Times(0)
+		}},
+		{"session store failure", testToken, func(s *svcMocks.MockSessionService, _ *repoMocks.MockUserRepo) {
+			s.EXPECT().ValidateSession(gomock.Any(), testToken).Return(nil, errors.New("connection refused"))
+		}},
+		{"deleted user", testToken, func(s *svcMocks.MockSessionService, u *repoMocks.MockUserRepo) {
+			s.EXPECT().ValidateSession(gomock.Any(), testToken).
+				Return(&model.Session{UserID: testID, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+			u.EXPECT().FindUserByID(gomock.Any(), testID).Return(nil, sql.ErrNoRows)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSessions, _, mockUsers, auth := setupAuthMocks(t)
+			tt.setup(mockSessions, mockUsers)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.token != "" {
+				req.AddCookie(&http.Cookie{Name: testCookieName, Value: tt.token})
+			}
+
+			called := false
+			next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				called = true
+				_, ok := service.UserFromContext(r.Context())
+				assert.False(t, ok, "user should not be stored in the context")
+			})
+
+			auth.LoadUser(next).ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.True(t, called, "next handler should be called")
+		})
+	}
+}
+
+func TestRequireAuth_Unauthenticated(t *testing.T) {
+	next := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		t.Error("next handler should not be called")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+
+	middleware.RequireAuth(next).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code, "Response status code should match")
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"), "Content-Type header should match")
+
+	var res handler.APIResponse
+	if err := json.NewDecoder(rr.Body).Decode(&res); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+
+	assert.NotEmpty(t, res.Message, "Message should not be empty")
+}
+
+func TestRequireAuth_Authenticated(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(service.ContextWithUser(req.Context(), &model.User{ID: testID}))
+	rr := httptest.NewRecorder()
+
+	middleware.RequireAuth(next).ServeHTTP(rr, req)
+
+	assert.True(t, called, "next handler should be called")
+	assert.Equal(t, http.StatusNoContent, rr.Code, "Response status code should match")
+}
+
+func TestAuth_LoadUser_FromAccessToken(t *testing.T) {
+	mockSessions, mockTokens, mockUsers, auth := setupAuthMocks(t)
+	mockTokens.EXPECT().VerifyAccessToken(gomock.Any(), testJWT).Return(testID, nil)
+	mockSessions.EXPECT().ValidateSession(gomock.Any(), gomock.Any()).Times(0)
+	mockUsers.EXPECT().FindUserByID(gomock.Any(), testID).Return(&model.User{ID: testID}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+testJWT)
+
+	var gotUser *model.User
+	var hasSession bool
+	next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		gotUser, _ = service.UserFromContext(r.Context())
+		_, hasSession = service.SessionFromContext(r.Context())
+	})
+
+	auth.LoadUser(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	if assert.NotNil(t, gotUser, "user should be stored in the context") {
+		assert.Equal(t, testID, gotUser.ID, "ID should match")
+	}
+	assert.False(t, hasSession, "access tokens should not carry a session")
+}
+
+func TestAuth_LoadUser_InvalidAccessToken(t *testing.T) {
+	mockSessions, mockTokens, mockUsers, auth := setupAuthMocks(t)
+	mockTokens.EXPECT().VerifyAccessToken(gomock.Any(), testJWT).Return("", service.ErrInvalidToken)
+	mockSessions.EXPECT().ValidateSession(gomock.Any(), gomock.Any()).Times(0)
+	mockUsers.EXPECT().FindUserByID(gomock.Any(), gomock.Any()).Times(0)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+testJWT)
+	req.AddCookie(&http.Cookie{Name: testCookieName, Value: testToken})
+
+	var ok bool
+	next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		_, ok = service.UserFromContext(r.Context())
+	})
+
+	auth.LoadUser(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.False(t, ok, "user should not be stored in the context")
+}
+
+func setupAuthMocks(t *testing.T) (*svcMocks.MockSessionService, *svcMocks.MockAuthService,
+	*repoMocks.MockUserRepo, *middleware.Auth) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockSessions := svcMocks.NewMockSessionService(ctrl)
+	mockTokens := svcMocks.NewMockAuthService(ctrl)
+	mockUsers := repoMocks.NewMockUserRepo(ctrl)
+	auth := middleware.NewAuth(mockSessions, mockTokens, mockUsers, handler.SessionCookie{Name: testCookieName})
+
+	return mockSessions, mockTokens, mockUsers, auth
+}
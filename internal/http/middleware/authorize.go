@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/ferdiebergado/fullstackgo/internal/http/handler"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+// RequirePermission responds with 401 unless LoadUser has authenticated the
+// request, and with 403 unless one of the user's roles allows action on
+// resource.
+func RequirePermission(authz service.Authorizer, action, resource string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := service.UserFromContext(r.Context())
+			if !ok {
+				handler.Unauthorized(w, "Authentication required.")
+				return
+			}
+
+			allowed, err := authz.Can(r.Context(), user, action, resource)
+			if err != nil {
+				slog.Error("check permission", "error", err)
+				http.Error(w, "An error occurred.", http.StatusInternalServerError)
+				return
+			}
+
+			if !allowed {
+				handler.Forbidden(w, "You do not have permission to do this.")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/http/middleware"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	svcMocks "github.com/ferdiebergado/fullstackgo/internal/service/mocks"
+)
+
+func TestRequirePermission(t *testing.T) {
+	user := &model.User{ID: testID}
+
+	tests := []struct {
+		name    string
+		user    *model.User
+		allowed bool
+		err     error
+		status  int
+	}{
+		{"anonymous request should be unauthorized", nil, false, nil, http.StatusUnauthorized},
+		{"user without the permission should be forbidden", user, false, nil, http.StatusForbidden},
+		{"user with the permission should pass", user, true, nil, http.StatusNoContent},
+		{"failed check should be a server error", user, false, errors.New("connection lost"),
+			http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			authz := svcMocks.NewMockAuthorizer(ctrl)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.user != nil {
+				req = req.WithContext(service.ContextWithUser(req.Context(), tt.user))
+				authz.EXPECT().Can(req.Context(), tt.user, service.ActionRead, service.ResourceRoles).
+					Return(tt.allowed, tt.err)
+			}
+			rr := httptest.NewRecorder()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			middleware.RequirePermission(authz, service.ActionRead, service.ResourceRoles)(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/http/middleware"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		trustProxy bool
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{"remote address should be used", false, "192.0.2.1:1234", "", "192.0.2.1"},
+		{"forwarded header should be ignored by default", false, "192.0.2.1:1234", "203.0.113.9", "192.0.2.1"},
+		{"proxy address should be used when trusted", true, "10.0.0.1:1234", "198.51.100.7, 203.0.113.9", "203.0.113.9"},
+		{"invalid forwarded header should fall back", true, "10.0.0.1:1234", "garbage", "10.0.0.1"},
+		{"IPv6 remote address should be parsed", false, "[2001:db8::1]:443", "", "2001:db8::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+
+			var got string
+			next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got, _ = service.ClientIPFromContext(r.Context())
+			})
+
+			middleware.ClientIP(tt.trustProxy)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.want, got, "client IP should match")
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/ferdiebergado/fullstackgo/internal/http/handler"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+// RequireOrganization scopes the request to the organization named by the
+// {org} path value. It responds with 401 unless LoadUser has authenticated
+// the request, and with 404 unless the user is a member, so that
+// non-members cannot tell which organizations exist. Permissions checked
+// after it include those of the user's role in the organization.
+func RequireOrganization(orgs service.OrganizationService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := service.UserFromContext(r.Context())
+			if !ok {
+				handler.Unauthorized(w, "Authentication required.")
+				return
+			}
+
+			org, err := orgs.MemberOrganization(r.Context(), r.PathValue("org"), user)
+			if err != nil {
+				if errors.Is(err, service.ErrOrganizationNotFound) {
+					handler.NotFound(w, "Organization not found.")
+					return
+				}
+
+				slog.Error("find member organization", "error", err)
+				http.Error(w, "An error occurred.", http.StatusInternalServerError)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(service.ContextWithOrganization(r.Context(), org)))
+		})
+	}
+}
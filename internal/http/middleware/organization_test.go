@@ -0,0 +1,63 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/http/middleware"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	svcMocks "github.com/ferdiebergado/fullstackgo/internal/service/mocks"
+)
+
+func TestRequireOrganization(t *testing.T) {
+	const orgID = "acme"
+	user := &model.User{ID: testID}
+	org := &model.Organization{ID: orgID, Name: "Acme"}
+
+	tests := []struct {
+		name   string
+		user   *model.User
+		org    *model.Organization
+		err    error
+		status int
+	}{
+		{"anonymous request should be unauthorized", nil, nil, nil, http.StatusUnauthorized},
+		{"non-member should not find the organization", user, nil, service.ErrOrganizationNotFound,
+			http.StatusNotFound},
+		{"member should be scoped to the organization", user, org, nil, http.StatusNoContent},
+		{"failed lookup should be a server error", user, nil, errors.New("connection lost"),
+			http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			orgs := svcMocks.NewMockOrganizationService(ctrl)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/orgs/"+orgID, nil)
+			req.SetPathValue("org", orgID)
+			if tt.user != nil {
+				req = req.WithContext(service.ContextWithUser(req.Context(), tt.user))
+				orgs.EXPECT().MemberOrganization(req.Context(), orgID, tt.user).Return(tt.org, tt.err)
+			}
+			rr := httptest.NewRecorder()
+
+			var scoped *model.Organization
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				scoped, _ = service.OrganizationFromContext(r.Context())
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			middleware.RequireOrganization(orgs)(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+			assert.Equal(t, tt.org, scoped, "the request should be scoped to the organization")
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/http/handler"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/ratelimit"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+// RateLimitKey returns the key a request is counted under, or false to
+// leave the request unlimited.
+type RateLimitKey func(r *http.Request) (string, bool)
+
+// KeyByIP counts requests per client IP.
+func KeyByIP(r *http.Request) (string, bool) {
+	ip, ok := service.ClientIPFromContext(r.Context())
+	if !ok {
+		return "", false
+	}
+
+	return "ip:" + ip, true
+}
+
+// KeyByUser counts requests per authenticated user, falling back to the
+// client IP for anonymous requests.
+func KeyByUser(r *http.Request) (string, bool) {
+	if user, ok := service.UserFromContext(r.Context()); ok {
+		return "user:" + user.ID, true
+	}
+
+	return KeyByIP(r)
+}
+
+// RateLimiter throttles requests with token buckets held in a store.
+type RateLimiter struct {
+	store ratelimit.Store
+}
+
+func NewRateLimiter(store ratelimit.Store) *RateLimiter {
+	return &RateLimiter{
+		store: store,
+	}
+}
+
+// Limit throttles requests to limit per key. The name scopes the buckets, so
+// routes limited under different names are counted separately. Responses
+// carry RateLimit-* headers, and denied requests get 429 with Retry-After.
+// Requests are let through if the store fails.
+func (l *RateLimiter) Limit(name string, limit ratelimit.Limit, key RateLimitKey) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if l == nil || !limit.Enabled() {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			k, ok := key(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			res, err := l.store.Take(r.Context(), name+":"+k, limit, time.Now())
+			if err != nil {
+				slog.Error("rate limit", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Policy", limit.Policy())
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(int(math.Ceil(res.Reset.Seconds()))))
+
+			if !res.Allowed {
+				handler.TooManyRequests(w, "Too many requests. Try again later.", res.RetryAfter)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/http/middleware"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/ratelimit"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/ratelimit/mocks"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var testRateLimit = ratelimit.Limit{Requests: 2, Period: time.Minute}
+
+func TestRateLimiter_Limit(t *testing.T) {
+	limiter := middleware.NewRateLimiter(ratelimit.NewMemoryStore())
+	h := limiter.Limit("test", testRateLimit, middleware.KeyByIP)(okHandler())
+
+	for _, remaining := range []string{"1", "0"} {
+		rr := serveFrom(h, "192.0.2.1")
+		assert.Equal(t, http.StatusOK, rr.Code, "Response status code should match")
+		assert.Equal(t, "2", rr.Header().Get("RateLimit-Limit"), "RateLimit-Limit should match")
+		assert.Equal(t, remaining, rr.Header().Get("RateLimit-Remaining"), "RateLimit-Remaining should match")
+		assert.Equal(t, "2;w=60", rr.Header().Get("RateLimit-Policy"), "RateLimit-Policy should match")
+		assert.NotEmpty(t, rr.Header().Get("RateLimit-Reset"), "RateLimit-Reset should be set")
+	}
+
+	rr := serveFrom(h, "192.0.2.1")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code, "Response status code should match")
+	assert.Equal(t, "30", rr.Header().Get("Retry-After"), "Retry-After should match")
+
+	rr = serveFrom(h, "198.51.100.1")
+	assert.Equal(t, http.StatusOK, rr.Code, "other clients should not be limited")
+}
+
+func TestRateLimiter_Limit_ScopedByName(t *testing.T) {
+	limiter := middleware.NewRateLimiter(ratelimit.NewMemoryStore())
+	limit := ratelimit.Limit{Requests: 1, Period: time.Minute}
+	signin := limiter.Limit("signin", limit, middleware.KeyByIP)(okHandler())
+	signup := limiter.Limit("signup", limit, middleware.KeyByIP)(okHandler())
+
+	assert.Equal(t, http.StatusOK, serveFrom(signin, "192.0.2.1").Code, "first request should be allowed")
+	assert.Equal(t, http.StatusTooManyRequests, serveFrom(signin, "192.0.2.1").Code, "second request should be denied")
+	assert.Equal(t, http.StatusOK, serveFrom(signup, "192.0.2.1").Code, "other routes should have their own bucket")
+}
+
+func TestRateLimiter_Limit_ByUser(t *testing.T) {
+	limiter := middleware.NewRateLimiter(ratelimit.NewMemoryStore())
+	h := limiter.Limit("api", ratelimit.Limit{Requests: 1, Period: time.Minute}, middleware.KeyByUser)(okHandler())
+
+	asUser := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		ctx := service.ContextWithClientIP(req.Context(), "192.0.2.1")
+		ctx = service.ContextWithUser(ctx, &model.User{ID: id})
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req.WithContext(ctx))
+		return rr
+	}
+
+	assert.Equal(t, http.StatusOK, asUser("1").Code, "first request should be allowed")
+	assert.Equal(t, http.StatusTooManyRequests, asUser("1").Code, "second request should be denied")
+	assert.Equal(t, http.StatusOK, asUser("2").Code, "users behind the same IP should be counted separately")
+}
+
+func TestRateLimiter_Limit_StoreErrorFailsOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockStore(ctrl)
+	store.EXPECT().Take(gomock.Any(), "test:ip:192.0.2.1", testRateLimit, gomock.Any()).
+		Return(ratelimit.Result{}, errors.New("cache down"))
+
+	h := middleware.NewRateLimiter(store).Limit("test", testRateLimit, middleware.KeyByIP)(okHandler())
+
+	assert.Equal(t, http.StatusOK, serveFrom(h, "192.0.2.1").Code, "requests should be allowed")
+}
+
+func TestRateLimiter_Limit_Disabled(t *testing.T) {
+	var limiter *middleware.RateLimiter
+	h := limiter.Limit("test", testRateLimit, middleware.KeyByIP)(okHandler())
+
+	for range 3 {
+		rr := serveFrom(h, "192.0.2.1")
+		assert.Equal(t, http.StatusOK, rr.Code, "requests should not be limited")
+		assert.Empty(t, rr.Header().Get("RateLimit-Limit"), "headers should not be set")
+	}
+}
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+}
+
+func serveFrom(h http.Handler, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(service.ContextWithClientIP(req.Context(), ip))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
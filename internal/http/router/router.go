@@ -0,0 +1,127 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/ferdiebergado/fullstackgo/internal/http/handler"
+	"github.com/ferdiebergado/fullstackgo/internal/http/middleware"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/ratelimit"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+)
+
+// Handlers groups the HTTP handlers and middleware mounted by the router.
+type Handlers struct {
+	Auth           handler.AuthHandler
+	AuthMiddleware *middleware.Auth
+	// OAuth is optional. The authorization server is not mounted when it is
+	// nil.
+	OAuth handler.OAuthHandler
+	// Roles is optional. The admin API is not mounted when it is nil, and
+	// Authorizer guards it otherwise.
+	Roles      handler.RoleHandler
+	Authorizer service.Authorizer
+	// Organizations is optional. The organizations API is not mounted when it
+	// is nil. Otherwise Tenants scopes its routes under an organization to
+	// it, and Authorizer guards them with the member's role.
+	Organizations handler.OrganizationHandler
+	Tenants       service.OrganizationService
+	// RateLimiter is optional. Requests are not throttled when it is nil.
+	RateLimiter *middleware.RateLimiter
+	RateLimits  RateLimits
+}
+
+// RateLimits sets the limits applied by the router. A zero limit is disabled.
+type RateLimits struct {
+	// API applies to every request, per user or per client IP when anonymous.
+	API ratelimit.Limit
+	// Credentials applies per client IP to each route that hashes a password
+	// or sends email, since those are expensive to serve.
+	Credentials ratelimit.Limit
+}
+
+func New(h Handlers) http.Handler {
+	mux := http.NewServeMux()
+
+	credentials := func(pattern string, next http.Handler) {
+		mux.Handle(pattern, h.RateLimiter.Limit(pattern, h.RateLimits.Credentials, middleware.KeyByIP)(next))
+	}
+
+	credentials("POST /api/signup", http.HandlerFunc(h.Auth.HandleUserSignUp))
+	credentials("POST /api/signin", http.HandlerFunc(h.Auth.HandleUserSignIn))
+	credentials("POST /api/signin/mfa", http.HandlerFunc(h.Auth.HandleMFASignIn))
+	credentials("POST /api/signin/passkey/options", http.HandlerFunc(h.Auth.HandlePasskeySignInOptions))
+	credentials("POST /api/signin/passkey", http.HandlerFunc(h.Auth.HandlePasskeySignIn))
+	credentials("POST /api/signin/oidc/{provider}", http.HandlerFunc(h.Auth.HandleOIDCSignIn))
+	credentials("POST /api/signin/oidc/{provider}/callback", http.HandlerFunc(h.Auth.HandleOIDCCallback))
+	credentials("POST /api/signin/magic-link", http.HandlerFunc(h.Auth.HandleRequestMagicLink))
+	credentials("POST /api/signin/magic-link/verify", http.HandlerFunc(h.Auth.HandleMagicLinkSignIn))
+	mux.HandleFunc("POST /api/signout", h.Auth.HandleUserSignOut)
+	credentials("POST /api/token", http.HandlerFunc(h.Auth.HandleTokenSignIn))
+	credentials("POST /api/token/mfa", http.HandlerFunc(h.Auth.HandleMFATokenSignIn))
+	mux.HandleFunc("POST /api/token/refresh", h.Auth.HandleTokenRefresh)
+	mux.HandleFunc("POST /api/token/revoke", h.Auth.HandleTokenRevoke)
+	mux.HandleFunc("POST /api/verify-email", h.Auth.HandleVerifyEmail)
+	credentials("POST /api/verify-email/resend", http.HandlerFunc(h.Auth.HandleResendVerification))
+	credentials("POST /api/password/forgot", http.HandlerFunc(h.Auth.HandleForgotPassword))
+	credentials("POST /api/password/reset", http.HandlerFunc(h.Auth.HandleResetPassword))
+	mux.Handle("GET /api/me", middleware.RequireAuth(http.HandlerFunc(h.Auth.HandleCurrentUser)))
+	credentials("POST /api/me/password", middleware.RequireAuth(http.HandlerFunc(h.Auth.HandleChangePassword)))
+	credentials("POST /api/me/email", middleware.RequireAuth(http.HandlerFunc(h.Auth.HandleChangeEmail)))
+	credentials("POST /api/me/mfa/totp", middleware.RequireAuth(http.HandlerFunc(h.Auth.HandleEnrollTOTP)))
+	credentials("POST /api/me/mfa/totp/confirm", middleware.RequireAuth(http.HandlerFunc(h.Auth.HandleConfirmTOTP)))
+	credentials("POST /api/me/mfa/totp/disable", middleware.RequireAuth(http.HandlerFunc(h.Auth.HandleDisableTOTP)))
+	credentials("POST /api/me/mfa/recovery-codes",
+		middleware.RequireAuth(http.HandlerFunc(h.Auth.HandleRegenerateRecoveryCodes)))
+	credentials("POST /api/me/passkeys/options",
+		middleware.RequireAuth(http.HandlerFunc(h.Auth.HandlePasskeyRegistrationOptions)))
+	mux.Handle("POST /api/me/passkeys", middleware.RequireAuth(http.HandlerFunc(h.Auth.HandleRegisterPasskey)))
+	mux.Handle("GET /api/me/passkeys", middleware.RequireAuth(http.HandlerFunc(h.Auth.HandleListPasskeys)))
+	mux.Handle("DELETE /api/me/passkeys/{id}", middleware.RequireAuth(http.HandlerFunc(h.Auth.HandleDeletePasskey)))
+	mux.Handle("GET /api/me/identities", middleware.RequireAuth(http.HandlerFunc(h.Auth.HandleListIdentities)))
+
+	if h.OAuth != nil {
+		mux.HandleFunc("GET /.well-known/oauth-authorization-server", h.OAuth.HandleMetadata)
+		mux.HandleFunc("GET /oauth/jwks", h.OAuth.HandleJWKS)
+		mux.HandleFunc("POST /oauth/register", h.OAuth.HandleRegisterClient)
+		mux.HandleFunc("POST /oauth/token", h.OAuth.HandleToken)
+		mux.HandleFunc("POST /oauth/introspect", h.OAuth.HandleIntrospect)
+		mux.HandleFunc("POST /oauth/revoke", h.OAuth.HandleRevoke)
+		mux.Handle("GET /api/oauth/authorize", middleware.RequireAuth(http.HandlerFunc(h.OAuth.HandleAuthorize)))
+		mux.Handle("POST /api/oauth/authorize", middleware.RequireAuth(http.HandlerFunc(h.OAuth.HandleDecide)))
+	}
+
+	if h.Roles != nil {
+		read := middleware.RequirePermission(h.Authorizer, service.ActionRead, service.ResourceRoles)
+		write := middleware.RequirePermission(h.Authorizer, service.ActionWrite, service.ResourceRoles)
+
+		mux.Handle("GET /api/admin/roles", read(http.HandlerFunc(h.Roles.HandleListRoles)))
+		mux.Handle("POST /api/admin/roles", write(http.HandlerFunc(h.Roles.HandleCreateRole)))
+		mux.Handle("GET /api/admin/users/{id}/roles", read(http.HandlerFunc(h.Roles.HandleListUserRoles)))
+		mux.Handle("PUT /api/admin/users/{id}/roles/{role}", write(http.HandlerFunc(h.Roles.HandleAssignRole)))
+		mux.Handle("DELETE /api/admin/users/{id}/roles/{role}", write(http.HandlerFunc(h.Roles.HandleUnassignRole)))
+	}
+
+	if h.Organizations != nil {
+		scoped := func(action, resource string, next http.HandlerFunc) http.Handler {
+			return middleware.RequireOrganization(h.Tenants)(middleware.RequirePermission(h.Authorizer, action, resource)(next))
+		}
+
+		mux.Handle("POST /api/orgs", middleware.RequireAuth(http.HandlerFunc(h.Organizations.HandleCreateOrganization)))
+		mux.Handle("GET /api/orgs", middleware.RequireAuth(http.HandlerFunc(h.Organizations.HandleListOrganizations)))
+		mux.Handle("GET /api/orgs/{org}",
+			scoped(service.ActionRead, service.ResourceOrganization, h.Organizations.HandleGetOrganization))
+		mux.Handle("GET /api/orgs/{org}/members",
+			scoped(service.ActionRead, service.ResourceMembers, h.Organizations.HandleListMembers))
+		mux.Handle("PUT /api/orgs/{org}/members/{id}",
+			scoped(service.ActionWrite, service.ResourceMembers, h.Organizations.HandleUpdateMember))
+		mux.Handle("DELETE /api/orgs/{org}/members/{id}",
+			scoped(service.ActionWrite, service.ResourceMembers, h.Organizations.HandleRemoveMember))
+		credentials("POST /api/orgs/{org}/invitations",
+			scoped(service.ActionWrite, service.ResourceInvitations, h.Organizations.HandleInvite))
+		credentials("POST /api/invitations/accept", http.HandlerFunc(h.Organizations.HandleAcceptInvitation))
+	}
+
+	api := h.RateLimiter.Limit("api", h.RateLimits.API, middleware.KeyByUser)(mux)
+
+	return h.AuthMiddleware.LoadUser(api)
+}
@@ -0,0 +1,569 @@
+package router_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/http/handler"
+	"github.com/ferdiebergado/fullstackgo/internal/http/handler/mocks"
+	"github.com/ferdiebergado/fullstackgo/internal/http/middleware"
+	"github.com/ferdiebergado/fullstackgo/internal/http/router"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/ratelimit"
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	svcMocks "github.com/ferdiebergado/fullstackgo/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testID    = "1"
+	testToken = "token"
+)
+
+type routerMocks struct {
+	auth     *mocks.MockAuthHandler
+	sessions *svcMocks.MockSessionService
+	users    *repoMocks.MockUserRepo
+}
+
+func TestRouter_AuthRoutes(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		token  string
+		setup  func(m routerMocks)
+		status int
+	}{
+		{"signup should be routed to the signup handler", http.MethodPost, "/api/signup", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleUserSignUp(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusCreated) })
+			}, http.StatusCreated},
+		{"signin should be routed to the signin handler", http.MethodPost, "/api/signin", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleUserSignIn(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"signout should be routed to the signout handler", http.MethodPost, "/api/signout", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleUserSignOut(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"token sign-in should be routed to the token handler", http.MethodPost, "/api/token", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleTokenSignIn(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"token refresh should be routed to the refresh handler", http.MethodPost, "/api/token/refresh", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleTokenRefresh(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"token revoke should be routed to the revoke handler", http.MethodPost, "/api/token/revoke", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleTokenRevoke(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"verify email should be routed to the verify email handler", http.MethodPost, "/api/verify-email", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleVerifyEmail(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"resend verification should be routed to the resend handler", http.MethodPost, "/api/verify-email/resend", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleResendVerification(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusAccepted) })
+			}, http.StatusAccepted},
+		{"forgot password should be routed to the forgot password handler", http.MethodPost, "/api/password/forgot", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleForgotPassword(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusAccepted) })
+			}, http.StatusAccepted},
+		{"reset password should be routed to the reset password handler", http.MethodPost, "/api/password/reset", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleResetPassword(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"me should require authentication", http.MethodGet, "/api/me", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleCurrentUser(gomock.Any(), gomock.Any()).Times(0)
+			}, http.StatusUnauthorized},
+		{"me should be routed to the current user handler when authenticated", http.MethodGet, "/api/me", testToken,
+			func(m routerMocks) {
+				m.sessions.EXPECT().ValidateSession(gomock.Any(), testToken).Return(&model.Session{
+					UserID:    testID,
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, nil)
+				m.users.EXPECT().FindUserByID(gomock.Any(), testID).Return(&model.User{ID: testID}, nil)
+				m.auth.EXPECT().HandleCurrentUser(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"change password should require authentication", http.MethodPost, "/api/me/password", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleChangePassword(gomock.Any(), gomock.Any()).Times(0)
+			}, http.StatusUnauthorized},
+		{"change email should require authentication", http.MethodPost, "/api/me/email", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleChangeEmail(gomock.Any(), gomock.Any()).Times(0)
+			}, http.StatusUnauthorized},
+		{"change email should be routed to the change email handler when authenticated", http.MethodPost,
+			"/api/me/email", testToken,
+			func(m routerMocks) {
+				m.sessions.EXPECT().ValidateSession(gomock.Any(), testToken).Return(&model.Session{
+					UserID:    testID,
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, nil)
+				m.users.EXPECT().FindUserByID(gomock.Any(), testID).Return(&model.User{ID: testID}, nil)
+				m.auth.EXPECT().HandleChangeEmail(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"mfa signin should be routed to the mfa signin handler", http.MethodPost, "/api/signin/mfa", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleMFASignIn(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"mfa token sign-in should be routed to the mfa token handler", http.MethodPost, "/api/token/mfa", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleMFATokenSignIn(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"totp enrollment should require authentication", http.MethodPost, "/api/me/mfa/totp", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleEnrollTOTP(gomock.Any(), gomock.Any()).Times(0)
+			}, http.StatusUnauthorized},
+		{"recovery codes should require authentication", http.MethodPost, "/api/me/mfa/recovery-codes", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleRegenerateRecoveryCodes(gomock.Any(), gomock.Any()).Times(0)
+			}, http.StatusUnauthorized},
+		{"totp disable should be routed to the disable handler when authenticated", http.MethodPost,
+			"/api/me/mfa/totp/disable", testToken,
+			func(m routerMocks) {
+				m.sessions.EXPECT().ValidateSession(gomock.Any(), testToken).Return(&model.Session{
+					UserID:    testID,
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, nil)
+				m.users.EXPECT().FindUserByID(gomock.Any(), testID).Return(&model.User{ID: testID}, nil)
+				m.auth.EXPECT().HandleDisableTOTP(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"passkey signin options should be routed to the options handler", http.MethodPost,
+			"/api/signin/passkey/options", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandlePasskeySignInOptions(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"passkey signin should be routed to the passkey signin handler", http.MethodPost, "/api/signin/passkey", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandlePasskeySignIn(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"passkey registration should require authentication", http.MethodPost, "/api/me/passkeys", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleRegisterPasskey(gomock.Any(), gomock.Any()).Times(0)
+			}, http.StatusUnauthorized},
+		{"passkey deletion should be routed to the delete handler when authenticated", http.MethodDelete,
+			"/api/me/passkeys/abc", testToken,
+			func(m routerMocks) {
+				m.sessions.EXPECT().ValidateSession(gomock.Any(), testToken).Return(&model.Session{
+					UserID:    testID,
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, nil)
+				m.users.EXPECT().FindUserByID(gomock.Any(), testID).Return(&model.User{ID: testID}, nil)
+				m.auth.EXPECT().HandleDeletePasskey(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, r *http.Request) {
+						if r.PathValue("id") != "abc" {
+							w.WriteHeader(http.StatusBadRequest)
+							return
+						}
+						w.WriteHeader(http.StatusOK)
+					})
+			}, http.StatusOK},
+		{"oidc signin should be routed to the oidc handler with the provider", http.MethodPost,
+			"/api/signin/oidc/google", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleOIDCSignIn(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, r *http.Request) {
+						if r.PathValue("provider") != "google" {
+							w.WriteHeader(http.StatusBadRequest)
+							return
+						}
+						w.WriteHeader(http.StatusOK)
+					})
+			}, http.StatusOK},
+		{"oidc callback should be routed to the callback handler", http.MethodPost,
+			"/api/signin/oidc/google/callback", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleOIDCCallback(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"magic link verification should be routed to the magic link handler", http.MethodPost,
+			"/api/signin/magic-link/verify", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleMagicLinkSignIn(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+		{"linked identities should require authentication", http.MethodGet, "/api/me/identities", "",
+			func(m routerMocks) {
+				m.auth.EXPECT().HandleListIdentities(gomock.Any(), gomock.Any()).Times(0)
+			}, http.StatusUnauthorized},
+		{"signup should not accept GET", http.MethodGet, "/api/signup", "",
+			func(_ routerMocks) {}, http.StatusMethodNotAllowed},
+		{"unknown routes should return not found", http.MethodPost, "/api/unknown", "",
+			func(_ routerMocks) {}, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := routerMocks{
+				auth:     mocks.NewMockAuthHandler(ctrl),
+				sessions: svcMocks.NewMockSessionService(ctrl),
+				users:    repoMocks.NewMockUserRepo(ctrl),
+			}
+			tt.setup(m)
+
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rr := httptest.NewRecorder()
+
+			r := router.New(router.Handlers{
+				Auth:           m.auth,
+				AuthMiddleware: middleware.NewAuth(m.sessions, nil, m.users, handler.SessionCookie{}),
+			})
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func TestRouter_OAuthRoutes(t *testing.T) {
+	ok := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		token  string
+		setup  func(m routerMocks, oauth *mocks.MockOAuthHandler)
+		status int
+	}{
+		{"metadata should be routed to the metadata handler", http.MethodGet,
+			"/.well-known/oauth-authorization-server", "",
+			func(_ routerMocks, oauth *mocks.MockOAuthHandler) {
+				oauth.EXPECT().HandleMetadata(gomock.Any(), gomock.Any()).Do(ok)
+			}, http.StatusOK},
+		{"jwks should be routed to the jwks handler", http.MethodGet, "/oauth/jwks", "",
+			func(_ routerMocks, oauth *mocks.MockOAuthHandler) {
+				oauth.EXPECT().HandleJWKS(gomock.Any(), gomock.Any()).Do(ok)
+			}, http.StatusOK},
+		{"token requests should be routed to the token handler", http.MethodPost, "/oauth/token", "",
+			func(_ routerMocks, oauth *mocks.MockOAuthHandler) {
+				oauth.EXPECT().HandleToken(gomock.Any(), gomock.Any()).Do(ok)
+			}, http.StatusOK},
+		{"introspection should be routed to the introspection handler", http.MethodPost, "/oauth/introspect", "",
+			func(_ routerMocks, oauth *mocks.MockOAuthHandler) {
+				oauth.EXPECT().HandleIntrospect(gomock.Any(), gomock.Any()).Do(ok)
+			}, http.StatusOK},
+		{"revocation should be routed to the revocation handler", http.MethodPost, "/oauth/revoke", "",
+			func(_ routerMocks, oauth *mocks.MockOAuthHandler) {
+				oauth.EXPECT().HandleRevoke(gomock.Any(), gomock.Any()).Do(ok)
+			}, http.StatusOK},
+		{"registration should be routed to the registration handler", http.MethodPost, "/oauth/register", "",
+			func(_ routerMocks, oauth *mocks.MockOAuthHandler) {
+				oauth.EXPECT().HandleRegisterClient(gomock.Any(), gomock.Any()).Do(ok)
+			}, http.StatusOK},
+		{"consent should require authentication", http.MethodGet, "/api/oauth/authorize", "",
+			func(_ routerMocks, oauth *mocks.MockOAuthHandler) {
+				oauth.EXPECT().HandleAuthorize(gomock.Any(), gomock.Any()).Times(0)
+			}, http.StatusUnauthorized},
+		{"decision should be routed to the decision handler when authenticated", http.MethodPost,
+			"/api/oauth/authorize", testToken,
+			func(m routerMocks, oauth *mocks.MockOAuthHandler) {
+				m.sessions.EXPECT().ValidateSession(gomock.Any(), testToken).Return(&model.Session{
+					UserID:    testID,
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, nil)
+				m.users.EXPECT().FindUserByID(gomock.Any(), testID).Return(&model.User{ID: testID}, nil)
+				oauth.EXPECT().HandleDecide(gomock.Any(), gomock.Any()).Do(ok)
+			}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := routerMocks{
+				auth:     mocks.NewMockAuthHandler(ctrl),
+				sessions: svcMocks.NewMockSessionService(ctrl),
+				users:    repoMocks.NewMockUserRepo(ctrl),
+			}
+			oauth := mocks.NewMockOAuthHandler(ctrl)
+			tt.setup(m, oauth)
+
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rr := httptest.NewRecorder()
+
+			r := router.New(router.Handlers{
+				Auth:           m.auth,
+				AuthMiddleware: middleware.NewAuth(m.sessions, nil, m.users, handler.SessionCookie{}),
+				OAuth:          oauth,
+			})
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+
+	t.Run("routes should not be mounted without a handler", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		r := router.New(router.Handlers{
+			Auth:           mocks.NewMockAuthHandler(ctrl),
+			AuthMiddleware: middleware.NewAuth(svcMocks.NewMockSessionService(ctrl), nil, nil, handler.SessionCookie{}),
+		})
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/oauth/token", nil))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code, "Response status code should match")
+	})
+}
+
+func TestRouter_AdminRoutes(t *testing.T) {
+	ok := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }
+	signedIn := func(m routerMocks) {
+		m.sessions.EXPECT().ValidateSession(gomock.Any(), testToken).Return(&model.Session{
+			UserID:    testID,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+		m.users.EXPECT().FindUserByID(gomock.Any(), testID).Return(&model.User{ID: testID}, nil)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		token  string
+		setup  func(m routerMocks, roles *mocks.MockRoleHandler, authz *svcMocks.MockAuthorizer)
+		status int
+	}{
+		{"admin routes should require authentication", http.MethodGet, "/api/admin/roles", "",
+			func(_ routerMocks, roles *mocks.MockRoleHandler, _ *svcMocks.MockAuthorizer) {
+				roles.EXPECT().HandleListRoles(gomock.Any(), gomock.Any()).Times(0)
+			}, http.StatusUnauthorized},
+		{"listing roles should require permission to read roles", http.MethodGet, "/api/admin/roles", testToken,
+			func(m routerMocks, roles *mocks.MockRoleHandler, authz *svcMocks.MockAuthorizer) {
+				signedIn(m)
+				authz.EXPECT().Can(gomock.Any(), gomock.Any(), service.ActionRead, service.ResourceRoles).Return(false, nil)
+				roles.EXPECT().HandleListRoles(gomock.Any(), gomock.Any()).Times(0)
+			}, http.StatusForbidden},
+		{"assigning a role should be routed with the user and role", http.MethodPut,
+			"/api/admin/users/abc/roles/editor", testToken,
+			func(m routerMocks, roles *mocks.MockRoleHandler, authz *svcMocks.MockAuthorizer) {
+				signedIn(m)
+				authz.EXPECT().Can(gomock.Any(), gomock.Any(), service.ActionWrite, service.ResourceRoles).Return(true, nil)
+				roles.EXPECT().HandleAssignRole(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, r *http.Request) {
+						if r.PathValue("id") != "abc" || r.PathValue("role") != "editor" {
+							w.WriteHeader(http.StatusBadRequest)
+							return
+						}
+						w.WriteHeader(http.StatusOK)
+					})
+			}, http.StatusOK},
+		{"unassigning a role should require permission to write roles", http.MethodDelete,
+			"/api/admin/users/abc/roles/editor", testToken,
+			func(m routerMocks, roles *mocks.MockRoleHandler, authz *svcMocks.MockAuthorizer) {
+				signedIn(m)
+				authz.EXPECT().Can(gomock.Any(), gomock.Any(), service.ActionWrite, service.ResourceRoles).Return(true, nil)
+				roles.EXPECT().HandleUnassignRole(gomock.Any(), gomock.Any()).Do(ok)
+			}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := routerMocks{
+				auth:     mocks.NewMockAuthHandler(ctrl),
+				sessions: svcMocks.NewMockSessionService(ctrl),
+				users:    repoMocks.NewMockUserRepo(ctrl),
+			}
+			roles := mocks.NewMockRoleHandler(ctrl)
+			authz := svcMocks.NewMockAuthorizer(ctrl)
+			tt.setup(m, roles, authz)
+
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rr := httptest.NewRecorder()
+
+			r := router.New(router.Handlers{
+				Auth:           m.auth,
+				AuthMiddleware: middleware.NewAuth(m.sessions, nil, m.users, handler.SessionCookie{}),
+				Roles:          roles,
+				Authorizer:     authz,
+			})
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
+
+func TestRouter_RateLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := routerMocks{
+		auth:     mocks.NewMockAuthHandler(ctrl),
+		sessions: svcMocks.NewMockSessionService(ctrl),
+		users:    repoMocks.NewMockUserRepo(ctrl),
+	}
+	ok := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }
+	m.auth.EXPECT().HandleUserSignIn(gomock.Any(), gomock.Any()).Do(ok).Times(1)
+	m.auth.EXPECT().HandleUserSignUp(gomock.Any(), gomock.Any()).Do(ok).Times(1)
+	m.auth.EXPECT().HandleUserSignOut(gomock.Any(), gomock.Any()).Do(ok).Times(2)
+
+	r := middleware.ClientIP(false)(router.New(router.Handlers{
+		Auth:           m.auth,
+		AuthMiddleware: middleware.NewAuth(m.sessions, nil, m.users, handler.SessionCookie{}),
+		RateLimiter:    middleware.NewRateLimiter(ratelimit.NewMemoryStore()),
+		RateLimits: router.RateLimits{
+			API:         ratelimit.Limit{Requests: 5, Period: time.Minute},
+			Credentials: ratelimit.Limit{Requests: 1, Period: time.Minute},
+		},
+	}))
+
+	tests := []struct {
+		name   string
+		url    string
+		status int
+	}{
+		{"first signin should be allowed", "/api/signin", http.StatusOK},
+		{"second signin should be limited", "/api/signin", http.StatusTooManyRequests},
+		{"signup should have its own limit", "/api/signup", http.StatusOK},
+		{"signout should only count against the API limit", "/api/signout", http.StatusOK},
+		{"signout should only count against the API limit", "/api/signout", http.StatusOK},
+		{"API limit should apply to every route", "/api/signout", http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, tt.url, nil))
+		assert.Equal(t, tt.status, rr.Code, tt.name)
+	}
+}
+
+func TestRouter_OrganizationRoutes(t *testing.T) {
+	org := &model.Organization{ID: "acme", Name: "Acme"}
+	signedIn := func(m routerMocks) {
+		m.sessions.EXPECT().ValidateSession(gomock.Any(), testToken).Return(&model.Session{
+			UserID:    testID,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+		m.users.EXPECT().FindUserByID(gomock.Any(), testID).Return(&model.User{ID: testID}, nil)
+	}
+
+	type orgMocks struct {
+		handler *mocks.MockOrganizationHandler
+		tenants *svcMocks.MockOrganizationService
+		authz   *svcMocks.MockAuthorizer
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		token  string
+		setup  func(m routerMocks, o orgMocks)
+		status int
+	}{
+		{"creating an organization should require authentication", http.MethodPost, "/api/orgs", "",
+			func(_ routerMocks, o orgMocks) {
+				o.handler.EXPECT().HandleCreateOrganization(gomock.Any(), gomock.Any()).Times(0)
+			}, http.StatusUnauthorized},
+		{"non-members should not find the organization", http.MethodGet, "/api/orgs/acme/members", testToken,
+			func(m routerMocks, o orgMocks) {
+				signedIn(m)
+				o.tenants.EXPECT().MemberOrganization(gomock.Any(), "acme", gomock.Any()).
+					Return(nil, service.ErrOrganizationNotFound)
+				o.handler.EXPECT().HandleListMembers(gomock.Any(), gomock.Any()).Times(0)
+			}, http.StatusNotFound},
+		{"removing a member should require permission to write members", http.MethodDelete,
+			"/api/orgs/acme/members/abc", testToken,
+			func(m routerMocks, o orgMocks) {
+				signedIn(m)
+				o.tenants.EXPECT().MemberOrganization(gomock.Any(), "acme", gomock.Any()).Return(org, nil)
+				o.authz.EXPECT().Can(gomock.Any(), gomock.Any(), service.ActionWrite, service.ResourceMembers).
+					Return(false, nil)
+				o.handler.EXPECT().HandleRemoveMember(gomock.Any(), gomock.Any()).Times(0)
+			}, http.StatusForbidden},
+		{"updating a member should be scoped to the organization", http.MethodPut,
+			"/api/orgs/acme/members/abc", testToken,
+			func(m routerMocks, o orgMocks) {
+				signedIn(m)
+				o.tenants.EXPECT().MemberOrganization(gomock.Any(), "acme", gomock.Any()).Return(org, nil)
+				o.authz.EXPECT().Can(gomock.Any(), gomock.Any(), service.ActionWrite, service.ResourceMembers).DoAndReturn(
+					func(ctx context.Context, _ *model.User, _, _ string) (bool, error) {
+						scoped, ok := service.OrganizationFromContext(ctx)
+						return ok && scoped.ID == org.ID, nil
+					})
+				o.handler.EXPECT().HandleUpdateMember(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, r *http.Request) {
+						if scoped, ok := service.OrganizationFromContext(r.Context()); !ok || scoped.ID != org.ID ||
+							r.PathValue("id") != "abc" {
+							w.WriteHeader(http.StatusBadRequest)
+							return
+						}
+						w.WriteHeader(http.StatusOK)
+					})
+			}, http.StatusOK},
+		{"accepting an invitation should not require authentication", http.MethodPost, "/api/invitations/accept", "",
+			func(_ routerMocks, o orgMocks) {
+				o.handler.EXPECT().HandleAcceptInvitation(gomock.Any(), gomock.Any()).Do(
+					func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := routerMocks{
+				auth:     mocks.NewMockAuthHandler(ctrl),
+				sessions: svcMocks.NewMockSessionService(ctrl),
+				users:    repoMocks.NewMockUserRepo(ctrl),
+			}
+			o := orgMocks{
+				handler: mocks.NewMockOrganizationHandler(ctrl),
+				tenants: svcMocks.NewMockOrganizationService(ctrl),
+				authz:   svcMocks.NewMockAuthorizer(ctrl),
+			}
+			tt.setup(m, o)
+
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rr := httptest.NewRecorder()
+
+			r := router.New(router.Handlers{
+				Auth:           m.auth,
+				AuthMiddleware: middleware.NewAuth(m.sessions, nil, m.users, handler.SessionCookie{}),
+				Authorizer:     o.authz,
+				Organizations:  o.handler,
+				Tenants:        o.tenants,
+			})
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code, "Response status code should match")
+		})
+	}
+}
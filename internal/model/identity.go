@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Identity links a user to an account at an OpenID provider. Subject is the
+// provider's stable ID for the account, and Email the address it last
+// reported.
+type Identity struct {
+	Provider   string     `json:"provider"`
+	Subject    string     `json:"-"`
+	UserID     string     `json:"-"`
+	Email      string     `json:"email"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// OIDCState is an outstanding sign-in with an OpenID provider. The ID is the
+// hash of the state parameter sent to the provider.
+type OIDCState struct {
+	ID           string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+// OIDCAuthorization starts a sign-in with an OpenID provider. The client
+// sends the user to URL and keeps State to prove the callback is theirs.
+type OIDCAuthorization struct {
+	URL   string `json:"authorization_url"`
+	State string `json:"-"`
+}
+
+// OIDCCallbackParams are the query parameters the provider redirected the
+// user back with.
+type OIDCCallbackParams struct {
+	State string `json:"state" validate:"required"`
+	Code  string `json:"code" validate:"required"`
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// LoginAttempt counts consecutive failed sign-ins for an account or client.
+// The ID identifies what is being tracked, such as an email or IP address.
+type LoginAttempt struct {
+	ID           string
+	Failures     int
+	LastFailedAt time.Time
+}
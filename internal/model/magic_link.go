@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// MagicLink is an outstanding passwordless sign-in emailed to a user. The ID
+// is the hash of the token in the link, and Email the address it was sent
+// to. NonceHash is the hash of the nonce held by the browser that asked for
+// the link, or empty when the link may be opened on any device.
+type MagicLink struct {
+	ID        string
+	UserID    string
+	Email     string
+	NonceHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// MagicLinkSignInParams redeems a magic link. Nonce is read from the cookie
+// set when the link was requested, not from the body.
+type MagicLinkSignInParams struct {
+	Token string `json:"token" validate:"required"`
+	Nonce string `json:"-"`
+}
@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+// TOTP is a user's authenticator app enrollment. It only protects sign-ins
+// once ConfirmedAt is set. LastStep is the time step of the last code
+// accepted, so that a code cannot be used twice.
+type TOTP struct {
+	UserID      string     `json:"-"`
+	Secret      string     `json:"-"`
+	LastStep    int64      `json:"-"`
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// MFAChallenge is a sign-in that passed the password check and awaits a
+// second factor. The ID is the hash of the token handed to the client.
+type MFAChallenge struct {
+	ID        string    `json:"-"`
+	UserID    string    `json:"user_id"`
+	Failures  int       `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PendingMFA is returned instead of a session or tokens when a sign-in needs
+// a second factor. The token is exchanged together with a code.
+type PendingMFA struct {
+	Token     string    `json:"mfa_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// TOTPEnrollment holds what a user needs to add the account to an
+// authenticator app: the secret to type in, or the otpauth URI, also rendered
+// as a PNG QR code.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+	QRCode []byte `json:"qr_code"`
+}
+
+// RecoveryCodes are single-use codes that stand in for an authenticator app.
+// They are only shown when generated.
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+type CurrentPasswordParams struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+}
+
+type TOTPCodeParams struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// MFACodeParams confirms a sensitive change with both the password and a
+// code from the authenticator app or a recovery code.
+type MFACodeParams struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	Code            string `json:"code" validate:"required"`
+}
+
+type MFASignInParams struct {
+	Token string `json:"mfa_token" validate:"required"`
+	Code  string `json:"code" validate:"required"`
+}
@@ -0,0 +1,161 @@
+package model
+
+import "time"
+
+// OAuthClient is an application registered to request tokens from the
+// authorization server. Public clients, such as single-page and native apps,
+// have no secret and must use PKCE. SecretHash is the hash of the secret of
+// confidential clients. Scope lists the space-separated scopes the client
+// may request.
+type OAuthClient struct {
+	ID           string
+	SecretHash   string
+	Name         string
+	RedirectURIs []string
+	GrantTypes   []string
+	Scope        string
+	CreatedAt    time.Time
+}
+
+// OAuthCode is an authorization code issued to a client after the user
+// consented. The ID is the hash of the code, and CodeChallenge the S256 PKCE
+// challenge the client's code verifier must match.
+type OAuthCode struct {
+	ID            string
+	ClientID      string
+	UserID        string
+	RedirectURI   string
+	Scope         string
+	CodeChallenge string
+	ExpiresAt     time.Time
+	UsedAt        *time.Time
+	CreatedAt     time.Time
+}
+
+// OAuthToken records an access token issued to a client, by the ID of the
+// JWT, so that it can be revoked. UserID is nil for tokens a client obtained
+// for itself, and CodeID names the authorization code the token was
+// exchanged for, if any.
+type OAuthToken struct {
+	ID        string
+	ClientID  string
+	UserID    *string
+	CodeID    *string
+	Scope     string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// OAuthClientParams is the client metadata of a dynamic client registration
+// request (RFC 7591).
+type OAuthClientParams struct {
+	Name                    string   `json:"client_name" validate:"required,max=100"`
+	RedirectURIs            []string `json:"redirect_uris" validate:"max=10,dive,required,url"`
+	GrantTypes              []string `json:"grant_types"`
+	Scope                   string   `json:"scope" validate:"max=1000"`
+	TokenEndpointAuthMethod string   `json:"token_endpoint_auth_method"`
+}
+
+// OAuthClientRegistration is the response to a client registration. The
+// secret is only ever shown here.
+type OAuthClientRegistration struct {
+	ClientID                string   `json:"client_id"`
+	ClientSecret            string   `json:"client_secret,omitempty"`
+	ClientIDIssuedAt        int64    `json:"client_id_issued_at"`
+	ClientSecretExpiresAt   *int64   `json:"client_secret_expires_at,omitempty"`
+	Name                    string   `json:"client_name"`
+	RedirectURIs            []string `json:"redirect_uris"`
+	GrantTypes              []string `json:"grant_types"`
+	Scope                   string   `json:"scope,omitempty"`
+	TokenEndpointAuthMethod string   `json:"token_endpoint_auth_method"`
+}
+
+// OAuthAuthorizeParams is an authorization request, passed on by the consent
+// page from the query string the client sent the user with.
+type OAuthAuthorizeParams struct {
+	ResponseType        string `json:"response_type"`
+	ClientID            string `json:"client_id"`
+	RedirectURI         string `json:"redirect_uri"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+}
+
+// OAuthConsent describes a valid authorization request for the consent page
+// to show the user.
+type OAuthConsent struct {
+	ClientID    string   `json:"client_id"`
+	ClientName  string   `json:"client_name"`
+	RedirectURI string   `json:"redirect_uri"`
+	Scopes      []string `json:"scopes"`
+}
+
+// OAuthDecisionParams is the user's answer to an authorization request.
+type OAuthDecisionParams struct {
+	OAuthAuthorizeParams
+	Approve bool `json:"approve"`
+}
+
+// OAuthRedirect is where the consent page sends the user back to the client.
+type OAuthRedirect struct {
+	RedirectURI string `json:"redirect_uri"`
+}
+
+// OAuthClientCredentials authenticate a client at the token, introspection
+// and revocation endpoints. Secret is empty for public clients.
+type OAuthClientCredentials struct {
+	ID     string
+	Secret string
+}
+
+// OAuthTokenParams is a token request (RFC 6749 section 4).
+type OAuthTokenParams struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	Scope        string
+}
+
+// OAuthTokenResponse is a successful token response.
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// OAuthIntrospection is the state of a token (RFC 7662). Only Active is set
+// for tokens that are invalid, expired or revoked.
+type OAuthIntrospection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	NotBefore int64  `json:"nbf,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	Audience  string `json:"aud,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+	ID        string `json:"jti,omitempty"`
+}
+
+// OAuthMetadata is the authorization server metadata (RFC 8414).
+type OAuthMetadata struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	RegistrationEndpoint              string   `json:"registration_endpoint,omitempty"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	AuthorizationResponseIssParameter bool     `json:"authorization_response_iss_parameter_supported"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// Organization is a company whose users share its data. Users belong to it
+// through memberships.
+type Organization struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OrganizationParams struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// Membership makes a user a member of an organization. Role names the role
+// whose permissions the user has within that organization. Email is the
+// user's, filled in when members are listed.
+type Membership struct {
+	OrganizationID string    `json:"organization_id"`
+	UserID         string    `json:"user_id"`
+	Email          string    `json:"email,omitempty"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// MembershipParams changes the role of a member.
+type MembershipParams struct {
+	Role string `json:"role" validate:"required,max=50"`
+}
+
+// Invitation is an outstanding invitation to join an organization, emailed
+// to Email. The ID is the hash of the token in the link.
+type Invitation struct {
+	ID             string    `json:"-"`
+	OrganizationID string    `json:"organization_id"`
+	Email          string    `json:"email"`
+	Role           string    `json:"role"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type InvitationParams struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,max=50"`
+}
+
+// AcceptInvitationParams redeems an invitation. The password is only needed,
+// and only checked, when no account has the invited email yet.
+type AcceptInvitationParams struct {
+	Token           string `json:"token" validate:"required"`
+	Password        string `json:"password" validate:"omitempty,password"`
+	PasswordConfirm string `json:"password_confirm" validate:"eqfield=Password"`
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/webauthn"
+)
+
+// Passkey is a WebAuthn credential a user can sign in with instead of a
+// password. The ID is the base64url encoded credential ID, and SignCount the
+// last signature counter the authenticator reported.
+type Passkey struct {
+	ID                string     `json:"id"`
+	UserID            string     `json:"-"`
+	Name              string     `json:"name"`
+	PublicKey         []byte     `json:"-"`
+	SignCount         uint32     `json:"-"`
+	AAGUID            []byte     `json:"-"`
+	AttestationFormat string     `json:"attestation_format"`
+	Transports        []string   `json:"transports"`
+	BackupEligible    bool       `json:"backup_eligible"`
+	BackupState       bool       `json:"backed_up"`
+	LastUsedAt        *time.Time `json:"last_used_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// WebAuthnCeremony is what a WebAuthn challenge was issued for.
+type WebAuthnCeremony string
+
+const (
+	CeremonyRegistration   WebAuthnCeremony = "registration"
+	CeremonyAuthentication WebAuthnCeremony = "authentication"
+)
+
+// WebAuthnChallenge is an outstanding registration or sign-in with a
+// passkey. The ID is the hash of the challenge sent to the browser. UserID
+// is nil for sign-ins, where the passkey identifies the user.
+type WebAuthnChallenge struct {
+	ID        string
+	UserID    *string
+	Ceremony  WebAuthnCeremony
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+type PasskeyRegistrationParams struct {
+	Name       string                        `json:"name" validate:"max=64"`
+	Credential *webauthn.AttestationResponse `json:"credential" validate:"required"`
+}
+
+type PasskeySignInParams struct {
+	Credential *webauthn.AssertionResponse `json:"credential" validate:"required"`
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// RoleScope is where a role is given: to users across the app, or to members
+// within an organization.
+type RoleScope string
+
+const (
+	RoleScopeGlobal       RoleScope = "global"
+	RoleScopeOrganization RoleScope = "organization"
+)
+
+// Role is a named set of permissions granted to the users it is assigned to,
+// or to the members of organizations for roles scoped to organizations.
+type Role struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Scope       RoleScope    `json:"scope"`
+	Permissions []Permission `json:"permissions"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// Permission allows an action, such as "read", on a kind of resource, such
+// as "roles". Either may be "*" to match any action or resource.
+type Permission struct {
+	Action   string `json:"action" validate:"required,max=50"`
+	Resource string `json:"resource" validate:"required,max=50"`
+}
+
+type RoleParams struct {
+	Name        string       `json:"name" validate:"required,max=50"`
+	Description string       `json:"description" validate:"max=200"`
+	Scope       RoleScope    `json:"scope" validate:"omitempty,oneof=global organization"`
+	Permissions []Permission `json:"permissions" validate:"max=100,dive"`
+}
+
+// UserRoles lists the names of the roles assigned to a user.
+type UserRoles struct {
+	UserID string   `json:"user_id"`
+	Roles  []string `json:"roles"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Session is a server-side sign-in session. The ID is the hash of the opaque
+// token handed to the client, never the token itself.
+type Session struct {
+	ID        string    `json:"-"`
+	UserID    string    `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// RefreshToken is a long-lived credential exchanged for new access tokens.
+// Tokens issued from the same sign-in share a FamilyID so that the whole
+// chain can be revoked when a rotated token is replayed.
+type RefreshToken struct {
+	ID        string     `json:"-"`
+	UserID    string     `json:"user_id"`
+	FamilyID  string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshTokenParams struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
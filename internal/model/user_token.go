@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// TokenPurpose scopes a UserToken to the flow that issued it.
+type TokenPurpose string
+
+const (
+	TokenPurposeVerifyEmail   TokenPurpose = "verify_email"
+	TokenPurposeResetPassword TokenPurpose = "reset_password"
+)
+
+// UserToken is a single-use token emailed to a user. The ID is the hash of
+// the token, and Email is the address it was sent to.
+type UserToken struct {
+	ID        string       `json:"-"`
+	UserID    string       `json:"user_id"`
+	Purpose   TokenPurpose `json:"purpose"`
+	Email     string       `json:"email"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
@@ -0,0 +1,50 @@
+package mail_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/mail"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testFrom = "no-reply@example.com"
+	testTo   = "abc@example.com"
+)
+
+func TestMessage_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     mail.Message
+		wantErr bool
+	}{
+		{"valid message should be accepted", mail.Message{To: testTo, Subject: "Hello"}, false},
+		{"invalid recipient should be rejected", mail.Message{To: "not an email", Subject: "Hello"}, true},
+		{"subject with line breaks should be rejected", mail.Message{To: testTo, Subject: "Hi\r\nBcc: x@example.com"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, mail.ErrInvalidMessage, "errors should match")
+			} else {
+				assert.NoError(t, err, "validate should not return an error")
+			}
+		})
+	}
+}
+
+func TestMessage_Bytes(t *testing.T) {
+	msg := mail.Message{To: testTo, Subject: "Hello", Body: "line one\nline two"}
+	date := time.Date(2025, 2, 15, 10, 0, 0, 0, time.UTC)
+
+	out := string(msg.Bytes(testFrom, date))
+
+	assert.Contains(t, out, "From: "+testFrom+"\r\n", "from header should be set")
+	assert.Contains(t, out, "To: "+testTo+"\r\n", "to header should be set")
+	assert.Contains(t, out, "Date: "+date.Format(time.RFC1123Z)+"\r\n", "date header should be set")
+	assert.True(t, strings.HasSuffix(out, "\r\n\r\nline one\r\nline two"), "body should use CRLF line endings")
+}
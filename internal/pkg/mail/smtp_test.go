@@ -0,0 +1,81 @@
+package mail_test
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/mail"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSMTPMailer_Send(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan []string, 1)
+	go serveSMTP(t, ln, received)
+
+	addr := ln.Addr().(*net.TCPAddr)
+	mailer := mail.NewSMTPMailer(mail.SMTPConfig{Host: "127.0.0.1", Port: addr.Port, From: testFrom})
+
+	err = mailer.Send(context.Background(), mail.Message{To: testTo, Subject: "Hello", Body: "body"})
+	assert.NoError(t, err, "send should not return an error")
+
+	commands := <-received
+	assert.Contains(t, commands, "MAIL FROM:<"+testFrom+">", "sender should match")
+	assert.Contains(t, commands, "RCPT TO:<"+testTo+">", "recipient should match")
+	assert.Contains(t, commands, "To: "+testTo, "message should be delivered")
+}
+
+// serveSMTP accepts a single connection and speaks just enough SMTP to
+// receive one message, reporting the lines the client sent.
+func serveSMTP(t *testing.T, ln net.Listener, received chan<- []string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Errorf("accept: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	var lines []string
+	reply := func(code int, msg string) { _ = tp.PrintfLine("%d %s", code, msg) }
+
+	reply(220, "localhost ready")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			break
+		}
+		lines = append(lines, line)
+
+		verb, _, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO", "MAIL", "RCPT":
+			reply(250, "OK")
+		case "DATA":
+			reply(354, "go ahead")
+			body, err := tp.ReadDotLines()
+			if err != nil {
+				t.Errorf("read data: %v", err)
+				return
+			}
+			lines = append(lines, body...)
+			reply(250, "queued")
+		case "QUIT":
+			reply(221, "bye")
+			received <- lines
+			return
+		default:
+			reply(502, "not implemented")
+		}
+	}
+
+	received <- lines
+}
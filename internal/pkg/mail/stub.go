@@ -0,0 +1,88 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// MemoryMailer keeps sent messages in memory. It is meant for tests.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+var _ Mailer = (*MemoryMailer)(nil)
+
+// Send implements Mailer.
+func (m *MemoryMailer) Send(_ context.Context, msg Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+
+	return nil
+}
+
+// Messages returns a copy of the messages sent so far.
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Message(nil), m.messages...)
+}
+
+// FileMailer writes each message to an .eml file in a directory instead of
+// delivering it, for local development without an SMTP server.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+var _ Mailer = (*FileMailer)(nil)
+
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{
+		dir:  dir,
+		from: from,
+	}
+}
+
+// Send implements Mailer.
+func (m *FileMailer) Send(_ context.Context, msg Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0o700); err != nil {
+		return fmt.Errorf("create mail directory: %w", err)
+	}
+
+	now := time.Now()
+	f, err := os.CreateTemp(m.dir, now.UTC().Format("20060102T150405")+"-*.eml")
+	if err != nil {
+		return fmt.Errorf("create mail file: %w", err)
+	}
+
+	if _, err := f.Write(msg.Bytes(m.from, now)); err != nil {
+		f.Close()
+		return fmt.Errorf("write mail file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close mail file: %w", err)
+	}
+
+	return nil
+}
+
+// Dir returns the directory messages are written to.
+func (m *FileMailer) Dir() string {
+	return filepath.Clean(m.dir)
+}
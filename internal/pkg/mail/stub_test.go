@@ -0,0 +1,37 @@
+package mail_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/mail"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryMailer_Send(t *testing.T) {
+	mailer := &mail.MemoryMailer{}
+	msg := mail.Message{To: testTo, Subject: "Hello", Body: "body"}
+
+	assert.NoError(t, mailer.Send(context.Background(), msg), "send should not return an error")
+	assert.Error(t, mailer.Send(context.Background(), mail.Message{To: "invalid"}), "invalid message should be rejected")
+
+	assert.Equal(t, []mail.Message{msg}, mailer.Messages(), "messages should match")
+}
+
+func TestFileMailer_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer := mail.NewFileMailer(dir, testFrom)
+
+	err := mailer.Send(context.Background(), mail.Message{To: testTo, Subject: "Hello", Body: "body"})
+	assert.NoError(t, err, "send should not return an error")
+
+	files, err := filepath.Glob(filepath.Join(mailer.Dir(), "*.eml"))
+	assert.NoError(t, err, "glob should not return an error")
+	if assert.Len(t, files, 1, "one file should be written") {
+		data, err := os.ReadFile(files[0])
+		assert.NoError(t, err, "read should not return an error")
+		assert.Contains(t, string(data), "To: "+testTo, "file should contain the message")
+	}
+}
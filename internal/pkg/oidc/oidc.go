@@ -0,0 +1,387 @@
+// Package oidc implements the relying party side of OpenID Connect: provider
+// discovery, the authorization code flow with PKCE, and verification of ID
+// tokens against the provider's published keys.
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// DiscoveryPath is appended to the issuer to find its metadata.
+	DiscoveryPath = "/.well-known/openid-configuration"
+	// VerifierLength is the number of random bytes in a PKCE code verifier.
+	VerifierLength = 32
+	// ClockSkew is the leeway allowed when checking token timestamps.
+	ClockSkew = time.Minute
+	// maxResponseSize caps the bodies read from the provider.
+	maxResponseSize = 1 << 20
+	// defaultTimeout bounds requests to the provider when no client is set.
+	defaultTimeout = 10 * time.Second
+)
+
+var (
+	// ErrInvalidResponse is returned when the provider answers with a
+	// malformed or unexpected document.
+	ErrInvalidResponse = errors.New("oidc: invalid provider response")
+	// ErrTokenRequest is returned when the provider refuses to exchange a code.
+	ErrTokenRequest = errors.New("oidc: token request failed")
+	// ErrInvalidIDToken is returned when an ID token fails verification.
+	ErrInvalidIDToken = errors.New("oidc: invalid id token")
+)
+
+// DefaultScopes are requested when a Config names none.
+var DefaultScopes = []string{"openid", "email", "profile"}
+
+// signingMethods are the ID token algorithms accepted. Symmetric algorithms
+// and "none" are not, so a token can only be signed with a published key.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "EdDSA"}
+
+// Config identifies the app to a provider.
+type Config struct {
+	// Issuer is the provider's issuer URL, such as "https://accounts.google.com".
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL receives the user back from the provider.
+	RedirectURL string
+	// Scopes default to DefaultScopes.
+	Scopes []string
+	// HTTPClient defaults to a client with a 10 second timeout.
+	HTTPClient *http.Client
+}
+
+// Metadata is the part of a provider's discovery document the flow uses.
+type Metadata struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	CodeChallengeMethods  []string `json:"code_challenge_methods_supported,omitempty"`
+}
+
+// Discover fetches the discovery document of an issuer.
+func Discover(ctx context.Context, client *http.Client, issuer string) (*Metadata, error) {
+	var meta Metadata
+	if err := getJSON(ctx, client, strings.TrimSuffix(issuer, "/")+DiscoveryPath, &meta); err != nil {
+		return nil, fmt.Errorf("fetch discovery document: %w", err)
+	}
+
+	// The issuer must match exactly, so one provider cannot vouch for another.
+	if meta.Issuer != issuer {
+		return nil, fmt.Errorf("%w: issuer %q does not match %q", ErrInvalidResponse, meta.Issuer, issuer)
+	}
+
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: discovery document is missing endpoints", ErrInvalidResponse)
+	}
+
+	return &meta, nil
+}
+
+// Provider signs users in with one OpenID provider. Its metadata is
+// discovered on first use, so the app starts while the provider is down.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu   sync.Mutex
+	meta *Metadata
+	keys *keySet
+}
+
+// NewProvider returns a provider for cfg.
+func NewProvider(cfg Config) *Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = DefaultScopes
+	}
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+
+	return &Provider{
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+// AuthRequest holds the values that bind an authorization response to the
+// request that started it. All three are secret and single-use.
+type AuthRequest struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// NewAuthRequest returns an AuthRequest with random values. They are
+// unpadded base64url, the character set PKCE allows for verifiers.
+func NewAuthRequest() (*AuthRequest, error) {
+	var values [3]string
+	for i := range values {
+		b, err := security.GenerateRandomBytes(VerifierLength)
+		if err != nil {
+			return nil, fmt.Errorf("generate auth request: %w", err)
+		}
+
+		values[i] = base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	return &AuthRequest{
+		State:        values[0],
+		Nonce:        values[1],
+		CodeVerifier: values[2],
+	}, nil
+}
+
+// CodeChallenge returns the S256 PKCE challenge of a code verifier.
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns the URL to send the user to for req.
+func (p *Provider) AuthCodeURL(ctx context.Context, req *AuthRequest) (string, error) {
+	meta, _, err := p.metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(meta.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: authorization endpoint: %w", ErrInvalidResponse, err)
+	}
+
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", req.State)
+	q.Set("nonce", req.Nonce)
+	q.Set("code_challenge", CodeChallenge(req.CodeVerifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// Token is a successful token response.
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// tokenError is an error response from the token endpoint.
+type tokenError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// Exchange trades an authorization code for tokens.
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (*Token, error) {
+	meta, _, err := p.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {verifier},
+		"client_id":     {p.cfg.ClientID},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		// RFC 6749 section 2.3.1 form-encodes the credentials first.
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send token request: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("read token response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		var tokenErr tokenError
+		if err := json.Unmarshal(body, &tokenErr); err != nil || tokenErr.Code == "" {
+			return nil, fmt.Errorf("%w: status %d", ErrTokenRequest, res.StatusCode)
+		}
+
+		return nil, fmt.Errorf("%w: %s: %s", ErrTokenRequest, tokenErr.Code, tokenErr.Description)
+	}
+
+	var token Token
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("%w: token response: %w", ErrInvalidResponse, err)
+	}
+
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("%w: token response has no id_token", ErrInvalidResponse)
+	}
+
+	return &token, nil
+}
+
+// IDToken holds the verified claims of an ID token.
+type IDToken struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	IssuedAt      time.Time
+	ExpiresAt     time.Time
+}
+
+// idTokenClaims are the claims read from an ID token.
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce           string   `json:"nonce"`
+	AuthorizedParty string   `json:"azp"`
+	Email           string   `json:"email"`
+	EmailVerified   flexBool `json:"email_verified"`
+	Name            string   `json:"name"`
+}
+
+// VerifyIDToken checks the signature, issuer, audience, lifetime and nonce of
+// an ID token and returns its claims.
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (*IDToken, error) {
+	meta, keys, err := p.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims idTokenClaims
+	_, err = jwt.ParseWithClaims(raw, &claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return keys.key(ctx, kid)
+	},
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(ClockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	if claims.Subject == "" || claims.IssuedAt == nil {
+		return nil, fmt.Errorf("%w: missing sub or iat", ErrInvalidIDToken)
+	}
+
+	// A token for several audiences must name the app as the party it was
+	// issued to.
+	if (len(claims.Audience) > 1 || claims.AuthorizedParty != "") && claims.AuthorizedParty != p.cfg.ClientID {
+		return nil, fmt.Errorf("%w: azp does not match the client", ErrInvalidIDToken)
+	}
+
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	return &IDToken{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+		IssuedAt:      claims.IssuedAt.Time,
+		ExpiresAt:     claims.ExpiresAt.Time,
+	}, nil
+}
+
+// metadata returns the provider's discovery document and key set,
+// discovering them on first use. A failed discovery is retried on the next
+// call.
+func (p *Provider) metadata(ctx context.Context) (*Metadata, *keySet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.meta == nil {
+		meta, err := Discover(ctx, p.client, p.cfg.Issuer)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		p.meta = meta
+		p.keys = newKeySet(p.client, meta.JWKSURI)
+	}
+
+	return p.meta, p.keys, nil
+}
+
+// flexBool decodes a JSON boolean, or the string "true" or "false" that some
+// providers send for email_verified.
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "true", `"true"`:
+		*b = true
+	case "false", `"false"`, "null":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+
+	return nil
+}
+
+// getJSON fetches a JSON document into dest.
+func getJSON(ctx context.Context, client *http.Client, url string, dest any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: status %d from %s", ErrInvalidResponse, res.StatusCode, url)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(dest); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/oidc"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testRedirectURL = "https://app.example.com/signin/oidc/test/callback"
+	testNonce       = "n-0S6_WzA2Mj"
+)
+
+func newTestProvider(t *testing.T) (*oidctest.Provider, *oidc.Provider) {
+	t.Helper()
+
+	idp := oidctest.New(t, testRedirectURL)
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       idp.Issuer(),
+		ClientID:     oidctest.ClientID,
+		ClientSecret: oidctest.ClientSecret,
+		RedirectURL:  testRedirectURL,
+		HTTPClient:   idp.Client(),
+	})
+
+	return idp, provider
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	idp, provider := newTestProvider(t)
+	ctx := context.Background()
+
+	req, err := oidc.NewAuthRequest()
+	assert.NoError(t, err, "new auth request should not return an error")
+	assert.Len(t, req.CodeVerifier, 43, "code verifier should be unpadded base64url")
+
+	authURL, err := provider.AuthCodeURL(ctx, req)
+	assert.NoError(t, err, "auth code url should not return an error")
+
+	callback := idp.Authorize(t, authURL)
+	assert.Equal(t, req.State, callback.Get("state"), "state should be returned")
+
+	token, err := provider.Exchange(ctx, callback.Get("code"), req.CodeVerifier)
+	assert.NoError(t, err, "exchange should not return an error")
+
+	idToken, err := provider.VerifyIDToken(ctx, token.IDToken, req.Nonce)
+	assert.NoError(t, err, "verify id token should not return an error")
+	assert.Equal(t, idp.Subject, idToken.Subject, "subject should match")
+	assert.Equal(t, idp.Email, idToken.Email, "email should match")
+	assert.True(t, idToken.EmailVerified, "email should be verified")
+	assert.Equal(t, idp.Issuer(), idToken.Issuer, "issuer should match")
+}
+
+func TestProvider_Exchange_Rejects(t *testing.T) {
+	idp, provider := newTestProvider(t)
+	ctx := context.Background()
+
+	req, err := oidc.NewAuthRequest()
+	assert.NoError(t, err, "new auth request should not return an error")
+
+	authURL, err := provider.AuthCodeURL(ctx, req)
+	assert.NoError(t, err, "auth code url should not return an error")
+	code := idp.Authorize(t, authURL).Get("code")
+
+	_, err = provider.Exchange(ctx, code, "wrong-verifier-wrong-verifier-wrong-verifier")
+	assert.ErrorIs(t, err, oidc.ErrTokenRequest, "a wrong code verifier should be rejected")
+
+	_, err = provider.Exchange(ctx, code, req.CodeVerifier)
+	assert.ErrorIs(t, err, oidc.ErrTokenRequest, "a code should only be exchanged once")
+}
+
+func TestProvider_VerifyIDToken_Rejects(t *testing.T) {
+	idp, provider := newTestProvider(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	valid := func(extra jwt.MapClaims) jwt.MapClaims {
+		claims := jwt.MapClaims{
+			"iss":   idp.Issuer(),
+			"sub":   idp.Subject,
+			"aud":   oidctest.ClientID,
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Hour).Unix(),
+			"nonce": testNonce,
+		}
+		for k, v := range extra {
+			claims[k] = v
+		}
+		return claims
+	}
+
+	unknownKey := jwt.NewWithClaims(jwt.SigningMethodRS256, valid(nil))
+	unknownKey.Header["kid"] = "rotated-out"
+	unknownKeyToken, err := unknownKey.SignedString(otherKey)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"wrong nonce", idp.SignIDToken(t, jwt.MapClaims{"nonce": "other"})},
+		{"missing nonce", idp.SignIDToken(t, nil)},
+		{"wrong audience", idp.SignIDToken(t, jwt.MapClaims{"nonce": testNonce, "aud": "other-client"})},
+		{"wrong issuer", idp.SignIDToken(t, jwt.MapClaims{"nonce": testNonce, "iss": "https://evil.example.com"})},
+		{"expired", idp.SignIDToken(t, jwt.MapClaims{
+			"nonce": testNonce, "exp": now.Add(-time.Hour).Unix(), "iat": now.Add(-2 * time.Hour).Unix(),
+		})},
+		{"issued in the future", idp.SignIDToken(t, jwt.MapClaims{"nonce": testNonce, "iat": now.Add(time.Hour).Unix()})},
+		{"missing subject", idp.SignIDToken(t, jwt.MapClaims{"nonce": testNonce, "sub": ""})},
+		{"missing issued at", idp.SignIDToken(t, jwt.MapClaims{"nonce": testNonce, "iat": nil})},
+		{"several audiences without azp", idp.SignIDToken(t, jwt.MapClaims{
+			"nonce": testNonce, "aud": []string{oidctest.ClientID, "other-client"},
+		})},
+		{"azp of another client", idp.SignIDToken(t, jwt.MapClaims{"nonce": testNonce, "azp": "other-client"})},
+		{"signed with another key", idp.Sign(t, jwt.SigningMethodRS256, otherKey, valid(nil))},
+		{"signed with the client secret", idp.Sign(t, jwt.SigningMethodHS256, []byte(oidctest.ClientSecret), valid(nil))},
+		{"unsigned", idp.Sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, valid(nil))},
+		{"unknown key", unknownKeyToken},
+		{"malformed", "not.a.token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.VerifyIDToken(ctx, tt.token, testNonce)
+			assert.ErrorIs(t, err, oidc.ErrInvalidIDToken, "errors should match")
+		})
+	}
+}
+
+func TestProvider_VerifyIDToken_Accepts(t *testing.T) {
+	idp, provider := newTestProvider(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		claims   jwt.MapClaims
+		verified bool
+	}{
+		{"azp of the client", jwt.MapClaims{
+			"aud": []string{oidctest.ClientID, "other-client"}, "azp": oidctest.ClientID,
+		}, true},
+		{"email_verified as a string", jwt.MapClaims{"email_verified": "true"}, true},
+		{"unverified email", jwt.MapClaims{"email_verified": false}, false},
+		{"no email_verified", jwt.MapClaims{"email_verified": nil}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.claims["nonce"] = testNonce
+
+			idToken, err := provider.VerifyIDToken(ctx, idp.SignIDToken(t, tt.claims), testNonce)
+			assert.NoError(t, err, "verify id token should not return an error")
+			assert.Equal(t, tt.verified, idToken.EmailVerified, "email verification should match")
+		})
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	idp := oidctest.New(t, testRedirectURL)
+	ctx := context.Background()
+
+	meta, err := oidc.Discover(ctx, idp.Client(), idp.Issuer())
+	assert.NoError(t, err, "discover should not return an error")
+	assert.Equal(t, idp.Issuer()+"/token", meta.TokenEndpoint, "token endpoint should match")
+	assert.Equal(t, []string{"S256"}, meta.CodeChallengeMethods, "code challenge methods should match")
+
+	_, err = oidc.Discover(ctx, idp.Client(), idp.Issuer()+"/")
+	assert.ErrorIs(t, err, oidc.ErrInvalidResponse, "an issuer that does not match should be rejected")
+
+	_, err = oidc.Discover(ctx, idp.Client(), idp.Issuer()+"/missing")
+	assert.ErrorIs(t, err, oidc.ErrInvalidResponse, "a missing document should be rejected")
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// RFC 7636 appendix B.
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		oidc.CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"), "challenge should match")
+}
@@ -0,0 +1,117 @@
+//go:generate mockgen -destination=mocks/breach_mock.go -package=mocks . BreachChecker
+package password
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1" //nolint:gosec // the breach corpus is keyed by SHA-1
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BreachChecker reports whether a password is known from a data breach.
+type BreachChecker interface {
+	Breached(ctx context.Context, password string) (bool, error)
+}
+
+// RangeSource looks up breached passwords by k-anonymity, as the Pwned
+// Passwords range API does: given the first 5 hex digits of a SHA-1 hash, it
+// returns the remaining 35 hex digits of every breached hash with that
+// prefix. Only the prefix leaves the caller.
+type RangeSource interface {
+	Range(ctx context.Context, prefix string) ([]string, error)
+}
+
+// SHA-1 prefix lengths used by range lookups, in hex digits.
+const (
+	rangePrefixLength = 5
+	rangeSuffixLength = 2*sha1.Size - rangePrefixLength
+)
+
+type rangeChecker struct {
+	source RangeSource
+}
+
+var _ BreachChecker = (*rangeChecker)(nil)
+
+// NewRangeChecker returns a BreachChecker that looks passwords up in source.
+func NewRangeChecker(source RangeSource) BreachChecker {
+	return &rangeChecker{source: source}
+}
+
+// Breached implements BreachChecker.
+func (c *rangeChecker) Breached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password)) //nolint:gosec // the breach corpus is keyed by SHA-1
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := digest[:rangePrefixLength], digest[rangePrefixLength:]
+
+	suffixes, err := c.source.Range(ctx, prefix)
+	if err != nil {
+		return false, fmt.Errorf("breach range %s: %w", prefix, err)
+	}
+
+	for _, s := range suffixes {
+		if strings.EqualFold(s, suffix) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// DirRangeSource reads ranges from a local copy of the Pwned Passwords
+// corpus, as written by its downloader: one file per prefix named
+// <PREFIX>.txt, holding <SUFFIX>:<COUNT> lines. A missing file is an empty
+// range.
+type DirRangeSource struct {
+	Dir string
+}
+
+var _ RangeSource = (*DirRangeSource)(nil)
+
+// Range implements RangeSource.
+func (s *DirRangeSource) Range(_ context.Context, prefix string) ([]string, error) {
+	if len(prefix) != rangePrefixLength || !isHex(prefix) {
+		return nil, fmt.Errorf("invalid range prefix %q", prefix)
+	}
+
+	f, err := os.Open(filepath.Join(s.Dir, strings.ToUpper(prefix)+".txt"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer f.Close()
+
+	var suffixes []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		suffix, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if len(suffix) == rangeSuffixLength {
+			suffixes = append(suffixes, suffix)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read range %s: %w", prefix, err)
+	}
+
+	return suffixes, nil
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+
+	return true
+}
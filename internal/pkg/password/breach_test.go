@@ -0,0 +1,69 @@
+package password_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/password"
+	"github.com/stretchr/testify/assert"
+)
+
+// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8.
+const (
+	breachedPrefix = "5BAA6"
+	breachedSuffix = "1E4C9B93F3F0682250B6CF8331B7EE68FD8"
+)
+
+func newCorpus(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	data := "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n" + breachedSuffix + ":9659365\r\n"
+	if err := os.WriteFile(filepath.Join(dir, breachedPrefix+".txt"), []byte(data), 0o600); err != nil {
+		t.Fatalf("write corpus: %v", err)
+	}
+
+	return dir
+}
+
+func TestRangeChecker_Dir(t *testing.T) {
+	checker := password.NewRangeChecker(&password.DirRangeSource{Dir: newCorpus(t)})
+	ctx := context.Background()
+
+	breached, err := checker.Breached(ctx, "password")
+	assert.NoError(t, err, "check should not return an error")
+	assert.True(t, breached, "password should be breached")
+
+	// SHA-1 of "passw0rd!x" does not start with 5BAA6, so its range file is missing.
+	breached, err = checker.Breached(ctx, "passw0rd!x")
+	assert.NoError(t, err, "a missing range should not be an error")
+	assert.False(t, breached, "password should not be breached")
+}
+
+func TestDirRangeSource_Range(t *testing.T) {
+	source := &password.DirRangeSource{Dir: newCorpus(t)}
+	ctx := context.Background()
+
+	suffixes, err := source.Range(ctx, "5baa6")
+	assert.NoError(t, err, "range should not return an error")
+	assert.Contains(t, suffixes, breachedSuffix, "suffixes should include the breached hash")
+
+	for _, prefix := range []string{"", "5BAA", "5BAA61", "../xx", "5BAAG"} {
+		_, err := source.Range(ctx, prefix)
+		assert.Error(t, err, "invalid prefix %q should be rejected", prefix)
+	}
+}
+
+type failingSource struct{}
+
+func (failingSource) Range(context.Context, string) ([]string, error) {
+	return nil, errors.New("corpus unavailable")
+}
+
+func TestRangeChecker_SourceError(t *testing.T) {
+	_, err := password.NewRangeChecker(failingSource{}).Breached(context.Background(), "password")
+	assert.Error(t, err, "source errors should be returned")
+}
@@ -0,0 +1,27 @@
+package password
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed common.txt
+var commonList string
+
+// commonRanks maps each common password or word to its rank, starting at 1
+// for the most common.
+var commonRanks = func() map[string]int {
+	ranks := make(map[string]int)
+	for _, line := range strings.Split(commonList, "\n") {
+		word := strings.TrimSpace(line)
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+
+		if _, ok := ranks[word]; !ok {
+			ranks[word] = len(ranks) + 1
+		}
+	}
+
+	return ranks
+}()
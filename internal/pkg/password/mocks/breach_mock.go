@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/pkg/password (interfaces: BreachChecker)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/breach_mock.go -package=mocks . BreachChecker
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBreachChecker is a mock of BreachChecker interface.
+type MockBreachChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockBreachCheckerMockRecorder
+	isgomock struct{}
+}
+
+// MockBreachCheckerMockRecorder is the mock recorder for MockBreachChecker.
+type MockBreachCheckerMockRecorder struct {
+	mock *MockBreachChecker
+}
+
+// NewMockBreachChecker creates a new mock instance.
+func NewMockBreachChecker(ctrl *gomock.Controller) *MockBreachChecker {
+	mock := &MockBreachChecker{ctrl: ctrl}
+	mock.recorder = &MockBreachCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBreachChecker) EXPECT() *MockBreachCheckerMockRecorder {
+	return m.recorder
+}
+
+// Breached mocks base method.
+func (m *MockBreachChecker) Breached(ctx context.Context, password string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Breached", ctx, password)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Breached indicates an expected call of Breached.
+func (mr *MockBreachCheckerMockRecorder) Breached(ctx, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Breached", reflect.TypeOf((*MockBreachChecker)(nil).Breached), ctx, password)
+}
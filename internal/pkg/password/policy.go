@@ -0,0 +1,78 @@
+// Package password checks new passwords against a strength policy and lists
+// of breached passwords.
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrTooShort = errors.New("password is too short")
+	ErrTooLong  = errors.New("password is too long")
+	ErrTooWeak  = errors.New("password is too easy to guess")
+)
+
+// Default policy values.
+const (
+	DefaultMinLength  = 10
+	DefaultMaxLength  = 128
+	DefaultMinEntropy = 40
+)
+
+// Policy sets the requirements for new passwords. Lengths are in characters
+// and MinEntropy is in bits, as estimated by Entropy.
+type Policy struct {
+	MinLength  int
+	MaxLength  int
+	MinEntropy float64
+}
+
+// DefaultPolicy returns the policy used when none is configured.
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLength:  DefaultMinLength,
+		MaxLength:  DefaultMaxLength,
+		MinEntropy: DefaultMinEntropy,
+	}
+}
+
+// Check returns ErrTooShort, ErrTooLong or ErrTooWeak if password does not
+// meet the policy. Context words, such as the user's email address, are
+// treated as the first words an attacker would guess.
+func (p Policy) Check(password string, context ...string) error {
+	n := utf8.RuneCountInString(password)
+
+	if n < p.MinLength {
+		return fmt.Errorf("%w: must be at least %d characters", ErrTooShort, p.MinLength)
+	}
+
+	if p.MaxLength > 0 && n > p.MaxLength {
+		return fmt.Errorf("%w: must be at most %d characters", ErrTooLong, p.MaxLength)
+	}
+
+	if Entropy(password, context...) < p.MinEntropy {
+		return ErrTooWeak
+	}
+
+	return nil
+}
+
+// ContextWords returns the words of an email address that a password should
+// not be built from: the local part and its pieces, and the domain name.
+func ContextWords(email string) []string {
+	local, domain, _ := strings.Cut(strings.ToLower(email), "@")
+	words := []string{local}
+
+	notLetter := func(r rune) bool { return !unicode.IsLetter(r) }
+	words = append(words, strings.FieldsFunc(local, notLetter)...)
+
+	if name, _, ok := strings.Cut(domain, "."); ok {
+		words = append(words, name)
+	}
+
+	return words
+}
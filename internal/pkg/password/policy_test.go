@@ -0,0 +1,58 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/password"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	policy := password.DefaultPolicy()
+
+	tests := []struct {
+		name     string
+		password string
+		context  []string
+		wantErr  error
+	}{
+		{"strong password", "vN4!qz8Rw#tK", nil, nil},
+		{"random words", "correcthorsebatterystaple", nil, nil},
+		{"too short", "a", nil, password.ErrTooShort},
+		{"too short in characters, not bytes", "ñandúñandú", nil, nil},
+		{"too long", strings.Repeat("vN4!qz8Rw#tK", 11), nil, password.ErrTooLong},
+		{"common password", "Password123!", nil, password.ErrTooWeak},
+		{"leet password", "P@ssw0rd2024", nil, password.ErrTooWeak},
+		{"keyboard walk", "qwertyuiop123", nil, password.ErrTooWeak},
+		{"sequence", "abcdefghij1234", nil, password.ErrTooWeak},
+		{"repeat", "aaaaaaaaaaaaaaaa", nil, password.ErrTooWeak},
+		{"name without context", "jonathanwhitfield", nil, nil},
+		{"name from context", "jonathanwhitfield", password.ContextWords("jonathan.whitfield@example.com"), password.ErrTooWeak},
+		{"reversed context word", "dleiftihwnahtanoj", []string{"jonathanwhitfield"}, password.ErrTooWeak},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.password, tt.context...)
+			if tt.wantErr == nil {
+				assert.NoError(t, err, "password should be accepted")
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr, "errors should match")
+			}
+		})
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	assert.Zero(t, password.Entropy(""), "an empty password should have no entropy")
+	assert.Less(t, password.Entropy("password"), password.Entropy("Password"), "capitals should add entropy")
+	assert.Less(t, password.Entropy("Password"), password.Entropy("Pzsswxrd"), "dictionary words should be cheap")
+	assert.Less(t, password.Entropy("summer2024"), password.Entropy("summer7319"), "years should be cheap")
+	assert.InDelta(t, 8*4.7, password.Entropy("kqzvmxtw"), 0.1, "random letters should cost log2(26) each")
+}
+
+func TestContextWords(t *testing.T) {
+	words := password.ContextWords("Jane.Doe+news@Example.com")
+	assert.Equal(t, []string{"jane.doe+news", "jane", "doe", "news", "example"}, words, "words should match")
+}
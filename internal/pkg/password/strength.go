@@ -0,0 +1,213 @@
+package password
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// minPatternLength is the shortest run matched as a word, repeat or sequence.
+const minPatternLength = 3
+
+// sequences are the alphabets along which runs such as "abc", "987" or
+// "qwerty" are cheap to guess.
+var sequences = []string{
+	"abcdefghijklmnopqrstuvwxyz",
+	"0123456789",
+	"qwertyuiop",
+	"asdfghjkl",
+	"zxcvbnm",
+	"1qaz2wsx3edc4rfv5tgb6yhn7ujm8ik9ol0p",
+}
+
+// leet maps common character substitutions back to letters.
+var leet = strings.NewReplacer(
+	"4", "a", "@", "a", "8", "b", "(", "c", "3", "e", "6", "g", "1", "i", "!", "i",
+	"0", "o", "$", "s", "5", "s", "7", "t", "+", "t", "2", "z",
+)
+
+// Entropy estimates the strength of password in bits, as the log2 of the
+// number of guesses an attacker needs. Like zxcvbn, it splits the password
+// into the cheapest sequence of dictionary words (common passwords and the
+// context words), repeats, sequences, years and single characters, and sums
+// their costs.
+func Entropy(password string, context ...string) float64 {
+	runes := []rune(password)
+	words := contextRanks(context)
+
+	// best[i] is the lowest cost of runes[:i].
+	best := make([]float64, len(runes)+1)
+	for i := 1; i <= len(runes); i++ {
+		best[i] = best[i-1] + charBits(runes[i-1])
+
+		for j := 0; j <= i-minPatternLength; j++ {
+			if bits, ok := patternBits(runes[j:i], words); ok && best[j]+bits < best[i] {
+				best[i] = best[j] + bits
+			}
+		}
+	}
+
+	return best[len(runes)]
+}
+
+// patternBits returns the cheapest pattern that matches all of s.
+func patternBits(s []rune, context map[string]int) (float64, bool) {
+	bits, ok := math.Inf(1), false
+	try := func(b float64, matched bool) {
+		if matched && b < bits {
+			bits, ok = b, true
+		}
+	}
+
+	try(dictionaryBits(s, context))
+	try(repeatBits(s))
+	try(sequenceBits(s))
+	try(yearBits(s))
+
+	return bits, ok
+}
+
+// dictionaryBits matches s against the ranked word lists, also trying the
+// reversed word and leet substitutions, and adds a bit for each variation.
+func dictionaryBits(s []rune, context map[string]int) (float64, bool) {
+	word := strings.ToLower(string(s))
+	extra := caseBits(s)
+
+	if unleeted := leet.Replace(word); unleeted != word {
+		word = unleeted
+		extra++
+	}
+
+	rank, ok := lookup(word, context)
+	if !ok {
+		if rank, ok = lookup(reverse(word), context); !ok {
+			return 0, false
+		}
+		extra++
+	}
+
+	return math.Log2(float64(rank)+1) + extra, true
+}
+
+func lookup(word string, context map[string]int) (int, bool) {
+	if rank, ok := context[word]; ok {
+		return rank, true
+	}
+
+	rank, ok := commonRanks[word]
+	return rank, ok
+}
+
+// caseBits is the cost of the capitalization of a word: nothing for lower
+// case, a bit for a capital first letter or all capitals, and a bit per
+// capital otherwise.
+func caseBits(s []rune) float64 {
+	upper := 0
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+
+	switch {
+	case upper == 0:
+		return 0
+	case upper == len(s), upper == 1 && unicode.IsUpper(s[0]):
+		return 1
+	default:
+		return float64(upper)
+	}
+}
+
+// repeatBits matches a single character repeated, such as "aaaa".
+func repeatBits(s []rune) (float64, bool) {
+	for _, r := range s[1:] {
+		if r != s[0] {
+			return 0, false
+		}
+	}
+
+	return charBits(s[0]) + math.Log2(float64(len(s))), true
+}
+
+// sequenceBits matches a run along one of the sequences, in either
+// direction, such as "abcd", "4321" or "asdf".
+func sequenceBits(s []rune) (float64, bool) {
+	lower := []rune(strings.ToLower(string(s)))
+
+	for _, seq := range sequences {
+		for _, step := range []int{1, -1} {
+			start := strings.IndexRune(seq, lower[0])
+			if start < 0 {
+				continue
+			}
+
+			matched := true
+			for k, r := range lower {
+				i := start + k*step
+				if i < 0 || i >= len(seq) || rune(seq[i]) != r {
+					matched = false
+					break
+				}
+			}
+
+			if matched {
+				bits := math.Log2(float64(len(seq))) + math.Log2(float64(len(s))) + caseBits(s)
+				if step < 0 {
+					bits++
+				}
+				return bits, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// yearBits matches a recent year, such as "1987" or "2024".
+func yearBits(s []rune) (float64, bool) {
+	if len(s) != 4 || !(string(s[:2]) == "19" || string(s[:2]) == "20") ||
+		!unicode.IsDigit(s[2]) || !unicode.IsDigit(s[3]) {
+		return 0, false
+	}
+
+	return math.Log2(200), true
+}
+
+// charBits is the cost of guessing a character by brute force within its
+// class.
+func charBits(r rune) float64 {
+	switch {
+	case r >= '0' && r <= '9':
+		return math.Log2(10)
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		return math.Log2(26)
+	case r < unicode.MaxASCII:
+		return math.Log2(33)
+	default:
+		return math.Log2(100)
+	}
+}
+
+// contextRanks ranks each context word of at least minPatternLength letters
+// as a most likely guess.
+func contextRanks(context []string) map[string]int {
+	ranks := make(map[string]int, len(context))
+	for _, word := range context {
+		word = strings.ToLower(word)
+		if len([]rune(word)) >= minPatternLength {
+			ranks[word] = 1
+		}
+	}
+
+	return ranks
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes)
+}
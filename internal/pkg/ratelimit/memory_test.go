@@ -0,0 +1,77 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+const testKey = "ip:192.0.2.1"
+
+var testLimit = ratelimit.Limit{Requests: 3, Period: 3 * time.Second}
+
+func TestMemoryStore_Take_Burst(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	for i := 2; i >= 0; i-- {
+		res, err := store.Take(ctx, testKey, testLimit, now)
+		assert.NoError(t, err, "take should not return an error")
+		assert.True(t, res.Allowed, "requests within the burst should be allowed")
+		assert.Equal(t, i, res.Remaining, "remaining should match")
+		assert.Equal(t, 3, res.Limit, "limit should match")
+	}
+
+	res, err := store.Take(ctx, testKey, testLimit, now)
+	assert.NoError(t, err, "take should not return an error")
+	assert.False(t, res.Allowed, "requests beyond the burst should be denied")
+	assert.Equal(t, time.Second, res.RetryAfter, "retry after should match the refill rate")
+	assert.Equal(t, 3*time.Second, res.Reset, "reset should match the time to refill")
+}
+
+func TestMemoryStore_Take_Refill(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	for range 3 {
+		_, err := store.Take(ctx, testKey, testLimit, now)
+		assert.NoError(t, err, "take should not return an error")
+	}
+
+	res, err := store.Take(ctx, testKey, testLimit, now.Add(time.Second))
+	assert.NoError(t, err, "take should not return an error")
+	assert.True(t, res.Allowed, "a refilled token should be allowed")
+	assert.Equal(t, 0, res.Remaining, "remaining should match")
+
+	res, err = store.Take(ctx, testKey, testLimit, now.Add(time.Hour))
+	assert.NoError(t, err, "take should not return an error")
+	assert.Equal(t, 2, res.Remaining, "bucket should not exceed its capacity")
+}
+
+func TestMemoryStore_Take_KeysAreIndependent(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	for range 3 {
+		_, err := store.Take(ctx, testKey, testLimit, now)
+		assert.NoError(t, err, "take should not return an error")
+	}
+
+	res, err := store.Take(ctx, "ip:198.51.100.1", testLimit, now)
+	assert.NoError(t, err, "take should not return an error")
+	assert.True(t, res.Allowed, "other keys should have their own bucket")
+}
+
+func TestLimit_Policy(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 100, Period: time.Minute}
+
+	assert.Equal(t, "100;w=60", limit.Policy(), "policy should match")
+	assert.True(t, limit.Enabled(), "limit should be enabled")
+	assert.False(t, ratelimit.Limit{}.Enabled(), "zero limit should be disabled")
+}
@@ -3,10 +3,9 @@ package security
 
 import (
 	"crypto/subtle"
-	"encoding/base64"
 	"errors"
 	"fmt"
-	"strings"
+	"strconv"
 
 	"golang.org/x/crypto/argon2"
 )
@@ -16,11 +15,23 @@ type Hasher interface {
 	Verify(plain, hashed string) (bool, error)
 }
 
-type Argon2Hasher struct{}
+// Rehasher is implemented by hashers that can tell when a stored hash was
+// made with weaker settings than they currently use.
+type Rehasher interface {
+	NeedsRehash(hashed string) bool
+}
 
-var _ Hasher = (*Argon2Hasher)(nil)
+// NeedsRehash reports whether hashed should be replaced by a fresh hash from
+// h. It is false if h does not implement Rehasher.
+func NeedsRehash(h Hasher, hashed string) bool {
+	if r, ok := h.(Rehasher); ok {
+		return r.NeedsRehash(hashed)
+	}
 
-// Parameters for the Argon2ID algorithm
+	return false
+}
+
+// Default parameters for the Argon2ID algorithm
 const (
 	Memory      = 64 * 1024 // 64 MB
 	Iterations  = 3
@@ -29,66 +40,298 @@ const (
 	KeyLength   = 32 // 32 bytes
 )
 
+// Upper bounds on Argon2 parameters, so that a tampered stored hash cannot
+// demand unbounded memory or time when it is verified.
+const (
+	MaxArgon2Memory      = 1024 * 1024 // 1 GB
+	MaxArgon2Iterations  = 32
+	MaxArgon2Parallelism = 64
+	MaxArgon2SaltLength  = 64
+	MaxArgon2KeyLength   = 128
+)
+
+var ErrInvalidArgon2Params = errors.New("invalid argon2 parameters")
+
+// Argon2Params is the cost of an Argon2ID hash. Memory is in KiB.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params returns the parameters used by a zero Argon2Hasher.
+func DefaultArgon2Params() Argon2Params {
+	return Argon2Params{
+		Memory:      Memory,
+		Iterations:  Iterations,
+		Parallelism: Parallelism,
+		SaltLength:  SaltLength,
+		KeyLength:   KeyLength,
+	}
+}
+
+// Validate checks the parameters against the minimums of RFC 9106 and the
+// Max* bounds.
+func (p Argon2Params) Validate() error {
+	switch {
+	case p.Iterations < 1 || p.Iterations > MaxArgon2Iterations:
+		return fmt.Errorf("%w: iterations must be from 1 to %d", ErrInvalidArgon2Params, MaxArgon2Iterations)
+	case p.Parallelism < 1 || p.Parallelism > MaxArgon2Parallelism:
+		return fmt.Errorf("%w: parallelism must be from 1 to %d", ErrInvalidArgon2Params, MaxArgon2Parallelism)
+	case p.Memory < 8*uint32(p.Parallelism) || p.Memory > MaxArgon2Memory:
+		return fmt.Errorf("%w: memory must be from 8 KiB per lane to %d KiB", ErrInvalidArgon2Params, MaxArgon2Memory)
+	case p.SaltLength < 8 || p.SaltLength > MaxArgon2SaltLength:
+		return fmt.Errorf("%w: salt length must be from 8 to %d bytes", ErrInvalidArgon2Params, MaxArgon2SaltLength)
+	case p.KeyLength < 16 || p.KeyLength > MaxArgon2KeyLength:
+		return fmt.Errorf("%w: key length must be from 16 to %d bytes", ErrInvalidArgon2Params, MaxArgon2KeyLength)
+	}
+
+	return nil
+}
+
+// weakerThan reports whether any cost of p is below that of q.
+func (p Argon2Params) weakerThan(q Argon2Params) bool {
+	return p.Memory < q.Memory ||
+		p.Iterations < q.Iterations ||
+		p.Parallelism < q.Parallelism ||
+		p.SaltLength < q.SaltLength ||
+		p.KeyLength < q.KeyLength
+}
+
+// Argon2Hasher hashes with Params, or DefaultArgon2Params if they are zero.
+type Argon2Hasher struct {
+	Params Argon2Params
+	// Peppers are applied to passwords before hashing, and the ID of the
+	// pepper used is stored as the keyid of the hash. New hashes use the
+	// first one; the others only verify existing hashes until they are
+	// rehashed. Passwords are not peppered if there are none.
+	Peppers []Pepper
+}
+
+var (
+	_ Hasher   = (*Argon2Hasher)(nil)
+	_ Rehasher = (*Argon2Hasher)(nil)
+)
+
+func (h *Argon2Hasher) params() Argon2Params {
+	if h.Params == (Argon2Params{}) {
+		return DefaultArgon2Params()
+	}
+
+	return h.Params
+}
+
 // Hash implements Hasher.
 func (h *Argon2Hasher) Hash(plain string) (string, error) {
+	params := h.params()
+
 	// Generate a random salt
-	salt, err := GenerateRandomBytes(SaltLength)
+	salt, err := GenerateRandomBytes(params.SaltLength)
 	if err != nil {
 		return "", fmt.Errorf("generate salt: %w", err)
 	}
 
-	// Hash the password
-	hash := argon2.IDKey([]byte(plain), salt, Iterations, Memory, Parallelism, KeyLength)
+	input := []byte(plain)
+	var keyID []byte
+	if len(h.Peppers) > 0 {
+		input = h.Peppers[0].apply(plain)
+		keyID = []byte(h.Peppers[0].ID)
+	}
 
-	// Encode the salt and hash for storage
-	saltBase64 := base64.RawStdEncoding.EncodeToString(salt)
-	hashBase64 := base64.RawStdEncoding.EncodeToString(hash)
+	hash := &Argon2Hash{
+		Params: params,
+		KeyID:  keyID,
+		Salt:   salt,
+		Key:    argon2.IDKey(input, salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength),
+	}
 
-	// Return the formatted password hash
-	return fmt.Sprintf("$argon2id$v=19$m=%d,t=%d,p=%d$%s$%s",
-		Memory, Iterations, Parallelism, saltBase64, hashBase64), nil
+	return hash.String(), nil
+}
+
+// NeedsRehash implements Rehasher. Hashes that cannot be parsed by
+// ParseArgon2Hash, or that were not made with the current pepper, always
+// need a rehash.
+func (h *Argon2Hasher) NeedsRehash(hashed string) bool {
+	stored, err := ParseArgon2Hash(hashed)
+	if err != nil {
+		return true
+	}
+
+	var currentID string
+	if len(h.Peppers) > 0 {
+		currentID = h.Peppers[0].ID
+	}
+
+	return string(stored.KeyID) != currentID || stored.Params.weakerThan(h.params())
 }
 
 // Verify implements Hasher.
 func (h *Argon2Hasher) Verify(plain string, hashed string) (bool, error) {
-	parts := strings.Split(hashed, "$")
-	if len(parts) != 6 {
-		return false, errors.New("invalid hash format")
+	stored, err := ParseArgon2Hash(hashed)
+	if err != nil {
+		return false, err
 	}
 
-	// Extract parameters and the salt/hash values
-	var memory uint32
-	var iterations uint32
-	var parallelism uint8
-	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &parallelism)
-	if err != nil {
-		return false, fmt.Errorf("failed to parse hash parameters: %w", err)
+	if stored.Data != nil {
+		return false, errors.New("argon2 associated data is not supported")
 	}
 
-	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	input, err := h.pepper(plain, stored.KeyID)
 	if err != nil {
-		return false, fmt.Errorf("failed to decode salt: %w", err)
+		return false, err
 	}
 
-	expectedHash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	// Compute the hash with the same parameters
+	p := stored.Params
+	computedHash := argon2.IDKey(input, stored.Salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+
+	// Constant time comparison to prevent timing attacks
+	return subtle.ConstantTimeCompare(computedHash, stored.Key) == 1, nil
+}
+
+// pepper applies the pepper with the given ID to plain. A nil ID leaves plain
+// as is.
+func (h *Argon2Hasher) pepper(plain string, keyID []byte) ([]byte, error) {
+	if keyID == nil {
+		return []byte(plain), nil
+	}
+
+	for _, p := range h.Peppers {
+		if p.ID == string(keyID) {
+			return p.apply(plain), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownPepper, keyID)
+}
+
+// Limits on the optional Argon2 fields, from the PHC string format.
+const (
+	maxArgon2KeyIDLength = 8
+	maxArgon2DataLength  = 32
+)
+
+// Argon2Hash is a decoded Argon2id hash. KeyID and Data hold the optional
+// keyid and data parameters, and are nil when absent. Params.SaltLength and
+// Params.KeyLength are the lengths of Salt and Key.
+type Argon2Hash struct {
+	Params Argon2Params
+	KeyID  []byte
+	Data   []byte
+	Salt   []byte
+	Key    []byte
+}
+
+// ParseArgon2Hash parses a hash made by Argon2Hasher:
+//
+//	$argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>[,keyid=<id>][,data=<data>]$<salt>$<key>
+//
+// Parameters must appear in that order, and must be within the Max* bounds
+// so that verifying the hash has bounded cost. A parsed hash encodes back to
+// exactly s.
+func ParseArgon2Hash(s string) (*Argon2Hash, error) {
+	phc, err := ParsePHC(s)
 	if err != nil {
-		return false, fmt.Errorf("failed to decode hash: %w", err)
+		return nil, err
 	}
 
-	// Check if len(expectedHash) can safely fit in a uint32
-	hashLen := len(expectedHash)
+	if phc.ID != "argon2id" {
+		return nil, fmt.Errorf("%w: algorithm %q is not argon2id", ErrInvalidPHC, phc.ID)
+	}
 
-	if hashLen > int(^uint32(0)) { // ^uint32(0) gives the max value of uint32
-		return false, errors.New("expected hash length exceeds uint32 limits")
+	if phc.Version != argon2.Version {
+		return nil, fmt.Errorf("%w: unsupported argon2 version %d", ErrInvalidPHC, phc.Version)
 	}
 
-	// Compute the hash with the same parameters
-	computedHash := argon2.IDKey([]byte(plain), salt, iterations, memory, parallelism, uint32(hashLen))
+	if phc.Salt == nil || phc.Hash == nil {
+		return nil, fmt.Errorf("%w: missing salt or hash", ErrInvalidPHC)
+	}
 
-	// Constant time comparison to prevent timing attacks
-	if subtle.ConstantTimeCompare(computedHash, expectedHash) == 1 {
-		return true, nil
+	hash := &Argon2Hash{Salt: phc.Salt, Key: phc.Hash}
+	params := phc.Params
+
+	for _, p := range []struct {
+		name string
+		max  uint64
+		dest func(uint64)
+	}{
+		{"m", MaxArgon2Memory, func(v uint64) { hash.Params.Memory = uint32(v) }},
+		{"t", MaxArgon2Iterations, func(v uint64) { hash.Params.Iterations = uint32(v) }},
+		{"p", MaxArgon2Parallelism, func(v uint64) { hash.Params.Parallelism = uint8(v) }},
+	} {
+		if len(params) == 0 || params[0].Name != p.name {
+			return nil, fmt.Errorf("%w: expected parameter %q", ErrInvalidPHC, p.name)
+		}
+
+		v, err := parsePHCDecimal(params[0].Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: parameter %q is not a decimal", ErrInvalidPHC, p.name)
+		}
+
+		if uint64(v) > p.max {
+			return nil, fmt.Errorf("%w: parameter %q above %d", ErrInvalidArgon2Params, p.name, p.max)
+		}
+		p.dest(uint64(v))
+		params = params[1:]
+	}
+
+	for _, p := range []struct {
+		name string
+		max  int
+		dest *[]byte
+	}{
+		{"keyid", maxArgon2KeyIDLength, &hash.KeyID},
+		{"data", maxArgon2DataLength, &hash.Data},
+	} {
+		if len(params) == 0 || params[0].Name != p.name {
+			continue
+		}
+
+		v, err := decodePHCBase64(params[0].Value)
+		if err != nil || len(v) > p.max {
+			return nil, fmt.Errorf("%w: bad parameter %q", ErrInvalidPHC, p.name)
+		}
+		*p.dest = v
+		params = params[1:]
+	}
+
+	if len(params) > 0 {
+		return nil, fmt.Errorf("%w: unexpected parameter %q", ErrInvalidPHC, params[0].Name)
+	}
+
+	hash.Params.SaltLength = uint32(len(hash.Salt))
+	hash.Params.KeyLength = uint32(len(hash.Key))
+
+	if err := hash.Params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return hash, nil
+}
+
+// String encodes the hash in the format read by ParseArgon2Hash.
+func (h *Argon2Hash) String() string {
+	phc := &PHC{
+		ID:      "argon2id",
+		Version: argon2.Version,
+		Params: []PHCParam{
+			{Name: "m", Value: strconv.FormatUint(uint64(h.Params.Memory), 10)},
+			{Name: "t", Value: strconv.FormatUint(uint64(h.Params.Iterations), 10)},
+			{Name: "p", Value: strconv.FormatUint(uint64(h.Params.Parallelism), 10)},
+		},
+		Salt: h.Salt,
+		Hash: h.Key,
+	}
+
+	if h.KeyID != nil {
+		phc.Params = append(phc.Params, PHCParam{Name: "keyid", Value: phcEncoding.EncodeToString(h.KeyID)})
+	}
+
+	if h.Data != nil {
+		phc.Params = append(phc.Params, PHCParam{Name: "data", Value: phcEncoding.EncodeToString(h.Data)})
 	}
 
-	return false, nil
+	return phc.String()
 }
@@ -18,3 +18,144 @@ func TestArgon2Hasher_Hash(t *testing.T) {
 	assert.NotEmpty(t, hashed, "Hashed password should not be empty")
 	assert.True(t, strings.HasPrefix(hashed, "$argon2id$"), "Hashed password should have the correct prefix")
 }
+
+// testParams are cheap parameters that keep tests fast.
+var testParams = security.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16}
+
+func TestArgon2Hasher_HashWithParams(t *testing.T) {
+	hasher := &security.Argon2Hasher{Params: testParams}
+
+	hashed, err := hasher.Hash("securepassword")
+	assert.NoError(t, err, "Hashing should not return an error")
+	assert.True(t, strings.HasPrefix(hashed, "$argon2id$v=19$m=64,t=1,p=1$"), "Hashed password should encode the parameters")
+
+	ok, err := hasher.Verify("securepassword", hashed)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, ok, "Password should match")
+}
+
+func TestArgon2Hasher_NeedsRehash(t *testing.T) {
+	hashed, err := (&security.Argon2Hasher{Params: testParams}).Hash("securepassword")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		params security.Argon2Params
+		hashed string
+		want   bool
+	}{
+		{"same parameters", testParams, hashed, false},
+		{"weaker policy", security.Argon2Params{Memory: 32, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16}, hashed, false},
+		{"more memory", security.Argon2Params{Memory: 128, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16}, hashed, true},
+		{"more iterations", security.Argon2Params{Memory: 64, Iterations: 2, Parallelism: 1, SaltLength: 8, KeyLength: 16}, hashed, true},
+		{"longer salt", security.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 16}, hashed, true},
+		{"longer key", security.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 32}, hashed, true},
+		{"default parameters", security.Argon2Params{}, hashed, true},
+		{"other version", testParams, strings.Replace(hashed, "v=19", "v=16", 1), true},
+		{"other algorithm", testParams, "$2b$10$abcdefghijklmnopqrstuu", true},
+		{"malformed", testParams, "$argon2id$v=19$m=x$salt$key", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher := &security.Argon2Hasher{Params: tt.params}
+			assert.Equal(t, tt.want, hasher.NeedsRehash(tt.hashed), "NeedsRehash should match")
+		})
+	}
+}
+
+func TestArgon2Params_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		params security.Argon2Params
+		valid  bool
+	}{
+		{"defaults", security.DefaultArgon2Params(), true},
+		{"minimums", testParams, true},
+		{"no iterations", security.Argon2Params{Memory: 64, Parallelism: 1, SaltLength: 8, KeyLength: 16}, false},
+		{"no parallelism", security.Argon2Params{Memory: 64, Iterations: 1, SaltLength: 8, KeyLength: 16}, false},
+		{"too little memory", security.Argon2Params{Memory: 8, Iterations: 1, Parallelism: 2, SaltLength: 8, KeyLength: 16}, false},
+		{"short salt", security.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 4, KeyLength: 16}, false},
+		{"short key", security.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 8}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if tt.valid {
+				assert.NoError(t, err, "parameters should be valid")
+			} else {
+				assert.ErrorIs(t, err, security.ErrInvalidArgon2Params, "parameters should be rejected")
+			}
+		})
+	}
+}
+
+func TestNeedsRehash_NotSupported(t *testing.T) {
+	pool, err := security.NewPooledHasher(&security.Argon2Hasher{Params: testParams}, security.PoolConfig{MaxConcurrent: 1})
+	assert.NoError(t, err, "new pooled hasher should not return an error")
+	assert.True(t, security.NeedsRehash(pool, "$2b$10$abc"), "the pool should ask the wrapped hasher")
+
+	var plain security.Hasher = struct{ security.Hasher }{pool}
+	assert.False(t, security.NeedsRehash(plain, "$2b$10$abc"), "hashers without NeedsRehash should never rehash")
+}
+
+func TestParseArgon2Hash(t *testing.T) {
+	const salt, key = "c2FsdHNhbHQ", "a2V5a2V5a2V5a2V5a2V5aw"
+
+	t.Run("keyid and data round-trip", func(t *testing.T) {
+		hashed := "$argon2id$v=19$m=64,t=1,p=1,keyid=AQ,data=ZGF0YQ$" + salt + "$" + key
+
+		hash, err := security.ParseArgon2Hash(hashed)
+		if !assert.NoError(t, err, "parse should not return an error") {
+			return
+		}
+
+		assert.Equal(t, testParams, hash.Params, "params should match")
+		assert.Equal(t, []byte{1}, hash.KeyID, "keyid should match")
+		assert.Equal(t, []byte("data"), hash.Data, "data should match")
+		assert.Equal(t, hashed, hash.String(), "encoding should round-trip")
+	})
+
+	tests := []struct {
+		name   string
+		hashed string
+	}{
+		{"other algorithm", "$argon2i$v=19$m=64,t=1,p=1$" + salt + "$" + key},
+		{"missing version", "$argon2id$m=64,t=1,p=1$" + salt + "$" + key},
+		{"other version", "$argon2id$v=16$m=64,t=1,p=1$" + salt + "$" + key},
+		{"missing hash", "$argon2id$v=19$m=64,t=1,p=1$" + salt},
+		{"reordered params", "$argon2id$v=19$t=1,m=64,p=1$" + salt + "$" + key},
+		{"missing param", "$argon2id$v=19$m=64,t=1$" + salt + "$" + key},
+		{"unknown param", "$argon2id$v=19$m=64,t=1,p=1,x=1$" + salt + "$" + key},
+		{"data before keyid", "$argon2id$v=19$m=64,t=1,p=1,data=AQ,keyid=AQ$" + salt + "$" + key},
+		{"huge memory", "$argon2id$v=19$m=4294967295,t=1,p=1$" + salt + "$" + key},
+		{"memory overflow", "$argon2id$v=19$m=99999999999999999999,t=1,p=1$" + salt + "$" + key},
+		{"huge iterations", "$argon2id$v=19$m=64,t=1000000,p=1$" + salt + "$" + key},
+		{"huge parallelism", "$argon2id$v=19$m=65536,t=1,p=255$" + salt + "$" + key},
+		{"zero iterations", "$argon2id$v=19$m=64,t=0,p=1$" + salt + "$" + key},
+		{"short salt", "$argon2id$v=19$m=64,t=1,p=1$c2FsdA$" + key},
+		{"short key", "$argon2id$v=19$m=64,t=1,p=1$" + salt + "$a2V5"},
+		{"long keyid", "$argon2id$v=19$m=64,t=1,p=1,keyid=MDEyMzQ1Njc4$" + salt + "$" + key},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := security.ParseArgon2Hash(tt.hashed)
+			assert.Error(t, err, "parse should fail")
+		})
+	}
+}
+
+func TestArgon2Hasher_VerifyRejectsUnsafeHashes(t *testing.T) {
+	hasher := &security.Argon2Hasher{}
+	const salt, key = "c2FsdHNhbHQ", "a2V5a2V5a2V5a2V5a2V5aw"
+
+	_, err := hasher.Verify("password", "$argon2id$v=19$m=4294967295,t=4294967295,p=255$"+salt+"$"+key)
+	assert.ErrorIs(t, err, security.ErrInvalidArgon2Params, "excessive costs should be rejected before hashing")
+
+	_, err = hasher.Verify("password", "$argon2id$v=19$m=64,t=1,p=1,data=AQ$"+salt+"$"+key)
+	assert.Error(t, err, "associated data should be rejected")
+}
@@ -0,0 +1,121 @@
+package security
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// BcryptHasher hashes with bcrypt. It is used to verify passwords imported
+// from systems that stored bcrypt hashes.
+type BcryptHasher struct {
+	// Cost is the bcrypt cost of new hashes, or bcrypt.DefaultCost if zero.
+	Cost int
+}
+
+var _ Hasher = (*BcryptHasher)(nil)
+
+// Hash implements Hasher.
+func (h *BcryptHasher) Hash(plain string) (string, error) {
+	cost := h.Cost
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(plain), cost)
+	if err != nil {
+		return "", fmt.Errorf("bcrypt: %w", err)
+	}
+
+	return string(hash), nil
+}
+
+// Verify implements Hasher.
+func (h *BcryptHasher) Verify(plain, hashed string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hashed), []byte(plain))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return false, nil
+	default:
+		return false, fmt.Errorf("bcrypt: %w", err)
+	}
+}
+
+// Parameters for PBKDF2-SHA256 hashes.
+const (
+	PBKDF2Iterations = 600_000
+	// maxPBKDF2Iterations bounds the work a stored hash can demand.
+	maxPBKDF2Iterations = 10_000_000
+)
+
+// PBKDF2Hasher hashes with PBKDF2-HMAC-SHA256 in the format used by passlib:
+// $pbkdf2-sha256$<rounds>$<salt>$<checksum>, where salt and checksum use the
+// adapted base64 alphabet with "." in place of "+". The PHC form of the rounds
+// segment, i=<rounds>, is also accepted.
+type PBKDF2Hasher struct {
+	// Iterations of new hashes, or PBKDF2Iterations if zero.
+	Iterations int
+}
+
+var _ Hasher = (*PBKDF2Hasher)(nil)
+
+// Hash implements Hasher.
+func (h *PBKDF2Hasher) Hash(plain string) (string, error) {
+	iterations := h.Iterations
+	if iterations == 0 {
+		iterations = PBKDF2Iterations
+	}
+
+	salt, err := GenerateRandomBytes(SaltLength)
+	if err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+
+	key := pbkdf2.Key([]byte(plain), salt, iterations, sha256.Size, sha256.New)
+
+	return fmt.Sprintf("$pbkdf2-sha256$%d$%s$%s", iterations, encodeAB64(salt), encodeAB64(key)), nil
+}
+
+// Verify implements Hasher.
+func (h *PBKDF2Hasher) Verify(plain, hashed string) (bool, error) {
+	parts := strings.Split(hashed, "$")
+	if len(parts) != 5 || parts[0] != "" || parts[1] != "pbkdf2-sha256" {
+		return false, errors.New("invalid hash format")
+	}
+
+	iterations, err := strconv.Atoi(strings.TrimPrefix(parts[2], "i="))
+	if err != nil || iterations < 1 || iterations > maxPBKDF2Iterations {
+		return false, errors.New("invalid pbkdf2 iterations")
+	}
+
+	salt, err := decodeAB64(parts[3])
+	if err != nil {
+		return false, fmt.Errorf("failed to decode salt: %w", err)
+	}
+
+	expectedKey, err := decodeAB64(parts[4])
+	if err != nil || len(expectedKey) == 0 {
+		return false, errors.New("failed to decode hash")
+	}
+
+	key := pbkdf2.Key([]byte(plain), salt, iterations, len(expectedKey), sha256.New)
+
+	return subtle.ConstantTimeCompare(key, expectedKey) == 1, nil
+}
+
+func encodeAB64(b []byte) string {
+	return strings.ReplaceAll(base64.RawStdEncoding.EncodeToString(b), "+", ".")
+}
+
+func decodeAB64(s string) ([]byte, error) {
+	return base64.RawStdEncoding.DecodeString(strings.ReplaceAll(s, ".", "+"))
+}
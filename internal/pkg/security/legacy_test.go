@@ -0,0 +1,76 @@
+package security_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passlibHash was made by passlib's pbkdf2_sha256 format from "correct horse".
+const passlibHash = "$pbkdf2-sha256$1000$bGVnYWN5c2FsdHZhbHVlIQ$.YuuIMgxnRW1nyfIyrZdXJFT1tOI7hAl5biw/zzh9as"
+
+func TestBcryptHasher(t *testing.T) {
+	hasher := &security.BcryptHasher{Cost: bcrypt.MinCost}
+
+	hashed, err := hasher.Hash("securepassword")
+	assert.NoError(t, err, "Hashing should not return an error")
+	assert.True(t, strings.HasPrefix(hashed, "$2a$04$"), "Hashed password should have the correct prefix")
+
+	ok, err := hasher.Verify("securepassword", hashed)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, ok, "Password should match")
+
+	ok, err = hasher.Verify("wrong", hashed)
+	assert.NoError(t, err, "a mismatch should not be an error")
+	assert.False(t, ok, "Password should not match")
+
+	_, err = hasher.Verify("securepassword", "$2b$")
+	assert.Error(t, err, "a malformed hash should be an error")
+}
+
+func TestPBKDF2Hasher_Verify(t *testing.T) {
+	tests := []struct {
+		name    string
+		plain   string
+		hashed  string
+		want    bool
+		wantErr bool
+	}{
+		{"passlib hash", "correct horse", passlibHash, true, false},
+		{"PHC rounds", "correct horse", strings.Replace(passlibHash, "$1000$", "$i=1000$", 1), true, false},
+		{"wrong password", "wrong horse", passlibHash, false, false},
+		{"other algorithm", "correct horse", strings.Replace(passlibHash, "sha256", "sha512", 1), false, true},
+		{"missing segment", "correct horse", "$pbkdf2-sha256$1000$bGVnYWN5c2FsdHZhbHVlIQ", false, true},
+		{"zero rounds", "correct horse", strings.Replace(passlibHash, "$1000$", "$0$", 1), false, true},
+		{"excessive rounds", "correct horse", strings.Replace(passlibHash, "$1000$", "$100000000$", 1), false, true},
+		{"bad salt", "correct horse", strings.Replace(passlibHash, "bGVn", "!!!!", 1), false, true},
+	}
+
+	hasher := &security.PBKDF2Hasher{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := hasher.Verify(tt.plain, tt.hashed)
+			if tt.wantErr {
+				assert.Error(t, err, "Verify should return an error")
+			} else {
+				assert.NoError(t, err, "Verify should not return an error")
+			}
+			assert.Equal(t, tt.want, ok, "result should match")
+		})
+	}
+}
+
+func TestPBKDF2Hasher_Hash(t *testing.T) {
+	hasher := &security.PBKDF2Hasher{Iterations: 1000}
+
+	hashed, err := hasher.Hash("securepassword")
+	assert.NoError(t, err, "Hashing should not return an error")
+	assert.True(t, strings.HasPrefix(hashed, "$pbkdf2-sha256$1000$"), "Hashed password should have the correct prefix")
+
+	ok, err := hasher.Verify("securepassword", hashed)
+	assert.NoError(t, err, "Verify should not return an error")
+	assert.True(t, ok, "Password should match")
+}
@@ -0,0 +1,78 @@
+package security
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnsupportedHash = errors.New("unsupported password hash")
+
+// MultiHasher hashes new passwords with Argon2id and verifies hashes made by
+// any supported algorithm, chosen by the identifier between the first two
+// "$" of the hash. Hashes made by other algorithms, or by Argon2id with
+// weaker parameters, are reported by NeedsRehash so that they are upgraded
+// on the next sign-in.
+type MultiHasher struct {
+	argon2    *Argon2Hasher
+	verifiers map[string]Hasher
+}
+
+var (
+	_ Hasher   = (*MultiHasher)(nil)
+	_ Rehasher = (*MultiHasher)(nil)
+)
+
+// NewMultiHasher returns a MultiHasher that hashes with argon2 and also
+// verifies bcrypt ($2a$, $2b$, $2y$) and PBKDF2-SHA256 ($pbkdf2-sha256$)
+// hashes.
+func NewMultiHasher(argon2 *Argon2Hasher) *MultiHasher {
+	bcrypt := &BcryptHasher{}
+
+	return &MultiHasher{
+		argon2: argon2,
+		verifiers: map[string]Hasher{
+			"argon2id":      argon2,
+			"2a":            bcrypt,
+			"2b":            bcrypt,
+			"2y":            bcrypt,
+			"pbkdf2-sha256": &PBKDF2Hasher{},
+		},
+	}
+}
+
+// Hash implements Hasher.
+func (h *MultiHasher) Hash(plain string) (string, error) {
+	return h.argon2.Hash(plain)
+}
+
+// Verify implements Hasher. It fails with ErrUnsupportedHash if no
+// algorithm matches the hash.
+func (h *MultiHasher) Verify(plain, hashed string) (bool, error) {
+	verifier, ok := h.verifiers[hashAlgorithm(hashed)]
+	if !ok {
+		return false, ErrUnsupportedHash
+	}
+
+	return verifier.Verify(plain, hashed)
+}
+
+// NeedsRehash implements Rehasher.
+func (h *MultiHasher) NeedsRehash(hashed string) bool {
+	return h.argon2.NeedsRehash(hashed)
+}
+
+// hashAlgorithm returns the identifier of a "$id$..." hash, or "" if there is
+// none.
+func hashAlgorithm(hashed string) string {
+	rest, ok := strings.CutPrefix(hashed, "$")
+	if !ok {
+		return ""
+	}
+
+	id, _, ok := strings.Cut(rest, "$")
+	if !ok {
+		return ""
+	}
+
+	return id
+}
@@ -0,0 +1,61 @@
+package security_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestMultiHasher(t *testing.T) {
+	argon2 := &security.Argon2Hasher{Params: testParams}
+	hasher := security.NewMultiHasher(argon2)
+
+	argon2Hash, err := hasher.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	assert.True(t, strings.HasPrefix(argon2Hash, "$argon2id$"), "new hashes should use Argon2id")
+
+	bcryptHash, err := (&security.BcryptHasher{Cost: bcrypt.MinCost}).Hash("correct horse")
+	if err != nil {
+		t.Fatalf("bcrypt hash: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		hashed      string
+		needsRehash bool
+	}{
+		{"argon2id", argon2Hash, false},
+		{"bcrypt 2a", bcryptHash, true},
+		{"bcrypt 2b", strings.Replace(bcryptHash, "$2a$", "$2b$", 1), true},
+		{"bcrypt 2y", strings.Replace(bcryptHash, "$2a$", "$2y$", 1), true},
+		{"pbkdf2-sha256", passlibHash, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := hasher.Verify("correct horse", tt.hashed)
+			assert.NoError(t, err, "Verify should not return an error")
+			assert.True(t, ok, "Password should match")
+
+			ok, err = hasher.Verify("wrong horse", tt.hashed)
+			assert.NoError(t, err, "Verify should not return an error")
+			assert.False(t, ok, "Password should not match")
+
+			assert.Equal(t, tt.needsRehash, hasher.NeedsRehash(tt.hashed), "NeedsRehash should match")
+		})
+	}
+}
+
+func TestMultiHasher_Unsupported(t *testing.T) {
+	hasher := security.NewMultiHasher(&security.Argon2Hasher{Params: testParams})
+
+	for _, hashed := range []string{"", "plaintext", "$md5$abc", "$1$salt$hash"} {
+		_, err := hasher.Verify("correct horse", hashed)
+		assert.ErrorIs(t, err, security.ErrUnsupportedHash, "unknown formats should be rejected")
+	}
+}
@@ -0,0 +1,90 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidPepper = errors.New("invalid pepper")
+	ErrUnknownPepper = errors.New("unknown pepper key id")
+)
+
+// Limits on peppers. The ID is stored in the keyid field of every hash, so it
+// is kept short.
+const (
+	maxPepperIDLength = maxArgon2KeyIDLength
+	minPepperLength   = 32
+)
+
+// Pepper is a server-side secret mixed into passwords with HMAC-SHA256
+// before they are hashed, so that hashes leaked without it cannot be
+// attacked offline.
+type Pepper struct {
+	ID  string
+	Key []byte
+}
+
+// Validate checks that the ID is 1 to 8 bytes and the key at least 32 bytes.
+func (p Pepper) Validate() error {
+	if p.ID == "" || len(p.ID) > maxPepperIDLength {
+		return fmt.Errorf("%w: id must be 1 to %d bytes", ErrInvalidPepper, maxPepperIDLength)
+	}
+
+	if len(p.Key) < minPepperLength {
+		return fmt.Errorf("%w: key %q must be at least %d bytes", ErrInvalidPepper, p.ID, minPepperLength)
+	}
+
+	return nil
+}
+
+func (p Pepper) apply(plain string) []byte {
+	mac := hmac.New(sha256.New, p.Key)
+	mac.Write([]byte(plain))
+	return mac.Sum(nil)
+}
+
+// ParsePeppers parses a list of <id>:<base64 key> entries separated by
+// commas or newlines. Blank lines and lines starting with # are ignored.
+// The first entry is the current pepper.
+func ParsePeppers(s string) ([]Pepper, error) {
+	var peppers []Pepper
+	seen := make(map[string]bool)
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		for _, entry := range strings.Split(line, ",") {
+			id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+			if !ok {
+				return nil, fmt.Errorf("%w: expected <id>:<base64 key>", ErrInvalidPepper)
+			}
+
+			key, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("%w: decode key %q: %w", ErrInvalidPepper, id, err)
+			}
+
+			pepper := Pepper{ID: id, Key: key}
+			if err := pepper.Validate(); err != nil {
+				return nil, err
+			}
+
+			if seen[id] {
+				return nil, fmt.Errorf("%w: repeated id %q", ErrInvalidPepper, id)
+			}
+			seen[id] = true
+
+			peppers = append(peppers, pepper)
+		}
+	}
+
+	return peppers, nil
+}
@@ -0,0 +1,121 @@
+package security_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	pepperKey1 = bytes.Repeat([]byte{1}, 32)
+	pepperKey2 = bytes.Repeat([]byte{2}, 32)
+)
+
+func TestParsePeppers(t *testing.T) {
+	key1 := base64.StdEncoding.EncodeToString(pepperKey1)
+	key2 := base64.StdEncoding.EncodeToString(pepperKey2)
+	want := []security.Pepper{{ID: "2", Key: pepperKey2}, {ID: "1", Key: pepperKey1}}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []security.Pepper
+	}{
+		{"empty", "", nil},
+		{"comma separated", "2:" + key2 + ", 1:" + key1, want},
+		{"file", "# current\n2:" + key2 + "\n\n# retired\n1:" + key1 + "\n", want},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peppers, err := security.ParsePeppers(tt.input)
+			assert.NoError(t, err, "parse should not return an error")
+			assert.Equal(t, tt.want, peppers, "peppers should match")
+		})
+	}
+}
+
+func TestParsePeppers_Invalid(t *testing.T) {
+	key1 := base64.StdEncoding.EncodeToString(pepperKey1)
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing id", key1},
+		{"empty id", ":" + key1},
+		{"long id", "123456789:" + key1},
+		{"bad key", "1:not base64!"},
+		{"short key", "1:" + base64.StdEncoding.EncodeToString([]byte("short"))},
+		{"repeated id", "1:" + key1 + ",1:" + key1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := security.ParsePeppers(tt.input)
+			assert.ErrorIs(t, err, security.ErrInvalidPepper, "parse should fail")
+		})
+	}
+}
+
+func TestArgon2Hasher_Pepper(t *testing.T) {
+	pepper1 := security.Pepper{ID: "1", Key: pepperKey1}
+	pepper2 := security.Pepper{ID: "2", Key: pepperKey2}
+	plainHasher := &security.Argon2Hasher{Params: testParams}
+	oldHasher := &security.Argon2Hasher{Params: testParams, Peppers: []security.Pepper{pepper1}}
+	rotated := &security.Argon2Hasher{Params: testParams, Peppers: []security.Pepper{pepper2, pepper1}}
+
+	peppered, err := oldHasher.Hash("securepassword")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	unpeppered, err := plainHasher.Hash("securepassword")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+
+	hash, err := security.ParseArgon2Hash(peppered)
+	assert.NoError(t, err, "parse should not return an error")
+	assert.Equal(t, []byte("1"), hash.KeyID, "the pepper ID should be stored")
+
+	t.Run("the pepper is required", func(t *testing.T) {
+		_, err := plainHasher.Verify("securepassword", peppered)
+		assert.ErrorIs(t, err, security.ErrUnknownPepper, "a hash with an unknown pepper should fail")
+
+		_, err = (&security.Argon2Hasher{Params: testParams, Peppers: []security.Pepper{pepper2}}).Verify("securepassword", peppered)
+		assert.ErrorIs(t, err, security.ErrUnknownPepper, "a retired pepper that was removed should fail")
+
+		wrongKey := &security.Argon2Hasher{Params: testParams, Peppers: []security.Pepper{{ID: "1", Key: pepperKey2}}}
+		ok, err := wrongKey.Verify("securepassword", peppered)
+		assert.NoError(t, err, "Verify should not return an error")
+		assert.False(t, ok, "a different key should not match")
+	})
+
+	t.Run("retired peppers still verify", func(t *testing.T) {
+		for _, hashed := range []string{peppered, unpeppered} {
+			ok, err := rotated.Verify("securepassword", hashed)
+			assert.NoError(t, err, "Verify should not return an error")
+			assert.True(t, ok, "Password should match")
+
+			ok, err = rotated.Verify("wrong", hashed)
+			assert.NoError(t, err, "Verify should not return an error")
+			assert.False(t, ok, "Password should not match")
+
+			assert.True(t, rotated.NeedsRehash(hashed), "hashes without the current pepper should be rehashed")
+		}
+	})
+
+	t.Run("new hashes use the current pepper", func(t *testing.T) {
+		hashed, err := rotated.Hash("securepassword")
+		assert.NoError(t, err, "Hashing should not return an error")
+		assert.False(t, rotated.NeedsRehash(hashed), "a fresh hash should not need a rehash")
+		assert.True(t, plainHasher.NeedsRehash(hashed), "removing the pepper should rehash")
+
+		hash, err := security.ParseArgon2Hash(hashed)
+		assert.NoError(t, err, "parse should not return an error")
+		assert.Equal(t, []byte("2"), hash.KeyID, "the current pepper ID should be stored")
+	})
+}
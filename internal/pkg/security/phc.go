@@ -0,0 +1,225 @@
+package security
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidPHC = errors.New("invalid PHC string")
+
+// phcEncoding is the unpadded base64 used by the PHC string format. Decoding
+// is strict so that every accepted string encodes back to itself.
+var phcEncoding = base64.RawStdEncoding.Strict()
+
+// Limits on the parts of a PHC string.
+const (
+	maxPHCNameLength = 32
+	maxPHCLength     = 1024
+)
+
+// PHCParam is a name=value parameter of a PHC string.
+type PHCParam struct {
+	Name  string
+	Value string
+}
+
+// PHC is a hash in the Password Hashing Competition string format:
+//
+//	$<id>[$v=<version>][$<param>=<value>(,<param>=<value>)*][$<salt>[$<hash>]]
+//
+// Params keep their order, and a zero Version is omitted, so that String
+// returns exactly the string ParsePHC was given.
+type PHC struct {
+	ID      string
+	Version int
+	Params  []PHCParam
+	Salt    []byte
+	Hash    []byte
+}
+
+// ParsePHC parses and validates s. It fails with ErrInvalidPHC unless s is in
+// canonical form: names are 1 to 32 characters of [a-z0-9-], values only use
+// [a-zA-Z0-9/+.-], names are not repeated, the version is a decimal without
+// leading zeros, and salt and hash are unpadded base64 with no stray bits.
+func ParsePHC(s string) (*PHC, error) {
+	if len(s) > maxPHCLength {
+		return nil, fmt.Errorf("%w: longer than %d bytes", ErrInvalidPHC, maxPHCLength)
+	}
+
+	rest, ok := strings.CutPrefix(s, "$")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing leading $", ErrInvalidPHC)
+	}
+
+	fields := strings.Split(rest, "$")
+	phc := &PHC{ID: fields[0]}
+	if !validPHCName(phc.ID) {
+		return nil, fmt.Errorf("%w: bad id %q", ErrInvalidPHC, phc.ID)
+	}
+	fields = fields[1:]
+
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "v=") {
+		v, err := parsePHCDecimal(strings.TrimPrefix(fields[0], "v="))
+		if err != nil || v < 1 {
+			return nil, fmt.Errorf("%w: bad version %q", ErrInvalidPHC, fields[0])
+		}
+		phc.Version = v
+		fields = fields[1:]
+	}
+
+	if len(fields) > 0 && strings.Contains(fields[0], "=") {
+		params, err := parsePHCParams(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		phc.Params = params
+		fields = fields[1:]
+	}
+
+	if len(fields) > 2 {
+		return nil, fmt.Errorf("%w: too many fields", ErrInvalidPHC)
+	}
+
+	if len(fields) > 0 {
+		salt, err := decodePHCBase64(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad salt", ErrInvalidPHC)
+		}
+		phc.Salt = salt
+	}
+
+	if len(fields) > 1 {
+		hash, err := decodePHCBase64(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad hash", ErrInvalidPHC)
+		}
+		phc.Hash = hash
+	}
+
+	return phc, nil
+}
+
+// String encodes the hash. Salt is omitted if nil, and Hash is only written
+// after a salt.
+func (p *PHC) String() string {
+	var b strings.Builder
+
+	b.WriteString("$")
+	b.WriteString(p.ID)
+
+	if p.Version != 0 {
+		b.WriteString("$v=")
+		b.WriteString(strconv.Itoa(p.Version))
+	}
+
+	for i, param := range p.Params {
+		if i == 0 {
+			b.WriteString("$")
+		} else {
+			b.WriteString(",")
+		}
+		b.WriteString(param.Name)
+		b.WriteString("=")
+		b.WriteString(param.Value)
+	}
+
+	if p.Salt != nil {
+		b.WriteString("$")
+		b.WriteString(phcEncoding.EncodeToString(p.Salt))
+
+		if p.Hash != nil {
+			b.WriteString("$")
+			b.WriteString(phcEncoding.EncodeToString(p.Hash))
+		}
+	}
+
+	return b.String()
+}
+
+// Param returns the value of the named parameter.
+func (p *PHC) Param(name string) (string, bool) {
+	for _, param := range p.Params {
+		if param.Name == name {
+			return param.Value, true
+		}
+	}
+
+	return "", false
+}
+
+func parsePHCParams(field string) ([]PHCParam, error) {
+	pairs := strings.Split(field, ",")
+	params := make([]PHCParam, 0, len(pairs))
+	seen := make(map[string]bool, len(pairs))
+
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || !validPHCName(name) || !validPHCValue(value) {
+			return nil, fmt.Errorf("%w: bad parameter %q", ErrInvalidPHC, pair)
+		}
+
+		if seen[name] {
+			return nil, fmt.Errorf("%w: repeated parameter %q", ErrInvalidPHC, name)
+		}
+		seen[name] = true
+
+		params = append(params, PHCParam{Name: name, Value: value})
+	}
+
+	return params, nil
+}
+
+func validPHCName(s string) bool {
+	if s == "" || len(s) > maxPHCNameLength {
+		return false
+	}
+
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func validPHCValue(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+			c == '/' || c == '+' || c == '.' || c == '-') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parsePHCDecimal parses a non-negative decimal without leading zeros.
+func parsePHCDecimal(s string) (int, error) {
+	if s == "" || len(s) > 1 && s[0] == '0' {
+		return 0, strconv.ErrSyntax
+	}
+
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return 0, strconv.ErrSyntax
+		}
+	}
+
+	return strconv.Atoi(s)
+}
+
+func decodePHCBase64(s string) ([]byte, error) {
+	if s == "" {
+		return nil, errors.New("empty")
+	}
+
+	return phcEncoding.DecodeString(s)
+}
@@ -0,0 +1,98 @@
+package security_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePHC_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		phc  string
+		want security.PHC
+	}{
+		{"id only", "$scrypt", security.PHC{ID: "scrypt"}},
+		{"version", "$argon2id$v=19", security.PHC{ID: "argon2id", Version: 19}},
+		{"params", "$argon2id$m=65536,t=3,p=2", security.PHC{
+			ID:     "argon2id",
+			Params: []security.PHCParam{{Name: "m", Value: "65536"}, {Name: "t", Value: "3"}, {Name: "p", Value: "2"}},
+		}},
+		{"salt", "$argon2id$v=19$c2FsdHNhbHQ", security.PHC{ID: "argon2id", Version: 19, Salt: []byte("saltsalt")}},
+		{"full", "$argon2id$v=19$m=64,t=1,p=1,keyid=a2V5$c2FsdHNhbHQ$aGFzaA", security.PHC{
+			ID:      "argon2id",
+			Version: 19,
+			Params: []security.PHCParam{
+				{Name: "m", Value: "64"}, {Name: "t", Value: "1"}, {Name: "p", Value: "1"}, {Name: "keyid", Value: "a2V5"},
+			},
+			Salt: []byte("saltsalt"),
+			Hash: []byte("hash"),
+		}},
+		{"param order kept", "$x$b=1,a=2", security.PHC{ID: "x", Params: []security.PHCParam{{Name: "b", Value: "1"}, {Name: "a", Value: "2"}}}},
+		{"value charset", "$x$k=aZ09/+.-", security.PHC{ID: "x", Params: []security.PHCParam{{Name: "k", Value: "aZ09/+.-"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phc, err := security.ParsePHC(tt.phc)
+			if !assert.NoError(t, err, "parse should not return an error") {
+				return
+			}
+
+			assert.Equal(t, tt.want, *phc, "parsed fields should match")
+			assert.Equal(t, tt.phc, phc.String(), "encoding should round-trip")
+		})
+	}
+}
+
+func TestParsePHC_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		phc  string
+	}{
+		{"empty", ""},
+		{"no leading $", "argon2id$v=19"},
+		{"empty id", "$"},
+		{"uppercase id", "$Argon2id"},
+		{"long id", "$" + strings.Repeat("a", 33)},
+		{"empty version", "$argon2id$v="},
+		{"zero version", "$argon2id$v=0"},
+		{"leading zero version", "$argon2id$v=019"},
+		{"signed version", "$argon2id$v=+19"},
+		{"empty param value", "$argon2id$m=,t=1"},
+		{"empty param name", "$argon2id$=1"},
+		{"bad param name", "$argon2id$M=1"},
+		{"bad param value", "$argon2id$m=1!"},
+		{"repeated param", "$argon2id$m=1,m=2"},
+		{"trailing comma", "$argon2id$m=1,"},
+		{"empty salt", "$argon2id$v=19$m=1$"},
+		{"padded salt", "$argon2id$c2FsdA=="},
+		{"salt with stray bits", "$argon2id$c2FsdB"},
+		{"bad hash", "$argon2id$c2FsdHNhbHQ$!!"},
+		{"too many fields", "$argon2id$v=19$m=1$c2FsdHNhbHQ$aGFzaA$aGFzaA"},
+		{"too long", "$argon2id$" + strings.Repeat("A", 1024)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := security.ParsePHC(tt.phc)
+			assert.ErrorIs(t, err, security.ErrInvalidPHC, "parse should fail")
+		})
+	}
+}
+
+func TestPHC_Param(t *testing.T) {
+	phc, err := security.ParsePHC("$argon2id$m=64,t=1")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	v, ok := phc.Param("t")
+	assert.True(t, ok, "param should be found")
+	assert.Equal(t, "1", v, "value should match")
+
+	_, ok = phc.Param("p")
+	assert.False(t, ok, "missing param should not be found")
+}
@@ -0,0 +1,174 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// ErrHasherBusy is returned when too many hashing operations are queued.
+var ErrHasherBusy = errors.New("password hasher is busy")
+
+var ErrInvalidPoolConfig = errors.New("invalid hasher pool config")
+
+// ContextHasher is implemented by hashers that can stop waiting for
+// resources when the context is done.
+type ContextHasher interface {
+	HashContext(ctx context.Context, plain string) (string, error)
+	VerifyContext(ctx context.Context, plain, hashed string) (bool, error)
+}
+
+// HashContext hashes with h, honoring ctx if h implements ContextHasher.
+func HashContext(ctx context.Context, h Hasher, plain string) (string, error) {
+	if ch, ok := h.(ContextHasher); ok {
+		return ch.HashContext(ctx, plain)
+	}
+
+	return h.Hash(plain)
+}
+
+// VerifyContext verifies with h, honoring ctx if h implements ContextHasher.
+func VerifyContext(ctx context.Context, h Hasher, plain, hashed string) (bool, error) {
+	if ch, ok := h.(ContextHasher); ok {
+		return ch.VerifyContext(ctx, plain, hashed)
+	}
+
+	return h.Verify(plain, hashed)
+}
+
+// PoolConfig sets the limits of a PooledHasher.
+type PoolConfig struct {
+	// MaxConcurrent caps the operations running at once. Every operation
+	// takes one slot whatever it costs, and an Argon2 operation holds the
+	// Memory of its hash until it finishes, so hashing can use up to
+	// MaxConcurrent times the largest Argon2 Memory in use. Size it against
+	// the memory the process can spare.
+	MaxConcurrent int64
+	// MaxQueued caps the operations waiting for a slot. Further calls fail
+	// with ErrHasherBusy.
+	MaxQueued int64
+}
+
+// Validate rejects limits that would leave the pool unable to hash, since
+// every operation would wait for a slot that never frees.
+func (c PoolConfig) Validate() error {
+	switch {
+	case c.MaxConcurrent < 1:
+		return fmt.Errorf("%w: max concurrent must be at least 1", ErrInvalidPoolConfig)
+	case c.MaxQueued < 0:
+		return fmt.Errorf("%w: max queued must not be negative", ErrInvalidPoolConfig)
+	}
+
+	return nil
+}
+
+// PoolStats is a snapshot of a PooledHasher.
+type PoolStats struct {
+	Active   int64  `json:"active"`
+	Queued   int64  `json:"queued"`
+	Rejected uint64 `json:"rejected"`
+}
+
+// PooledHasher bounds the number of concurrent operations of a Hasher so
+// that bursts of sign-ins cannot exhaust memory.
+type PooledHasher struct {
+	hasher   Hasher
+	sem      *semaphore.Weighted
+	cfg      PoolConfig
+	active   atomic.Int64
+	queued   atomic.Int64
+	rejected atomic.Uint64
+}
+
+var (
+	_ Hasher        = (*PooledHasher)(nil)
+	_ ContextHasher = (*PooledHasher)(nil)
+	_ Rehasher      = (*PooledHasher)(nil)
+)
+
+// NewPooledHasher returns an error if cfg is invalid.
+func NewPooledHasher(hasher Hasher, cfg PoolConfig) (*PooledHasher, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &PooledHasher{
+		hasher: hasher,
+		sem:    semaphore.NewWeighted(cfg.MaxConcurrent),
+		cfg:    cfg,
+	}, nil
+}
+
+// Hash implements Hasher.
+func (p *PooledHasher) Hash(plain string) (string, error) {
+	return p.HashContext(context.Background(), plain)
+}
+
+// Verify implements Hasher.
+func (p *PooledHasher) Verify(plain, hashed string) (bool, error) {
+	return p.VerifyContext(context.Background(), plain, hashed)
+}
+
+// HashContext implements ContextHasher.
+func (p *PooledHasher) HashContext(ctx context.Context, plain string) (string, error) {
+	if err := p.acquire(ctx); err != nil {
+		return "", err
+	}
+	defer p.release()
+
+	return p.hasher.Hash(plain)
+}
+
+// VerifyContext implements ContextHasher.
+func (p *PooledHasher) VerifyContext(ctx context.Context, plain, hashed string) (bool, error) {
+	if err := p.acquire(ctx); err != nil {
+		return false, err
+	}
+	defer p.release()
+
+	return p.hasher.Verify(plain, hashed)
+}
+
+// NeedsRehash implements Rehasher by asking the wrapped hasher. It does not
+// take a slot since it only parses the hash.
+func (p *PooledHasher) NeedsRehash(hashed string) bool {
+	return NeedsRehash(p.hasher, hashed)
+}
+
+// Stats returns the current queue depth and load.
+func (p *PooledHasher) Stats() PoolStats {
+	return PoolStats{
+		Active:   p.active.Load(),
+		Queued:   p.queued.Load(),
+		Rejected: p.rejected.Load(),
+	}
+}
+
+func (p *PooledHasher) acquire(ctx context.Context) error {
+	if p.sem.TryAcquire(1) {
+		p.active.Add(1)
+		return nil
+	}
+
+	if p.queued.Add(1) > p.cfg.MaxQueued {
+		p.queued.Add(-1)
+		p.rejected.Add(1)
+		return ErrHasherBusy
+	}
+	defer p.queued.Add(-1)
+
+	if err := p.sem.Acquire(ctx, 1); err != nil {
+		return err
+	}
+
+	p.active.Add(1)
+	return nil
+}
+
+func (p *PooledHasher) release() {
+	p.active.Add(-1)
+	p.sem.Release(1)
+}
@@ -0,0 +1,133 @@
+package security_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingHasher holds every call until release is closed.
+type blockingHasher struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingHasher() *blockingHasher {
+	return &blockingHasher{
+		started: make(chan struct{}, 16),
+		release: make(chan struct{}),
+	}
+}
+
+func (h *blockingHasher) Hash(plain string) (string, error) {
+	h.started <- struct{}{}
+	<-h.release
+	return "hashed:" + plain, nil
+}
+
+func (h *blockingHasher) Verify(plain, hashed string) (bool, error) {
+	h.started <- struct{}{}
+	<-h.release
+	return hashed == "hashed:"+plain, nil
+}
+
+// waitFor polls cond until it holds or the test times out.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPooledHasher_HashAndVerify(t *testing.T) {
+	pool, err := security.NewPooledHasher(&security.Argon2Hasher{}, security.PoolConfig{MaxConcurrent: 1, MaxQueued: 1})
+	assert.NoError(t, err, "new pooled hasher should not return an error")
+
+	hashed, err := security.HashContext(context.Background(), pool, "securepassword")
+	assert.NoError(t, err, "hash should not return an error")
+
+	ok, err := pool.Verify("securepassword", hashed)
+	assert.NoError(t, err, "verify should not return an error")
+	assert.True(t, ok, "password should match")
+	assert.Equal(t, security.PoolStats{}, pool.Stats(), "stats should be zero when idle")
+}
+
+func TestNewPooledHasher_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  security.PoolConfig
+	}{
+		{"no concurrent operations should be rejected", security.PoolConfig{MaxConcurrent: 0, MaxQueued: 1}},
+		{"negative concurrent operations should be rejected", security.PoolConfig{MaxConcurrent: -1, MaxQueued: 1}},
+		{"negative queue should be rejected", security.PoolConfig{MaxConcurrent: 1, MaxQueued: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := security.NewPooledHasher(&security.Argon2Hasher{}, tt.cfg)
+
+			assert.ErrorIs(t, err, security.ErrInvalidPoolConfig, "errors should match")
+		})
+	}
+}
+
+func TestPooledHasher_LimitsConcurrency(t *testing.T) {
+	inner := newBlockingHasher()
+	pool, err := security.NewPooledHasher(inner, security.PoolConfig{MaxConcurrent: 2, MaxQueued: 2})
+	assert.NoError(t, err, "new pooled hasher should not return an error")
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := pool.Hash("password")
+			assert.NoError(t, err, "hash should not return an error")
+		}()
+	}
+
+	waitFor(t, func() bool { return pool.Stats() == security.PoolStats{Active: 2, Queued: 2} })
+	assert.Len(t, inner.started, 2, "only two operations should run")
+
+	_, err = pool.HashContext(context.Background(), "password")
+	assert.ErrorIs(t, err, security.ErrHasherBusy, "a full queue should reject calls")
+	assert.Equal(t, uint64(1), pool.Stats().Rejected, "rejected count should match")
+
+	close(inner.release)
+	wg.Wait()
+
+	assert.Len(t, inner.started, 4, "queued operations should run")
+	assert.Equal(t, int64(0), pool.Stats().Active, "no operations should be active")
+}
+
+func TestPooledHasher_ContextCanceledWhileQueued(t *testing.T) {
+	inner := newBlockingHasher()
+	pool, err := security.NewPooledHasher(inner, security.PoolConfig{MaxConcurrent: 1, MaxQueued: 1})
+	assert.NoError(t, err, "new pooled hasher should not return an error")
+	defer close(inner.release)
+
+	go func() { _, _ = pool.Verify("password", "hashed:password") }()
+	<-inner.started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := pool.VerifyContext(ctx, "password", "hashed:password")
+		errCh <- err
+	}()
+
+	waitFor(t, func() bool { return pool.Stats().Queued == 1 })
+	cancel()
+
+	assert.ErrorIs(t, <-errCh, context.Canceled, "waiting should stop when the context is done")
+	assert.Equal(t, int64(0), pool.Stats().Queued, "the canceled call should leave the queue")
+}
@@ -2,7 +2,9 @@ package security
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 )
 
 func GenerateRandomBytes(length uint32) ([]byte, error) {
@@ -25,3 +27,10 @@ func GenerateRandomBytesEncoded(length uint32) (string, error) {
 
 	return base64.URLEncoding.EncodeToString(key), nil
 }
+
+// HashToken returns the hex-encoded SHA-256 digest of a random token. Tokens
+// are stored hashed so that a database leak does not expose usable values.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,200 @@
+//go:generate mockgen -destination=mocks/token_signer_mock.go -package=mocks . TokenSigner
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// MinHMACKeyLength is the minimum secret length accepted for HS256 signing.
+const MinHMACKeyLength = 32
+
+// jtiLength is the number of random bytes in a token ID.
+const jtiLength = 16
+
+var (
+	ErrInvalidToken   = errors.New("invalid token")
+	ErrWeakSigningKey = errors.New("signing key is too short")
+	ErrInvalidKey     = errors.New("invalid private key")
+)
+
+// TokenSigner issues and verifies signed access tokens.
+type TokenSigner interface {
+	Sign(subject string, ttl time.Duration) (string, error)
+	Verify(token string) (*jwt.RegisteredClaims, error)
+}
+
+// JWTSigner signs access tokens as JWTs with either HS256 or EdDSA keys.
+type JWTSigner struct {
+	method    jwt.SigningMethod
+	signKey   any
+	verifyKey any
+	keyID     string
+	issuer    string
+}
+
+var _ TokenSigner = (*JWTSigner)(nil)
+
+// NewHS256Signer returns a signer using a shared HMAC-SHA256 secret.
+func NewHS256Signer(secret []byte, issuer string) (*JWTSigner, error) {
+	if len(secret) < MinHMACKeyLength {
+		return nil, ErrWeakSigningKey
+	}
+
+	return &JWTSigner{
+		method:    jwt.SigningMethodHS256,
+		signKey:   secret,
+		verifyKey: secret,
+		issuer:    issuer,
+	}, nil
+}
+
+// NewEdDSASigner returns a signer using an Ed25519 key pair. The key ID
+// advertised in the token header is derived from the public key.
+func NewEdDSASigner(key ed25519.PrivateKey, issuer string) *JWTSigner {
+	pub, _ := key.Public().(ed25519.PublicKey)
+	sum := sha256.Sum256(pub)
+
+	return &JWTSigner{
+		method:    jwt.SigningMethodEdDSA,
+		signKey:   key,
+		verifyKey: pub,
+		keyID:     base64.RawURLEncoding.EncodeToString(sum[:8]),
+		issuer:    issuer,
+	}
+}
+
+// ParseEd25519PrivateKey decodes a PEM encoded PKCS #8 Ed25519 private key.
+func ParseEd25519PrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block found", ErrInvalidKey)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: not an Ed25519 key", ErrInvalidKey)
+	}
+
+	return edKey, nil
+}
+
+// Issuer returns the issuer named in the signer's tokens.
+func (s *JWTSigner) Issuer() string {
+	return s.issuer
+}
+
+// Sign implements TokenSigner.
+func (s *JWTSigner) Sign(subject string, ttl time.Duration) (string, error) {
+	jti, err := GenerateRandomBytesEncoded(jtiLength)
+	if err != nil {
+		return "", fmt.Errorf("generate token id: %w", err)
+	}
+
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		ID:        jti,
+		Issuer:    s.issuer,
+		Subject:   subject,
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+
+	return s.SignClaims(claims)
+}
+
+// SignClaims signs claims as they are. They should name the signer's issuer
+// for Verify and ParseClaims to accept them.
+func (s *JWTSigner) SignClaims(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.method, claims)
+	if s.keyID != "" {
+		token.Header["kid"] = s.keyID
+	}
+
+	signed, err := token.SignedString(s.signKey)
+	if err != nil {
+		return "", fmt.Errorf("sign token: %w", err)
+	}
+
+	return signed, nil
+}
+
+// Verify implements TokenSigner.
+func (s *JWTSigner) Verify(token string) (*jwt.RegisteredClaims, error) {
+	var claims jwt.RegisteredClaims
+	if err := s.ParseClaims(token, &claims); err != nil {
+		return nil, err
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	return &claims, nil
+}
+
+// ParseClaims checks the signature, issuer and expiry of a token and decodes
+// its claims into claims. Options add further checks.
+func (s *JWTSigner) ParseClaims(token string, claims jwt.Claims, opts ...jwt.ParserOption) error {
+	opts = append([]jwt.ParserOption{
+		jwt.WithValidMethods([]string{s.method.Alg()}),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+	}, opts...)
+
+	if _, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return s.verifyKey, nil
+	}, opts...); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	return nil
+}
+
+// JWK is a public key of a JSON Web Key Set (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+}
+
+// JWKSet is a JSON Web Key Set.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys that verify the signer's tokens. It is empty
+// for HS256 signers, whose key is secret.
+func (s *JWTSigner) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+
+	if pub, ok := s.verifyKey.(ed25519.PublicKey); ok {
+		set.Keys = append(set.Keys, JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+			Kid: s.keyID,
+			Use: "sig",
+			Alg: s.method.Alg(),
+		})
+	}
+
+	return set
+}
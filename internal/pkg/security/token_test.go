@@ -0,0 +1,180 @@
+package security_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testIssuer  = "fullstackgo"
+	testSubject = "1"
+)
+
+func TestJWTSigner_HS256_RoundTrip(t *testing.T) {
+	signer, err := security.NewHS256Signer([]byte(strings.Repeat("s", security.MinHMACKeyLength)), testIssuer)
+	assert.NoError(t, err, "creating the signer should not return an error")
+
+	token, err := signer.Sign(testSubject, time.Minute)
+	assert.NoError(t, err, "signing should not return an error")
+
+	claims, err := signer.Verify(token)
+	assert.NoError(t, err, "verifying should not return an error")
+	assert.Equal(t, testSubject, claims.Subject, "subject should match")
+	assert.Equal(t, testIssuer, claims.Issuer, "issuer should match")
+	assert.NotEmpty(t, claims.ID, "token ID should not be empty")
+}
+
+func TestJWTSigner_HS256_WeakSecret(t *testing.T) {
+	_, err := security.NewHS256Signer([]byte("short"), testIssuer)
+
+	assert.ErrorIs(t, err, security.ErrWeakSigningKey, "errors should match")
+}
+
+func TestJWTSigner_EdDSA_RoundTrip(t *testing.T) {
+	signer := newEdDSASigner(t)
+
+	token, err := signer.Sign(testSubject, time.Minute)
+	assert.NoError(t, err, "signing should not return an error")
+
+	claims, err := signer.Verify(token)
+	assert.NoError(t, err, "verifying should not return an error")
+	assert.Equal(t, testSubject, claims.Subject, "subject should match")
+}
+
+func TestJWTSigner_Verify_Rejects(t *testing.T) {
+	signer := newEdDSASigner(t)
+	hmacSigner, err := security.NewHS256Signer([]byte(strings.Repeat("s", security.MinHMACKeyLength)), testIssuer)
+	assert.NoError(t, err, "creating the signer should not return an error")
+
+	expired, err := signer.Sign(testSubject, -time.Minute)
+	assert.NoError(t, err, "signing should not return an error")
+
+	otherKey, err := newEdDSASigner(t).Sign(testSubject, time.Minute)
+	assert.NoError(t, err, "signing should not return an error")
+
+	otherAlg, err := hmacSigner.Sign(testSubject, time.Minute)
+	assert.NoError(t, err, "signing should not return an error")
+
+	otherIssuer, err := security.NewEdDSASigner(signerKey(t), "someone-else").Sign(testSubject, time.Minute)
+	assert.NoError(t, err, "signing should not return an error")
+
+	valid, err := signer.Sign(testSubject, time.Minute)
+	assert.NoError(t, err, "signing should not return an error")
+	tampered := tamper(valid)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"expired token", expired},
+		{"token signed with another key", otherKey},
+		{"token signed with another algorithm", otherAlg},
+		{"token from another issuer", otherIssuer},
+		{"tampered signature", tampered},
+		{"garbage", "not.a.jwt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signer.Verify(tt.token)
+			assert.ErrorIs(t, err, security.ErrInvalidToken, "errors should match")
+		})
+	}
+}
+
+func newEdDSASigner(t *testing.T) *security.JWTSigner {
+	t.Helper()
+	return security.NewEdDSASigner(signerKey(t), testIssuer)
+}
+
+func signerKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return key
+}
+
+// tamper flips a character in the middle of the token signature.
+func tamper(token string) string {
+	i := strings.LastIndex(token, ".") + 10
+	replacement := byte('A')
+	if token[i] == replacement {
+		replacement = 'B'
+	}
+	return token[:i] + string(replacement) + token[i+1:]
+}
+
+func TestParseEd25519PrivateKey(t *testing.T) {
+	key := signerKey(t)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	parsed, err := security.ParseEd25519PrivateKey(data)
+
+	assert.NoError(t, err, "parsing should not return an error")
+	assert.True(t, key.Equal(parsed), "keys should match")
+
+	_, err = security.ParseEd25519PrivateKey([]byte("not a key"))
+	assert.ErrorIs(t, err, security.ErrInvalidKey, "errors should match")
+}
+
+func TestJWTSigner_JWKS(t *testing.T) {
+	key := signerKey(t)
+	signer := security.NewEdDSASigner(key, testIssuer)
+
+	set := signer.JWKS()
+
+	if assert.Len(t, set.Keys, 1, "the public key should be published") {
+		jwk := set.Keys[0]
+		assert.Equal(t, "OKP", jwk.Kty, "key type should match")
+		assert.Equal(t, "EdDSA", jwk.Alg, "algorithm should match")
+		assert.Equal(t, base64.RawURLEncoding.EncodeToString(key.Public().(ed25519.PublicKey)), jwk.X,
+			"public key should match")
+
+		token, err := signer.Sign(testSubject, time.Minute)
+		assert.NoError(t, err, "signing should not return an error")
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+		assert.NoError(t, err, "parsing should not return an error")
+		assert.Equal(t, jwk.Kid, parsed.Header["kid"], "key ID should match the token header")
+	}
+
+	hmacSigner, err := security.NewHS256Signer([]byte(strings.Repeat("s", security.MinHMACKeyLength)), testIssuer)
+	assert.NoError(t, err, "creating the signer should not return an error")
+	assert.Empty(t, hmacSigner.JWKS().Keys, "secret keys should not be published")
+}
+
+func TestJWTSigner_ParseClaims(t *testing.T) {
+	signer := newEdDSASigner(t)
+	now := time.Now()
+
+	token, err := signer.SignClaims(jwt.MapClaims{
+		"iss":   testIssuer,
+		"sub":   testSubject,
+		"aud":   "client",
+		"scope": "read",
+		"exp":   now.Add(time.Minute).Unix(),
+	})
+	assert.NoError(t, err, "signing should not return an error")
+
+	var claims jwt.MapClaims
+	assert.NoError(t, signer.ParseClaims(token, &claims, jwt.WithAudience("client")), "parsing should not return an error")
+	assert.Equal(t, "read", claims["scope"], "custom claims should be decoded")
+
+	err = signer.ParseClaims(token, &jwt.MapClaims{}, jwt.WithAudience("other"))
+	assert.ErrorIs(t, err, security.ErrInvalidToken, "options should be checked")
+}
@@ -0,0 +1,99 @@
+package totp_test
+
+import (
+	"bytes"
+	"image/png"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/totp"
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 test vectors.
+var rfcSecret = []byte("12345678901234567890")
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	// The RFC lists 8 digit codes; 6 digit codes are their last 6 digits.
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		step := totp.Step(time.Unix(tt.unix, 0))
+		assert.Equal(t, tt.code, totp.Code(rfcSecret, step), "code at %d should match", tt.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := totp.Step(now)
+
+	tests := []struct {
+		name string
+		code string
+		step int64
+		ok   bool
+	}{
+		{"current", totp.Code(rfcSecret, current), current, true},
+		{"previous", totp.Code(rfcSecret, current-1), current - 1, true},
+		{"next", totp.Code(rfcSecret, current+1), current + 1, true},
+		{"too old", totp.Code(rfcSecret, current-2), 0, false},
+		{"too new", totp.Code(rfcSecret, current+2), 0, false},
+		{"wrong length", "12345", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := totp.Validate(rfcSecret, tt.code, now)
+
+			assert.Equal(t, tt.ok, ok, "validity should match")
+			assert.Equal(t, tt.step, step, "step should match")
+		})
+	}
+}
+
+func TestSecret_RoundTrip(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	assert.NoError(t, err, "generating the secret should not return an error")
+	assert.Len(t, secret, totp.SecretLength, "secret length should match")
+
+	encoded := totp.EncodeSecret(secret)
+	assert.NotContains(t, encoded, "=", "encoded secret should not be padded")
+
+	decoded, err := totp.DecodeSecret(encoded)
+	assert.NoError(t, err, "decoding should not return an error")
+	assert.Equal(t, secret, decoded, "secret should match")
+}
+
+func TestURI(t *testing.T) {
+	uri := totp.URI("Example Co", "abc@example.com", rfcSecret)
+
+	u, err := url.Parse(uri)
+	assert.NoError(t, err, "uri should parse")
+	assert.Equal(t, "otpauth", u.Scheme, "scheme should match")
+	assert.Equal(t, "totp", u.Host, "type should match")
+	assert.Equal(t, "/Example Co:abc@example.com", u.Path, "label should match")
+	assert.Equal(t, totp.EncodeSecret(rfcSecret), u.Query().Get("secret"), "secret should match")
+	assert.Equal(t, "Example Co", u.Query().Get("issuer"), "issuer should match")
+	assert.Equal(t, "6", u.Query().Get("digits"), "digits should match")
+	assert.Equal(t, "30", u.Query().Get("period"), "period should match")
+}
+
+func TestQRCode(t *testing.T) {
+	data, err := totp.QRCode(totp.URI("Example", "abc@example.com", rfcSecret), 256)
+	assert.NoError(t, err, "rendering should not return an error")
+
+	img, err := png.Decode(bytes.NewReader(data))
+	assert.NoError(t, err, "qr code should be a png")
+	assert.Equal(t, 256, img.Bounds().Dx(), "width should match")
+}
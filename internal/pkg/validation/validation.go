@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/password"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -12,7 +13,30 @@ type Validator interface {
 	Struct(s any) error
 }
 
-func Instance() *validator.Validate {
+type options struct {
+	passwordPolicy password.Policy
+}
+
+type Option func(*options)
+
+// WithPasswordPolicy sets the policy enforced by the password tag. The
+// default is password.DefaultPolicy.
+func WithPasswordPolicy(policy password.Policy) Option {
+	return func(o *options) {
+		o.passwordPolicy = policy
+	}
+}
+
+// Instance returns a validator that names fields by their JSON keys. It adds
+// the password tag, which checks a field against the password policy. Its
+// optional parameter names a sibling email field whose words the password
+// must not be built from, as in `validate:"password=Email"`.
+func Instance(opts ...Option) *validator.Validate {
+	o := options{passwordPolicy: password.DefaultPolicy()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	validate := validator.New()
 
 	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
@@ -23,5 +47,22 @@ func Instance() *validator.Validate {
 		return name
 	})
 
+	// Registration only fails for an empty tag or nil function.
+	_ = validate.RegisterValidation("password", passwordValidator(o.passwordPolicy))
+
 	return validate
 }
+
+func passwordValidator(policy password.Policy) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		var context []string
+		if name := fl.Param(); name != "" {
+			email, kind, _, ok := fl.GetStructFieldOKAdvanced2(fl.Parent(), name)
+			if ok && kind == reflect.String {
+				context = password.ContextWords(email.String())
+			}
+		}
+
+		return policy.Check(fl.Field().String(), context...) == nil
+	}
+}
@@ -0,0 +1,72 @@
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/password"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+const strongPassword = "vN4!qz8Rw#tK"
+
+func TestInstance_PasswordConfirm(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  any
+		wantErr bool
+	}{
+		{"matching sign-up passwords should be accepted",
+			model.UserSignUpParams{Email: "abc@example.com", Password: strongPassword, PasswordConfirm: strongPassword}, false},
+		{"mismatched sign-up passwords should be rejected",
+			model.UserSignUpParams{Email: "abc@example.com", Password: strongPassword, PasswordConfirm: "other"}, true},
+		{"matching reset passwords should be accepted",
+			model.ResetPasswordParams{Token: "token", Password: strongPassword, PasswordConfirm: strongPassword}, false},
+		{"mismatched reset passwords should be rejected",
+			model.ResetPasswordParams{Token: "token", Password: strongPassword, PasswordConfirm: "other"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validation.Instance().Struct(tt.params)
+			if tt.wantErr {
+				assert.Error(t, err, "validation should fail")
+			} else {
+				assert.NoError(t, err, "validation should pass")
+			}
+		})
+	}
+}
+
+func TestInstance_PasswordPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		wantErr  bool
+	}{
+		{"strong password should be accepted", "abc@example.com", strongPassword, false},
+		{"short password should be rejected", "abc@example.com", "a", true},
+		{"common password should be rejected", "abc@example.com", "Password123!", true},
+		{"password built from the email should be rejected", "jonathan.whitfield@example.com", "jonathanwhitfield", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := model.UserSignUpParams{Email: tt.email, Password: tt.password, PasswordConfirm: tt.password}
+			err := validation.Instance().Struct(params)
+			if tt.wantErr {
+				assert.Error(t, err, "validation should fail")
+			} else {
+				assert.NoError(t, err, "validation should pass")
+			}
+		})
+	}
+
+	t.Run("the policy should be configurable", func(t *testing.T) {
+		lenient := validation.Instance(validation.WithPasswordPolicy(password.Policy{MinLength: 1}))
+		params := model.ChangePasswordParams{CurrentPassword: "old", Password: "a", PasswordConfirm: "a"}
+		assert.NoError(t, lenient.Struct(params), "validation should pass")
+	})
+}
@@ -0,0 +1,118 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"slices"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Attestation statement formats.
+const (
+	FormatNone   = "none"
+	FormatPacked = "packed"
+)
+
+// oidFIDOAAGUID is the certificate extension holding the AAGUID of the
+// authenticator models an attestation certificate is issued for.
+var oidFIDOAAGUID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 45724, 1, 1, 4}
+
+type packedStatement struct {
+	Alg int64    `cbor:"alg"`
+	Sig []byte   `cbor:"sig"`
+	X5C [][]byte `cbor:"x5c,omitempty"`
+}
+
+// verifyAttestation checks the attestation statement of a new credential.
+func verifyAttestation(obj *attestationObject, authData *authenticatorData, key *publicKey,
+	clientDataHash []byte) error {
+	switch obj.Format {
+	case FormatNone:
+		var stmt map[string]cbor.RawMessage
+		if err := decMode.Unmarshal(obj.AttStmt, &stmt); err != nil || len(stmt) != 0 {
+			return fmt.Errorf("%w: none format with a statement", ErrInvalidAttestation)
+		}
+
+		return nil
+	case FormatPacked:
+		return verifyPacked(obj.AttStmt, authData, key, clientDataHash)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, obj.Format)
+	}
+}
+
+// verifyPacked checks a packed attestation statement (WebAuthn §8.2). With
+// an x5c certificate chain the signature is made by the attestation key in
+// its first certificate; otherwise it is self attestation, signed by the
+// credential key itself.
+func verifyPacked(raw cbor.RawMessage, authData *authenticatorData, key *publicKey,
+	clientDataHash []byte) error {
+	var stmt packedStatement
+	if err := decMode.Unmarshal(raw, &stmt); err != nil {
+		return fmt.Errorf("%w: decode packed statement: %v", ErrInvalidAttestation, err)
+	}
+
+	if !slices.Contains(supportedAlgorithms, stmt.Alg) || len(stmt.Sig) == 0 {
+		return fmt.Errorf("%w: packed algorithm %d", ErrInvalidAttestation, stmt.Alg)
+	}
+
+	signed := append(bytes.Clone(authData.raw), clientDataHash...)
+
+	if len(stmt.X5C) == 0 {
+		if stmt.Alg != key.alg {
+			return fmt.Errorf("%w: self attestation algorithm does not match the credential", ErrInvalidAttestation)
+		}
+
+		if err := key.verify(signed, stmt.Sig); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidAttestation, err)
+		}
+
+		return nil
+	}
+
+	cert, err := x509.ParseCertificate(stmt.X5C[0])
+	if err != nil {
+		return fmt.Errorf("%w: parse attestation certificate: %v", ErrInvalidAttestation, err)
+	}
+
+	if err := verifySignature(stmt.Alg, cert.PublicKey, signed, stmt.Sig); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAttestation, err)
+	}
+
+	return checkAttestationCertificate(cert, authData.credential.aaguid)
+}
+
+// checkAttestationCertificate applies the packed attestation certificate
+// requirements (WebAuthn §8.2.1).
+func checkAttestationCertificate(cert *x509.Certificate, aaguid []byte) error {
+	subject := cert.Subject
+	if cert.Version != 3 ||
+		len(subject.Country) == 0 || len(subject.Organization) == 0 || subject.CommonName == "" ||
+		!slices.Equal(subject.OrganizationalUnit, []string{"Authenticator Attestation"}) {
+		return fmt.Errorf("%w: attestation certificate subject", ErrInvalidAttestation)
+	}
+
+	if !cert.BasicConstraintsValid || cert.IsCA {
+		return fmt.Errorf("%w: attestation certificate is a CA", ErrInvalidAttestation)
+	}
+
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidFIDOAAGUID) {
+			continue
+		}
+
+		if ext.Critical {
+			return fmt.Errorf("%w: critical AAGUID extension", ErrInvalidAttestation)
+		}
+
+		var value []byte
+		if _, err := asn1.Unmarshal(ext.Value, &value); err != nil || !bytes.Equal(value, aaguid) {
+			return fmt.Errorf("%w: AAGUID does not match the certificate", ErrInvalidAttestation)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// COSE algorithm identifiers (RFC 9053).
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+// supportedAlgorithms lists the accepted algorithms in order of preference.
+var supportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+// COSE key types, curves and labels (RFC 9052, RFC 9053).
+const (
+	coseKtyOKP = 1
+	coseKtyEC2 = 2
+	coseKtyRSA = 3
+
+	coseCrvP256    = 1
+	coseCrvEd25519 = 6
+
+	coseLabelKty = 1
+	coseLabelAlg = 3
+	// EC2 and OKP keys.
+	coseLabelCrv = -1
+	coseLabelX   = -2
+	coseLabelY   = -3
+	// RSA keys.
+	coseLabelN = -1
+	coseLabelE = -2
+)
+
+// minRSABits is the smallest accepted RSA modulus.
+const minRSABits = 2048
+
+type publicKey struct {
+	alg int64
+	key crypto.PublicKey
+}
+
+func (k *publicKey) verify(data, sig []byte) error {
+	return verifySignature(k.alg, k.key, data, sig)
+}
+
+// parsePublicKey decodes a COSE_Key with one of the supported algorithms.
+func parsePublicKey(raw []byte) (*publicKey, error) {
+	var fields map[int]cbor.RawMessage
+	if err := decMode.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("%w: decode public key: %v", ErrUnsupportedKey, err)
+	}
+
+	var kty, alg int64
+	if err := decodeField(fields, coseLabelKty, &kty); err != nil {
+		return nil, err
+	}
+
+	if err := decodeField(fields, coseLabelAlg, &alg); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case kty == coseKtyEC2 && alg == AlgES256:
+		return parseEC2Key(fields)
+	case kty == coseKtyOKP && alg == AlgEdDSA:
+		return parseOKPKey(fields)
+	case kty == coseKtyRSA && alg == AlgRS256:
+		return parseRSAKey(fields)
+	default:
+		return nil, fmt.Errorf("%w: key type %d with algorithm %d", ErrUnsupportedKey, kty, alg)
+	}
+}
+
+func parseEC2Key(fields map[int]cbor.RawMessage) (*publicKey, error) {
+	var crv int64
+	var x, y []byte
+	if err := decodeFields(fields, map[int]any{coseLabelCrv: &crv, coseLabelX: &x, coseLabelY: &y}); err != nil {
+		return nil, err
+	}
+
+	if crv != coseCrvP256 || len(x) != 32 || len(y) != 32 {
+		return nil, fmt.Errorf("%w: invalid P-256 key", ErrUnsupportedKey)
+	}
+
+	// crypto/ecdh rejects points that are not on the curve.
+	point := make([]byte, 0, 65)
+	point = append(append(append(point, 4), x...), y...)
+	if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+		return nil, fmt.Errorf("%w: invalid P-256 key: %v", ErrUnsupportedKey, err)
+	}
+
+	key := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+
+	return &publicKey{alg: AlgES256, key: key}, nil
+}
+
+func parseOKPKey(fields map[int]cbor.RawMessage) (*publicKey, error) {
+	var crv int64
+	var x []byte
+	if err := decodeFields(fields, map[int]any{coseLabelCrv: &crv, coseLabelX: &x}); err != nil {
+		return nil, err
+	}
+
+	if crv != coseCrvEd25519 || len(x) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: invalid Ed25519 key", ErrUnsupportedKey)
+	}
+
+	return &publicKey{alg: AlgEdDSA, key: ed25519.PublicKey(x)}, nil
+}
+
+func parseRSAKey(fields map[int]cbor.RawMessage) (*publicKey, error) {
+	var n, e []byte
+	if err := decodeFields(fields, map[int]any{coseLabelN: &n, coseLabelE: &e}); err != nil {
+		return nil, err
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	modulus := new(big.Int).SetBytes(n)
+	if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 || modulus.BitLen() < minRSABits {
+		return nil, fmt.Errorf("%w: invalid RSA key", ErrUnsupportedKey)
+	}
+
+	return &publicKey{alg: AlgRS256, key: &rsa.PublicKey{N: modulus, E: int(exponent.Int64())}}, nil
+}
+
+func decodeFields(fields map[int]cbor.RawMessage, dst map[int]any) error {
+	for label, v := range dst {
+		if err := decodeField(fields, label, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decodeField(fields map[int]cbor.RawMessage, label int, v any) error {
+	raw, ok := fields[label]
+	if !ok {
+		return fmt.Errorf("%w: missing key parameter %d", ErrUnsupportedKey, label)
+	}
+
+	if err := decMode.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("%w: key parameter %d: %v", ErrUnsupportedKey, label, err)
+	}
+
+	return nil
+}
+
+// verifySignature checks sig over data with a key of the type alg requires.
+func verifySignature(alg int64, key crypto.PublicKey, data, sig []byte) error {
+	digest := sha256.Sum256(data)
+
+	ok := false
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		ok = alg == AlgES256 && k.Curve == elliptic.P256() && ecdsa.VerifyASN1(k, digest[:], sig)
+	case ed25519.PublicKey:
+		ok = alg == AlgEdDSA && ed25519.Verify(k, data, sig)
+	case *rsa.PublicKey:
+		ok = alg == AlgRS256 && rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+	}
+
+	if !ok {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package webauthn
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Values of ClientData.Type.
+const (
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+)
+
+// Sizes of the fixed fields of authenticator data.
+const (
+	rpIDHashLength      = 32
+	authDataMinLength   = rpIDHashLength + 1 + 4
+	aaguidLength        = 16
+	maxCredentialLength = 1023
+)
+
+// Bits of the authenticator data flags.
+const (
+	flagUserPresent    authFlags = 1 << 0
+	flagUserVerified   authFlags = 1 << 2
+	flagBackupEligible authFlags = 1 << 3
+	flagBackupState    authFlags = 1 << 4
+	flagAttestedData   authFlags = 1 << 6
+	flagExtensionData  authFlags = 1 << 7
+)
+
+// decMode decodes the CBOR of authenticators, rejecting duplicate map keys
+// and indefinite lengths, which CTAP2 canonical encoding does not allow.
+var decMode, _ = cbor.DecOptions{
+	DupMapKey:        cbor.DupMapKeyEnforcedAPF,
+	IndefLength:      cbor.IndefLengthForbidden,
+	MaxNestedLevels:  16,
+	MaxArrayElements: 64,
+	MaxMapPairs:      64,
+}.DecMode()
+
+// ClientData is the data the browser collects and the authenticator signs
+// the hash of.
+type ClientData struct {
+	Type string `json:"type"`
+	// Challenge is the base64url encoded challenge of the ceremony.
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// ParseClientData decodes clientDataJSON. It lets a caller look up the
+// challenge a response claims to answer before verifying the response.
+func ParseClientData(raw []byte) (*ClientData, error) {
+	var data ClientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("%w: decode client data: %v", ErrInvalidResponse, err)
+	}
+
+	return &data, nil
+}
+
+func (rp *RelyingParty) verifyClientData(raw []byte, ceremony string, challenge []byte) error {
+	data, err := ParseClientData(raw)
+	if err != nil {
+		return err
+	}
+
+	if data.Type != ceremony {
+		return fmt.Errorf("%w: client data type %q", ErrInvalidResponse, data.Type)
+	}
+
+	got, err := base64.RawURLEncoding.DecodeString(data.Challenge)
+	if err != nil || subtle.ConstantTimeCompare(got, challenge) != 1 {
+		return ErrChallengeMismatch
+	}
+
+	// Cross-origin ceremonies come from iframes, which are not supported.
+	if data.CrossOrigin || !slices.Contains(rp.Origins, data.Origin) {
+		return ErrOriginMismatch
+	}
+
+	return nil
+}
+
+type authFlags byte
+
+func (f authFlags) has(flag authFlags) bool {
+	return f&flag == flag
+}
+
+type attestedCredential struct {
+	aaguid    []byte
+	id        []byte
+	publicKey []byte
+}
+
+type authenticatorData struct {
+	raw        []byte
+	rpIDHash   []byte
+	flags      authFlags
+	signCount  uint32
+	credential *attestedCredential
+}
+
+func parseAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	if len(raw) < authDataMinLength {
+		return nil, fmt.Errorf("%w: authenticator data too short", ErrInvalidResponse)
+	}
+
+	data := &authenticatorData{
+		raw:       raw,
+		rpIDHash:  raw[:rpIDHashLength],
+		flags:     authFlags(raw[rpIDHashLength]),
+		signCount: binary.BigEndian.Uint32(raw[rpIDHashLength+1 : authDataMinLength]),
+	}
+
+	rest := raw[authDataMinLength:]
+	if data.flags.has(flagAttestedData) {
+		if len(rest) < aaguidLength+2 {
+			return nil, fmt.Errorf("%w: attested credential data too short", ErrInvalidResponse)
+		}
+
+		cred := &attestedCredential{aaguid: rest[:aaguidLength]}
+		idLen := int(binary.BigEndian.Uint16(rest[aaguidLength:]))
+		rest = rest[aaguidLength+2:]
+		if idLen > maxCredentialLength || len(rest) < idLen {
+			return nil, fmt.Errorf("%w: invalid credential ID length", ErrInvalidResponse)
+		}
+
+		cred.id, rest = rest[:idLen], rest[idLen:]
+
+		var key cbor.RawMessage
+		var err error
+		if rest, err = decMode.UnmarshalFirst(rest, &key); err != nil {
+			return nil, fmt.Errorf("%w: decode credential public key: %v", ErrInvalidResponse, err)
+		}
+
+		cred.publicKey = key
+		data.credential = cred
+	}
+
+	if data.flags.has(flagExtensionData) {
+		var extensions map[string]cbor.RawMessage
+		var err error
+		if rest, err = decMode.UnmarshalFirst(rest, &extensions); err != nil {
+			return nil, fmt.Errorf("%w: decode extensions: %v", ErrInvalidResponse, err)
+		}
+	}
+
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing authenticator data", ErrInvalidResponse)
+	}
+
+	return data, nil
+}
+
+func (rp *RelyingParty) verifyAuthenticatorData(data *authenticatorData) error {
+	want := sha256.Sum256([]byte(rp.ID))
+	if subtle.ConstantTimeCompare(data.rpIDHash, want[:]) != 1 {
+		return ErrRPIDMismatch
+	}
+
+	if !data.flags.has(flagUserPresent) {
+		return ErrUserNotPresent
+	}
+
+	if rp.RequireUserVerification && !data.flags.has(flagUserVerified) {
+		return ErrUserNotVerified
+	}
+
+	if data.flags.has(flagBackupState) && !data.flags.has(flagBackupEligible) {
+		return fmt.Errorf("%w: backed up credential is not backup eligible", ErrInvalidResponse)
+	}
+
+	return nil
+}
+
+type attestationObject struct {
+	Format   string          `cbor:"fmt"`
+	AttStmt  cbor.RawMessage `cbor:"attStmt"`
+	AuthData []byte          `cbor:"authData"`
+}
+
+func parseAttestationObject(raw []byte) (*attestationObject, error) {
+	var obj attestationObject
+	if err := decMode.Unmarshal(raw, &obj); err != nil {
+		return nil, fmt.Errorf("%w: decode attestation object: %v", ErrInvalidResponse, err)
+	}
+
+	return &obj, nil
+}
+
+func sha256Sum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
@@ -0,0 +1,372 @@
+// Package webauthn implements the relying party side of Web Authentication
+// (W3C WebAuthn Level 2) for passkeys: the options sent to the browser, and
+// verification of the registration and authentication ceremonies.
+//
+// Registration accepts the "none" and "packed" attestation formats. Packed
+// attestation statements are checked for integrity, but their certificates
+// are not chained to a trusted root, so they do not prove the authenticator
+// model.
+package webauthn
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+)
+
+// ChallengeLength is the size in bytes of generated challenges.
+const ChallengeLength = 32
+
+// Values of the enumerations in options and responses.
+const (
+	CredentialTypePublicKey = "public-key"
+
+	AttestationNone   = "none"
+	AttestationDirect = "direct"
+
+	ResidentKeyRequired  = "required"
+	ResidentKeyPreferred = "preferred"
+
+	UserVerificationRequired  = "required"
+	UserVerificationPreferred = "preferred"
+)
+
+// ErrInvalidResponse is matched with errors.Is by every error returned when
+// a ceremony response fails verification.
+var ErrInvalidResponse = errors.New("invalid webauthn response")
+
+var (
+	ErrChallengeMismatch   = fmt.Errorf("%w: challenge mismatch", ErrInvalidResponse)
+	ErrOriginMismatch      = fmt.Errorf("%w: origin not allowed", ErrInvalidResponse)
+	ErrRPIDMismatch        = fmt.Errorf("%w: relying party ID mismatch", ErrInvalidResponse)
+	ErrUserNotPresent      = fmt.Errorf("%w: user not present", ErrInvalidResponse)
+	ErrUserNotVerified     = fmt.Errorf("%w: user not verified", ErrInvalidResponse)
+	ErrUnsupportedKey      = fmt.Errorf("%w: unsupported public key", ErrInvalidResponse)
+	ErrUnsupportedFormat   = fmt.Errorf("%w: unsupported attestation format", ErrInvalidResponse)
+	ErrInvalidAttestation  = fmt.Errorf("%w: invalid attestation statement", ErrInvalidResponse)
+	ErrInvalidSignature    = fmt.Errorf("%w: invalid signature", ErrInvalidResponse)
+	ErrSignCountRegression = fmt.Errorf("%w: signature counter did not increase", ErrInvalidResponse)
+)
+
+// RelyingParty verifies ceremonies for one site.
+type RelyingParty struct {
+	// ID is the domain credentials are scoped to, such as "example.com".
+	ID string
+	// Name is shown to the user by the browser.
+	Name string
+	// Origins are the allowed origins of ceremonies, such as
+	// "https://example.com".
+	Origins []string
+	// Timeout is the time the browser gives the user to finish a ceremony.
+	Timeout time.Duration
+	// Attestation is the attestation conveyance preference, AttestationNone
+	// when empty.
+	Attestation string
+	// RequireUserVerification rejects authenticators that did not verify the
+	// user with a PIN or biometric.
+	RequireUserVerification bool
+}
+
+// NewChallenge returns a random challenge.
+func NewChallenge() ([]byte, error) {
+	return security.GenerateRandomBytes(ChallengeLength)
+}
+
+// Base64URL is binary data encoded in JSON as unpadded base64url, the
+// encoding of PublicKeyCredential.toJSON(). Padded input is accepted.
+type Base64URL []byte
+
+func (b Base64URL) String() string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (b Base64URL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+func (b *Base64URL) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return fmt.Errorf("decode base64url: %w", err)
+	}
+
+	*b = decoded
+	return nil
+}
+
+type RPEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserEntity identifies the account a credential is created for. The ID is
+// returned as the user handle of assertions and must not contain personal
+// information.
+type UserEntity struct {
+	ID          Base64URL `json:"id"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"displayName"`
+}
+
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+type CredentialDescriptor struct {
+	Type       string    `json:"type"`
+	ID         Base64URL `json:"id"`
+	Transports []string  `json:"transports,omitempty"`
+}
+
+type AuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// CreationOptions are passed to navigator.credentials.create() to register
+// a credential.
+type CreationOptions struct {
+	Challenge              Base64URL              `json:"challenge"`
+	RP                     RPEntity               `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout,omitempty"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials,omitempty"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions are passed to navigator.credentials.get() to sign in. An
+// empty AllowCredentials lets the user pick any passkey for the site.
+type RequestOptions struct {
+	Challenge        Base64URL              `json:"challenge"`
+	Timeout          int64                  `json:"timeout,omitempty"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials,omitempty"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// CreationOptions returns the options to register a discoverable credential
+// for user. Credentials in exclude are already registered, and the browser
+// will not create a second one on the same authenticator.
+func (rp *RelyingParty) CreationOptions(challenge []byte, user UserEntity,
+	exclude []CredentialDescriptor) *CreationOptions {
+	attestation := rp.Attestation
+	if attestation == "" {
+		attestation = AttestationNone
+	}
+
+	params := make([]CredentialParameter, len(supportedAlgorithms))
+	for i, alg := range supportedAlgorithms {
+		params[i] = CredentialParameter{Type: CredentialTypePublicKey, Alg: alg}
+	}
+
+	return &CreationOptions{
+		Challenge:          challenge,
+		RP:                 RPEntity{ID: rp.ID, Name: rp.Name},
+		User:               user,
+		PubKeyCredParams:   params,
+		Timeout:            rp.Timeout.Milliseconds(),
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:        ResidentKeyRequired,
+			RequireResidentKey: true,
+			UserVerification:   rp.userVerification(),
+		},
+		Attestation: attestation,
+	}
+}
+
+// RequestOptions returns the options to sign in with one of the credentials
+// in allow, or with any discoverable credential when allow is empty.
+func (rp *RelyingParty) RequestOptions(challenge []byte, allow []CredentialDescriptor) *RequestOptions {
+	return &RequestOptions{
+		Challenge:        challenge,
+		Timeout:          rp.Timeout.Milliseconds(),
+		RPID:             rp.ID,
+		AllowCredentials: allow,
+		UserVerification: rp.userVerification(),
+	}
+}
+
+func (rp *RelyingParty) userVerification() string {
+	if rp.RequireUserVerification {
+		return UserVerificationRequired
+	}
+
+	return UserVerificationPreferred
+}
+
+// AttestationResponse is the JSON form of the PublicKeyCredential returned
+// by navigator.credentials.create().
+type AttestationResponse struct {
+	ID                      string         `json:"id"`
+	RawID                   Base64URL      `json:"rawId"`
+	Type                    string         `json:"type"`
+	AuthenticatorAttachment string         `json:"authenticatorAttachment,omitempty"`
+	ClientExtensionResults  map[string]any `json:"clientExtensionResults,omitempty"`
+	Response                struct {
+		ClientDataJSON     Base64URL `json:"clientDataJSON"`
+		AttestationObject  Base64URL `json:"attestationObject"`
+		Transports         []string  `json:"transports,omitempty"`
+		AuthenticatorData  Base64URL `json:"authenticatorData,omitempty"`
+		PublicKey          Base64URL `json:"publicKey,omitempty"`
+		PublicKeyAlgorithm int64     `json:"publicKeyAlgorithm,omitempty"`
+	} `json:"response"`
+}
+
+// AssertionResponse is the JSON form of the PublicKeyCredential returned by
+// navigator.credentials.get().
+type AssertionResponse struct {
+	ID                      string         `json:"id"`
+	RawID                   Base64URL      `json:"rawId"`
+	Type                    string         `json:"type"`
+	AuthenticatorAttachment string         `json:"authenticatorAttachment,omitempty"`
+	ClientExtensionResults  map[string]any `json:"clientExtensionResults,omitempty"`
+	Response                struct {
+		ClientDataJSON    Base64URL `json:"clientDataJSON"`
+		AuthenticatorData Base64URL `json:"authenticatorData"`
+		Signature         Base64URL `json:"signature"`
+		UserHandle        Base64URL `json:"userHandle,omitempty"`
+	} `json:"response"`
+}
+
+// Credential is a verified public key credential, to be stored with the
+// user it was registered for.
+type Credential struct {
+	ID []byte
+	// PublicKey is the COSE_Key of the credential.
+	PublicKey         []byte
+	SignCount         uint32
+	AAGUID            []byte
+	AttestationFormat string
+	Transports        []string
+	BackupEligible    bool
+	BackupState       bool
+}
+
+// Assertion is the result of a verified authentication ceremony.
+type Assertion struct {
+	SignCount    uint32
+	UserVerified bool
+	BackupState  bool
+}
+
+// VerifyRegistration checks the response to CreationOptions issued with
+// challenge, and returns the new credential.
+func (rp *RelyingParty) VerifyRegistration(challenge []byte, resp *AttestationResponse) (*Credential, error) {
+	if resp.Type != CredentialTypePublicKey {
+		return nil, fmt.Errorf("%w: credential type %q", ErrInvalidResponse, resp.Type)
+	}
+
+	if err := rp.verifyClientData(resp.Response.ClientDataJSON, ceremonyCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	obj, err := parseAttestationObject(resp.Response.AttestationObject)
+	if err != nil {
+		return nil, err
+	}
+
+	authData, err := parseAuthenticatorData(obj.AuthData)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rp.verifyAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+
+	if authData.credential == nil {
+		return nil, fmt.Errorf("%w: no attested credential data", ErrInvalidResponse)
+	}
+
+	if !bytes.Equal(resp.RawID, authData.credential.id) {
+		return nil, fmt.Errorf("%w: credential ID mismatch", ErrInvalidResponse)
+	}
+
+	key, err := parsePublicKey(authData.credential.publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256Sum(resp.Response.ClientDataJSON)
+	if err := verifyAttestation(obj, authData, key, clientDataHash); err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:                authData.credential.id,
+		PublicKey:         authData.credential.publicKey,
+		SignCount:         authData.signCount,
+		AAGUID:            authData.credential.aaguid,
+		AttestationFormat: obj.Format,
+		Transports:        resp.Response.Transports,
+		BackupEligible:    authData.flags.has(flagBackupEligible),
+		BackupState:       authData.flags.has(flagBackupState),
+	}, nil
+}
+
+// VerifyAssertion checks the response to RequestOptions issued with
+// challenge against a stored credential. The caller must check that the
+// credential belongs to the user being signed in, and store the new
+// signature counter.
+func (rp *RelyingParty) VerifyAssertion(challenge []byte, cred *Credential,
+	resp *AssertionResponse) (*Assertion, error) {
+	if resp.Type != CredentialTypePublicKey {
+		return nil, fmt.Errorf("%w: credential type %q", ErrInvalidResponse, resp.Type)
+	}
+
+	if !bytes.Equal(resp.RawID, cred.ID) {
+		return nil, fmt.Errorf("%w: credential ID mismatch", ErrInvalidResponse)
+	}
+
+	if err := rp.verifyClientData(resp.Response.ClientDataJSON, ceremonyGet, challenge); err != nil {
+		return nil, err
+	}
+
+	authData, err := parseAuthenticatorData(resp.Response.AuthenticatorData)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rp.verifyAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+
+	key, err := parsePublicKey(cred.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := append(bytes.Clone(resp.Response.AuthenticatorData), sha256Sum(resp.Response.ClientDataJSON)...)
+	if err := key.verify(signed, resp.Response.Signature); err != nil {
+		return nil, err
+	}
+
+	// Authenticators that do not count signatures always report zero. A
+	// counter that did not increase means the credential may have been
+	// cloned.
+	if (authData.signCount != 0 || cred.SignCount != 0) && authData.signCount <= cred.SignCount {
+		return nil, ErrSignCountRegression
+	}
+
+	return &Assertion{
+		SignCount:    authData.signCount,
+		UserVerified: authData.flags.has(flagUserVerified),
+		BackupState:  authData.flags.has(flagBackupState),
+	}, nil
+}
@@ -0,0 +1,285 @@
+package webauthn_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/webauthn"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/webauthn/webauthntest"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://example.com"
+)
+
+func newRelyingParty() *webauthn.RelyingParty {
+	return &webauthn.RelyingParty{
+		ID:      testRPID,
+		Name:    "Example",
+		Origins: []string{testOrigin},
+	}
+}
+
+func newChallenge(t *testing.T) []byte {
+	t.Helper()
+
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		t.Fatalf("NewChallenge should not return an error: %v", err)
+	}
+	return challenge
+}
+
+func creationOptions(t *testing.T, rp *webauthn.RelyingParty) *webauthn.CreationOptions {
+	t.Helper()
+
+	user := webauthn.UserEntity{ID: []byte("user-1"), Name: "user@example.com", DisplayName: "user@example.com"}
+	return rp.CreationOptions(newChallenge(t), user, nil)
+}
+
+// register creates a credential on auth and returns it as verified.
+func register(t *testing.T, rp *webauthn.RelyingParty, auth *webauthntest.Authenticator) *webauthn.Credential {
+	t.Helper()
+
+	opts := creationOptions(t, rp)
+	cred, err := rp.VerifyRegistration(opts.Challenge, auth.Register(t, opts))
+	if err != nil {
+		t.Fatalf("VerifyRegistration should not return an error: %v", err)
+	}
+	return cred
+}
+
+func TestRelyingParty_VerifyRegistration(t *testing.T) {
+	tests := []struct {
+		name        string
+		attestation int
+		format      string
+	}{
+		{"none attestation should be accepted", webauthntest.AttestationNone, webauthn.FormatNone},
+		{"packed self attestation should be accepted", webauthntest.AttestationSelf, webauthn.FormatPacked},
+		{"packed basic attestation should be accepted", webauthntest.AttestationBasic, webauthn.FormatPacked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp := newRelyingParty()
+			auth := webauthntest.New(t)
+			auth.Attestation = tt.attestation
+
+			cred := register(t, rp, auth)
+			assert.Equal(t, auth.CredentialID(), cred.ID, "credential ID should match")
+			assert.Equal(t, tt.format, cred.AttestationFormat, "attestation format should match")
+			assert.Equal(t, auth.AAGUID, cred.AAGUID, "AAGUID should match")
+			assert.Equal(t, []string{"internal"}, cred.Transports, "transports should match")
+		})
+	}
+}
+
+func TestRelyingParty_VerifyRegistration_Rejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(rp *webauthn.RelyingParty, auth *webauthntest.Authenticator)
+		tamper  func(resp *webauthn.AttestationResponse)
+		wantErr error
+	}{
+		{
+			name:    "another origin",
+			setup:   func(_ *webauthn.RelyingParty, auth *webauthntest.Authenticator) { auth.Origin = "https://evil.example" },
+			wantErr: webauthn.ErrOriginMismatch,
+		},
+		{
+			name: "another relying party",
+			setup: func(rp *webauthn.RelyingParty, auth *webauthntest.Authenticator) {
+				rp.ID = "other.example"
+				auth.Origin = testOrigin
+			},
+			wantErr: webauthn.ErrRPIDMismatch,
+		},
+		{
+			name: "unverified user when verification is required",
+			setup: func(rp *webauthn.RelyingParty, auth *webauthntest.Authenticator) {
+				rp.RequireUserVerification = true
+				auth.UserVerified = false
+			},
+			wantErr: webauthn.ErrUserNotVerified,
+		},
+		{
+			name: "forged self attestation",
+			setup: func(_ *webauthn.RelyingParty, auth *webauthntest.Authenticator) {
+				auth.Attestation = webauthntest.AttestationSelf
+			},
+			tamper: func(resp *webauthn.AttestationResponse) {
+				resp.Response.ClientDataJSON = append(resp.Response.ClientDataJSON, ' ')
+			},
+			wantErr: webauthn.ErrInvalidAttestation,
+		},
+		{
+			name:    "mismatched raw ID",
+			tamper:  func(resp *webauthn.AttestationResponse) { resp.RawID = []byte("other") },
+			wantErr: webauthn.ErrInvalidResponse,
+		},
+		{
+			name:    "malformed attestation object",
+			tamper:  func(resp *webauthn.AttestationResponse) { resp.Response.AttestationObject = []byte{0xff} },
+			wantErr: webauthn.ErrInvalidResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" should be rejected", func(t *testing.T) {
+			rp := newRelyingParty()
+			auth := webauthntest.New(t)
+			if tt.setup != nil {
+				tt.setup(rp, auth)
+			}
+
+			opts := creationOptions(t, rp)
+			resp := auth.Register(t, opts)
+			if tt.tamper != nil {
+				tt.tamper(resp)
+			}
+
+			// Verify against the original relying party ID and origin.
+			verifier := newRelyingParty()
+			verifier.RequireUserVerification = rp.RequireUserVerification
+			_, err := verifier.VerifyRegistration(opts.Challenge, resp)
+			assert.ErrorIs(t, err, tt.wantErr, "VerifyRegistration should return the expected error")
+			assert.ErrorIs(t, err, webauthn.ErrInvalidResponse, "errors should match ErrInvalidResponse")
+		})
+	}
+}
+
+func TestRelyingParty_VerifyRegistration_WrongChallenge(t *testing.T) {
+	rp := newRelyingParty()
+	resp := webauthntest.New(t).Register(t, creationOptions(t, rp))
+
+	_, err := rp.VerifyRegistration(newChallenge(t), resp)
+	assert.ErrorIs(t, err, webauthn.ErrChallengeMismatch, "a response to another challenge should be rejected")
+}
+
+func TestRelyingParty_VerifyAssertion(t *testing.T) {
+	rp := newRelyingParty()
+	auth := webauthntest.New(t)
+	cred := register(t, rp, auth)
+
+	for i := 1; i <= 2; i++ {
+		opts := rp.RequestOptions(newChallenge(t), nil)
+		resp := auth.Login(t, opts)
+		assert.Equal(t, []byte("user-1"), []byte(resp.Response.UserHandle), "user handle should match")
+
+		assertion, err := rp.VerifyAssertion(opts.Challenge, cred, resp)
+		if err != nil {
+			t.Fatalf("VerifyAssertion should not return an error: %v", err)
+		}
+		assert.Equal(t, uint32(i), assertion.SignCount, "signature counter should match")
+		assert.True(t, assertion.UserVerified, "user should be verified")
+
+		cred.SignCount = assertion.SignCount
+	}
+}
+
+func TestRelyingParty_VerifyAssertion_Rejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  func(cred *webauthn.Credential, resp *webauthn.AssertionResponse)
+		wantErr error
+	}{
+		{
+			name: "bad signature",
+			tamper: func(_ *webauthn.Credential, resp *webauthn.AssertionResponse) {
+				resp.Response.Signature[len(resp.Response.Signature)-1] ^= 0xff
+			},
+			wantErr: webauthn.ErrInvalidSignature,
+		},
+		{
+			name: "signature counter that did not increase",
+			tamper: func(cred *webauthn.Credential, _ *webauthn.AssertionResponse) {
+				cred.SignCount = 10
+			},
+			wantErr: webauthn.ErrSignCountRegression,
+		},
+		{
+			name: "another credential",
+			tamper: func(cred *webauthn.Credential, _ *webauthn.AssertionResponse) {
+				cred.ID = []byte("other")
+			},
+			wantErr: webauthn.ErrInvalidResponse,
+		},
+		{
+			name: "registration client data",
+			tamper: func(_ *webauthn.Credential, resp *webauthn.AssertionResponse) {
+				resp.Response.ClientDataJSON = bytes.Replace(resp.Response.ClientDataJSON,
+					[]byte("webauthn.get"), []byte("webauthn.create"), 1)
+			},
+			wantErr: webauthn.ErrInvalidResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" should be rejected", func(t *testing.T) {
+			rp := newRelyingParty()
+			auth := webauthntest.New(t)
+			cred := register(t, rp, auth)
+
+			opts := rp.RequestOptions(newChallenge(t), nil)
+			resp := auth.Login(t, opts)
+			tt.tamper(cred, resp)
+
+			_, err := rp.VerifyAssertion(opts.Challenge, cred, resp)
+			assert.ErrorIs(t, err, tt.wantErr, "VerifyAssertion should return the expected error")
+		})
+	}
+}
+
+func TestRelyingParty_VerifyAssertion_ClonedAuthenticator(t *testing.T) {
+	rp := newRelyingParty()
+	auth := webauthntest.New(t)
+	cred := register(t, rp, auth)
+
+	opts := rp.RequestOptions(newChallenge(t), nil)
+	assertion, err := rp.VerifyAssertion(opts.Challenge, cred, auth.Login(t, opts))
+	if err != nil {
+		t.Fatalf("VerifyAssertion should not return an error: %v", err)
+	}
+	cred.SignCount = assertion.SignCount
+
+	// A clone carries on from the counter value it was copied at.
+	auth.SignCount = 0
+	opts = rp.RequestOptions(newChallenge(t), nil)
+	_, err = rp.VerifyAssertion(opts.Challenge, cred, auth.Login(t, opts))
+	assert.ErrorIs(t, err, webauthn.ErrSignCountRegression, "a replayed counter should be rejected")
+}
+
+func TestResponses_JSONRoundTrip(t *testing.T) {
+	rp := newRelyingParty()
+	auth := webauthntest.New(t)
+	opts := creationOptions(t, rp)
+
+	data, err := json.Marshal(auth.Register(t, opts))
+	if err != nil {
+		t.Fatalf("marshal should not return an error: %v", err)
+	}
+
+	var resp webauthn.AttestationResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	_, err = rp.VerifyRegistration(opts.Challenge, &resp)
+	assert.NoError(t, err, "a decoded response should verify")
+}
+
+func TestRelyingParty_CreationOptions(t *testing.T) {
+	rp := newRelyingParty()
+	rp.RequireUserVerification = true
+	opts := creationOptions(t, rp)
+
+	assert.Equal(t, testRPID, opts.RP.ID, "relying party ID should match")
+	assert.Equal(t, webauthn.AttestationNone, opts.Attestation, "attestation should default to none")
+	assert.Equal(t, webauthn.UserVerificationRequired, opts.AuthenticatorSelection.UserVerification,
+		"user verification should match")
+	assert.Equal(t, webauthn.AlgES256, opts.PubKeyCredParams[0].Alg, "ES256 should be preferred")
+}
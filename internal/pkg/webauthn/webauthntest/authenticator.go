@@ -0,0 +1,259 @@
+// Package webauthntest provides a software authenticator that answers
+// WebAuthn ceremonies in tests, the way a browser and a security key would.
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/webauthn"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Attestation statements the authenticator can produce.
+const (
+	AttestationNone = iota
+	// AttestationSelf is packed attestation signed by the credential key.
+	AttestationSelf
+	// AttestationBasic is packed attestation signed by a key certified by a
+	// generated attestation certificate.
+	AttestationBasic
+)
+
+// Authenticator holds one ES256 credential.
+type Authenticator struct {
+	// Origin is reported in the client data. It defaults to
+	// "https://" + the relying party ID.
+	Origin string
+	// Attestation selects the statement returned when registering.
+	Attestation int
+	// UserVerified sets the UV flag, as after a PIN or biometric check.
+	UserVerified bool
+	// SignCount is the signature counter, incremented before each
+	// assertion. Tests can reset it to simulate a cloned credential.
+	SignCount uint32
+	AAGUID    []byte
+
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+}
+
+// New returns an authenticator that verifies its user and counts signatures.
+func New(t testing.TB) *Authenticator {
+	t.Helper()
+
+	return &Authenticator{
+		UserVerified: true,
+		AAGUID:       randomBytes(t, 16),
+	}
+}
+
+// CredentialID returns the ID of the credential created by Register.
+func (a *Authenticator) CredentialID() []byte {
+	return a.credentialID
+}
+
+// Register creates a credential for the given options.
+func (a *Authenticator) Register(t testing.TB, opts *webauthn.CreationOptions) *webauthn.AttestationResponse {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate credential key: %v", err)
+	}
+
+	a.key = key
+	a.credentialID = randomBytes(t, 32)
+	a.userHandle = opts.User.ID
+
+	clientData := a.clientData(t, "webauthn.create", opts.Challenge, opts.RP.ID)
+	authData := a.authData(opts.RP.ID, true)
+	authData = append(authData, a.AAGUID...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, marshalCBOR(t, a.coseKey())...)
+
+	obj := map[string]any{
+		"fmt":      webauthn.FormatNone,
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	}
+
+	signed := append(authData, sum(clientData)...)
+	switch a.Attestation {
+	case AttestationSelf:
+		obj["fmt"] = webauthn.FormatPacked
+		obj["attStmt"] = map[string]any{"alg": webauthn.AlgES256, "sig": sign(t, a.key, signed)}
+	case AttestationBasic:
+		certKey, cert := a.attestationCertificate(t)
+		obj["fmt"] = webauthn.FormatPacked
+		obj["attStmt"] = map[string]any{
+			"alg": webauthn.AlgES256,
+			"sig": sign(t, certKey, signed),
+			"x5c": [][]byte{cert},
+		}
+	}
+
+	resp := &webauthn.AttestationResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		RawID: a.credentialID,
+		Type:  webauthn.CredentialTypePublicKey,
+	}
+	resp.Response.ClientDataJSON = clientData
+	resp.Response.AttestationObject = marshalCBOR(t, obj)
+	resp.Response.Transports = []string{"internal"}
+
+	return resp
+}
+
+// Login signs the challenge of the given options with the credential
+// created by Register.
+func (a *Authenticator) Login(t testing.TB, opts *webauthn.RequestOptions) *webauthn.AssertionResponse {
+	t.Helper()
+
+	if a.key == nil {
+		t.Fatal("login before register")
+	}
+
+	a.SignCount++
+
+	clientData := a.clientData(t, "webauthn.get", opts.Challenge, opts.RPID)
+	authData := a.authData(opts.RPID, false)
+
+	resp := &webauthn.AssertionResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		RawID: a.credentialID,
+		Type:  webauthn.CredentialTypePublicKey,
+	}
+	resp.Response.ClientDataJSON = clientData
+	resp.Response.AuthenticatorData = authData
+	resp.Response.Signature = sign(t, a.key, append(authData, sum(clientData)...))
+	resp.Response.UserHandle = a.userHandle
+
+	return resp
+}
+
+func (a *Authenticator) clientData(t testing.TB, typ string, challenge []byte, rpID string) []byte {
+	origin := a.Origin
+	if origin == "" {
+		origin = "https://" + rpID
+	}
+
+	data, err := json.Marshal(webauthn.ClientData{
+		Type:      typ,
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+		Origin:    origin,
+	})
+	if err != nil {
+		t.Fatalf("marshal client data: %v", err)
+	}
+
+	return data
+}
+
+func (a *Authenticator) authData(rpID string, attested bool) []byte {
+	flags := byte(0x01) // user present
+	if a.UserVerified {
+		flags |= 0x04
+	}
+
+	if attested {
+		flags |= 0x40
+	}
+
+	data := sum([]byte(rpID))
+	data = append(data, flags)
+	return binary.BigEndian.AppendUint32(data, a.SignCount)
+}
+
+func (a *Authenticator) coseKey() map[int]any {
+	return map[int]any{
+		1:  2, // EC2
+		3:  webauthn.AlgES256,
+		-1: 1, // P-256
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	}
+}
+
+// attestationCertificate returns a key and a self-signed certificate that
+// meet the packed attestation requirements.
+func (a *Authenticator) attestationCertificate(t testing.TB) (*ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate attestation key: %v", err)
+	}
+
+	aaguid, err := asn1.Marshal(a.AAGUID)
+	if err != nil {
+		t.Fatalf("marshal aaguid: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{
+			Country:            []string{"US"},
+			Organization:       []string{"Test Authenticators"},
+			OrganizationalUnit: []string{"Authenticator Attestation"},
+			CommonName:         "Test Attestation",
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		ExtraExtensions: []pkix.Extension{
+			{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 45724, 1, 1, 4}, Value: aaguid},
+		},
+	}
+
+	cert, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create attestation certificate: %v", err)
+	}
+
+	return key, cert
+}
+
+func sign(t testing.TB, key *ecdsa.PrivateKey, data []byte) []byte {
+	digest := sha256.Sum256(data)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	return sig
+}
+
+func marshalCBOR(t testing.TB, v any) []byte {
+	data, err := cbor.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal cbor: %v", err)
+	}
+
+	return data
+}
+
+func randomBytes(t testing.TB, n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("random bytes: %v", err)
+	}
+
+	return b
+}
+
+func sum(data []byte) []byte {
+	s := sha256.Sum256(data)
+	return s[:]
+}
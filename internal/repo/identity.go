@@ -0,0 +1,117 @@
+//go:generate mockgen -destination=mocks/identity_repo_mock.go -package=mocks . IdentityRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/db"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type IdentityRepo interface {
+	CreateIdentity(ctx context.Context, params model.Identity) (*model.Identity, error)
+	FindIdentity(ctx context.Context, provider, subject string) (*model.Identity, error)
+	ListUserIdentities(ctx context.Context, userID string) ([]model.Identity, error)
+	UpdateIdentityUse(ctx context.Context, provider, subject, email string, usedAt time.Time) error
+}
+
+type identityRepo struct {
+	db *sql.DB
+}
+
+func NewIdentityRepo(db *sql.DB) IdentityRepo {
+	return &identityRepo{
+		db: db,
+	}
+}
+
+const CreateIdentityQuery = `
+INSERT INTO user_identities (provider, subject, user_id, email, last_used_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING provider, subject, user_id, email, last_used_at, created_at
+`
+
+// CreateIdentity links a provider account to a user. It returns ErrDuplicate
+// if the account is already linked.
+func (r *identityRepo) CreateIdentity(ctx context.Context, params model.Identity) (*model.Identity, error) {
+	row := r.db.QueryRowContext(ctx, CreateIdentityQuery, params.Provider, params.Subject, params.UserID,
+		params.Email, params.LastUsedAt)
+
+	identity, err := scanIdentity(row)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return nil, ErrDuplicate
+		}
+
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+const FindIdentityQuery = `
+SELECT provider, subject, user_id, email, last_used_at, created_at
+FROM user_identities
+WHERE provider = $1 AND subject = $2
+`
+
+func (r *identityRepo) FindIdentity(ctx context.Context, provider, subject string) (*model.Identity, error) {
+	return scanIdentity(r.db.QueryRowContext(ctx, FindIdentityQuery, provider, subject))
+}
+
+const ListUserIdentitiesQuery = `
+SELECT provider, subject, user_id, email, last_used_at, created_at
+FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at, provider
+`
+
+func (r *identityRepo) ListUserIdentities(ctx context.Context, userID string) ([]model.Identity, error) {
+	rows, err := r.db.QueryContext(ctx, ListUserIdentitiesQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := []model.Identity{}
+	for rows.Next() {
+		identity, err := scanIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		identities = append(identities, *identity)
+	}
+
+	return identities, rows.Err()
+}
+
+const UpdateIdentityUseQuery = `
+UPDATE user_identities
+SET email = $3, last_used_at = $4
+WHERE provider = $1 AND subject = $2
+`
+
+// UpdateIdentityUse records a sign-in with the provider account and the
+// email it reported.
+func (r *identityRepo) UpdateIdentityUse(ctx context.Context, provider, subject, email string,
+	usedAt time.Time) error {
+	res, err := r.db.ExecContext(ctx, UpdateIdentityUseQuery, provider, subject, email, usedAt)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(res)
+}
+
+func scanIdentity(row rowScanner) (*model.Identity, error) {
+	var identity model.Identity
+	if err := row.Scan(&identity.Provider, &identity.Subject, &identity.UserID, &identity.Email,
+		&identity.LastUsedAt, &identity.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &identity, nil
+}
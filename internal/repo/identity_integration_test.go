@@ -0,0 +1,50 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityRepo_Integration_Lifecycle(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	identities := repo.NewIdentityRepo(conn)
+	ctx := context.Background()
+
+	params := model.Identity{
+		Provider: testProvider,
+		Subject:  testSubject,
+		UserID:   user.ID,
+		Email:    testEmail,
+	}
+	created, err := identities.CreateIdentity(ctx, params)
+	assert.NoError(t, err, "create identity should not return an error")
+	assert.Nil(t, created.LastUsedAt, "last use must be empty")
+
+	other := createTestUser(t, repo.NewUserRepo(conn), "other@example.com")
+	params.UserID = other.ID
+	_, err = identities.CreateIdentity(ctx, params)
+	assert.ErrorIs(t, err, repo.ErrDuplicate, "provider accounts should only be linked once")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	assert.NoError(t, identities.UpdateIdentityUse(ctx, testProvider, testSubject, "new@example.com", now),
+		"update identity use should not return an error")
+	assert.ErrorIs(t, identities.UpdateIdentityUse(ctx, testProvider, "unknown", testEmail, now), sql.ErrNoRows,
+		"unknown identities should not be updated")
+
+	found, err := identities.FindIdentity(ctx, testProvider, testSubject)
+	assert.NoError(t, err, "find identity should not return an error")
+	assert.Equal(t, user.ID, found.UserID, "user ID must match")
+	assert.Equal(t, "new@example.com", found.Email, "email must match")
+	assert.NotNil(t, found.LastUsedAt, "last use should be recorded")
+
+	list, err := identities.ListUserIdentities(ctx, user.ID)
+	assert.NoError(t, err, "list identities should not return an error")
+	assert.Len(t, list, 1, "list length must match")
+}
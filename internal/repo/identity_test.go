@@ -0,0 +1,80 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testProvider = "google"
+	testSubject  = "248289761001"
+)
+
+var identityCols = []string{"provider", "subject", "user_id", "email", "last_used_at", "created_at"}
+
+func TestIdentityRepo_CreateIdentity_Success(t *testing.T) {
+	mock, identities := setupMockIdentityRepo(t)
+	now := time.Now().UTC()
+	params := model.Identity{
+		Provider:   testProvider,
+		Subject:    testSubject,
+		UserID:     testID,
+		Email:      testEmail,
+		LastUsedAt: &now,
+	}
+
+	mock.ExpectQuery(repo.CreateIdentityQuery).
+		WithArgs(params.Provider, params.Subject, params.UserID, params.Email, params.LastUsedAt).
+		WillReturnRows(sqlmock.NewRows(identityCols).
+			AddRow(params.Provider, params.Subject, params.UserID, params.Email, now, now))
+
+	identity, err := identities.CreateIdentity(context.Background(), params)
+
+	assert.NoError(t, err, "create identity should not return an error")
+	assert.Equal(t, testID, identity.UserID, "user ID must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestIdentityRepo_FindIdentity_NotFound(t *testing.T) {
+	mock, identities := setupMockIdentityRepo(t)
+	mock.ExpectQuery(repo.FindIdentityQuery).
+		WithArgs(testProvider, testSubject).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := identities.FindIdentity(context.Background(), testProvider, testSubject)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestIdentityRepo_ListUserIdentities_Empty(t *testing.T) {
+	mock, identities := setupMockIdentityRepo(t)
+	mock.ExpectQuery(repo.ListUserIdentitiesQuery).
+		WithArgs(testID).
+		WillReturnRows(sqlmock.NewRows(identityCols))
+
+	list, err := identities.ListUserIdentities(context.Background(), testID)
+
+	assert.NoError(t, err, "list identities should not return an error")
+	assert.NotNil(t, list, "an empty list should not be nil")
+	assert.Empty(t, list, "list should be empty")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockIdentityRepo(t *testing.T) (sqlmock.Sqlmock, repo.IdentityRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	identities := repo.NewIdentityRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, identities
+}
@@ -0,0 +1,128 @@
+//go:generate mockgen -destination=mocks/invitation_repo_mock.go -package=mocks . InvitationRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/db"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type InvitationRepo interface {
+	CreateInvitation(ctx context.Context, params model.Invitation) (*model.Invitation, error)
+	FindInvitation(ctx context.Context, id string) (*model.Invitation, error)
+	AcceptInvitation(ctx context.Context, id string, user model.User, verifiedAt time.Time) (string, error)
+	DeleteInvitations(ctx context.Context, organizationID, email string) error
+}
+
+type invitationRepo struct {
+	db *sql.DB
+}
+
+func NewInvitationRepo(db *sql.DB) InvitationRepo {
+	return &invitationRepo{
+		db: db,
+	}
+}
+
+const CreateInvitationQuery = `
+INSERT INTO invitations (id, organization_id, email, role, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, organization_id, email, role, expires_at, created_at
+`
+
+func (r *invitationRepo) CreateInvitation(ctx context.Context, params model.Invitation) (*model.Invitation, error) {
+	var inv model.Invitation
+	if err := r.db.QueryRowContext(ctx, CreateInvitationQuery,
+		params.ID, params.OrganizationID, params.Email, params.Role, params.ExpiresAt).
+		Scan(&inv.ID, &inv.OrganizationID, &inv.Email, &inv.Role, &inv.ExpiresAt, &inv.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &inv, nil
+}
+
+const FindInvitationQuery = `
+SELECT id, organization_id, email, role, expires_at, created_at
+FROM invitations
+WHERE id = $1
+`
+
+func (r *invitationRepo) FindInvitation(ctx context.Context, id string) (*model.Invitation, error) {
+	var inv model.Invitation
+	if err := r.db.QueryRowContext(ctx, FindInvitationQuery, id).
+		Scan(&inv.ID, &inv.OrganizationID, &inv.Email, &inv.Role, &inv.ExpiresAt, &inv.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &inv, nil
+}
+
+const ConsumeInvitationQuery = `
+DELETE FROM invitations
+WHERE id = $1
+RETURNING id, organization_id, email, role, expires_at, created_at
+`
+
+// AcceptInvitation consumes the invitation and makes the user a member with
+// its role in a single transaction, so that a failure leaves the invitation
+// usable. A user without an ID is first created from its email and password
+// hash, with the email verified at verifiedAt. It returns the ID of the
+// member, sql.ErrNoRows if the invitation was already consumed, and
+// ErrDuplicate if the user to create has a taken email.
+func (r *invitationRepo) AcceptInvitation(ctx context.Context, id string, user model.User,
+	verifiedAt time.Time) (string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback() //nolint:errcheck // a no-op after Commit
+
+	var inv model.Invitation
+	if err := tx.QueryRowContext(ctx, ConsumeInvitationQuery, id).
+		Scan(&inv.ID, &inv.OrganizationID, &inv.Email, &inv.Role, &inv.ExpiresAt, &inv.CreatedAt); err != nil {
+		return "", err
+	}
+
+	if user.ID == "" {
+		var created model.User
+		if err := tx.QueryRowContext(ctx, CreateUserQuery, user.Email, user.PasswordHash).
+			Scan(&created.ID, &created.Email, &created.EmailVerifiedAt, &created.CreatedAt, &created.UpdatedAt); err != nil {
+			if db.IsUniqueViolation(err) {
+				return "", ErrDuplicate
+			}
+
+			return "", err
+		}
+
+		if _, err := tx.ExecContext(ctx, MarkEmailVerifiedQuery, created.ID, verifiedAt); err != nil {
+			return "", err
+		}
+
+		user.ID = created.ID
+	}
+
+	if _, err := tx.ExecContext(ctx, AddMemberQuery, inv.OrganizationID, user.ID, inv.Role); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return user.ID, nil
+}
+
+const DeleteInvitationsQuery = `
+DELETE FROM invitations
+WHERE organization_id = $1 AND email = $2
+`
+
+// DeleteInvitations deletes the organization's outstanding invitations to the
+// email.
+func (r *invitationRepo) DeleteInvitations(ctx context.Context, organizationID, email string) error {
+	_, err := r.db.ExecContext(ctx, DeleteInvitationsQuery, organizationID, email)
+	return err
+}
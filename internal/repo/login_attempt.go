@@ -0,0 +1,170 @@
+//go:generate mockgen -destination=mocks/login_attempt_store_mock.go -package=mocks . LoginAttemptStore
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type LoginAttemptStore interface {
+	FindLoginAttempt(ctx context.Context, id string) (*model.LoginAttempt, error)
+	RecordLoginFailure(ctx context.Context, id string, failedAt time.Time) (*model.LoginAttempt, error)
+	ForgetLoginFailure(ctx context.Context, id string, lastFailedAt time.Time) error
+	ResetLoginAttempts(ctx context.Context, id string) error
+}
+
+type loginAttemptStore struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptStore(db *sql.DB) LoginAttemptStore {
+	return &loginAttemptStore{
+		db: db,
+	}
+}
+
+const FindLoginAttemptQuery = `
+SELECT id, failures, last_failed_at
+FROM login_attempts
+WHERE id = $1
+`
+
+func (s *loginAttemptStore) FindLoginAttempt(ctx context.Context, id string) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	if err := s.db.QueryRowContext(ctx, FindLoginAttemptQuery, id).
+		Scan(&attempt.ID, &attempt.Failures, &attempt.LastFailedAt); err != nil {
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+const RecordLoginFailureQuery = `
+INSERT INTO login_attempts (id, failures, last_failed_at)
+VALUES ($1, 1, $2)
+ON CONFLICT (id) DO UPDATE
+SET failures = login_attempts.failures + 1, last_failed_at = $2
+RETURNING id, failures, last_failed_at
+`
+
+// RecordLoginFailure increments the failure count atomically so that
+// concurrent attempts are all counted.
+func (s *loginAttemptStore) RecordLoginFailure(ctx context.Context, id string,
+	failedAt time.Time) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	if err := s.db.QueryRowContext(ctx, RecordLoginFailureQuery, id, failedAt).
+		Scan(&attempt.ID, &attempt.Failures, &attempt.LastFailedAt); err != nil {
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+const ForgetLoginFailureQuery = `
+UPDATE login_attempts
+SET failures = failures - 1, last_failed_at = $2
+WHERE id = $1 AND failures > 0
+`
+
+// ForgetLoginFailure takes back one failure recorded for an attempt that
+// turned out not to fail, restoring the time of the failure before it.
+func (s *loginAttemptStore) ForgetLoginFailure(ctx context.Context, id string, lastFailedAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, ForgetLoginFailureQuery, id, lastFailedAt)
+	return err
+}
+
+const ResetLoginAttemptsQuery = `
+DELETE FROM login_attempts
+WHERE id = $1
+`
+
+func (s *loginAttemptStore) ResetLoginAttempts(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, ResetLoginAttemptsQuery, id)
+	return err
+}
+
+// memoryLoginAttemptStore keeps attempts in process memory. Counts are lost
+// on restart and not shared between instances, so it only suits a single node.
+type memoryLoginAttemptStore struct {
+	mu         sync.Mutex
+	attempts   map[string]model.LoginAttempt
+	ttl        time.Duration
+	lastPruned time.Time
+}
+
+// NewMemoryLoginAttemptStore returns an in-memory store that forgets attempts
+// ttl after their last failure.
+func NewMemoryLoginAttemptStore(ttl time.Duration) LoginAttemptStore {
+	return &memoryLoginAttemptStore{
+		attempts: make(map[string]model.LoginAttempt),
+		ttl:      ttl,
+	}
+}
+
+func (s *memoryLoginAttemptStore) FindLoginAttempt(_ context.Context, id string) (*model.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &attempt, nil
+}
+
+func (s *memoryLoginAttemptStore) RecordLoginFailure(_ context.Context, id string,
+	failedAt time.Time) (*model.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune(failedAt)
+
+	attempt := s.attempts[id]
+	attempt.ID = id
+	attempt.Failures++
+	attempt.LastFailedAt = failedAt
+	s.attempts[id] = attempt
+
+	return &attempt, nil
+}
+
+func (s *memoryLoginAttemptStore) ForgetLoginFailure(_ context.Context, id string, lastFailedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if attempt, ok := s.attempts[id]; ok && attempt.Failures > 0 {
+		attempt.Failures--
+		attempt.LastFailedAt = lastFailedAt
+		s.attempts[id] = attempt
+	}
+
+	return nil
+}
+
+func (s *memoryLoginAttemptStore) ResetLoginAttempts(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, id)
+	return nil
+}
+
+// prune drops attempts older than the ttl, at most once a minute.
+func (s *memoryLoginAttemptStore) prune(now time.Time) {
+	if now.Sub(s.lastPruned) < time.Minute {
+		return
+	}
+
+	for id, attempt := range s.attempts {
+		if now.Sub(attempt.LastFailedAt) > s.ttl {
+			delete(s.attempts, id)
+		}
+	}
+
+	s.lastPruned = now
+}
@@ -0,0 +1,67 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginAttemptStore_Integration(t *testing.T) {
+	stores := []struct {
+		name  string
+		store func(t *testing.T) repo.LoginAttemptStore
+	}{
+		{"database", func(t *testing.T) repo.LoginAttemptStore { return repo.NewLoginAttemptStore(newTestDB(t)) }},
+		{"memory", func(_ *testing.T) repo.LoginAttemptStore { return repo.NewMemoryLoginAttemptStore(time.Hour) }},
+	}
+
+	for _, tt := range stores {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := tt.store(t)
+			ctx := context.Background()
+			now := time.Now().UTC().Truncate(time.Second)
+
+			_, err := attempts.FindLoginAttempt(ctx, testAttemptID)
+			assert.ErrorIs(t, err, sql.ErrNoRows, "unknown attempts should not be found")
+
+			for i := 1; i <= 3; i++ {
+				attempt, err := attempts.RecordLoginFailure(ctx, testAttemptID, now.Add(time.Duration(i)*time.Second))
+				assert.NoError(t, err, "record login failure should not return an error")
+				assert.Equal(t, i, attempt.Failures, "failures should be counted")
+			}
+
+			attempt, err := attempts.FindLoginAttempt(ctx, testAttemptID)
+			assert.NoError(t, err, "find login attempt should not return an error")
+			assert.Equal(t, 3, attempt.Failures, "failures should match")
+			assert.True(t, now.Add(3*time.Second).Equal(attempt.LastFailedAt), "last failure should match")
+
+			assert.NoError(t, attempts.ForgetLoginFailure(ctx, testAttemptID, now.Add(2*time.Second)), "forget should not return an error")
+			attempt, err = attempts.FindLoginAttempt(ctx, testAttemptID)
+			assert.NoError(t, err, "find login attempt should not return an error")
+			assert.Equal(t, 2, attempt.Failures, "a forgotten failure should not count")
+			assert.True(t, now.Add(2*time.Second).Equal(attempt.LastFailedAt), "last failure should be restored")
+
+			assert.NoError(t, attempts.ResetLoginAttempts(ctx, testAttemptID), "reset should not return an error")
+			_, err = attempts.FindLoginAttempt(ctx, testAttemptID)
+			assert.ErrorIs(t, err, sql.ErrNoRows, "reset attempts should not be found")
+		})
+	}
+}
+
+func TestMemoryLoginAttemptStore_Prune(t *testing.T) {
+	attempts := repo.NewMemoryLoginAttemptStore(time.Hour)
+	ctx := context.Background()
+	now := time.Now()
+
+	_, err := attempts.RecordLoginFailure(ctx, "stale", now.Add(-2*time.Hour))
+	assert.NoError(t, err, "record login failure should not return an error")
+	_, err = attempts.RecordLoginFailure(ctx, testAttemptID, now)
+	assert.NoError(t, err, "record login failure should not return an error")
+
+	_, err = attempts.FindLoginAttempt(ctx, "stale")
+	assert.ErrorIs(t, err, sql.ErrNoRows, "stale attempts should be pruned")
+}
@@ -0,0 +1,73 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAttemptID = "email:" + testEmail
+
+var loginAttemptCols = []string{"id", "failures", "last_failed_at"}
+
+func TestLoginAttemptStore_RecordLoginFailure(t *testing.T) {
+	mock, attempts := setupMockLoginAttemptStore(t)
+	now := time.Now().UTC()
+
+	mock.ExpectQuery(repo.RecordLoginFailureQuery).
+		WithArgs(testAttemptID, now).
+		WillReturnRows(sqlmock.NewRows(loginAttemptCols).AddRow(testAttemptID, 3, now))
+
+	attempt, err := attempts.RecordLoginFailure(context.Background(), testAttemptID, now)
+
+	assert.NoError(t, err, "record login failure should not return an error")
+	assert.Equal(t, 3, attempt.Failures, "failures should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestLoginAttemptStore_FindLoginAttempt_NotFound(t *testing.T) {
+	mock, attempts := setupMockLoginAttemptStore(t)
+	mock.ExpectQuery(repo.FindLoginAttemptQuery).WithArgs(testAttemptID).WillReturnError(sql.ErrNoRows)
+
+	_, err := attempts.FindLoginAttempt(context.Background(), testAttemptID)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestLoginAttemptStore_ResetLoginAttempts(t *testing.T) {
+	mock, attempts := setupMockLoginAttemptStore(t)
+	mock.ExpectExec(repo.ResetLoginAttemptsQuery).WithArgs(testAttemptID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := attempts.ResetLoginAttempts(context.Background(), testAttemptID)
+
+	assert.NoError(t, err, "reset login attempts should not return an error")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestLoginAttemptStore_ForgetLoginFailure(t *testing.T) {
+	mock, attempts := setupMockLoginAttemptStore(t)
+	lastFailedAt := time.Now().UTC()
+	mock.ExpectExec(repo.ForgetLoginFailureQuery).WithArgs(testAttemptID, lastFailedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := attempts.ForgetLoginFailure(context.Background(), testAttemptID, lastFailedAt)
+
+	assert.NoError(t, err, "forget login failure should not return an error")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockLoginAttemptStore(t *testing.T) (sqlmock.Sqlmock, repo.LoginAttemptStore) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	attempts := repo.NewLoginAttemptStore(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, attempts
+}
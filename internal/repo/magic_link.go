@@ -0,0 +1,71 @@
+//go:generate mockgen -destination=mocks/magic_link_repo_mock.go -package=mocks . MagicLinkRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type MagicLinkRepo interface {
+	CreateMagicLink(ctx context.Context, params model.MagicLink) (*model.MagicLink, error)
+	ConsumeMagicLink(ctx context.Context, id, nonceHash string) (*model.MagicLink, error)
+	DeleteUserMagicLinks(ctx context.Context, userID string) error
+}
+
+type magicLinkRepo struct {
+	db *sql.DB
+}
+
+func NewMagicLinkRepo(db *sql.DB) MagicLinkRepo {
+	return &magicLinkRepo{
+		db: db,
+	}
+}
+
+const CreateMagicLinkQuery = `
+INSERT INTO magic_links (id, user_id, email, nonce_hash, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, email, nonce_hash, expires_at, created_at
+`
+
+func (r *magicLinkRepo) CreateMagicLink(ctx context.Context, params model.MagicLink) (*model.MagicLink, error) {
+	var link model.MagicLink
+	if err := r.db.QueryRowContext(ctx, CreateMagicLinkQuery,
+		params.ID, params.UserID, params.Email, params.NonceHash, params.ExpiresAt).
+		Scan(&link.ID, &link.UserID, &link.Email, &link.NonceHash, &link.ExpiresAt, &link.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+const ConsumeMagicLinkQuery = `
+DELETE FROM magic_links
+WHERE id = $1 AND (nonce_hash = '' OR nonce_hash = $2)
+RETURNING id, user_id, email, nonce_hash, expires_at, created_at
+`
+
+// ConsumeMagicLink deletes the link and returns it, so that it signs in at
+// most once. A link bound to a nonce is only consumed with the hash of that
+// nonce, so that opening it on another device does not use it up.
+func (r *magicLinkRepo) ConsumeMagicLink(ctx context.Context, id, nonceHash string) (*model.MagicLink, error) {
+	var link model.MagicLink
+	if err := r.db.QueryRowContext(ctx, ConsumeMagicLinkQuery, id, nonceHash).
+		Scan(&link.ID, &link.UserID, &link.Email, &link.NonceHash, &link.ExpiresAt, &link.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+const DeleteUserMagicLinksQuery = `
+DELETE FROM magic_links
+WHERE user_id = $1
+`
+
+func (r *magicLinkRepo) DeleteUserMagicLinks(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, DeleteUserMagicLinksQuery, userID)
+	return err
+}
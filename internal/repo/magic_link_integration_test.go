@@ -0,0 +1,61 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMagicLinkRepo_Integration_ConsumeOnce(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	links := repo.NewMagicLinkRepo(conn)
+	ctx := context.Background()
+
+	_, err := links.CreateMagicLink(ctx, model.MagicLink{
+		ID:        testMagicLinkID,
+		UserID:    user.ID,
+		Email:     user.Email,
+		ExpiresAt: time.Now().UTC().Add(15 * time.Minute),
+	})
+	assert.NoError(t, err, "create magic link should not return an error")
+
+	link, err := links.ConsumeMagicLink(ctx, testMagicLinkID, testNonceHash)
+	assert.NoError(t, err, "unbound links should be consumed with any nonce")
+	assert.Equal(t, user.ID, link.UserID, "user ID must match")
+
+	_, err = links.ConsumeMagicLink(ctx, testMagicLinkID, "")
+	assert.ErrorIs(t, err, sql.ErrNoRows, "links should only be consumed once")
+}
+
+func TestMagicLinkRepo_Integration_NonceBound(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	links := repo.NewMagicLinkRepo(conn)
+	ctx := context.Background()
+
+	_, err := links.CreateMagicLink(ctx, model.MagicLink{
+		ID:        testMagicLinkID,
+		UserID:    user.ID,
+		Email:     user.Email,
+		NonceHash: testNonceHash,
+		ExpiresAt: time.Now().UTC().Add(15 * time.Minute),
+	})
+	assert.NoError(t, err, "create magic link should not return an error")
+
+	for _, nonceHash := range []string{"", "other"} {
+		_, err = links.ConsumeMagicLink(ctx, testMagicLinkID, nonceHash)
+		assert.ErrorIs(t, err, sql.ErrNoRows, "bound links should not be consumed without their nonce")
+	}
+
+	_, err = links.ConsumeMagicLink(ctx, testMagicLinkID, testNonceHash)
+	assert.NoError(t, err, "bound links should be consumed with their nonce")
+
+	err = links.DeleteUserMagicLinks(ctx, user.ID)
+	assert.NoError(t, err, "delete user magic links should not return an error")
+}
@@ -0,0 +1,78 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testMagicLinkID = "magiclinkhash"
+	testNonceHash   = "noncehash"
+)
+
+var magicLinkCols = []string{"id", "user_id", "email", "nonce_hash", "expires_at", "created_at"}
+
+func TestMagicLinkRepo_CreateMagicLink_Success(t *testing.T) {
+	mock, links := setupMockMagicLinkRepo(t)
+	now := time.Now().UTC()
+	params := model.MagicLink{
+		ID:        testMagicLinkID,
+		UserID:    testID,
+		Email:     testEmail,
+		NonceHash: testNonceHash,
+		ExpiresAt: now.Add(15 * time.Minute),
+	}
+
+	mock.ExpectQuery(repo.CreateMagicLinkQuery).
+		WithArgs(params.ID, params.UserID, params.Email, params.NonceHash, params.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows(magicLinkCols).
+			AddRow(params.ID, params.UserID, params.Email, params.NonceHash, params.ExpiresAt, now))
+
+	link, err := links.CreateMagicLink(context.Background(), params)
+
+	assert.NoError(t, err, "create magic link should not return an error")
+	assert.Equal(t, testNonceHash, link.NonceHash, "nonce hash must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestMagicLinkRepo_ConsumeMagicLink_NotFound(t *testing.T) {
+	mock, links := setupMockMagicLinkRepo(t)
+	mock.ExpectQuery(repo.ConsumeMagicLinkQuery).
+		WithArgs(testMagicLinkID, testNonceHash).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := links.ConsumeMagicLink(context.Background(), testMagicLinkID, testNonceHash)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestMagicLinkRepo_DeleteUserMagicLinks(t *testing.T) {
+	mock, links := setupMockMagicLinkRepo(t)
+	mock.ExpectExec(repo.DeleteUserMagicLinksQuery).
+		WithArgs(testID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := links.DeleteUserMagicLinks(context.Background(), testID)
+
+	assert.NoError(t, err, "delete user magic links should not return an error")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockMagicLinkRepo(t *testing.T) (sqlmock.Sqlmock, repo.MagicLinkRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	links := repo.NewMagicLinkRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, links
+}
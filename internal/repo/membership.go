@@ -0,0 +1,135 @@
+//go:generate mockgen -destination=mocks/membership_repo_mock.go -package=mocks . MembershipRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+// MembershipRepo stores the members of organizations. Every method is scoped
+// to the organization it is given.
+type MembershipRepo interface {
+	AddMember(ctx context.Context, params model.Membership) error
+	FindMember(ctx context.Context, organizationID, userID string) (*model.Membership, error)
+	ListMembers(ctx context.Context, organizationID string) ([]model.Membership, error)
+	UpdateMemberRole(ctx context.Context, organizationID, userID, role string) error
+	RemoveMember(ctx context.Context, organizationID, userID string) error
+	CountMembersWithRole(ctx context.Context, organizationID, role string) (int, error)
+}
+
+type membershipRepo struct {
+	db *sql.DB
+}
+
+func NewMembershipRepo(db *sql.DB) MembershipRepo {
+	return &membershipRepo{
+		db: db,
+	}
+}
+
+const AddMemberQuery = `
+INSERT INTO memberships (organization_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING
+`
+
+// AddMember makes the user a member. A user who is already a member keeps
+// their role.
+func (r *membershipRepo) AddMember(ctx context.Context, params model.Membership) error {
+	_, err := r.db.ExecContext(ctx, AddMemberQuery, params.OrganizationID, params.UserID, params.Role)
+	return err
+}
+
+const FindMemberQuery = `
+SELECT m.organization_id, m.user_id, u.email, m.role, m.created_at
+FROM memberships m
+JOIN users u ON u.id = m.user_id
+WHERE m.organization_id = $1 AND m.user_id = $2
+`
+
+func (r *membershipRepo) FindMember(ctx context.Context, organizationID, userID string) (*model.Membership, error) {
+	var member model.Membership
+	if err := r.db.QueryRowContext(ctx, FindMemberQuery, organizationID, userID).
+		Scan(&member.OrganizationID, &member.UserID, &member.Email, &member.Role, &member.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+const ListMembersQuery = `
+SELECT m.organization_id, m.user_id, u.email, m.role, m.created_at
+FROM memberships m
+JOIN users u ON u.id = m.user_id
+WHERE m.organization_id = $1
+ORDER BY u.email
+`
+
+func (r *membershipRepo) ListMembers(ctx context.Context, organizationID string) ([]model.Membership, error) {
+	rows, err := r.db.QueryContext(ctx, ListMembersQuery, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []model.Membership{}
+	for rows.Next() {
+		var member model.Membership
+		if err := rows.Scan(&member.OrganizationID, &member.UserID, &member.Email, &member.Role,
+			&member.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+const UpdateMemberRoleQuery = `
+UPDATE memberships
+SET role = $3
+WHERE organization_id = $1 AND user_id = $2
+`
+
+// UpdateMemberRole returns sql.ErrNoRows if the user is not a member.
+func (r *membershipRepo) UpdateMemberRole(ctx context.Context, organizationID, userID, role string) error {
+	res, err := r.db.ExecContext(ctx, UpdateMemberRoleQuery, organizationID, userID, role)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(res)
+}
+
+const RemoveMemberQuery = `
+DELETE FROM memberships
+WHERE organization_id = $1 AND user_id = $2
+`
+
+// RemoveMember returns sql.ErrNoRows if the user is not a member.
+func (r *membershipRepo) RemoveMember(ctx context.Context, organizationID, userID string) error {
+	res, err := r.db.ExecContext(ctx, RemoveMemberQuery, organizationID, userID)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(res)
+}
+
+const CountMembersWithRoleQuery = `
+SELECT COUNT(*)
+FROM memberships
+WHERE organization_id = $1 AND role = $2
+`
+
+func (r *membershipRepo) CountMembersWithRole(ctx context.Context, organizationID, role string) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, CountMembersWithRoleQuery, organizationID, role).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,94 @@
+//go:generate mockgen -destination=mocks/mfa_challenge_repo_mock.go -package=mocks . MFAChallengeRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type MFAChallengeRepo interface {
+	CreateMFAChallenge(ctx context.Context, params model.MFAChallenge) (*model.MFAChallenge, error)
+	FindMFAChallenge(ctx context.Context, id string) (*model.MFAChallenge, error)
+	RecordMFAFailure(ctx context.Context, id string) (int, error)
+	ConsumeMFAChallenge(ctx context.Context, id string) (*model.MFAChallenge, error)
+}
+
+type mfaChallengeRepo struct {
+	db *sql.DB
+}
+
+func NewMFAChallengeRepo(db *sql.DB) MFAChallengeRepo {
+	return &mfaChallengeRepo{
+		db: db,
+	}
+}
+
+const CreateMFAChallengeQuery = `
+INSERT INTO mfa_challenges (id, user_id, expires_at)
+VALUES ($1, $2, $3)
+RETURNING id, user_id, failures, expires_at, created_at
+`
+
+func (r *mfaChallengeRepo) CreateMFAChallenge(ctx context.Context, params model.MFAChallenge) (*model.MFAChallenge, error) {
+	var challenge model.MFAChallenge
+	if err := r.db.QueryRowContext(ctx, CreateMFAChallengeQuery, params.ID, params.UserID, params.ExpiresAt).
+		Scan(&challenge.ID, &challenge.UserID, &challenge.Failures, &challenge.ExpiresAt, &challenge.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+const FindMFAChallengeQuery = `
+SELECT id, user_id, failures, expires_at, created_at
+FROM mfa_challenges
+WHERE id = $1
+`
+
+func (r *mfaChallengeRepo) FindMFAChallenge(ctx context.Context, id string) (*model.MFAChallenge, error) {
+	var challenge model.MFAChallenge
+	if err := r.db.QueryRowContext(ctx, FindMFAChallengeQuery, id).
+		Scan(&challenge.ID, &challenge.UserID, &challenge.Failures, &challenge.ExpiresAt, &challenge.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+const RecordMFAFailureQuery = `
+UPDATE mfa_challenges
+SET failures = failures + 1
+WHERE id = $1
+RETURNING failures
+`
+
+// RecordMFAFailure increments the failure count atomically and returns it,
+// so that concurrent guesses are all counted.
+func (r *mfaChallengeRepo) RecordMFAFailure(ctx context.Context, id string) (int, error) {
+	var failures int
+	if err := r.db.QueryRowContext(ctx, RecordMFAFailureQuery, id).Scan(&failures); err != nil {
+		return 0, err
+	}
+
+	return failures, nil
+}
+
+const ConsumeMFAChallengeQuery = `
+DELETE FROM mfa_challenges
+WHERE id = $1
+RETURNING id, user_id, failures, expires_at, created_at
+`
+
+// ConsumeMFAChallenge deletes the challenge and returns it, so that it
+// completes at most one sign-in.
+func (r *mfaChallengeRepo) ConsumeMFAChallenge(ctx context.Context, id string) (*model.MFAChallenge, error) {
+	var challenge model.MFAChallenge
+	if err := r.db.QueryRowContext(ctx, ConsumeMFAChallengeQuery, id).
+		Scan(&challenge.ID, &challenge.UserID, &challenge.Failures, &challenge.ExpiresAt, &challenge.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
@@ -0,0 +1,46 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMFAChallengeRepo_Integration_Lifecycle(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	challenges := repo.NewMFAChallengeRepo(conn)
+	ctx := context.Background()
+
+	_, err := challenges.CreateMFAChallenge(ctx, model.MFAChallenge{
+		ID:        testChallengeID,
+		UserID:    user.ID,
+		ExpiresAt: time.Now().UTC().Add(5 * time.Minute),
+	})
+	assert.NoError(t, err, "create mfa challenge should not return an error")
+
+	for want := 1; want <= 2; want++ {
+		failures, err := challenges.RecordMFAFailure(ctx, testChallengeID)
+		assert.NoError(t, err, "record mfa failure should not return an error")
+		assert.Equal(t, want, failures, "failures must match")
+	}
+
+	found, err := challenges.FindMFAChallenge(ctx, testChallengeID)
+	assert.NoError(t, err, "find mfa challenge should not return an error")
+	assert.Equal(t, 2, found.Failures, "failures must match")
+
+	consumed, err := challenges.ConsumeMFAChallenge(ctx, testChallengeID)
+	assert.NoError(t, err, "consume mfa challenge should not return an error")
+	assert.Equal(t, user.ID, consumed.UserID, "user ID must match")
+
+	_, err = challenges.ConsumeMFAChallenge(ctx, testChallengeID)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "challenges should only be consumed once")
+
+	_, err = challenges.RecordMFAFailure(ctx, testChallengeID)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "failures should not be recorded for consumed challenges")
+}
@@ -0,0 +1,73 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+const testChallengeID = "challengehash"
+
+var mfaChallengeCols = []string{"id", "user_id", "failures", "expires_at", "created_at"}
+
+func TestMFAChallengeRepo_CreateMFAChallenge_Success(t *testing.T) {
+	mock, challenges := setupMockMFAChallengeRepo(t)
+	now := time.Now().UTC()
+	params := model.MFAChallenge{
+		ID:        testChallengeID,
+		UserID:    testID,
+		ExpiresAt: now.Add(5 * time.Minute),
+	}
+
+	mock.ExpectQuery(repo.CreateMFAChallengeQuery).
+		WithArgs(params.ID, params.UserID, params.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows(mfaChallengeCols).AddRow(params.ID, params.UserID, 0, params.ExpiresAt, now))
+
+	challenge, err := challenges.CreateMFAChallenge(context.Background(), params)
+
+	assert.NoError(t, err, "create mfa challenge should not return an error")
+	assert.Equal(t, testID, challenge.UserID, "user ID must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestMFAChallengeRepo_RecordMFAFailure(t *testing.T) {
+	mock, challenges := setupMockMFAChallengeRepo(t)
+	mock.ExpectQuery(repo.RecordMFAFailureQuery).
+		WithArgs(testChallengeID).
+		WillReturnRows(sqlmock.NewRows([]string{"failures"}).AddRow(3))
+
+	failures, err := challenges.RecordMFAFailure(context.Background(), testChallengeID)
+
+	assert.NoError(t, err, "record mfa failure should not return an error")
+	assert.Equal(t, 3, failures, "failures must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestMFAChallengeRepo_ConsumeMFAChallenge_NotFound(t *testing.T) {
+	mock, challenges := setupMockMFAChallengeRepo(t)
+	mock.ExpectQuery(repo.ConsumeMFAChallengeQuery).
+		WithArgs(testChallengeID).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := challenges.ConsumeMFAChallenge(context.Background(), testChallengeID)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockMFAChallengeRepo(t *testing.T) (sqlmock.Sqlmock, repo.MFAChallengeRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	challenges := repo.NewMFAChallengeRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, challenges
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: IdentityRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/identity_repo_mock.go -package=mocks . IdentityRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIdentityRepo is a mock of IdentityRepo interface.
+type MockIdentityRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockIdentityRepoMockRecorder is the mock recorder for MockIdentityRepo.
+type MockIdentityRepoMockRecorder struct {
+	mock *MockIdentityRepo
+}
+
+// NewMockIdentityRepo creates a new mock instance.
+func NewMockIdentityRepo(ctrl *gomock.Controller) *MockIdentityRepo {
+	mock := &MockIdentityRepo{ctrl: ctrl}
+	mock.recorder = &MockIdentityRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityRepo) EXPECT() *MockIdentityRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateIdentity mocks base method.
+func (m *MockIdentityRepo) CreateIdentity(ctx context.Context, params model.Identity) (*model.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdentity", ctx, params)
+	ret0, _ := ret[0].(*model.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdentity indicates an expected call of CreateIdentity.
+func (mr *MockIdentityRepoMockRecorder) CreateIdentity(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdentity", reflect.TypeOf((*MockIdentityRepo)(nil).CreateIdentity), ctx, params)
+}
+
+// FindIdentity mocks base method.
+func (m *MockIdentityRepo) FindIdentity(ctx context.Context, provider, subject string) (*model.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIdentity", ctx, provider, subject)
+	ret0, _ := ret[0].(*model.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIdentity indicates an expected call of FindIdentity.
+func (mr *MockIdentityRepoMockRecorder) FindIdentity(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentity", reflect.TypeOf((*MockIdentityRepo)(nil).FindIdentity), ctx, provider, subject)
+}
+
+// ListUserIdentities mocks base method.
+func (m *MockIdentityRepo) ListUserIdentities(ctx context.Context, userID string) ([]model.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserIdentities", ctx, userID)
+	ret0, _ := ret[0].([]model.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserIdentities indicates an expected call of ListUserIdentities.
+func (mr *MockIdentityRepoMockRecorder) ListUserIdentities(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserIdentities", reflect.TypeOf((*MockIdentityRepo)(nil).ListUserIdentities), ctx, userID)
+}
+
+// UpdateIdentityUse mocks base method.
+func (m *MockIdentityRepo) UpdateIdentityUse(ctx context.Context, provider, subject, email string, usedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIdentityUse", ctx, provider, subject, email, usedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIdentityUse indicates an expected call of UpdateIdentityUse.
+func (mr *MockIdentityRepoMockRecorder) UpdateIdentityUse(ctx, provider, subject, email, usedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIdentityUse", reflect.TypeOf((*MockIdentityRepo)(nil).UpdateIdentityUse), ctx, provider, subject, email, usedAt)
+}
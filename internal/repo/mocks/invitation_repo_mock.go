@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: InvitationRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/invitation_repo_mock.go -package=mocks . InvitationRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockInvitationRepo is a mock of InvitationRepo interface.
+type MockInvitationRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvitationRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockInvitationRepoMockRecorder is the mock recorder for MockInvitationRepo.
+type MockInvitationRepoMockRecorder struct {
+	mock *MockInvitationRepo
+}
+
+// NewMockInvitationRepo creates a new mock instance.
+func NewMockInvitationRepo(ctrl *gomock.Controller) *MockInvitationRepo {
+	mock := &MockInvitationRepo{ctrl: ctrl}
+	mock.recorder = &MockInvitationRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvitationRepo) EXPECT() *MockInvitationRepoMockRecorder {
+	return m.recorder
+}
+
+// AcceptInvitation mocks base method.
+func (m *MockInvitationRepo) AcceptInvitation(ctx context.Context, id string, user model.User, verifiedAt time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptInvitation", ctx, id, user, verifiedAt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptInvitation indicates an expected call of AcceptInvitation.
+func (mr *MockInvitationRepoMockRecorder) AcceptInvitation(ctx, id, user, verifiedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptInvitation", reflect.TypeOf((*MockInvitationRepo)(nil).AcceptInvitation), ctx, id, user, verifiedAt)
+}
+
+// CreateInvitation mocks base method.
+func (m *MockInvitationRepo) CreateInvitation(ctx context.Context, params model.Invitation) (*model.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvitation", ctx, params)
+	ret0, _ := ret[0].(*model.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInvitation indicates an expected call of CreateInvitation.
+func (mr *MockInvitationRepoMockRecorder) CreateInvitation(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvitation", reflect.TypeOf((*MockInvitationRepo)(nil).CreateInvitation), ctx, params)
+}
+
+// DeleteInvitations mocks base method.
+func (m *MockInvitationRepo) DeleteInvitations(ctx context.Context, organizationID, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInvitations", ctx, organizationID, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteInvitations indicates an expected call of DeleteInvitations.
+func (mr *MockInvitationRepoMockRecorder) DeleteInvitations(ctx, organizationID, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInvitations", reflect.TypeOf((*MockInvitationRepo)(nil).DeleteInvitations), ctx, organizationID, email)
+}
+
+// FindInvitation mocks base method.
+func (m *MockInvitationRepo) FindInvitation(ctx context.Context, id string) (*model.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInvitation", ctx, id)
+	ret0, _ := ret[0].(*model.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindInvitation indicates an expected call of FindInvitation.
+func (mr *MockInvitationRepoMockRecorder) FindInvitation(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInvitation", reflect.TypeOf((*MockInvitationRepo)(nil).FindInvitation), ctx, id)
+}
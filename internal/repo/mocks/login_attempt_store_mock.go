@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: LoginAttemptStore)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/login_attempt_store_mock.go -package=mocks . LoginAttemptStore
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLoginAttemptStore is a mock of LoginAttemptStore interface.
+type MockLoginAttemptStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAttemptStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginAttemptStoreMockRecorder is the mock recorder for MockLoginAttemptStore.
+type MockLoginAttemptStoreMockRecorder struct {
+	mock *MockLoginAttemptStore
+}
+
+// NewMockLoginAttemptStore creates a new mock instance.
+func NewMockLoginAttemptStore(ctrl *gomock.Controller) *MockLoginAttemptStore {
+	mock := &MockLoginAttemptStore{ctrl: ctrl}
+	mock.recorder = &MockLoginAttemptStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAttemptStore) EXPECT() *MockLoginAttemptStoreMockRecorder {
+	return m.recorder
+}
+
+// FindLoginAttempt mocks base method.
+func (m *MockLoginAttemptStore) FindLoginAttempt(ctx context.Context, id string) (*model.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLoginAttempt", ctx, id)
+	ret0, _ := ret[0].(*model.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLoginAttempt indicates an expected call of FindLoginAttempt.
+func (mr *MockLoginAttemptStoreMockRecorder) FindLoginAttempt(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLoginAttempt", reflect.TypeOf((*MockLoginAttemptStore)(nil).FindLoginAttempt), ctx, id)
+}
+
+// ForgetLoginFailure mocks base method.
+func (m *MockLoginAttemptStore) ForgetLoginFailure(ctx context.Context, id string, lastFailedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgetLoginFailure", ctx, id, lastFailedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgetLoginFailure indicates an expected call of ForgetLoginFailure.
+func (mr *MockLoginAttemptStoreMockRecorder) ForgetLoginFailure(ctx, id, lastFailedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgetLoginFailure", reflect.TypeOf((*MockLoginAttemptStore)(nil).ForgetLoginFailure), ctx, id, lastFailedAt)
+}
+
+// RecordLoginFailure mocks base method.
+func (m *MockLoginAttemptStore) RecordLoginFailure(ctx context.Context, id string, failedAt time.Time) (*model.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginFailure", ctx, id, failedAt)
+	ret0, _ := ret[0].(*model.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordLoginFailure indicates an expected call of RecordLoginFailure.
+func (mr *MockLoginAttemptStoreMockRecorder) RecordLoginFailure(ctx, id, failedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginFailure", reflect.TypeOf((*MockLoginAttemptStore)(nil).RecordLoginFailure), ctx, id, failedAt)
+}
+
+// ResetLoginAttempts mocks base method.
+func (m *MockLoginAttemptStore) ResetLoginAttempts(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetLoginAttempts", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetLoginAttempts indicates an expected call of ResetLoginAttempts.
+func (mr *MockLoginAttemptStoreMockRecorder) ResetLoginAttempts(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetLoginAttempts", reflect.TypeOf((*MockLoginAttemptStore)(nil).ResetLoginAttempts), ctx, id)
+}
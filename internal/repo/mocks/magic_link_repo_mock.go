@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: MagicLinkRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/magic_link_repo_mock.go -package=mocks . MagicLinkRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMagicLinkRepo is a mock of MagicLinkRepo interface.
+type MockMagicLinkRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockMagicLinkRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockMagicLinkRepoMockRecorder is the mock recorder for MockMagicLinkRepo.
+type MockMagicLinkRepoMockRecorder struct {
+	mock *MockMagicLinkRepo
+}
+
+// NewMockMagicLinkRepo creates a new mock instance.
+func NewMockMagicLinkRepo(ctrl *gomock.Controller) *MockMagicLinkRepo {
+	mock := &MockMagicLinkRepo{ctrl: ctrl}
+	mock.recorder = &MockMagicLinkRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMagicLinkRepo) EXPECT() *MockMagicLinkRepoMockRecorder {
+	return m.recorder
+}
+
+// ConsumeMagicLink mocks base method.
+func (m *MockMagicLinkRepo) ConsumeMagicLink(ctx context.Context, id, nonceHash string) (*model.MagicLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeMagicLink", ctx, id, nonceHash)
+	ret0, _ := ret[0].(*model.MagicLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeMagicLink indicates an expected call of ConsumeMagicLink.
+func (mr *MockMagicLinkRepoMockRecorder) ConsumeMagicLink(ctx, id, nonceHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeMagicLink", reflect.TypeOf((*MockMagicLinkRepo)(nil).ConsumeMagicLink), ctx, id, nonceHash)
+}
+
+// CreateMagicLink mocks base method.
+func (m *MockMagicLinkRepo) CreateMagicLink(ctx context.Context, params model.MagicLink) (*model.MagicLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMagicLink", ctx, params)
+	ret0, _ := ret[0].(*model.MagicLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMagicLink indicates an expected call of CreateMagicLink.
+func (mr *MockMagicLinkRepoMockRecorder) CreateMagicLink(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMagicLink", reflect.TypeOf((*MockMagicLinkRepo)(nil).CreateMagicLink), ctx, params)
+}
+
+// DeleteUserMagicLinks mocks base method.
+func (m *MockMagicLinkRepo) DeleteUserMagicLinks(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserMagicLinks", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserMagicLinks indicates an expected call of DeleteUserMagicLinks.
+func (mr *MockMagicLinkRepoMockRecorder) DeleteUserMagicLinks(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserMagicLinks", reflect.TypeOf((*MockMagicLinkRepo)(nil).DeleteUserMagicLinks), ctx, userID)
+}
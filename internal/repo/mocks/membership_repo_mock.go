@@ -0,0 +1,129 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: MembershipRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/membership_repo_mock.go -package=mocks . MembershipRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMembershipRepo is a mock of MembershipRepo interface.
+type MockMembershipRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockMembershipRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockMembershipRepoMockRecorder is the mock recorder for MockMembershipRepo.
+type MockMembershipRepoMockRecorder struct {
+	mock *MockMembershipRepo
+}
+
+// NewMockMembershipRepo creates a new mock instance.
+func NewMockMembershipRepo(ctrl *gomock.Controller) *MockMembershipRepo {
+	mock := &MockMembershipRepo{ctrl: ctrl}
+	mock.recorder = &MockMembershipRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMembershipRepo) EXPECT() *MockMembershipRepoMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockMembershipRepo) AddMember(ctx context.Context, params model.Membership) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockMembershipRepoMockRecorder) AddMember(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockMembershipRepo)(nil).AddMember), ctx, params)
+}
+
+// CountMembersWithRole mocks base method.
+func (m *MockMembershipRepo) CountMembersWithRole(ctx context.Context, organizationID, role string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountMembersWithRole", ctx, organizationID, role)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountMembersWithRole indicates an expected call of CountMembersWithRole.
+func (mr *MockMembershipRepoMockRecorder) CountMembersWithRole(ctx, organizationID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountMembersWithRole", reflect.TypeOf((*MockMembershipRepo)(nil).CountMembersWithRole), ctx, organizationID, role)
+}
+
+// FindMember mocks base method.
+func (m *MockMembershipRepo) FindMember(ctx context.Context, organizationID, userID string) (*model.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMember", ctx, organizationID, userID)
+	ret0, _ := ret[0].(*model.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMember indicates an expected call of FindMember.
+func (mr *MockMembershipRepoMockRecorder) FindMember(ctx, organizationID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMember", reflect.TypeOf((*MockMembershipRepo)(nil).FindMember), ctx, organizationID, userID)
+}
+
+// ListMembers mocks base method.
+func (m *MockMembershipRepo) ListMembers(ctx context.Context, organizationID string) ([]model.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", ctx, organizationID)
+	ret0, _ := ret[0].([]model.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockMembershipRepoMockRecorder) ListMembers(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockMembershipRepo)(nil).ListMembers), ctx, organizationID)
+}
+
+// RemoveMember mocks base method.
+func (m *MockMembershipRepo) RemoveMember(ctx context.Context, organizationID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, organizationID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockMembershipRepoMockRecorder) RemoveMember(ctx, organizationID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockMembershipRepo)(nil).RemoveMember), ctx, organizationID, userID)
+}
+
+// UpdateMemberRole mocks base method.
+func (m *MockMembershipRepo) UpdateMemberRole(ctx context.Context, organizationID, userID, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMemberRole", ctx, organizationID, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMemberRole indicates an expected call of UpdateMemberRole.
+func (mr *MockMembershipRepoMockRecorder) UpdateMemberRole(ctx, organizationID, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRole", reflect.TypeOf((*MockMembershipRepo)(nil).UpdateMemberRole), ctx, organizationID, userID, role)
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: MFAChallengeRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mfa_challenge_repo_mock.go -package=mocks . MFAChallengeRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMFAChallengeRepo is a mock of MFAChallengeRepo interface.
+type MockMFAChallengeRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFAChallengeRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockMFAChallengeRepoMockRecorder is the mock recorder for MockMFAChallengeRepo.
+type MockMFAChallengeRepoMockRecorder struct {
+	mock *MockMFAChallengeRepo
+}
+
+// NewMockMFAChallengeRepo creates a new mock instance.
+func NewMockMFAChallengeRepo(ctrl *gomock.Controller) *MockMFAChallengeRepo {
+	mock := &MockMFAChallengeRepo{ctrl: ctrl}
+	mock.recorder = &MockMFAChallengeRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMFAChallengeRepo) EXPECT() *MockMFAChallengeRepoMockRecorder {
+	return m.recorder
+}
+
+// ConsumeMFAChallenge mocks base method.
+func (m *MockMFAChallengeRepo) ConsumeMFAChallenge(ctx context.Context, id string) (*model.MFAChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeMFAChallenge", ctx, id)
+	ret0, _ := ret[0].(*model.MFAChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeMFAChallenge indicates an expected call of ConsumeMFAChallenge.
+func (mr *MockMFAChallengeRepoMockRecorder) ConsumeMFAChallenge(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeMFAChallenge", reflect.TypeOf((*MockMFAChallengeRepo)(nil).ConsumeMFAChallenge), ctx, id)
+}
+
+// CreateMFAChallenge mocks base method.
+func (m *MockMFAChallengeRepo) CreateMFAChallenge(ctx context.Context, params model.MFAChallenge) (*model.MFAChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMFAChallenge", ctx, params)
+	ret0, _ := ret[0].(*model.MFAChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMFAChallenge indicates an expected call of CreateMFAChallenge.
+func (mr *MockMFAChallengeRepoMockRecorder) CreateMFAChallenge(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMFAChallenge", reflect.TypeOf((*MockMFAChallengeRepo)(nil).CreateMFAChallenge), ctx, params)
+}
+
+// FindMFAChallenge mocks base method.
+func (m *MockMFAChallengeRepo) FindMFAChallenge(ctx context.Context, id string) (*model.MFAChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMFAChallenge", ctx, id)
+	ret0, _ := ret[0].(*model.MFAChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMFAChallenge indicates an expected call of FindMFAChallenge.
+func (mr *MockMFAChallengeRepoMockRecorder) FindMFAChallenge(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMFAChallenge", reflect.TypeOf((*MockMFAChallengeRepo)(nil).FindMFAChallenge), ctx, id)
+}
+
+// RecordMFAFailure mocks base method.
+func (m *MockMFAChallengeRepo) RecordMFAFailure(ctx context.Context, id string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMFAFailure", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordMFAFailure indicates an expected call of RecordMFAFailure.
+func (mr *MockMFAChallengeRepoMockRecorder) RecordMFAFailure(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMFAFailure", reflect.TypeOf((*MockMFAChallengeRepo)(nil).RecordMFAFailure), ctx, id)
+}
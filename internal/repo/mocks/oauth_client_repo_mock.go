@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: OAuthClientRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/oauth_client_repo_mock.go -package=mocks . OAuthClientRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOAuthClientRepo is a mock of OAuthClientRepo interface.
+type MockOAuthClientRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthClientRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockOAuthClientRepoMockRecorder is the mock recorder for MockOAuthClientRepo.
+type MockOAuthClientRepoMockRecorder struct {
+	mock *MockOAuthClientRepo
+}
+
+// NewMockOAuthClientRepo creates a new mock instance.
+func NewMockOAuthClientRepo(ctrl *gomock.Controller) *MockOAuthClientRepo {
+	mock := &MockOAuthClientRepo{ctrl: ctrl}
+	mock.recorder = &MockOAuthClientRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthClientRepo) EXPECT() *MockOAuthClientRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateOAuthClient mocks base method.
+func (m *MockOAuthClientRepo) CreateOAuthClient(ctx context.Context, params model.OAuthClient) (*model.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOAuthClient", ctx, params)
+	ret0, _ := ret[0].(*model.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOAuthClient indicates an expected call of CreateOAuthClient.
+func (mr *MockOAuthClientRepoMockRecorder) CreateOAuthClient(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOAuthClient", reflect.TypeOf((*MockOAuthClientRepo)(nil).CreateOAuthClient), ctx, params)
+}
+
+// FindOAuthClient mocks base method.
+func (m *MockOAuthClientRepo) FindOAuthClient(ctx context.Context, id string) (*model.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOAuthClient", ctx, id)
+	ret0, _ := ret[0].(*model.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOAuthClient indicates an expected call of FindOAuthClient.
+func (mr *MockOAuthClientRepoMockRecorder) FindOAuthClient(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOAuthClient", reflect.TypeOf((*MockOAuthClientRepo)(nil).FindOAuthClient), ctx, id)
+}
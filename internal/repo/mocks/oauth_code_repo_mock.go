@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: OAuthCodeRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/oauth_code_repo_mock.go -package=mocks . OAuthCodeRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOAuthCodeRepo is a mock of OAuthCodeRepo interface.
+type MockOAuthCodeRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthCodeRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockOAuthCodeRepoMockRecorder is the mock recorder for MockOAuthCodeRepo.
+type MockOAuthCodeRepoMockRecorder struct {
+	mock *MockOAuthCodeRepo
+}
+
+// NewMockOAuthCodeRepo creates a new mock instance.
+func NewMockOAuthCodeRepo(ctrl *gomock.Controller) *MockOAuthCodeRepo {
+	mock := &MockOAuthCodeRepo{ctrl: ctrl}
+	mock.recorder = &MockOAuthCodeRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthCodeRepo) EXPECT() *MockOAuthCodeRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateOAuthCode mocks base method.
+func (m *MockOAuthCodeRepo) CreateOAuthCode(ctx context.Context, params model.OAuthCode) (*model.OAuthCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOAuthCode", ctx, params)
+	ret0, _ := ret[0].(*model.OAuthCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOAuthCode indicates an expected call of CreateOAuthCode.
+func (mr *MockOAuthCodeRepoMockRecorder) CreateOAuthCode(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOAuthCode", reflect.TypeOf((*MockOAuthCodeRepo)(nil).CreateOAuthCode), ctx, params)
+}
+
+// FindOAuthCode mocks base method.
+func (m *MockOAuthCodeRepo) FindOAuthCode(ctx context.Context, id string) (*model.OAuthCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOAuthCode", ctx, id)
+	ret0, _ := ret[0].(*model.OAuthCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOAuthCode indicates an expected call of FindOAuthCode.
+func (mr *MockOAuthCodeRepoMockRecorder) FindOAuthCode(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOAuthCode", reflect.TypeOf((*MockOAuthCodeRepo)(nil).FindOAuthCode), ctx, id)
+}
+
+// UseOAuthCode mocks base method.
+func (m *MockOAuthCodeRepo) UseOAuthCode(ctx context.Context, id, clientID, redirectURI string, usedAt time.Time) (*model.OAuthCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseOAuthCode", ctx, id, clientID, redirectURI, usedAt)
+	ret0, _ := ret[0].(*model.OAuthCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseOAuthCode indicates an expected call of UseOAuthCode.
+func (mr *MockOAuthCodeRepoMockRecorder) UseOAuthCode(ctx, id, clientID, redirectURI, usedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseOAuthCode", reflect.TypeOf((*MockOAuthCodeRepo)(nil).UseOAuthCode), ctx, id, clientID, redirectURI, usedAt)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: OAuthTokenRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/oauth_token_repo_mock.go -package=mocks . OAuthTokenRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOAuthTokenRepo is a mock of OAuthTokenRepo interface.
+type MockOAuthTokenRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthTokenRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockOAuthTokenRepoMockRecorder is the mock recorder for MockOAuthTokenRepo.
+type MockOAuthTokenRepoMockRecorder struct {
+	mock *MockOAuthTokenRepo
+}
+
+// NewMockOAuthTokenRepo creates a new mock instance.
+func NewMockOAuthTokenRepo(ctrl *gomock.Controller) *MockOAuthTokenRepo {
+	mock := &MockOAuthTokenRepo{ctrl: ctrl}
+	mock.recorder = &MockOAuthTokenRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthTokenRepo) EXPECT() *MockOAuthTokenRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateOAuthToken mocks base method.
+func (m *MockOAuthTokenRepo) CreateOAuthToken(ctx context.Context, params model.OAuthToken) (*model.OAuthToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOAuthToken", ctx, params)
+	ret0, _ := ret[0].(*model.OAuthToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOAuthToken indicates an expected call of CreateOAuthToken.
+func (mr *MockOAuthTokenRepoMockRecorder) CreateOAuthToken(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOAuthToken", reflect.TypeOf((*MockOAuthTokenRepo)(nil).CreateOAuthToken), ctx, params)
+}
+
+// FindOAuthToken mocks base method.
+func (m *MockOAuthTokenRepo) FindOAuthToken(ctx context.Context, id string) (*model.OAuthToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOAuthToken", ctx, id)
+	ret0, _ := ret[0].(*model.OAuthToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOAuthToken indicates an expected call of FindOAuthToken.
+func (mr *MockOAuthTokenRepoMockRecorder) FindOAuthToken(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOAuthToken", reflect.TypeOf((*MockOAuthTokenRepo)(nil).FindOAuthToken), ctx, id)
+}
+
+// RevokeOAuthCodeTokens mocks base method.
+func (m *MockOAuthTokenRepo) RevokeOAuthCodeTokens(ctx context.Context, codeID string, revokedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOAuthCodeTokens", ctx, codeID, revokedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOAuthCodeTokens indicates an expected call of RevokeOAuthCodeTokens.
+func (mr *MockOAuthTokenRepoMockRecorder) RevokeOAuthCodeTokens(ctx, codeID, revokedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOAuthCodeTokens", reflect.TypeOf((*MockOAuthTokenRepo)(nil).RevokeOAuthCodeTokens), ctx, codeID, revokedAt)
+}
+
+// RevokeOAuthToken mocks base method.
+func (m *MockOAuthTokenRepo) RevokeOAuthToken(ctx context.Context, id string, revokedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOAuthToken", ctx, id, revokedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOAuthToken indicates an expected call of RevokeOAuthToken.
+func (mr *MockOAuthTokenRepoMockRecorder) RevokeOAuthToken(ctx, id, revokedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOAuthToken", reflect.TypeOf((*MockOAuthTokenRepo)(nil).RevokeOAuthToken), ctx, id, revokedAt)
+}
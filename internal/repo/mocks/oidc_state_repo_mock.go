@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: OIDCStateRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/oidc_state_repo_mock.go -package=mocks . OIDCStateRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOIDCStateRepo is a mock of OIDCStateRepo interface.
+type MockOIDCStateRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCStateRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockOIDCStateRepoMockRecorder is the mock recorder for MockOIDCStateRepo.
+type MockOIDCStateRepoMockRecorder struct {
+	mock *MockOIDCStateRepo
+}
+
+// NewMockOIDCStateRepo creates a new mock instance.
+func NewMockOIDCStateRepo(ctrl *gomock.Controller) *MockOIDCStateRepo {
+	mock := &MockOIDCStateRepo{ctrl: ctrl}
+	mock.recorder = &MockOIDCStateRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCStateRepo) EXPECT() *MockOIDCStateRepoMockRecorder {
+	return m.recorder
+}
+
+// ConsumeOIDCState mocks base method.
+func (m *MockOIDCStateRepo) ConsumeOIDCState(ctx context.Context, id string) (*model.OIDCState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeOIDCState", ctx, id)
+	ret0, _ := ret[0].(*model.OIDCState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeOIDCState indicates an expected call of ConsumeOIDCState.
+func (mr *MockOIDCStateRepoMockRecorder) ConsumeOIDCState(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeOIDCState", reflect.TypeOf((*MockOIDCStateRepo)(nil).ConsumeOIDCState), ctx, id)
+}
+
+// CreateOIDCState mocks base method.
+func (m *MockOIDCStateRepo) CreateOIDCState(ctx context.Context, params model.OIDCState) (*model.OIDCState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOIDCState", ctx, params)
+	ret0, _ := ret[0].(*model.OIDCState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOIDCState indicates an expected call of CreateOIDCState.
+func (mr *MockOIDCStateRepoMockRecorder) CreateOIDCState(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOIDCState", reflect.TypeOf((*MockOIDCStateRepo)(nil).CreateOIDCState), ctx, params)
+}
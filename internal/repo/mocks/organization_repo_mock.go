@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: OrganizationRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/organization_repo_mock.go -package=mocks . OrganizationRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOrganizationRepo is a mock of OrganizationRepo interface.
+type MockOrganizationRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrganizationRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockOrganizationRepoMockRecorder is the mock recorder for MockOrganizationRepo.
+type MockOrganizationRepoMockRecorder struct {
+	mock *MockOrganizationRepo
+}
+
+// NewMockOrganizationRepo creates a new mock instance.
+func NewMockOrganizationRepo(ctrl *gomock.Controller) *MockOrganizationRepo {
+	mock := &MockOrganizationRepo{ctrl: ctrl}
+	mock.recorder = &MockOrganizationRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrganizationRepo) EXPECT() *MockOrganizationRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateOrganization mocks base method.
+func (m *MockOrganizationRepo) CreateOrganization(ctx context.Context, params model.Organization, owner model.Membership) (*model.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrganization", ctx, params, owner)
+	ret0, _ := ret[0].(*model.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockOrganizationRepoMockRecorder) CreateOrganization(ctx, params, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockOrganizationRepo)(nil).CreateOrganization), ctx, params, owner)
+}
+
+// FindMemberOrganization mocks base method.
+func (m *MockOrganizationRepo) FindMemberOrganization(ctx context.Context, id, userID string) (*model.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMemberOrganization", ctx, id, userID)
+	ret0, _ := ret[0].(*model.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMemberOrganization indicates an expected call of FindMemberOrganization.
+func (mr *MockOrganizationRepoMockRecorder) FindMemberOrganization(ctx, id, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMemberOrganization", reflect.TypeOf((*MockOrganizationRepo)(nil).FindMemberOrganization), ctx, id, userID)
+}
+
+// ListUserOrganizations mocks base method.
+func (m *MockOrganizationRepo) ListUserOrganizations(ctx context.Context, userID string) ([]model.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserOrganizations", ctx, userID)
+	ret0, _ := ret[0].([]model.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserOrganizations indicates an expected call of ListUserOrganizations.
+func (mr *MockOrganizationRepoMockRecorder) ListUserOrganizations(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserOrganizations", reflect.TypeOf((*MockOrganizationRepo)(nil).ListUserOrganizations), ctx, userID)
+}
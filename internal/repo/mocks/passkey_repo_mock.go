@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: PasskeyRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/passkey_repo_mock.go -package=mocks . PasskeyRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPasskeyRepo is a mock of PasskeyRepo interface.
+type MockPasskeyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasskeyRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockPasskeyRepoMockRecorder is the mock recorder for MockPasskeyRepo.
+type MockPasskeyRepoMockRecorder struct {
+	mock *MockPasskeyRepo
+}
+
+// NewMockPasskeyRepo creates a new mock instance.
+func NewMockPasskeyRepo(ctrl *gomock.Controller) *MockPasskeyRepo {
+	mock := &MockPasskeyRepo{ctrl: ctrl}
+	mock.recorder = &MockPasskeyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasskeyRepo) EXPECT() *MockPasskeyRepoMockRecorder {
+	return m.recorder
+}
+
+// CreatePasskey mocks base method.
+func (m *MockPasskeyRepo) CreatePasskey(ctx context.Context, params model.Passkey) (*model.Passkey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasskey", ctx, params)
+	ret0, _ := ret[0].(*model.Passkey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePasskey indicates an expected call of CreatePasskey.
+func (mr *MockPasskeyRepoMockRecorder) CreatePasskey(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasskey", reflect.TypeOf((*MockPasskeyRepo)(nil).CreatePasskey), ctx, params)
+}
+
+// DeletePasskey mocks base method.
+func (m *MockPasskeyRepo) DeletePasskey(ctx context.Context, userID, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePasskey", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePasskey indicates an expected call of DeletePasskey.
+func (mr *MockPasskeyRepoMockRecorder) DeletePasskey(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePasskey", reflect.TypeOf((*MockPasskeyRepo)(nil).DeletePasskey), ctx, userID, id)
+}
+
+// FindPasskey mocks base method.
+func (m *MockPasskeyRepo) FindPasskey(ctx context.Context, id string) (*model.Passkey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPasskey", ctx, id)
+	ret0, _ := ret[0].(*model.Passkey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPasskey indicates an expected call of FindPasskey.
+func (mr *MockPasskeyRepoMockRecorder) FindPasskey(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPasskey", reflect.TypeOf((*MockPasskeyRepo)(nil).FindPasskey), ctx, id)
+}
+
+// ListUserPasskeys mocks base method.
+func (m *MockPasskeyRepo) ListUserPasskeys(ctx context.Context, userID string) ([]model.Passkey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserPasskeys", ctx, userID)
+	ret0, _ := ret[0].([]model.Passkey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserPasskeys indicates an expected call of ListUserPasskeys.
+func (mr *MockPasskeyRepoMockRecorder) ListUserPasskeys(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserPasskeys", reflect.TypeOf((*MockPasskeyRepo)(nil).ListUserPasskeys), ctx, userID)
+}
+
+// UpdatePasskeyUse mocks base method.
+func (m *MockPasskeyRepo) UpdatePasskeyUse(ctx context.Context, id string, signCount uint32, backupState bool, usedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasskeyUse", ctx, id, signCount, backupState, usedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasskeyUse indicates an expected call of UpdatePasskeyUse.
+func (mr *MockPasskeyRepoMockRecorder) UpdatePasskeyUse(ctx, id, signCount, backupState, usedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasskeyUse", reflect.TypeOf((*MockPasskeyRepo)(nil).UpdatePasskeyUse), ctx, id, signCount, backupState, usedAt)
+}
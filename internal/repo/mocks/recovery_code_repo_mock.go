@@ -0,0 +1,84 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: RecoveryCodeRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/recovery_code_repo_mock.go -package=mocks . RecoveryCodeRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRecoveryCodeRepo is a mock of RecoveryCodeRepo interface.
+type MockRecoveryCodeRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecoveryCodeRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockRecoveryCodeRepoMockRecorder is the mock recorder for MockRecoveryCodeRepo.
+type MockRecoveryCodeRepoMockRecorder struct {
+	mock *MockRecoveryCodeRepo
+}
+
+// NewMockRecoveryCodeRepo creates a new mock instance.
+func NewMockRecoveryCodeRepo(ctrl *gomock.Controller) *MockRecoveryCodeRepo {
+	mock := &MockRecoveryCodeRepo{ctrl: ctrl}
+	mock.recorder = &MockRecoveryCodeRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecoveryCodeRepo) EXPECT() *MockRecoveryCodeRepoMockRecorder {
+	return m.recorder
+}
+
+// DeleteRecoveryCodes mocks base method.
+func (m *MockRecoveryCodeRepo) DeleteRecoveryCodes(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodes", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecoveryCodes indicates an expected call of DeleteRecoveryCodes.
+func (mr *MockRecoveryCodeRepoMockRecorder) DeleteRecoveryCodes(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodes", reflect.TypeOf((*MockRecoveryCodeRepo)(nil).DeleteRecoveryCodes), ctx, userID)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockRecoveryCodeRepo) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", ctx, userID, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockRecoveryCodeRepoMockRecorder) ReplaceRecoveryCodes(ctx, userID, codeHashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockRecoveryCodeRepo)(nil).ReplaceRecoveryCodes), ctx, userID, codeHashes)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockRecoveryCodeRepo) UseRecoveryCode(ctx context.Context, userID, codeHash string, usedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, userID, codeHash, usedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockRecoveryCodeRepoMockRecorder) UseRecoveryCode(ctx, userID, codeHash, usedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockRecoveryCodeRepo)(nil).UseRecoveryCode), ctx, userID, codeHash, usedAt)
+}
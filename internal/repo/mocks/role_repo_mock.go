@@ -0,0 +1,160 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: RoleRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/role_repo_mock.go -package=mocks . RoleRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRoleRepo is a mock of RoleRepo interface.
+type MockRoleRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockRoleRepoMockRecorder is the mock recorder for MockRoleRepo.
+type MockRoleRepoMockRecorder struct {
+	mock *MockRoleRepo
+}
+
+// NewMockRoleRepo creates a new mock instance.
+func NewMockRoleRepo(ctrl *gomock.Controller) *MockRoleRepo {
+	mock := &MockRoleRepo{ctrl: ctrl}
+	mock.recorder = &MockRoleRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleRepo) EXPECT() *MockRoleRepoMockRecorder {
+	return m.recorder
+}
+
+// AssignRole mocks base method.
+func (m *MockRoleRepo) AssignRole(ctx context.Context, userID, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRole", ctx, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignRole indicates an expected call of AssignRole.
+func (mr *MockRoleRepoMockRecorder) AssignRole(ctx, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRole", reflect.TypeOf((*MockRoleRepo)(nil).AssignRole), ctx, userID, role)
+}
+
+// CreateRole mocks base method.
+func (m *MockRoleRepo) CreateRole(ctx context.Context, params model.Role) (*model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRole", ctx, params)
+	ret0, _ := ret[0].(*model.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRole indicates an expected call of CreateRole.
+func (mr *MockRoleRepoMockRecorder) CreateRole(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*MockRoleRepo)(nil).CreateRole), ctx, params)
+}
+
+// FindRole mocks base method.
+func (m *MockRoleRepo) FindRole(ctx context.Context, name string) (*model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRole", ctx, name)
+	ret0, _ := ret[0].(*model.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRole indicates an expected call of FindRole.
+func (mr *MockRoleRepoMockRecorder) FindRole(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRole", reflect.TypeOf((*MockRoleRepo)(nil).FindRole), ctx, name)
+}
+
+// ListMemberPermissions mocks base method.
+func (m *MockRoleRepo) ListMemberPermissions(ctx context.Context, organizationID, userID string) ([]model.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMemberPermissions", ctx, organizationID, userID)
+	ret0, _ := ret[0].([]model.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMemberPermissions indicates an expected call of ListMemberPermissions.
+func (mr *MockRoleRepoMockRecorder) ListMemberPermissions(ctx, organizationID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMemberPermissions", reflect.TypeOf((*MockRoleRepo)(nil).ListMemberPermissions), ctx, organizationID, userID)
+}
+
+// ListRoles mocks base method.
+func (m *MockRoleRepo) ListRoles(ctx context.Context) ([]model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoles", ctx)
+	ret0, _ := ret[0].([]model.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoles indicates an expected call of ListRoles.
+func (mr *MockRoleRepoMockRecorder) ListRoles(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoles", reflect.TypeOf((*MockRoleRepo)(nil).ListRoles), ctx)
+}
+
+// ListUserPermissions mocks base method.
+func (m *MockRoleRepo) ListUserPermissions(ctx context.Context, userID string) ([]model.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserPermissions", ctx, userID)
+	ret0, _ := ret[0].([]model.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserPermissions indicates an expected call of ListUserPermissions.
+func (mr *MockRoleRepoMockRecorder) ListUserPermissions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserPermissions", reflect.TypeOf((*MockRoleRepo)(nil).ListUserPermissions), ctx, userID)
+}
+
+// ListUserRoles mocks base method.
+func (m *MockRoleRepo) ListUserRoles(ctx context.Context, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserRoles", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserRoles indicates an expected call of ListUserRoles.
+func (mr *MockRoleRepoMockRecorder) ListUserRoles(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserRoles", reflect.TypeOf((*MockRoleRepo)(nil).ListUserRoles), ctx, userID)
+}
+
+// UnassignRole mocks base method.
+func (m *MockRoleRepo) UnassignRole(ctx context.Context, userID, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignRole", ctx, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnassignRole indicates an expected call of UnassignRole.
+func (mr *MockRoleRepoMockRecorder) UnassignRole(ctx, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignRole", reflect.TypeOf((*MockRoleRepo)(nil).UnassignRole), ctx, userID, role)
+}
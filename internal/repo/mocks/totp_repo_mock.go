@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: TOTPRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/totp_repo_mock.go -package=mocks . TOTPRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTOTPRepo is a mock of TOTPRepo interface.
+type MockTOTPRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTOTPRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockTOTPRepoMockRecorder is the mock recorder for MockTOTPRepo.
+type MockTOTPRepoMockRecorder struct {
+	mock *MockTOTPRepo
+}
+
+// NewMockTOTPRepo creates a new mock instance.
+func NewMockTOTPRepo(ctrl *gomock.Controller) *MockTOTPRepo {
+	mock := &MockTOTPRepo{ctrl: ctrl}
+	mock.recorder = &MockTOTPRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTOTPRepo) EXPECT() *MockTOTPRepoMockRecorder {
+	return m.recorder
+}
+
+// ConfirmTOTP mocks base method.
+func (m *MockTOTPRepo) ConfirmTOTP(ctx context.Context, userID string, step int64, confirmedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTP", ctx, userID, step, confirmedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmTOTP indicates an expected call of ConfirmTOTP.
+func (mr *MockTOTPRepoMockRecorder) ConfirmTOTP(ctx, userID, step, confirmedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTP", reflect.TypeOf((*MockTOTPRepo)(nil).ConfirmTOTP), ctx, userID, step, confirmedAt)
+}
+
+// DeleteTOTP mocks base method.
+func (m *MockTOTPRepo) DeleteTOTP(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTOTP", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTOTP indicates an expected call of DeleteTOTP.
+func (mr *MockTOTPRepoMockRecorder) DeleteTOTP(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTOTP", reflect.TypeOf((*MockTOTPRepo)(nil).DeleteTOTP), ctx, userID)
+}
+
+// FindTOTP mocks base method.
+func (m *MockTOTPRepo) FindTOTP(ctx context.Context, userID string) (*model.TOTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTOTP", ctx, userID)
+	ret0, _ := ret[0].(*model.TOTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTOTP indicates an expected call of FindTOTP.
+func (mr *MockTOTPRepoMockRecorder) FindTOTP(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTOTP", reflect.TypeOf((*MockTOTPRepo)(nil).FindTOTP), ctx, userID)
+}
+
+// SaveTOTP mocks base method.
+func (m *MockTOTPRepo) SaveTOTP(ctx context.Context, userID, secret string) (*model.TOTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTOTP", ctx, userID, secret)
+	ret0, _ := ret[0].(*model.TOTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveTOTP indicates an expected call of SaveTOTP.
+func (mr *MockTOTPRepoMockRecorder) SaveTOTP(ctx, userID, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTOTP", reflect.TypeOf((*MockTOTPRepo)(nil).SaveTOTP), ctx, userID, secret)
+}
+
+// UpdateTOTPStep mocks base method.
+func (m *MockTOTPRepo) UpdateTOTPStep(ctx context.Context, userID string, step int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTOTPStep", ctx, userID, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTOTPStep indicates an expected call of UpdateTOTPStep.
+func (mr *MockTOTPRepoMockRecorder) UpdateTOTPStep(ctx, userID, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTOTPStep", reflect.TypeOf((*MockTOTPRepo)(nil).UpdateTOTPStep), ctx, userID, step)
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: UserTokenRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/user_token_repo_mock.go -package=mocks . UserTokenRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserTokenRepo is a mock of UserTokenRepo interface.
+type MockUserTokenRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserTokenRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockUserTokenRepoMockRecorder is the mock recorder for MockUserTokenRepo.
+type MockUserTokenRepoMockRecorder struct {
+	mock *MockUserTokenRepo
+}
+
+// NewMockUserTokenRepo creates a new mock instance.
+func NewMockUserTokenRepo(ctrl *gomock.Controller) *MockUserTokenRepo {
+	mock := &MockUserTokenRepo{ctrl: ctrl}
+	mock.recorder = &MockUserTokenRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserTokenRepo) EXPECT() *MockUserTokenRepoMockRecorder {
+	return m.recorder
+}
+
+// ConsumeUserToken mocks base method.
+func (m *MockUserTokenRepo) ConsumeUserToken(ctx context.Context, id string, purpose model.TokenPurpose) (*model.UserToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeUserToken", ctx, id, purpose)
+	ret0, _ := ret[0].(*model.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeUserToken indicates an expected call of ConsumeUserToken.
+func (mr *MockUserTokenRepoMockRecorder) ConsumeUserToken(ctx, id, purpose any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeUserToken", reflect.TypeOf((*MockUserTokenRepo)(nil).ConsumeUserToken), ctx, id, purpose)
+}
+
+// CreateUserToken mocks base method.
+func (m *MockUserTokenRepo) CreateUserToken(ctx context.Context, params model.UserToken) (*model.UserToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserToken", ctx, params)
+	ret0, _ := ret[0].(*model.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserToken indicates an expected call of CreateUserToken.
+func (mr *MockUserTokenRepoMockRecorder) CreateUserToken(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserToken", reflect.TypeOf((*MockUserTokenRepo)(nil).CreateUserToken), ctx, params)
+}
+
+// DeleteUserTokens mocks base method.
+func (m *MockUserTokenRepo) DeleteUserTokens(ctx context.Context, userID string, purpose model.TokenPurpose) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserTokens", ctx, userID, purpose)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserTokens indicates an expected call of DeleteUserTokens.
+func (mr *MockUserTokenRepoMockRecorder) DeleteUserTokens(ctx, userID, purpose any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserTokens", reflect.TypeOf((*MockUserTokenRepo)(nil).DeleteUserTokens), ctx, userID, purpose)
+}
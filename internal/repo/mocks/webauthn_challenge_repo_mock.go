@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/repo (interfaces: WebAuthnChallengeRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/webauthn_challenge_repo_mock.go -package=mocks . WebAuthnChallengeRepo
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWebAuthnChallengeRepo is a mock of WebAuthnChallengeRepo interface.
+type MockWebAuthnChallengeRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebAuthnChallengeRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockWebAuthnChallengeRepoMockRecorder is the mock recorder for MockWebAuthnChallengeRepo.
+type MockWebAuthnChallengeRepoMockRecorder struct {
+	mock *MockWebAuthnChallengeRepo
+}
+
+// NewMockWebAuthnChallengeRepo creates a new mock instance.
+func NewMockWebAuthnChallengeRepo(ctrl *gomock.Controller) *MockWebAuthnChallengeRepo {
+	mock := &MockWebAuthnChallengeRepo{ctrl: ctrl}
+	mock.recorder = &MockWebAuthnChallengeRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebAuthnChallengeRepo) EXPECT() *MockWebAuthnChallengeRepoMockRecorder {
+	return m.recorder
+}
+
+// ConsumeWebAuthnChallenge mocks base method.
+func (m *MockWebAuthnChallengeRepo) ConsumeWebAuthnChallenge(ctx context.Context, id string) (*model.WebAuthnChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeWebAuthnChallenge", ctx, id)
+	ret0, _ := ret[0].(*model.WebAuthnChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeWebAuthnChallenge indicates an expected call of ConsumeWebAuthnChallenge.
+func (mr *MockWebAuthnChallengeRepoMockRecorder) ConsumeWebAuthnChallenge(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeWebAuthnChallenge", reflect.TypeOf((*MockWebAuthnChallengeRepo)(nil).ConsumeWebAuthnChallenge), ctx, id)
+}
+
+// CreateWebAuthnChallenge mocks base method.
+func (m *MockWebAuthnChallengeRepo) CreateWebAuthnChallenge(ctx context.Context, params model.WebAuthnChallenge) (*model.WebAuthnChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebAuthnChallenge", ctx, params)
+	ret0, _ := ret[0].(*model.WebAuthnChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebAuthnChallenge indicates an expected call of CreateWebAuthnChallenge.
+func (mr *MockWebAuthnChallengeRepoMockRecorder) CreateWebAuthnChallenge(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebAuthnChallenge", reflect.TypeOf((*MockWebAuthnChallengeRepo)(nil).CreateWebAuthnChallenge), ctx, params)
+}
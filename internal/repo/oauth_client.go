@@ -0,0 +1,71 @@
+//go:generate mockgen -destination=mocks/oauth_client_repo_mock.go -package=mocks . OAuthClientRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/ferdiebergado/fullstackgo/internal/db"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type OAuthClientRepo interface {
+	CreateOAuthClient(ctx context.Context, params model.OAuthClient) (*model.OAuthClient, error)
+	FindOAuthClient(ctx context.Context, id string) (*model.OAuthClient, error)
+}
+
+type oauthClientRepo struct {
+	db *sql.DB
+}
+
+func NewOAuthClientRepo(db *sql.DB) OAuthClientRepo {
+	return &oauthClientRepo{
+		db: db,
+	}
+}
+
+const CreateOAuthClientQuery = `
+INSERT INTO oauth_clients (id, secret_hash, name, redirect_uris, grant_types, scope)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, secret_hash, name, redirect_uris, grant_types, scope, created_at
+`
+
+// CreateOAuthClient registers a client. Redirect URIs and grant types are
+// stored space-separated, as neither can contain spaces.
+func (r *oauthClientRepo) CreateOAuthClient(ctx context.Context, params model.OAuthClient) (*model.OAuthClient, error) {
+	client, err := scanOAuthClient(r.db.QueryRowContext(ctx, CreateOAuthClientQuery, params.ID, params.SecretHash,
+		params.Name, strings.Join(params.RedirectURIs, " "), strings.Join(params.GrantTypes, " "), params.Scope))
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return nil, ErrDuplicate
+		}
+		return nil, err
+	}
+
+	return client, nil
+}
+
+const FindOAuthClientQuery = `
+SELECT id, secret_hash, name, redirect_uris, grant_types, scope, created_at
+FROM oauth_clients
+WHERE id = $1
+`
+
+func (r *oauthClientRepo) FindOAuthClient(ctx context.Context, id string) (*model.OAuthClient, error) {
+	return scanOAuthClient(r.db.QueryRowContext(ctx, FindOAuthClientQuery, id))
+}
+
+func scanOAuthClient(row rowScanner) (*model.OAuthClient, error) {
+	var client model.OAuthClient
+	var redirectURIs, grantTypes string
+	if err := row.Scan(&client.ID, &client.SecretHash, &client.Name, &redirectURIs, &grantTypes,
+		&client.Scope, &client.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	client.RedirectURIs = strings.Fields(redirectURIs)
+	client.GrantTypes = strings.Fields(grantTypes)
+
+	return &client, nil
+}
@@ -0,0 +1,81 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+const testClientID = "client"
+
+var oauthClientCols = []string{"id", "secret_hash", "name", "redirect_uris", "grant_types", "scope", "created_at"}
+
+func TestOAuthClientRepo_CreateOAuthClient_Success(t *testing.T) {
+	mock, clients := setupMockOAuthClientRepo(t)
+	now := time.Now().UTC()
+	params := model.OAuthClient{
+		ID:           testClientID,
+		SecretHash:   "secrethash",
+		Name:         "Example",
+		RedirectURIs: []string{"https://app.example.com/callback", "https://app.example.com/other"},
+		GrantTypes:   []string{"authorization_code"},
+		Scope:        "profile",
+	}
+
+	mock.ExpectQuery(repo.CreateOAuthClientQuery).
+		WithArgs(params.ID, params.SecretHash, params.Name,
+			"https://app.example.com/callback https://app.example.com/other", "authorization_code", params.Scope).
+		WillReturnRows(sqlmock.NewRows(oauthClientCols).
+			AddRow(params.ID, params.SecretHash, params.Name,
+				"https://app.example.com/callback https://app.example.com/other", "authorization_code", params.Scope, now))
+
+	client, err := clients.CreateOAuthClient(context.Background(), params)
+
+	assert.NoError(t, err, "create oauth client should not return an error")
+	assert.Equal(t, params.RedirectURIs, client.RedirectURIs, "redirect URIs must match")
+	assert.Equal(t, params.GrantTypes, client.GrantTypes, "grant types must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestOAuthClientRepo_FindOAuthClient_NoRedirectURIs(t *testing.T) {
+	mock, clients := setupMockOAuthClientRepo(t)
+
+	mock.ExpectQuery(repo.FindOAuthClientQuery).
+		WithArgs(testClientID).
+		WillReturnRows(sqlmock.NewRows(oauthClientCols).
+			AddRow(testClientID, "secrethash", "Service", "", "client_credentials", "", time.Now()))
+
+	client, err := clients.FindOAuthClient(context.Background(), testClientID)
+
+	assert.NoError(t, err, "find oauth client should not return an error")
+	assert.Empty(t, client.RedirectURIs, "redirect URIs should be empty")
+	assert.Equal(t, []string{"client_credentials"}, client.GrantTypes, "grant types must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestOAuthClientRepo_FindOAuthClient_NotFound(t *testing.T) {
+	mock, clients := setupMockOAuthClientRepo(t)
+	mock.ExpectQuery(repo.FindOAuthClientQuery).WithArgs(testClientID).WillReturnError(sql.ErrNoRows)
+
+	_, err := clients.FindOAuthClient(context.Background(), testClientID)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockOAuthClientRepo(t *testing.T) (sqlmock.Sqlmock, repo.OAuthClientRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	clients := repo.NewOAuthClientRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, clients
+}
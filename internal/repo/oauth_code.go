@@ -0,0 +1,73 @@
+//go:generate mockgen -destination=mocks/oauth_code_repo_mock.go -package=mocks . OAuthCodeRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type OAuthCodeRepo interface {
+	CreateOAuthCode(ctx context.Context, params model.OAuthCode) (*model.OAuthCode, error)
+	FindOAuthCode(ctx context.Context, id string) (*model.OAuthCode, error)
+	UseOAuthCode(ctx context.Context, id, clientID, redirectURI string, usedAt time.Time) (*model.OAuthCode, error)
+}
+
+type oauthCodeRepo struct {
+	db *sql.DB
+}
+
+func NewOAuthCodeRepo(db *sql.DB) OAuthCodeRepo {
+	return &oauthCodeRepo{
+		db: db,
+	}
+}
+
+const CreateOAuthCodeQuery = `
+INSERT INTO oauth_codes (id, client_id, user_id, redirect_uri, scope, code_challenge, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, client_id, user_id, redirect_uri, scope, code_challenge, expires_at, used_at, created_at
+`
+
+func (r *oauthCodeRepo) CreateOAuthCode(ctx context.Context, params model.OAuthCode) (*model.OAuthCode, error) {
+	return scanOAuthCode(r.db.QueryRowContext(ctx, CreateOAuthCodeQuery, params.ID, params.ClientID, params.UserID,
+		params.RedirectURI, params.Scope, params.CodeChallenge, params.ExpiresAt))
+}
+
+const FindOAuthCodeQuery = `
+SELECT id, client_id, user_id, redirect_uri, scope, code_challenge, expires_at, used_at, created_at
+FROM oauth_codes
+WHERE id = $1
+`
+
+func (r *oauthCodeRepo) FindOAuthCode(ctx context.Context, id string) (*model.OAuthCode, error) {
+	return scanOAuthCode(r.db.QueryRowContext(ctx, FindOAuthCodeQuery, id))
+}
+
+const UseOAuthCodeQuery = `
+UPDATE oauth_codes
+SET used_at = $4
+WHERE id = $1 AND client_id = $2 AND redirect_uri = $3 AND used_at IS NULL
+RETURNING id, client_id, user_id, redirect_uri, scope, code_challenge, expires_at, used_at, created_at
+`
+
+// UseOAuthCode marks the code as exchanged and returns it. It returns
+// sql.ErrNoRows if the code does not exist, was already used, or was issued
+// to another client or redirect URI, so that a code is exchanged at most once
+// even by concurrent requests and cannot be burned by whoever else holds it.
+func (r *oauthCodeRepo) UseOAuthCode(ctx context.Context, id, clientID, redirectURI string,
+	usedAt time.Time) (*model.OAuthCode, error) {
+	return scanOAuthCode(r.db.QueryRowContext(ctx, UseOAuthCodeQuery, id, clientID, redirectURI, usedAt))
+}
+
+func scanOAuthCode(row rowScanner) (*model.OAuthCode, error) {
+	var code model.OAuthCode
+	if err := row.Scan(&code.ID, &code.ClientID, &code.UserID, &code.RedirectURI, &code.Scope,
+		&code.CodeChallenge, &code.ExpiresAt, &code.UsedAt, &code.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &code, nil
+}
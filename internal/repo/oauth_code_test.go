@@ -0,0 +1,72 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testCodeID       = "codehash"
+	testCodeRedirect = "https://app.example.com/callback"
+)
+
+var oauthCodeCols = []string{"id", "client_id", "user_id", "redirect_uri", "scope", "code_challenge",
+	"expires_at", "used_at", "created_at"}
+
+func TestOAuthCodeRepo_CreateOAuthCode_Success(t *testing.T) {
+	mock, codes := setupMockOAuthCodeRepo(t)
+	now := time.Now().UTC()
+	params := model.OAuthCode{
+		ID:            testCodeID,
+		ClientID:      testClientID,
+		UserID:        testID,
+		RedirectURI:   "https://app.example.com/callback",
+		Scope:         "profile",
+		CodeChallenge: "challenge",
+		ExpiresAt:     now.Add(time.Minute),
+	}
+
+	mock.ExpectQuery(repo.CreateOAuthCodeQuery).
+		WithArgs(params.ID, params.ClientID, params.UserID, params.RedirectURI, params.Scope,
+			params.CodeChallenge, params.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows(oauthCodeCols).
+			AddRow(params.ID, params.ClientID, params.UserID, params.RedirectURI, params.Scope,
+				params.CodeChallenge, params.ExpiresAt, nil, now))
+
+	code, err := codes.CreateOAuthCode(context.Background(), params)
+
+	assert.NoError(t, err, "create oauth code should not return an error")
+	assert.Equal(t, params.CodeChallenge, code.CodeChallenge, "code challenge must match")
+	assert.Nil(t, code.UsedAt, "new code should not be used")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestOAuthCodeRepo_UseOAuthCode_AlreadyUsed(t *testing.T) {
+	mock, codes := setupMockOAuthCodeRepo(t)
+	now := time.Now().UTC()
+	mock.ExpectQuery(repo.UseOAuthCodeQuery).WithArgs(testCodeID, testClientID, testCodeRedirect, now).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := codes.UseOAuthCode(context.Background(), testCodeID, testClientID, testCodeRedirect, now)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockOAuthCodeRepo(t *testing.T) (sqlmock.Sqlmock, repo.OAuthCodeRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	codes := repo.NewOAuthCodeRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, codes
+}
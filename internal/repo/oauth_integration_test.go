@@ -0,0 +1,80 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOAuthRepos_Integration_Lifecycle(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	clients := repo.NewOAuthClientRepo(conn)
+	codes := repo.NewOAuthCodeRepo(conn)
+	tokens := repo.NewOAuthTokenRepo(conn)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	params := model.OAuthClient{
+		ID:           testClientID,
+		Name:         "Example",
+		RedirectURIs: []string{"https://app.example.com/callback"},
+		GrantTypes:   []string{"authorization_code"},
+		Scope:        "profile",
+	}
+	_, err := clients.CreateOAuthClient(ctx, params)
+	assert.NoError(t, err, "create oauth client should not return an error")
+	_, err = clients.CreateOAuthClient(ctx, params)
+	assert.ErrorIs(t, err, repo.ErrDuplicate, "client IDs should be unique")
+
+	client, err := clients.FindOAuthClient(ctx, testClientID)
+	assert.NoError(t, err, "find oauth client should not return an error")
+	assert.Equal(t, params.RedirectURIs, client.RedirectURIs, "redirect URIs must match")
+	assert.Empty(t, client.SecretHash, "public clients should have no secret")
+
+	_, err = codes.CreateOAuthCode(ctx, model.OAuthCode{
+		ID:            testCodeID,
+		ClientID:      testClientID,
+		UserID:        user.ID,
+		RedirectURI:   "https://app.example.com/callback",
+		CodeChallenge: "challenge",
+		ExpiresAt:     now.Add(time.Minute),
+	})
+	assert.NoError(t, err, "create oauth code should not return an error")
+
+	_, err = codes.UseOAuthCode(ctx, testCodeID, "other", "https://app.example.com/callback", now)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "other clients should not use the code")
+	_, err = codes.UseOAuthCode(ctx, testCodeID, testClientID, "https://app.example.com/other", now)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "other redirect URIs should not use the code")
+
+	used, err := codes.UseOAuthCode(ctx, testCodeID, testClientID, "https://app.example.com/callback", now)
+	assert.NoError(t, err, "use oauth code should not return an error")
+	assert.Equal(t, user.ID, used.UserID, "user ID must match")
+	_, err = codes.UseOAuthCode(ctx, testCodeID, testClientID, "https://app.example.com/callback", now)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "codes should only be used once")
+
+	found, err := codes.FindOAuthCode(ctx, testCodeID)
+	assert.NoError(t, err, "find oauth code should not return an error")
+	assert.NotNil(t, found.UsedAt, "use should be recorded")
+
+	codeID := testCodeID
+	_, err = tokens.CreateOAuthToken(ctx, model.OAuthToken{
+		ID:        testOAuthTokenID,
+		ClientID:  testClientID,
+		UserID:    &user.ID,
+		CodeID:    &codeID,
+		ExpiresAt: now.Add(time.Hour),
+	})
+	assert.NoError(t, err, "create oauth token should not return an error")
+
+	assert.NoError(t, tokens.RevokeOAuthCodeTokens(ctx, testCodeID, now),
+		"revoke code tokens should not return an error")
+	token, err := tokens.FindOAuthToken(ctx, testOAuthTokenID)
+	assert.NoError(t, err, "find oauth token should not return an error")
+	assert.NotNil(t, token.RevokedAt, "tokens of the code should be revoked")
+}
@@ -0,0 +1,82 @@
+//go:generate mockgen -destination=mocks/oauth_token_repo_mock.go -package=mocks . OAuthTokenRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type OAuthTokenRepo interface {
+	CreateOAuthToken(ctx context.Context, params model.OAuthToken) (*model.OAuthToken, error)
+	FindOAuthToken(ctx context.Context, id string) (*model.OAuthToken, error)
+	RevokeOAuthToken(ctx context.Context, id string, revokedAt time.Time) error
+	RevokeOAuthCodeTokens(ctx context.Context, codeID string, revokedAt time.Time) error
+}
+
+type oauthTokenRepo struct {
+	db *sql.DB
+}
+
+func NewOAuthTokenRepo(db *sql.DB) OAuthTokenRepo {
+	return &oauthTokenRepo{
+		db: db,
+	}
+}
+
+const CreateOAuthTokenQuery = `
+INSERT INTO oauth_tokens (id, client_id, user_id, code_id, scope, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, client_id, user_id, code_id, scope, expires_at, revoked_at, created_at
+`
+
+func (r *oauthTokenRepo) CreateOAuthToken(ctx context.Context, params model.OAuthToken) (*model.OAuthToken, error) {
+	return scanOAuthToken(r.db.QueryRowContext(ctx, CreateOAuthTokenQuery, params.ID, params.ClientID, params.UserID,
+		params.CodeID, params.Scope, params.ExpiresAt))
+}
+
+const FindOAuthTokenQuery = `
+SELECT id, client_id, user_id, code_id, scope, expires_at, revoked_at, created_at
+FROM oauth_tokens
+WHERE id = $1
+`
+
+func (r *oauthTokenRepo) FindOAuthToken(ctx context.Context, id string) (*model.OAuthToken, error) {
+	return scanOAuthToken(r.db.QueryRowContext(ctx, FindOAuthTokenQuery, id))
+}
+
+const RevokeOAuthTokenQuery = `
+UPDATE oauth_tokens
+SET revoked_at = $2
+WHERE id = $1 AND revoked_at IS NULL
+`
+
+func (r *oauthTokenRepo) RevokeOAuthToken(ctx context.Context, id string, revokedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, RevokeOAuthTokenQuery, id, revokedAt)
+	return err
+}
+
+const RevokeOAuthCodeTokensQuery = `
+UPDATE oauth_tokens
+SET revoked_at = $2
+WHERE code_id = $1 AND revoked_at IS NULL
+`
+
+// RevokeOAuthCodeTokens revokes the tokens issued for an authorization code,
+// as required when the code is replayed.
+func (r *oauthTokenRepo) RevokeOAuthCodeTokens(ctx context.Context, codeID string, revokedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, RevokeOAuthCodeTokensQuery, codeID, revokedAt)
+	return err
+}
+
+func scanOAuthToken(row rowScanner) (*model.OAuthToken, error) {
+	var token model.OAuthToken
+	if err := row.Scan(&token.ID, &token.ClientID, &token.UserID, &token.CodeID, &token.Scope,
+		&token.ExpiresAt, &token.RevokedAt, &token.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
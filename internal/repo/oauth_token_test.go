@@ -0,0 +1,81 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+const testOAuthTokenID = "jti"
+
+var oauthTokenCols = []string{"id", "client_id", "user_id", "code_id", "scope", "expires_at", "revoked_at",
+	"created_at"}
+
+func TestOAuthTokenRepo_CreateOAuthToken_ClientToken(t *testing.T) {
+	mock, tokens := setupMockOAuthTokenRepo(t)
+	now := time.Now().UTC()
+	params := model.OAuthToken{
+		ID:        testOAuthTokenID,
+		ClientID:  testClientID,
+		Scope:     "reports",
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	mock.ExpectQuery(repo.CreateOAuthTokenQuery).
+		WithArgs(params.ID, params.ClientID, nil, nil, params.Scope, params.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows(oauthTokenCols).
+			AddRow(params.ID, params.ClientID, nil, nil, params.Scope, params.ExpiresAt, nil, now))
+
+	token, err := tokens.CreateOAuthToken(context.Background(), params)
+
+	assert.NoError(t, err, "create oauth token should not return an error")
+	assert.Nil(t, token.UserID, "client tokens should have no user")
+	assert.Nil(t, token.CodeID, "client tokens should have no code")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestOAuthTokenRepo_FindOAuthToken_Revoked(t *testing.T) {
+	mock, tokens := setupMockOAuthTokenRepo(t)
+	now := time.Now().UTC()
+
+	mock.ExpectQuery(repo.FindOAuthTokenQuery).
+		WithArgs(testOAuthTokenID).
+		WillReturnRows(sqlmock.NewRows(oauthTokenCols).
+			AddRow(testOAuthTokenID, testClientID, testID, testCodeID, "", now.Add(time.Hour), now, now))
+
+	token, err := tokens.FindOAuthToken(context.Background(), testOAuthTokenID)
+
+	assert.NoError(t, err, "find oauth token should not return an error")
+	if assert.NotNil(t, token.UserID, "user ID should be loaded") {
+		assert.Equal(t, testID, *token.UserID, "user ID must match")
+	}
+	assert.NotNil(t, token.RevokedAt, "revoked_at should be loaded")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestOAuthTokenRepo_RevokeOAuthCodeTokens(t *testing.T) {
+	mock, tokens := setupMockOAuthTokenRepo(t)
+	now := time.Now().UTC()
+	mock.ExpectExec(repo.RevokeOAuthCodeTokensQuery).WithArgs(testCodeID, now).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := tokens.RevokeOAuthCodeTokens(context.Background(), testCodeID, now)
+
+	assert.NoError(t, err, "revoke code tokens should not return an error")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockOAuthTokenRepo(t *testing.T) (sqlmock.Sqlmock, repo.OAuthTokenRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	tokens := repo.NewOAuthTokenRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, tokens
+}
@@ -0,0 +1,59 @@
+//go:generate mockgen -destination=mocks/oidc_state_repo_mock.go -package=mocks . OIDCStateRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type OIDCStateRepo interface {
+	CreateOIDCState(ctx context.Context, params model.OIDCState) (*model.OIDCState, error)
+	ConsumeOIDCState(ctx context.Context, id string) (*model.OIDCState, error)
+}
+
+type oidcStateRepo struct {
+	db *sql.DB
+}
+
+func NewOIDCStateRepo(db *sql.DB) OIDCStateRepo {
+	return &oidcStateRepo{
+		db: db,
+	}
+}
+
+const CreateOIDCStateQuery = `
+INSERT INTO oidc_states (id, provider, nonce, code_verifier, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, provider, nonce, code_verifier, expires_at, created_at
+`
+
+func (r *oidcStateRepo) CreateOIDCState(ctx context.Context, params model.OIDCState) (*model.OIDCState, error) {
+	var state model.OIDCState
+	if err := r.db.QueryRowContext(ctx, CreateOIDCStateQuery, params.ID, params.Provider, params.Nonce,
+		params.CodeVerifier, params.ExpiresAt).
+		Scan(&state.ID, &state.Provider, &state.Nonce, &state.CodeVerifier, &state.ExpiresAt, &state.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+const ConsumeOIDCStateQuery = `
+DELETE FROM oidc_states
+WHERE id = $1
+RETURNING id, provider, nonce, code_verifier, expires_at, created_at
+`
+
+// ConsumeOIDCState deletes the state and returns it, so that it answers at
+// most one callback.
+func (r *oidcStateRepo) ConsumeOIDCState(ctx context.Context, id string) (*model.OIDCState, error) {
+	var state model.OIDCState
+	if err := r.db.QueryRowContext(ctx, ConsumeOIDCStateQuery, id).
+		Scan(&state.ID, &state.Provider, &state.Nonce, &state.CodeVerifier, &state.ExpiresAt, &state.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
@@ -0,0 +1,35 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOIDCStateRepo_Integration_Lifecycle(t *testing.T) {
+	conn := newTestDB(t)
+	states := repo.NewOIDCStateRepo(conn)
+	ctx := context.Background()
+
+	_, err := states.CreateOIDCState(ctx, model.OIDCState{
+		ID:           testChallengeID,
+		Provider:     testProvider,
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		ExpiresAt:    time.Now().UTC().Add(10 * time.Minute),
+	})
+	assert.NoError(t, err, "create oidc state should not return an error")
+
+	consumed, err := states.ConsumeOIDCState(ctx, testChallengeID)
+	assert.NoError(t, err, "consume oidc state should not return an error")
+	assert.Equal(t, testProvider, consumed.Provider, "provider must match")
+	assert.Equal(t, "nonce", consumed.Nonce, "nonce must match")
+
+	_, err = states.ConsumeOIDCState(ctx, testChallengeID)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "states should only be consumed once")
+}
@@ -0,0 +1,61 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+var oidcStateCols = []string{"id", "provider", "nonce", "code_verifier", "expires_at", "created_at"}
+
+func TestOIDCStateRepo_CreateOIDCState_Success(t *testing.T) {
+	mock, states := setupMockOIDCStateRepo(t)
+	now := time.Now().UTC()
+	params := model.OIDCState{
+		ID:           testChallengeID,
+		Provider:     testProvider,
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		ExpiresAt:    now.Add(10 * time.Minute),
+	}
+
+	mock.ExpectQuery(repo.CreateOIDCStateQuery).
+		WithArgs(params.ID, params.Provider, params.Nonce, params.CodeVerifier, params.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows(oidcStateCols).
+			AddRow(params.ID, params.Provider, params.Nonce, params.CodeVerifier, params.ExpiresAt, now))
+
+	state, err := states.CreateOIDCState(context.Background(), params)
+
+	assert.NoError(t, err, "create oidc state should not return an error")
+	assert.Equal(t, "verifier", state.CodeVerifier, "code verifier must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestOIDCStateRepo_ConsumeOIDCState_NotFound(t *testing.T) {
+	mock, states := setupMockOIDCStateRepo(t)
+	mock.ExpectQuery(repo.ConsumeOIDCStateQuery).
+		WithArgs(testChallengeID).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := states.ConsumeOIDCState(context.Background(), testChallengeID)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockOIDCStateRepo(t *testing.T) (sqlmock.Sqlmock, repo.OIDCStateRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	states := repo.NewOIDCStateRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, states
+}
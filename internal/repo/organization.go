@@ -0,0 +1,105 @@
+//go:generate mockgen -destination=mocks/organization_repo_mock.go -package=mocks . OrganizationRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type OrganizationRepo interface {
+	CreateOrganization(ctx context.Context, params model.Organization, owner model.Membership) (*model.Organization, error)
+	FindMemberOrganization(ctx context.Context, id, userID string) (*model.Organization, error)
+	ListUserOrganizations(ctx context.Context, userID string) ([]model.Organization, error)
+}
+
+type organizationRepo struct {
+	db *sql.DB
+}
+
+func NewOrganizationRepo(db *sql.DB) OrganizationRepo {
+	return &organizationRepo{
+		db: db,
+	}
+}
+
+const CreateOrganizationQuery = `
+INSERT INTO organizations (name)
+VALUES ($1)
+RETURNING id, name, created_at
+`
+
+// CreateOrganization stores an organization with its first member in a
+// single transaction, so that no organization is left without one. The
+// organization ID of owner is ignored.
+func (r *organizationRepo) CreateOrganization(ctx context.Context, params model.Organization, owner model.Membership) (*model.Organization, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck // a no-op after Commit
+
+	var org model.Organization
+	if err := tx.QueryRowContext(ctx, CreateOrganizationQuery, params.Name).
+		Scan(&org.ID, &org.Name, &org.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, AddMemberQuery, org.ID, owner.UserID, owner.Role); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &org, nil
+}
+
+const FindMemberOrganizationQuery = `
+SELECT o.id, o.name, o.created_at
+FROM organizations o
+JOIN memberships m ON m.organization_id = o.id
+WHERE o.id = $1 AND m.user_id = $2
+`
+
+// FindMemberOrganization returns the organization only if the user is one of
+// its members, and sql.ErrNoRows otherwise.
+func (r *organizationRepo) FindMemberOrganization(ctx context.Context, id, userID string) (*model.Organization, error) {
+	var org model.Organization
+	if err := r.db.QueryRowContext(ctx, FindMemberOrganizationQuery, id, userID).
+		Scan(&org.ID, &org.Name, &org.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &org, nil
+}
+
+const ListUserOrganizationsQuery = `
+SELECT o.id, o.name, o.created_at
+FROM organizations o
+JOIN memberships m ON m.organization_id = o.id
+WHERE m.user_id = $1
+ORDER BY o.name, o.id
+`
+
+func (r *organizationRepo) ListUserOrganizations(ctx context.Context, userID string) ([]model.Organization, error) {
+	rows, err := r.db.QueryContext(ctx, ListUserOrganizationsQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orgs := []model.Organization{}
+	for rows.Next() {
+		var org model.Organization
+		if err := rows.Scan(&org.ID, &org.Name, &org.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		orgs = append(orgs, org)
+	}
+
+	return orgs, rows.Err()
+}
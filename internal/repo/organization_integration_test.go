@@ -0,0 +1,126 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganizationRepo_Integration_TenantScoping(t *testing.T) {
+	conn := newTestDB(t)
+	users := repo.NewUserRepo(conn)
+	owner := createTestUser(t, users, testEmail)
+	other := createTestUser(t, users, "other@example.com")
+	orgs := repo.NewOrganizationRepo(conn)
+	members := repo.NewMembershipRepo(conn)
+	roles := repo.NewRoleRepo(conn)
+	ctx := context.Background()
+
+	acme, err := orgs.CreateOrganization(ctx, model.Organization{Name: "Acme"},
+		model.Membership{UserID: owner.ID, Role: "owner"})
+	assert.NoError(t, err, "create organization should not return an error")
+
+	globex, err := orgs.CreateOrganization(ctx, model.Organization{Name: "Globex"},
+		model.Membership{UserID: other.ID, Role: "owner"})
+	assert.NoError(t, err, "create organization should not return an error")
+
+	_, err = orgs.FindMemberOrganization(ctx, globex.ID, owner.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "organizations should only be found by their members")
+
+	err = members.AddMember(ctx, model.Membership{OrganizationID: acme.ID, UserID: other.ID, Role: "member"})
+	assert.NoError(t, err, "add member should not return an error")
+
+	err = members.AddMember(ctx, model.Membership{OrganizationID: acme.ID, UserID: other.ID, Role: "owner"})
+	assert.NoError(t, err, "adding a member again should not return an error")
+
+	list, err := orgs.ListUserOrganizations(ctx, other.ID)
+	assert.NoError(t, err, "list user organizations should not return an error")
+	assert.Len(t, list, 2, "the user should belong to both organizations")
+
+	acmeMembers, err := members.ListMembers(ctx, acme.ID)
+	assert.NoError(t, err, "list members should not return an error")
+	assert.Len(t, acmeMembers, 2, "only the organization's members should be listed")
+
+	member, err := members.FindMember(ctx, acme.ID, other.ID)
+	assert.NoError(t, err, "find member should not return an error")
+	assert.Equal(t, "member", member.Role, "adding a member again should keep their role")
+
+	perms, err := roles.ListMemberPermissions(ctx, acme.ID, other.ID)
+	assert.NoError(t, err, "list member permissions should not return an error")
+	assert.Contains(t, perms, model.Permission{Action: "read", Resource: "members"}, "permissions should include the role's")
+	assert.NotContains(t, perms, model.Permission{Action: "*", Resource: "members"},
+		"permissions should not include another organization's role")
+
+	owners, err := members.CountMembersWithRole(ctx, acme.ID, "owner")
+	assert.NoError(t, err, "count members with role should not return an error")
+	assert.Equal(t, 1, owners, "count must match")
+
+	err = members.UpdateMemberRole(ctx, globex.ID, owner.ID, "member")
+	assert.ErrorIs(t, err, sql.ErrNoRows, "members of other organizations should not be updated")
+
+	err = members.UpdateMemberRole(ctx, globex.ID, other.ID, "admin")
+	assert.NoError(t, err, "update member role should not return an error")
+
+	perms, err = roles.ListMemberPermissions(ctx, globex.ID, other.ID)
+	assert.NoError(t, err, "list member permissions should not return an error")
+	assert.Empty(t, perms, "global roles should grant members nothing")
+
+	err = members.RemoveMember(ctx, acme.ID, other.ID)
+	assert.NoError(t, err, "remove member should not return an error")
+
+	perms, err = roles.ListMemberPermissions(ctx, acme.ID, other.ID)
+	assert.NoError(t, err, "list member permissions should not return an error")
+	assert.Empty(t, perms, "removed members should have no permissions")
+}
+
+func TestInvitationRepo_Integration_AcceptOnce(t *testing.T) {
+	conn := newTestDB(t)
+	users := repo.NewUserRepo(conn)
+	owner := createTestUser(t, users, testEmail)
+	org, err := repo.NewOrganizationRepo(conn).CreateOrganization(context.Background(),
+		model.Organization{Name: "Acme"}, model.Membership{UserID: owner.ID, Role: "owner"})
+	assert.NoError(t, err, "create organization should not return an error")
+
+	invitations := repo.NewInvitationRepo(conn)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	_, err = invitations.CreateInvitation(ctx, model.Invitation{
+		ID:             testInvitationID,
+		OrganizationID: org.ID,
+		Email:          "invitee@example.com",
+		Role:           "member",
+		ExpiresAt:      time.Now().UTC().Add(24 * time.Hour),
+	})
+	assert.NoError(t, err, "create invitation should not return an error")
+
+	found, err := invitations.FindInvitation(ctx, testInvitationID)
+	assert.NoError(t, err, "find invitation should not return an error")
+	assert.Equal(t, org.ID, found.OrganizationID, "organization ID must match")
+
+	_, err = invitations.AcceptInvitation(ctx, testInvitationID, model.User{Email: testEmail, PasswordHash: "hashed"}, now)
+	assert.ErrorIs(t, err, repo.ErrDuplicate, "taken emails should be rejected")
+
+	userID, err := invitations.AcceptInvitation(ctx, testInvitationID,
+		model.User{Email: "invitee@example.com", PasswordHash: "hashed"}, now)
+	assert.NoError(t, err, "accepting again should not return an error")
+
+	invitee, err := users.FindUserByID(ctx, userID)
+	assert.NoError(t, err, "find user by id should not return an error")
+	assert.NotNil(t, invitee.EmailVerifiedAt, "the email of created users should be verified")
+
+	member, err := repo.NewMembershipRepo(conn).FindMember(ctx, org.ID, userID)
+	assert.NoError(t, err, "find member should not return an error")
+	assert.Equal(t, "member", member.Role, "the member should have the invited role")
+
+	_, err = invitations.AcceptInvitation(ctx, testInvitationID, model.User{ID: owner.ID}, now)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "invitations should only be accepted once")
+
+	err = invitations.DeleteInvitations(ctx, org.ID, "invitee@example.com")
+	assert.NoError(t, err, "delete invitations should not return an error")
+}
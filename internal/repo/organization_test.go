@@ -0,0 +1,166 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testOrgID        = "6f1c2b9e-4d5a-4e7b-9c3d-2a1b0c9d8e7f"
+	testInvitationID = "invitationhash"
+)
+
+var organizationCols = []string{"id", "name", "created_at"}
+
+func TestOrganizationRepo_CreateOrganization_Success(t *testing.T) {
+	mock, orgs := setupMockOrganizationRepo(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(repo.CreateOrganizationQuery).
+		WithArgs("Acme").
+		WillReturnRows(sqlmock.NewRows(organizationCols).AddRow(testOrgID, "Acme", time.Now()))
+	mock.ExpectExec(repo.AddMemberQuery).
+		WithArgs(testOrgID, testID, "owner").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	org, err := orgs.CreateOrganization(context.Background(), model.Organization{Name: "Acme"},
+		model.Membership{UserID: testID, Role: "owner"})
+
+	assert.NoError(t, err, "create organization should not return an error")
+	assert.Equal(t, testOrgID, org.ID, "organization ID must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestOrganizationRepo_CreateOrganization_MemberFails(t *testing.T) {
+	mock, orgs := setupMockOrganizationRepo(t)
+	dbErr := errors.New("connection lost")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(repo.CreateOrganizationQuery).
+		WithArgs("Acme").
+		WillReturnRows(sqlmock.NewRows(organizationCols).AddRow(testOrgID, "Acme", time.Now()))
+	mock.ExpectExec(repo.AddMemberQuery).
+		WithArgs(testOrgID, testID, "owner").
+		WillReturnError(dbErr)
+	mock.ExpectRollback()
+
+	_, err := orgs.CreateOrganization(context.Background(), model.Organization{Name: "Acme"},
+		model.Membership{UserID: testID, Role: "owner"})
+
+	assert.ErrorIs(t, err, dbErr, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "the organization should be rolled back")
+}
+
+func TestOrganizationRepo_FindMemberOrganization_NotMember(t *testing.T) {
+	mock, orgs := setupMockOrganizationRepo(t)
+	mock.ExpectQuery(repo.FindMemberOrganizationQuery).
+		WithArgs(testOrgID, testID).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := orgs.FindMemberOrganization(context.Background(), testOrgID, testID)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestMembershipRepo_UpdateMemberRole_NotMember(t *testing.T) {
+	mock, members := setupMockMembershipRepo(t)
+	mock.ExpectExec(repo.UpdateMemberRoleQuery).
+		WithArgs(testOrgID, testID, testRole).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := members.UpdateMemberRole(context.Background(), testOrgID, testID, testRole)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestMembershipRepo_CountMembersWithRole(t *testing.T) {
+	mock, members := setupMockMembershipRepo(t)
+	mock.ExpectQuery(repo.CountMembersWithRoleQuery).
+		WithArgs(testOrgID, "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	count, err := members.CountMembersWithRole(context.Background(), testOrgID, "owner")
+
+	assert.NoError(t, err, "count members with role should not return an error")
+	assert.Equal(t, 2, count, "count must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestInvitationRepo_AcceptInvitation_NotFound(t *testing.T) {
+	mock, invitations := setupMockInvitationRepo(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(repo.ConsumeInvitationQuery).
+		WithArgs(testInvitationID).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err := invitations.AcceptInvitation(context.Background(), testInvitationID, model.User{ID: testID}, time.Now())
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestInvitationRepo_AcceptInvitation_MemberFails(t *testing.T) {
+	mock, invitations := setupMockInvitationRepo(t)
+	dbErr := errors.New("connection lost")
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(repo.ConsumeInvitationQuery).
+		WithArgs(testInvitationID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "organization_id", "email", "role", "expires_at", "created_at"}).
+			AddRow(testInvitationID, testOrgID, testEmail, "member", now, now))
+	mock.ExpectExec(repo.AddMemberQuery).
+		WithArgs(testOrgID, testID, "member").
+		WillReturnError(dbErr)
+	mock.ExpectRollback()
+
+	_, err := invitations.AcceptInvitation(context.Background(), testInvitationID, model.User{ID: testID}, now)
+
+	assert.ErrorIs(t, err, dbErr, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "the invitation should not be consumed")
+}
+
+func setupMockOrganizationRepo(t *testing.T) (sqlmock.Sqlmock, repo.OrganizationRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	orgs := repo.NewOrganizationRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, orgs
+}
+
+func setupMockMembershipRepo(t *testing.T) (sqlmock.Sqlmock, repo.MembershipRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	members := repo.NewMembershipRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, members
+}
+
+func setupMockInvitationRepo(t *testing.T) (sqlmock.Sqlmock, repo.InvitationRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	invitations := repo.NewInvitationRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, invitations
+}
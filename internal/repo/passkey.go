@@ -0,0 +1,152 @@
+//go:generate mockgen -destination=mocks/passkey_repo_mock.go -package=mocks . PasskeyRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/db"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type PasskeyRepo interface {
+	CreatePasskey(ctx context.Context, params model.Passkey) (*model.Passkey, error)
+	FindPasskey(ctx context.Context, id string) (*model.Passkey, error)
+	ListUserPasskeys(ctx context.Context, userID string) ([]model.Passkey, error)
+	UpdatePasskeyUse(ctx context.Context, id string, signCount uint32, backupState bool, usedAt time.Time) error
+	DeletePasskey(ctx context.Context, userID, id string) error
+}
+
+type passkeyRepo struct {
+	db *sql.DB
+}
+
+func NewPasskeyRepo(db *sql.DB) PasskeyRepo {
+	return &passkeyRepo{
+		db: db,
+	}
+}
+
+const CreatePasskeyQuery = `
+INSERT INTO passkeys (id, user_id, name, public_key, sign_count, aaguid, attestation_format, transports,
+backup_eligible, backup_state)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, user_id, name, public_key, sign_count, aaguid, attestation_format, transports,
+backup_eligible, backup_state, last_used_at, created_at
+`
+
+// CreatePasskey stores a registered credential. It returns ErrDuplicate if
+// the credential is already registered, to this or another user.
+func (r *passkeyRepo) CreatePasskey(ctx context.Context, params model.Passkey) (*model.Passkey, error) {
+	row := r.db.QueryRowContext(ctx, CreatePasskeyQuery, params.ID, params.UserID, params.Name, params.PublicKey,
+		params.SignCount, params.AAGUID, params.AttestationFormat, strings.Join(params.Transports, ","),
+		params.BackupEligible, params.BackupState)
+
+	passkey, err := scanPasskey(row)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return nil, ErrDuplicate
+		}
+
+		return nil, err
+	}
+
+	return passkey, nil
+}
+
+const FindPasskeyQuery = `
+SELECT id, user_id, name, public_key, sign_count, aaguid, attestation_format, transports,
+backup_eligible, backup_state, last_used_at, created_at
+FROM passkeys
+WHERE id = $1
+`
+
+func (r *passkeyRepo) FindPasskey(ctx context.Context, id string) (*model.Passkey, error) {
+	return scanPasskey(r.db.QueryRowContext(ctx, FindPasskeyQuery, id))
+}
+
+const ListUserPasskeysQuery = `
+SELECT id, user_id, name, public_key, sign_count, aaguid, attestation_format, transports,
+backup_eligible, backup_state, last_used_at, created_at
+FROM passkeys
+WHERE user_id = $1
+ORDER BY created_at, id
+`
+
+func (r *passkeyRepo) ListUserPasskeys(ctx context.Context, userID string) ([]model.Passkey, error) {
+	rows, err := r.db.QueryContext(ctx, ListUserPasskeysQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	passkeys := []model.Passkey{}
+	for rows.Next() {
+		passkey, err := scanPasskey(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		passkeys = append(passkeys, *passkey)
+	}
+
+	return passkeys, rows.Err()
+}
+
+const UpdatePasskeyUseQuery = `
+UPDATE passkeys
+SET sign_count = $2, backup_state = $3, last_used_at = $4
+WHERE id = $1 AND (sign_count < $2 OR sign_count = 0)
+`
+
+// UpdatePasskeyUse records a sign-in with the credential. It returns
+// sql.ErrNoRows if a concurrent sign-in already stored the same or a later
+// signature counter.
+func (r *passkeyRepo) UpdatePasskeyUse(ctx context.Context, id string, signCount uint32, backupState bool,
+	usedAt time.Time) error {
+	res, err := r.db.ExecContext(ctx, UpdatePasskeyUseQuery, id, signCount, backupState, usedAt)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(res)
+}
+
+const DeletePasskeyQuery = `
+DELETE FROM passkeys
+WHERE user_id = $1 AND id = $2
+`
+
+// DeletePasskey removes one of the user's credentials. It returns
+// sql.ErrNoRows if the user has no such credential.
+func (r *passkeyRepo) DeletePasskey(ctx context.Context, userID, id string) error {
+	res, err := r.db.ExecContext(ctx, DeletePasskeyQuery, userID, id)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(res)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPasskey(row rowScanner) (*model.Passkey, error) {
+	var passkey model.Passkey
+	var transports string
+	if err := row.Scan(&passkey.ID, &passkey.UserID, &passkey.Name, &passkey.PublicKey, &passkey.SignCount,
+		&passkey.AAGUID, &passkey.AttestationFormat, &transports, &passkey.BackupEligible, &passkey.BackupState,
+		&passkey.LastUsedAt, &passkey.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	passkey.Transports = []string{}
+	if transports != "" {
+		passkey.Transports = strings.Split(transports, ",")
+	}
+
+	return &passkey, nil
+}
@@ -0,0 +1,52 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasskeyRepo_Integration_Lifecycle(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	passkeys := repo.NewPasskeyRepo(conn)
+	ctx := context.Background()
+
+	params := newTestPasskey()
+	params.UserID = user.ID
+	created, err := passkeys.CreatePasskey(ctx, params)
+	assert.NoError(t, err, "create passkey should not return an error")
+	assert.Equal(t, params.PublicKey, created.PublicKey, "public key must match")
+	assert.True(t, created.BackupEligible, "backup eligibility must match")
+
+	_, err = passkeys.CreatePasskey(ctx, params)
+	assert.ErrorIs(t, err, repo.ErrDuplicate, "credentials should only be registered once")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	assert.NoError(t, passkeys.UpdatePasskeyUse(ctx, params.ID, 5, false, now),
+		"update passkey use should not return an error")
+	assert.ErrorIs(t, passkeys.UpdatePasskeyUse(ctx, params.ID, 5, false, now), sql.ErrNoRows,
+		"a signature counter should not be accepted twice")
+
+	found, err := passkeys.FindPasskey(ctx, params.ID)
+	assert.NoError(t, err, "find passkey should not return an error")
+	assert.Equal(t, uint32(5), found.SignCount, "sign count must match")
+	assert.False(t, found.BackupState, "backup state must match")
+	assert.NotNil(t, found.LastUsedAt, "last use should be recorded")
+
+	list, err := passkeys.ListUserPasskeys(ctx, user.ID)
+	assert.NoError(t, err, "list passkeys should not return an error")
+	assert.Len(t, list, 1, "list length must match")
+
+	other := createTestUser(t, repo.NewUserRepo(conn), "other@example.com")
+	assert.ErrorIs(t, passkeys.DeletePasskey(ctx, other.ID, params.ID), sql.ErrNoRows,
+		"users should not delete passkeys of others")
+	assert.NoError(t, passkeys.DeletePasskey(ctx, user.ID, params.ID), "delete passkey should not return an error")
+
+	_, err = passkeys.FindPasskey(ctx, params.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "deleted passkeys should not be found")
+}
@@ -0,0 +1,102 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPasskeyID = "credentialid"
+
+var passkeyCols = []string{
+	"id", "user_id", "name", "public_key", "sign_count", "aaguid", "attestation_format", "transports",
+	"backup_eligible", "backup_state", "last_used_at", "created_at",
+}
+
+func newTestPasskey() model.Passkey {
+	return model.Passkey{
+		ID:                testPasskeyID,
+		UserID:            testID,
+		Name:              "Laptop",
+		PublicKey:         []byte("cose"),
+		AAGUID:            make([]byte, 16),
+		AttestationFormat: "none",
+		Transports:        []string{"internal", "hybrid"},
+		BackupEligible:    true,
+		BackupState:       true,
+	}
+}
+
+func TestPasskeyRepo_CreatePasskey_Success(t *testing.T) {
+	mock, passkeys := setupMockPasskeyRepo(t)
+	params := newTestPasskey()
+	now := time.Now().UTC()
+
+	mock.ExpectQuery(repo.CreatePasskeyQuery).
+		WithArgs(params.ID, params.UserID, params.Name, params.PublicKey, params.SignCount, params.AAGUID,
+			params.AttestationFormat, "internal,hybrid", true, true).
+		WillReturnRows(sqlmock.NewRows(passkeyCols).AddRow(params.ID, params.UserID, params.Name, params.PublicKey,
+			0, params.AAGUID, params.AttestationFormat, "internal,hybrid", true, true, nil, now))
+
+	passkey, err := passkeys.CreatePasskey(context.Background(), params)
+
+	assert.NoError(t, err, "create passkey should not return an error")
+	assert.Equal(t, params.Transports, passkey.Transports, "transports must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestPasskeyRepo_ListUserPasskeys_Empty(t *testing.T) {
+	mock, passkeys := setupMockPasskeyRepo(t)
+	mock.ExpectQuery(repo.ListUserPasskeysQuery).
+		WithArgs(testID).
+		WillReturnRows(sqlmock.NewRows(passkeyCols))
+
+	list, err := passkeys.ListUserPasskeys(context.Background(), testID)
+
+	assert.NoError(t, err, "list passkeys should not return an error")
+	assert.NotNil(t, list, "an empty list should not be nil")
+	assert.Empty(t, list, "list should be empty")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestPasskeyRepo_UpdatePasskeyUse_Stale(t *testing.T) {
+	mock, passkeys := setupMockPasskeyRepo(t)
+	now := time.Now().UTC()
+	mock.ExpectExec(repo.UpdatePasskeyUseQuery).
+		WithArgs(testPasskeyID, uint32(3), false, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := passkeys.UpdatePasskeyUse(context.Background(), testPasskeyID, 3, false, now)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestPasskeyRepo_DeletePasskey_NotFound(t *testing.T) {
+	mock, passkeys := setupMockPasskeyRepo(t)
+	mock.ExpectExec(repo.DeletePasskeyQuery).
+		WithArgs(testID, testPasskeyID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := passkeys.DeletePasskey(context.Background(), testID, testPasskeyID)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockPasskeyRepo(t *testing.T) (sqlmock.Sqlmock, repo.PasskeyRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	passkeys := repo.NewPasskeyRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, passkeys
+}
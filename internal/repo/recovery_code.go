@@ -0,0 +1,80 @@
+//go:generate mockgen -destination=mocks/recovery_code_repo_mock.go -package=mocks . RecoveryCodeRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// RecoveryCodeRepo stores the hashes of a user's MFA recovery codes.
+type RecoveryCodeRepo interface {
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID, codeHash string, usedAt time.Time) error
+	DeleteRecoveryCodes(ctx context.Context, userID string) error
+}
+
+type recoveryCodeRepo struct {
+	db *sql.DB
+}
+
+func NewRecoveryCodeRepo(db *sql.DB) RecoveryCodeRepo {
+	return &recoveryCodeRepo{
+		db: db,
+	}
+}
+
+const DeleteRecoveryCodesQuery = `
+DELETE FROM recovery_codes
+WHERE user_id = $1
+`
+
+const CreateRecoveryCodeQuery = `
+INSERT INTO recovery_codes (user_id, code_hash)
+VALUES ($1, $2)
+`
+
+// ReplaceRecoveryCodes swaps all of the user's codes for new ones in a single
+// transaction, so that the old codes stop working exactly when the new ones
+// start.
+func (r *recoveryCodeRepo) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // a no-op after Commit
+
+	if _, err := tx.ExecContext(ctx, DeleteRecoveryCodesQuery, userID); err != nil {
+		return err
+	}
+
+	for _, hash := range codeHashes {
+		if _, err := tx.ExecContext(ctx, CreateRecoveryCodeQuery, userID, hash); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+const UseRecoveryCodeQuery = `
+UPDATE recovery_codes
+SET used_at = $3
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+`
+
+// UseRecoveryCode marks a code as used. It returns sql.ErrNoRows if the code
+// is unknown or was already used.
+func (r *recoveryCodeRepo) UseRecoveryCode(ctx context.Context, userID, codeHash string, usedAt time.Time) error {
+	res, err := r.db.ExecContext(ctx, UseRecoveryCodeQuery, userID, codeHash, usedAt)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(res)
+}
+
+func (r *recoveryCodeRepo) DeleteRecoveryCodes(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, DeleteRecoveryCodesQuery, userID)
+	return err
+}
@@ -0,0 +1,45 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoveryCodeRepo_Integration_UseOnce(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	codes := repo.NewRecoveryCodeRepo(conn)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	err := codes.ReplaceRecoveryCodes(ctx, user.ID, []string{"a", "b"})
+	assert.NoError(t, err, "replace recovery codes should not return an error")
+
+	assert.NoError(t, codes.UseRecoveryCode(ctx, user.ID, "a", now), "use recovery code should not return an error")
+	assert.ErrorIs(t, codes.UseRecoveryCode(ctx, user.ID, "a", now), sql.ErrNoRows, "codes should only be used once")
+	assert.ErrorIs(t, codes.UseRecoveryCode(ctx, user.ID, "c", now), sql.ErrNoRows, "unknown codes should be rejected")
+
+	err = codes.ReplaceRecoveryCodes(ctx, user.ID, []string{"c"})
+	assert.NoError(t, err, "replace recovery codes should not return an error")
+
+	assert.ErrorIs(t, codes.UseRecoveryCode(ctx, user.ID, "b", now), sql.ErrNoRows, "replaced codes should be rejected")
+	assert.NoError(t, codes.UseRecoveryCode(ctx, user.ID, "c", now), "new codes should be accepted")
+}
+
+func TestRecoveryCodeRepo_Integration_DeleteRecoveryCodes(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	codes := repo.NewRecoveryCodeRepo(conn)
+	ctx := context.Background()
+
+	assert.NoError(t, codes.ReplaceRecoveryCodes(ctx, user.ID, []string{"a"}), "replace recovery codes should not return an error")
+	assert.NoError(t, codes.DeleteRecoveryCodes(ctx, user.ID), "delete recovery codes should not return an error")
+
+	err := codes.UseRecoveryCode(ctx, user.ID, "a", time.Now().UTC())
+	assert.ErrorIs(t, err, sql.ErrNoRows, "deleted codes should be rejected")
+}
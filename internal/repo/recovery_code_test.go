@@ -0,0 +1,65 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoveryCodeRepo_ReplaceRecoveryCodes_Success(t *testing.T) {
+	mock, codes := setupMockRecoveryCodeRepo(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(repo.DeleteRecoveryCodesQuery).WithArgs(testID).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(repo.CreateRecoveryCodeQuery).WithArgs(testID, "a").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(repo.CreateRecoveryCodeQuery).WithArgs(testID, "b").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := codes.ReplaceRecoveryCodes(context.Background(), testID, []string{"a", "b"})
+
+	assert.NoError(t, err, "replace recovery codes should not return an error")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestRecoveryCodeRepo_ReplaceRecoveryCodes_RollsBack(t *testing.T) {
+	mock, codes := setupMockRecoveryCodeRepo(t)
+	insertErr := errors.New("insert failed")
+	mock.ExpectBegin()
+	mock.ExpectExec(repo.DeleteRecoveryCodesQuery).WithArgs(testID).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(repo.CreateRecoveryCodeQuery).WithArgs(testID, "a").WillReturnError(insertErr)
+	mock.ExpectRollback()
+
+	err := codes.ReplaceRecoveryCodes(context.Background(), testID, []string{"a", "b"})
+
+	assert.ErrorIs(t, err, insertErr, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestRecoveryCodeRepo_UseRecoveryCode_Used(t *testing.T) {
+	mock, codes := setupMockRecoveryCodeRepo(t)
+	now := time.Now().UTC()
+	mock.ExpectExec(repo.UseRecoveryCodeQuery).
+		WithArgs(testID, "a", now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := codes.UseRecoveryCode(context.Background(), testID, "a", now)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockRecoveryCodeRepo(t *testing.T) (sqlmock.Sqlmock, repo.RecoveryCodeRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	codes := repo.NewRecoveryCodeRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, codes
+}
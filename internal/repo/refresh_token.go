@@ -0,0 +1,105 @@
+//go:generate mockgen -destination=mocks/refresh_token_repo_mock.go -package=mocks . RefreshTokenRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type RefreshTokenRepo interface {
+	CreateRefreshToken(ctx context.Context, params model.RefreshToken) (*model.RefreshToken, error)
+	FindRefreshToken(ctx context.Context, id string) (*model.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, id string, revokedAt time.Time) (bool, error)
+	RevokeTokenFamily(ctx context.Context, familyID string, revokedAt time.Time) error
+	RevokeUserRefreshTokens(ctx context.Context, userID string, revokedAt time.Time) error
+}
+
+type refreshTokenRepo struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenRepo(db *sql.DB) RefreshTokenRepo {
+	return &refreshTokenRepo{
+		db: db,
+	}
+}
+
+const CreateRefreshTokenQuery = `
+INSERT INTO refresh_tokens (id, user_id, family_id, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, family_id, expires_at, revoked_at, created_at
+`
+
+func (r *refreshTokenRepo) CreateRefreshToken(ctx context.Context, params model.RefreshToken) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	if err := r.db.QueryRowContext(ctx, CreateRefreshTokenQuery,
+		params.ID, params.UserID, params.FamilyID, params.ExpiresAt).
+		Scan(&token.ID, &token.UserID, &token.FamilyID, &token.ExpiresAt, &token.RevokedAt, &token.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+const FindRefreshTokenQuery = `
+SELECT id, user_id, family_id, expires_at, revoked_at, created_at
+FROM refresh_tokens
+WHERE id = $1
+`
+
+func (r *refreshTokenRepo) FindRefreshToken(ctx context.Context, id string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	if err := r.db.QueryRowContext(ctx, FindRefreshTokenQuery, id).
+		Scan(&token.ID, &token.UserID, &token.FamilyID, &token.ExpiresAt, &token.RevokedAt, &token.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+const RevokeRefreshTokenQuery = `
+UPDATE refresh_tokens
+SET revoked_at = $2
+WHERE id = $1 AND revoked_at IS NULL
+`
+
+// RevokeRefreshToken marks a token as used. It reports false if the token was
+// already revoked, which lets callers detect concurrent reuse.
+func (r *refreshTokenRepo) RevokeRefreshToken(ctx context.Context, id string, revokedAt time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, RevokeRefreshTokenQuery, id, revokedAt)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+const RevokeTokenFamilyQuery = `
+UPDATE refresh_tokens
+SET revoked_at = $2
+WHERE family_id = $1 AND revoked_at IS NULL
+`
+
+func (r *refreshTokenRepo) RevokeTokenFamily(ctx context.Context, familyID string, revokedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, RevokeTokenFamilyQuery, familyID, revokedAt)
+	return err
+}
+
+const RevokeUserRefreshTokensQuery = `
+UPDATE refresh_tokens
+SET revoked_at = $2
+WHERE user_id = $1 AND revoked_at IS NULL
+`
+
+func (r *refreshTokenRepo) RevokeUserRefreshTokens(ctx context.Context, userID string, revokedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, RevokeUserRefreshTokensQuery, userID, revokedAt)
+	return err
+}
@@ -0,0 +1,76 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTokenRepo_Integration_Rotation(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	tokens := repo.NewRefreshTokenRepo(conn)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	created, err := tokens.CreateRefreshToken(ctx, model.RefreshToken{
+		ID:        testRefreshTokenID,
+		UserID:    user.ID,
+		FamilyID:  testFamilyID,
+		ExpiresAt: now.Add(time.Hour),
+	})
+	assert.NoError(t, err, "create refresh token should not return an error")
+	assert.Nil(t, created.RevokedAt, "new token should not be revoked")
+
+	revoked, err := tokens.RevokeRefreshToken(ctx, testRefreshTokenID, now)
+	assert.NoError(t, err, "revoke should not return an error")
+	assert.True(t, revoked, "active token should be revoked")
+
+	revoked, err = tokens.RevokeRefreshToken(ctx, testRefreshTokenID, now)
+	assert.NoError(t, err, "revoke should not return an error")
+	assert.False(t, revoked, "revoked token should not be revoked twice")
+
+	found, err := tokens.FindRefreshToken(ctx, testRefreshTokenID)
+	assert.NoError(t, err, "find refresh token should not return an error")
+	assert.NotNil(t, found.RevokedAt, "revoked_at should be set")
+}
+
+func TestRefreshTokenRepo_Integration_RevokeTokenFamily(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	tokens := repo.NewRefreshTokenRepo(conn)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	for _, tok := range []model.RefreshToken{
+		{ID: "a", UserID: user.ID, FamilyID: testFamilyID, ExpiresAt: now.Add(time.Hour)},
+		{ID: "b", UserID: user.ID, FamilyID: testFamilyID, ExpiresAt: now.Add(time.Hour)},
+		{ID: "c", UserID: user.ID, FamilyID: "other", ExpiresAt: now.Add(time.Hour)},
+	} {
+		if _, err := tokens.CreateRefreshToken(ctx, tok); err != nil {
+			t.Fatalf("create refresh token: %v", err)
+		}
+	}
+
+	assert.NoError(t, tokens.RevokeTokenFamily(ctx, testFamilyID, now), "revoke family should not return an error")
+
+	for _, id := range []string{"a", "b"} {
+		found, err := tokens.FindRefreshToken(ctx, id)
+		assert.NoError(t, err, "find refresh token should not return an error")
+		assert.NotNil(t, found.RevokedAt, "tokens in the family should be revoked")
+	}
+
+	found, err := tokens.FindRefreshToken(ctx, "c")
+	assert.NoError(t, err, "find refresh token should not return an error")
+	assert.Nil(t, found.RevokedAt, "tokens in other families should be kept")
+
+	assert.NoError(t, tokens.RevokeUserRefreshTokens(ctx, user.ID, now), "revoke user tokens should not return an error")
+
+	found, err = tokens.FindRefreshToken(ctx, "c")
+	assert.NoError(t, err, "find refresh token should not return an error")
+	assert.NotNil(t, found.RevokedAt, "all tokens of the user should be revoked")
+}
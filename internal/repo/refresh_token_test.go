@@ -0,0 +1,122 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testRefreshTokenID = "refreshhash"
+	testFamilyID       = "family"
+)
+
+var refreshTokenCols = []string{"id", "user_id", "family_id", "expires_at", "revoked_at", "created_at"}
+
+func TestRefreshTokenRepo_CreateRefreshToken_Success(t *testing.T) {
+	mock, tokens := setupMockRefreshTokenRepo(t)
+	now := time.Now().UTC()
+	params := model.RefreshToken{
+		ID:        testRefreshTokenID,
+		UserID:    testID,
+		FamilyID:  testFamilyID,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	mock.ExpectQuery(repo.CreateRefreshTokenQuery).
+		WithArgs(params.ID, params.UserID, params.FamilyID, params.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows(refreshTokenCols).
+			AddRow(params.ID, params.UserID, params.FamilyID, params.ExpiresAt, nil, now))
+
+	token, err := tokens.CreateRefreshToken(context.Background(), params)
+
+	assert.NoError(t, err, "create refresh token should not return an error")
+	assert.Equal(t, testFamilyID, token.FamilyID, "family ID must match")
+	assert.Nil(t, token.RevokedAt, "new token should not be revoked")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestRefreshTokenRepo_FindRefreshToken_Revoked(t *testing.T) {
+	mock, tokens := setupMockRefreshTokenRepo(t)
+	now := time.Now().UTC()
+
+	mock.ExpectQuery(repo.FindRefreshTokenQuery).
+		WithArgs(testRefreshTokenID).
+		WillReturnRows(sqlmock.NewRows(refreshTokenCols).
+			AddRow(testRefreshTokenID, testID, testFamilyID, now.Add(time.Hour), now, now))
+
+	token, err := tokens.FindRefreshToken(context.Background(), testRefreshTokenID)
+
+	assert.NoError(t, err, "find refresh token should not return an error")
+	if assert.NotNil(t, token.RevokedAt, "revoked_at should be loaded") {
+		assert.Equal(t, now, *token.RevokedAt, "revoked_at must match")
+	}
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestRefreshTokenRepo_FindRefreshToken_NotFound(t *testing.T) {
+	mock, tokens := setupMockRefreshTokenRepo(t)
+	mock.ExpectQuery(repo.FindRefreshTokenQuery).WithArgs(testRefreshTokenID).WillReturnError(sql.ErrNoRows)
+
+	_, err := tokens.FindRefreshToken(context.Background(), testRefreshTokenID)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestRefreshTokenRepo_RevokeRefreshToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		want     bool
+	}{
+		{"active token should be revoked", 1, true},
+		{"already revoked token should be reported", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, tokens := setupMockRefreshTokenRepo(t)
+			now := time.Now().UTC()
+			mock.ExpectExec(repo.RevokeRefreshTokenQuery).
+				WithArgs(testRefreshTokenID, now).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			revoked, err := tokens.RevokeRefreshToken(context.Background(), testRefreshTokenID, now)
+
+			assert.NoError(t, err, "revoke should not return an error")
+			assert.Equal(t, tt.want, revoked, "revoked should match")
+			assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+		})
+	}
+}
+
+func TestRefreshTokenRepo_RevokeTokenFamily(t *testing.T) {
+	mock, tokens := setupMockRefreshTokenRepo(t)
+	now := time.Now().UTC()
+	mock.ExpectExec(repo.RevokeTokenFamilyQuery).
+		WithArgs(testFamilyID, now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err := tokens.RevokeTokenFamily(context.Background(), testFamilyID, now)
+
+	assert.NoError(t, err, "revoke family should not return an error")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockRefreshTokenRepo(t *testing.T) (sqlmock.Sqlmock, repo.RefreshTokenRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	tokens := repo.NewRefreshTokenRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, tokens
+}
@@ -1,7 +1,23 @@
 package repo
 
 import (
+	"database/sql"
 	"errors"
 )
 
 var ErrNullValue = errors.New("not null constraint violation")
+var ErrDuplicate = errors.New("unique constraint violation")
+
+// requireAffected returns sql.ErrNoRows when a statement matched no rows.
+func requireAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,271 @@
+//go:generate mockgen -destination=mocks/role_repo_mock.go -package=mocks . RoleRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ferdiebergado/fullstackgo/internal/db"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type RoleRepo interface {
+	CreateRole(ctx context.Context, params model.Role) (*model.Role, error)
+	FindRole(ctx context.Context, name string) (*model.Role, error)
+	ListRoles(ctx context.Context) ([]model.Role, error)
+	AssignRole(ctx context.Context, userID, role string) error
+	UnassignRole(ctx context.Context, userID, role string) error
+	ListUserRoles(ctx context.Context, userID string) ([]string, error)
+	ListUserPermissions(ctx context.Context, userID string) ([]model.Permission, error)
+	ListMemberPermissions(ctx context.Context, organizationID, userID string) ([]model.Permission, error)
+}
+
+type roleRepo struct {
+	db *sql.DB
+}
+
+func NewRoleRepo(db *sql.DB) RoleRepo {
+	return &roleRepo{
+		db: db,
+	}
+}
+
+const CreateRoleQuery = `
+INSERT INTO roles (name, description, scope)
+VALUES ($1, $2, $3)
+RETURNING name, description, scope, created_at
+`
+
+const CreatePermissionQuery = `
+INSERT INTO permissions (role, action, resource)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING
+`
+
+// CreateRole stores a role with its permissions in a single transaction. It
+// returns ErrDuplicate if a role with the name exists.
+func (r *roleRepo) CreateRole(ctx context.Context, params model.Role) (*model.Role, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck // a no-op after Commit
+
+	var role model.Role
+	if err := tx.QueryRowContext(ctx, CreateRoleQuery, params.Name, params.Description, params.Scope).
+		Scan(&role.Name, &role.Description, &role.Scope, &role.CreatedAt); err != nil {
+		if db.IsUniqueViolation(err) {
+			return nil, ErrDuplicate
+		}
+
+		return nil, err
+	}
+
+	for _, perm := range params.Permissions {
+		if _, err := tx.ExecContext(ctx, CreatePermissionQuery, role.Name, perm.Action, perm.Resource); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	role.Permissions = append([]model.Permission{}, params.Permissions...)
+
+	return &role, nil
+}
+
+const FindRoleQuery = `
+SELECT name, description, scope, created_at
+FROM roles
+WHERE name = $1
+`
+
+const ListRolePermissionsQuery = `
+SELECT action, resource
+FROM permissions
+WHERE role = $1
+ORDER BY resource, action
+`
+
+func (r *roleRepo) FindRole(ctx context.Context, name string) (*model.Role, error) {
+	var role model.Role
+	if err := r.db.QueryRowContext(ctx, FindRoleQuery, name).
+		Scan(&role.Name, &role.Description, &role.Scope, &role.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, ListRolePermissionsQuery, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if role.Permissions, err = scanPermissions(rows); err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+const ListRolesQuery = `
+SELECT name, description, scope, created_at
+FROM roles
+ORDER BY name
+`
+
+const ListPermissionsQuery = `
+SELECT role, action, resource
+FROM permissions
+ORDER BY role, resource, action
+`
+
+func (r *roleRepo) ListRoles(ctx context.Context) ([]model.Role, error) {
+	rows, err := r.db.QueryContext(ctx, ListRolesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []model.Role{}
+	index := make(map[string]int)
+	for rows.Next() {
+		role := model.Role{Permissions: []model.Permission{}}
+		if err := rows.Scan(&role.Name, &role.Description, &role.Scope, &role.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		index[role.Name] = len(roles)
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	permRows, err := r.db.QueryContext(ctx, ListPermissionsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer permRows.Close()
+
+	for permRows.Next() {
+		var name string
+		var perm model.Permission
+		if err := permRows.Scan(&name, &perm.Action, &perm.Resource); err != nil {
+			return nil, err
+		}
+
+		if i, ok := index[name]; ok {
+			roles[i].Permissions = append(roles[i].Permissions, perm)
+		}
+	}
+
+	return roles, permRows.Err()
+}
+
+const AssignRoleQuery = `
+INSERT INTO user_roles (user_id, role)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+// AssignRole gives the user the role. Assigning a role the user already has
+// is not an error.
+func (r *roleRepo) AssignRole(ctx context.Context, userID, role string) error {
+	_, err := r.db.ExecContext(ctx, AssignRoleQuery, userID, role)
+	return err
+}
+
+const UnassignRoleQuery = `
+DELETE FROM user_roles
+WHERE user_id = $1 AND role = $2
+`
+
+func (r *roleRepo) UnassignRole(ctx context.Context, userID, role string) error {
+	_, err := r.db.ExecContext(ctx, UnassignRoleQuery, userID, role)
+	return err
+}
+
+const ListUserRolesQuery = `
+SELECT role
+FROM user_roles
+WHERE user_id = $1
+ORDER BY role
+`
+
+func (r *roleRepo) ListUserRoles(ctx context.Context, userID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, ListUserRolesQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []string{}
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+const ListUserPermissionsQuery = `
+SELECT DISTINCT p.action, p.resource
+FROM permissions p
+JOIN user_roles ur ON ur.role = p.role
+JOIN roles r ON r.name = p.role
+WHERE ur.user_id = $1 AND r.scope = 'global'
+ORDER BY p.resource, p.action
+`
+
+// ListUserPermissions returns the permissions of all of the user's global
+// roles.
+func (r *roleRepo) ListUserPermissions(ctx context.Context, userID string) ([]model.Permission, error) {
+	rows, err := r.db.QueryContext(ctx, ListUserPermissionsQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanPermissions(rows)
+}
+
+const ListMemberPermissionsQuery = `
+SELECT DISTINCT p.action, p.resource
+FROM permissions p
+JOIN memberships m ON m.role = p.role
+JOIN roles r ON r.name = p.role
+WHERE m.organization_id = $1 AND m.user_id = $2 AND r.scope = 'organization'
+ORDER BY p.resource, p.action
+`
+
+// ListMemberPermissions returns the permissions of the user's role in the
+// organization, and none if the user is not a member.
+func (r *roleRepo) ListMemberPermissions(ctx context.Context, organizationID, userID string) ([]model.Permission, error) {
+	rows, err := r.db.QueryContext(ctx, ListMemberPermissionsQuery, organizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanPermissions(rows)
+}
+
+// scanPermissions reads action and resource columns and closes rows.
+func scanPermissions(rows *sql.Rows) ([]model.Permission, error) {
+	defer rows.Close()
+
+	perms := []model.Permission{}
+	for rows.Next() {
+		var perm model.Permission
+		if err := rows.Scan(&perm.Action, &perm.Resource); err != nil {
+			return nil, err
+		}
+
+		perms = append(perms, perm)
+	}
+
+	return perms, rows.Err()
+}
@@ -0,0 +1,59 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleRepo_Integration_Lifecycle(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	roles := repo.NewRoleRepo(conn)
+	ctx := context.Background()
+
+	_, err := roles.CreateRole(ctx, model.Role{
+		Name:  testRole,
+		Scope: model.RoleScopeGlobal,
+		Permissions: []model.Permission{
+			{Action: "read", Resource: "posts"},
+			{Action: "write", Resource: "posts"},
+		},
+	})
+	assert.NoError(t, err, "create role should not return an error")
+
+	_, err = roles.CreateRole(ctx, model.Role{Name: testRole, Scope: model.RoleScopeGlobal})
+	assert.ErrorIs(t, err, repo.ErrDuplicate, "duplicate roles should be rejected")
+
+	list, err := roles.ListRoles(ctx)
+	assert.NoError(t, err, "list roles should not return an error")
+	if assert.Len(t, list, 4, "the seeded admin, member and owner roles should be listed") {
+		assert.Equal(t, "admin", list[0].Name, "roles should be sorted by name")
+		assert.Equal(t, []model.Permission{{Action: "*", Resource: "*"}}, list[0].Permissions,
+			"admin should have every permission")
+		assert.Len(t, list[1].Permissions, 2, "permissions should be listed with their role")
+		assert.Equal(t, model.RoleScopeOrganization, list[3].Scope, "owner should be scoped to organizations")
+	}
+
+	for _, role := range []string{testRole, "admin", testRole, "owner"} {
+		assert.NoError(t, roles.AssignRole(ctx, user.ID, role), "assign role should not return an error")
+	}
+
+	assigned, err := roles.ListUserRoles(ctx, user.ID)
+	assert.NoError(t, err, "list user roles should not return an error")
+	assert.Equal(t, []string{"admin", testRole, "owner"}, assigned, "roles should be assigned once")
+
+	perms, err := roles.ListUserPermissions(ctx, user.ID)
+	assert.NoError(t, err, "list user permissions should not return an error")
+	assert.Len(t, perms, 3, "permissions of every global role should be listed")
+
+	assert.NoError(t, roles.UnassignRole(ctx, user.ID, "admin"), "unassign role should not return an error")
+
+	perms, err = roles.ListUserPermissions(ctx, user.ID)
+	assert.NoError(t, err, "list user permissions should not return an error")
+	assert.Equal(t, []model.Permission{{Action: "read", Resource: "posts"}, {Action: "write", Resource: "posts"}}, perms,
+		"unassigned roles should no longer grant permissions")
+}
@@ -0,0 +1,105 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRole = "editor"
+
+var roleCols = []string{"name", "description", "scope", "created_at"}
+
+func TestRoleRepo_CreateRole_Success(t *testing.T) {
+	mock, roles := setupMockRoleRepo(t)
+	params := model.Role{
+		Name:        testRole,
+		Description: "Edits posts",
+		Scope:       model.RoleScopeGlobal,
+		Permissions: []model.Permission{{Action: "write", Resource: "posts"}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(repo.CreateRoleQuery).
+		WithArgs(params.Name, params.Description, params.Scope).
+		WillReturnRows(sqlmock.NewRows(roleCols).AddRow(params.Name, params.Description, params.Scope, time.Now()))
+	mock.ExpectExec(repo.CreatePermissionQuery).
+		WithArgs(testRole, "write", "posts").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	role, err := roles.CreateRole(context.Background(), params)
+
+	assert.NoError(t, err, "create role should not return an error")
+	assert.Equal(t, params.Permissions, role.Permissions, "permissions must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestRoleRepo_CreateRole_PermissionFails(t *testing.T) {
+	mock, roles := setupMockRoleRepo(t)
+	dbErr := errors.New("connection lost")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(repo.CreateRoleQuery).
+		WithArgs(testRole, "", model.RoleScopeGlobal).
+		WillReturnRows(sqlmock.NewRows(roleCols).AddRow(testRole, "", model.RoleScopeGlobal, time.Now()))
+	mock.ExpectExec(repo.CreatePermissionQuery).
+		WithArgs(testRole, "read", "posts").
+		WillReturnError(dbErr)
+	mock.ExpectRollback()
+
+	_, err := roles.CreateRole(context.Background(), model.Role{
+		Name:        testRole,
+		Scope:       model.RoleScopeGlobal,
+		Permissions: []model.Permission{{Action: "read", Resource: "posts"}},
+	})
+
+	assert.ErrorIs(t, err, dbErr, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "the role should be rolled back")
+}
+
+func TestRoleRepo_FindRole_NotFound(t *testing.T) {
+	mock, roles := setupMockRoleRepo(t)
+	mock.ExpectQuery(repo.FindRoleQuery).
+		WithArgs(testRole).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := roles.FindRole(context.Background(), testRole)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestRoleRepo_ListUserPermissions(t *testing.T) {
+	mock, roles := setupMockRoleRepo(t)
+	mock.ExpectQuery(repo.ListUserPermissionsQuery).
+		WithArgs(testID).
+		WillReturnRows(sqlmock.NewRows([]string{"action", "resource"}).
+			AddRow("read", "posts").
+			AddRow("*", "roles"))
+
+	perms, err := roles.ListUserPermissions(context.Background(), testID)
+
+	assert.NoError(t, err, "list user permissions should not return an error")
+	assert.Equal(t, []model.Permission{{Action: "read", Resource: "posts"}, {Action: "*", Resource: "roles"}}, perms,
+		"permissions must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockRoleRepo(t *testing.T) (sqlmock.Sqlmock, repo.RoleRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	roles := repo.NewRoleRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, roles
+}
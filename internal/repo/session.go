@@ -0,0 +1,78 @@
+//go:generate mockgen -destination=mocks/session_store_mock.go -package=mocks . SessionStore
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type SessionStore interface {
+	CreateSession(ctx context.Context, params model.Session) (*model.Session, error)
+	FindSession(ctx context.Context, id string) (*model.Session, error)
+	DeleteSession(ctx context.Context, id string) error
+	DeleteUserSessions(ctx context.Context, userID string) error
+}
+
+type sessionStore struct {
+	db *sql.DB
+}
+
+func NewSessionStore(db *sql.DB) SessionStore {
+	return &sessionStore{
+		db: db,
+	}
+}
+
+const CreateSessionQuery = `
+INSERT INTO sessions (id, user_id, expires_at)
+VALUES ($1, $2, $3)
+RETURNING id, user_id, expires_at, created_at
+`
+
+func (s *sessionStore) CreateSession(ctx context.Context, params model.Session) (*model.Session, error) {
+	var session model.Session
+	if err := s.db.QueryRowContext(ctx, CreateSessionQuery, params.ID, params.UserID, params.ExpiresAt).
+		Scan(&session.ID, &session.UserID, &session.ExpiresAt, &session.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+const FindSessionQuery = `
+SELECT id, user_id, expires_at, created_at
+FROM sessions
+WHERE id = $1
+`
+
+func (s *sessionStore) FindSession(ctx context.Context, id string) (*model.Session, error) {
+	var session model.Session
+	if err := s.db.QueryRowContext(ctx, FindSessionQuery, id).
+		Scan(&session.ID, &session.UserID, &session.ExpiresAt, &session.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+const DeleteSessionQuery = `
+DELETE FROM sessions
+WHERE id = $1
+`
+
+func (s *sessionStore) DeleteSession(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, DeleteSessionQuery, id)
+	return err
+}
+
+const DeleteUserSessionsQuery = `
+DELETE FROM sessions
+WHERE user_id = $1
+`
+
+func (s *sessionStore) DeleteUserSessions(ctx context.Context, userID string) error {
+	_, err := s.db.ExecContext(ctx, DeleteUserSessionsQuery, userID)
+	return err
+}
@@ -0,0 +1,80 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionStore_Integration_Lifecycle(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	store := repo.NewSessionStore(conn)
+	ctx := context.Background()
+	expiresAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+
+	created, err := store.CreateSession(ctx, model.Session{
+		ID:        testSessionID,
+		UserID:    user.ID,
+		ExpiresAt: expiresAt,
+	})
+	assert.NoError(t, err, "create session should not return an error")
+	assert.Equal(t, user.ID, created.UserID, "user ID must match")
+
+	found, err := store.FindSession(ctx, testSessionID)
+	assert.NoError(t, err, "find session should not return an error")
+	assert.Equal(t, user.ID, found.UserID, "user ID must match")
+	assert.True(t, expiresAt.Equal(found.ExpiresAt), "expiry must round-trip")
+
+	assert.NoError(t, store.DeleteSession(ctx, testSessionID), "delete session should not return an error")
+
+	_, err = store.FindSession(ctx, testSessionID)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "deleted sessions should not be found")
+}
+
+func TestSessionStore_Integration_DeleteUserSessions(t *testing.T) {
+	conn := newTestDB(t)
+	userRepo := repo.NewUserRepo(conn)
+	user := createTestUser(t, userRepo, testEmail)
+	other := createTestUser(t, userRepo, "other@example.com")
+	store := repo.NewSessionStore(conn)
+	ctx := context.Background()
+	expiresAt := time.Now().UTC().Add(time.Hour)
+
+	for _, s := range []model.Session{
+		{ID: "a", UserID: user.ID, ExpiresAt: expiresAt},
+		{ID: "b", UserID: user.ID, ExpiresAt: expiresAt},
+		{ID: "c", UserID: other.ID, ExpiresAt: expiresAt},
+	} {
+		if _, err := store.CreateSession(ctx, s); err != nil {
+			t.Fatalf("create session: %v", err)
+		}
+	}
+
+	assert.NoError(t, store.DeleteUserSessions(ctx, user.ID), "delete user sessions should not return an error")
+
+	for _, id := range []string{"a", "b"} {
+		_, err := store.FindSession(ctx, id)
+		assert.ErrorIs(t, err, sql.ErrNoRows, "sessions of the user should be deleted")
+	}
+
+	_, err := store.FindSession(ctx, "c")
+	assert.NoError(t, err, "sessions of other users should be kept")
+}
+
+func TestSessionStore_Integration_UnknownUser(t *testing.T) {
+	store := repo.NewSessionStore(newTestDB(t))
+
+	_, err := store.CreateSession(context.Background(), model.Session{
+		ID:        testSessionID,
+		UserID:    "00000000-0000-0000-0000-000000000000",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+
+	assert.Error(t, err, "sessions must belong to an existing user")
+}
@@ -0,0 +1,97 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSessionID = "sessionhash"
+
+var sessionCols = []string{"id", "user_id", "expires_at", "created_at"}
+
+func TestSessionStore_CreateSession_Success(t *testing.T) {
+	mock, store := setupMockSessionStore(t)
+	now := time.Now().UTC()
+	params := model.Session{
+		ID:        testSessionID,
+		UserID:    testID,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	mock.ExpectQuery(repo.CreateSessionQuery).
+		WithArgs(params.ID, params.UserID, params.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows(sessionCols).
+			AddRow(params.ID, params.UserID, params.ExpiresAt, now))
+
+	session, err := store.CreateSession(context.Background(), params)
+
+	assert.NoError(t, err, "create session should not return an error")
+	assert.Equal(t, testSessionID, session.ID, "ID must match")
+	assert.Equal(t, testID, session.UserID, "user ID must match")
+	assert.NotZero(t, session.CreatedAt, "CreatedAt should not be zero")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestSessionStore_FindSession_Success(t *testing.T) {
+	mock, store := setupMockSessionStore(t)
+	now := time.Now().UTC()
+
+	mock.ExpectQuery(repo.FindSessionQuery).
+		WithArgs(testSessionID).
+		WillReturnRows(sqlmock.NewRows(sessionCols).
+			AddRow(testSessionID, testID, now.Add(time.Hour), now))
+
+	session, err := store.FindSession(context.Background(), testSessionID)
+
+	assert.NoError(t, err, "find session should not return an error")
+	assert.Equal(t, testID, session.UserID, "user ID must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestSessionStore_FindSession_NotFound(t *testing.T) {
+	mock, store := setupMockSessionStore(t)
+	mock.ExpectQuery(repo.FindSessionQuery).WithArgs(testSessionID).WillReturnError(sql.ErrNoRows)
+
+	_, err := store.FindSession(context.Background(), testSessionID)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestSessionStore_DeleteSession(t *testing.T) {
+	mock, store := setupMockSessionStore(t)
+	mock.ExpectExec(repo.DeleteSessionQuery).WithArgs(testSessionID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := store.DeleteSession(context.Background(), testSessionID)
+
+	assert.NoError(t, err, "delete session should not return an error")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestSessionStore_DeleteUserSessions(t *testing.T) {
+	mock, store := setupMockSessionStore(t)
+	mock.ExpectExec(repo.DeleteUserSessionsQuery).WithArgs(testID).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := store.DeleteUserSessions(context.Background(), testID)
+
+	assert.NoError(t, err, "delete user sessions should not return an error")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockSessionStore(t *testing.T) (sqlmock.Sqlmock, repo.SessionStore) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	store := repo.NewSessionStore(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, store
+}
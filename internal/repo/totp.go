@@ -0,0 +1,111 @@
+//go:generate mockgen -destination=mocks/totp_repo_mock.go -package=mocks . TOTPRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type TOTPRepo interface {
+	SaveTOTP(ctx context.Context, userID, secret string) (*model.TOTP, error)
+	FindTOTP(ctx context.Context, userID string) (*model.TOTP, error)
+	ConfirmTOTP(ctx context.Context, userID string, step int64, confirmedAt time.Time) error
+	UpdateTOTPStep(ctx context.Context, userID string, step int64) error
+	DeleteTOTP(ctx context.Context, userID string) error
+}
+
+type totpRepo struct {
+	db *sql.DB
+}
+
+func NewTOTPRepo(db *sql.DB) TOTPRepo {
+	return &totpRepo{
+		db: db,
+	}
+}
+
+const SaveTOTPQuery = `
+INSERT INTO user_totp (user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = excluded.secret, last_step = 0, created_at = CURRENT_TIMESTAMP
+WHERE user_totp.confirmed_at IS NULL
+RETURNING user_id, secret, last_step, confirmed_at, created_at
+`
+
+// SaveTOTP starts an enrollment, replacing any unconfirmed one. It returns
+// sql.ErrNoRows if the user has a confirmed enrollment, which must be deleted
+// first.
+func (r *totpRepo) SaveTOTP(ctx context.Context, userID, secret string) (*model.TOTP, error) {
+	var totp model.TOTP
+	if err := r.db.QueryRowContext(ctx, SaveTOTPQuery, userID, secret).
+		Scan(&totp.UserID, &totp.Secret, &totp.LastStep, &totp.ConfirmedAt, &totp.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &totp, nil
+}
+
+const FindTOTPQuery = `
+SELECT user_id, secret, last_step, confirmed_at, created_at
+FROM user_totp
+WHERE user_id = $1
+`
+
+func (r *totpRepo) FindTOTP(ctx context.Context, userID string) (*model.TOTP, error) {
+	var totp model.TOTP
+	if err := r.db.QueryRowContext(ctx, FindTOTPQuery, userID).
+		Scan(&totp.UserID, &totp.Secret, &totp.LastStep, &totp.ConfirmedAt, &totp.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &totp, nil
+}
+
+const ConfirmTOTPQuery = `
+UPDATE user_totp
+SET confirmed_at = $3, last_step = $2
+WHERE user_id = $1 AND confirmed_at IS NULL
+`
+
+// ConfirmTOTP enables an enrollment with the step of the code that confirmed
+// it. It returns sql.ErrNoRows if there is no unconfirmed enrollment.
+func (r *totpRepo) ConfirmTOTP(ctx context.Context, userID string, step int64, confirmedAt time.Time) error {
+	res, err := r.db.ExecContext(ctx, ConfirmTOTPQuery, userID, step, confirmedAt)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(res)
+}
+
+const UpdateTOTPStepQuery = `
+UPDATE user_totp
+SET last_step = $2
+WHERE user_id = $1 AND last_step < $2
+`
+
+// UpdateTOTPStep records the step of an accepted code. It returns
+// sql.ErrNoRows if a code of the same or a later step was already accepted,
+// so that concurrent requests cannot both use one code.
+func (r *totpRepo) UpdateTOTPStep(ctx context.Context, userID string, step int64) error {
+	res, err := r.db.ExecContext(ctx, UpdateTOTPStepQuery, userID, step)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(res)
+}
+
+const DeleteTOTPQuery = `
+DELETE FROM user_totp
+WHERE user_id = $1
+`
+
+func (r *totpRepo) DeleteTOTP(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, DeleteTOTPQuery, userID)
+	return err
+}
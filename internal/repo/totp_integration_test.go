@@ -0,0 +1,64 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOTPRepo_Integration_Enrollment(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	totps := repo.NewTOTPRepo(conn)
+	ctx := context.Background()
+
+	_, err := totps.FindTOTP(ctx, user.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "users should start without an enrollment")
+
+	_, err = totps.SaveTOTP(ctx, user.ID, "first")
+	assert.NoError(t, err, "save totp should not return an error")
+
+	saved, err := totps.SaveTOTP(ctx, user.ID, testTOTPSecret)
+	assert.NoError(t, err, "an unconfirmed enrollment should be replaced")
+	assert.Equal(t, testTOTPSecret, saved.Secret, "secret must match")
+
+	err = totps.ConfirmTOTP(ctx, user.ID, 10, time.Now().UTC())
+	assert.NoError(t, err, "confirm totp should not return an error")
+
+	err = totps.ConfirmTOTP(ctx, user.ID, 11, time.Now().UTC())
+	assert.ErrorIs(t, err, sql.ErrNoRows, "an enrollment should only be confirmed once")
+
+	_, err = totps.SaveTOTP(ctx, user.ID, "other")
+	assert.ErrorIs(t, err, sql.ErrNoRows, "a confirmed enrollment should not be replaced")
+
+	found, err := totps.FindTOTP(ctx, user.ID)
+	assert.NoError(t, err, "find totp should not return an error")
+	assert.Equal(t, testTOTPSecret, found.Secret, "secret must match")
+	assert.Equal(t, int64(10), found.LastStep, "last step must match")
+	assert.NotNil(t, found.ConfirmedAt, "enrollment should be confirmed")
+
+	err = totps.DeleteTOTP(ctx, user.ID)
+	assert.NoError(t, err, "delete totp should not return an error")
+
+	_, err = totps.FindTOTP(ctx, user.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "deleted enrollments should not be found")
+}
+
+func TestTOTPRepo_Integration_UpdateTOTPStep(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	totps := repo.NewTOTPRepo(conn)
+	ctx := context.Background()
+
+	_, err := totps.SaveTOTP(ctx, user.ID, testTOTPSecret)
+	assert.NoError(t, err, "save totp should not return an error")
+	assert.NoError(t, totps.ConfirmTOTP(ctx, user.ID, 10, time.Now().UTC()), "confirm totp should not return an error")
+
+	assert.NoError(t, totps.UpdateTOTPStep(ctx, user.ID, 11), "a later step should be accepted")
+	assert.ErrorIs(t, totps.UpdateTOTPStep(ctx, user.ID, 11), sql.ErrNoRows, "a step should only be accepted once")
+	assert.ErrorIs(t, totps.UpdateTOTPStep(ctx, user.ID, 9), sql.ErrNoRows, "an earlier step should be rejected")
+}
@@ -0,0 +1,68 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+var totpCols = []string{"user_id", "secret", "last_step", "confirmed_at", "created_at"}
+
+func TestTOTPRepo_SaveTOTP_Success(t *testing.T) {
+	mock, totps := setupMockTOTPRepo(t)
+	now := time.Now().UTC()
+
+	mock.ExpectQuery(repo.SaveTOTPQuery).
+		WithArgs(testID, testTOTPSecret).
+		WillReturnRows(sqlmock.NewRows(totpCols).AddRow(testID, testTOTPSecret, 0, nil, now))
+
+	totp, err := totps.SaveTOTP(context.Background(), testID, testTOTPSecret)
+
+	assert.NoError(t, err, "save totp should not return an error")
+	assert.Equal(t, testTOTPSecret, totp.Secret, "secret must match")
+	assert.Nil(t, totp.ConfirmedAt, "enrollment should not be confirmed")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestTOTPRepo_UpdateTOTPStep_Replayed(t *testing.T) {
+	mock, totps := setupMockTOTPRepo(t)
+	mock.ExpectExec(repo.UpdateTOTPStepQuery).
+		WithArgs(testID, int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := totps.UpdateTOTPStep(context.Background(), testID, 42)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestTOTPRepo_ConfirmTOTP(t *testing.T) {
+	mock, totps := setupMockTOTPRepo(t)
+	now := time.Now().UTC()
+	mock.ExpectExec(repo.ConfirmTOTPQuery).
+		WithArgs(testID, int64(42), now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := totps.ConfirmTOTP(context.Background(), testID, 42, now)
+
+	assert.NoError(t, err, "confirm totp should not return an error")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockTOTPRepo(t *testing.T) (sqlmock.Sqlmock, repo.TOTPRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	totps := repo.NewTOTPRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, totps
+}
@@ -4,13 +4,19 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"time"
 
+	"github.com/ferdiebergado/fullstackgo/internal/db"
 	"github.com/ferdiebergado/fullstackgo/internal/model"
 )
 
 type UserRepo interface {
 	CreateUser(ctx context.Context, params model.User) (*model.User, error)
 	FindUserByEmail(ctx context.Context, email string) (*model.User, error)
+	FindUserByID(ctx context.Context, id string) (*model.User, error)
+	MarkEmailVerified(ctx context.Context, id string, verifiedAt time.Time) error
+	UpdatePasswordHash(ctx context.Context, id, passwordHash string, updatedAt time.Time) error
+	UpdateEmail(ctx context.Context, id, email string, updatedAt time.Time) (*model.User, error)
 }
 
 type userRepo struct {
@@ -24,15 +30,19 @@ func NewUserRepo(db *sql.DB) UserRepo {
 }
 
 const CreateUserQuery = `
-INSERT into users (email, password_hash)
-VALUES $1, $2
-RETURNING id, email, created_at, updated_at
+INSERT INTO users (email, password_hash)
+VALUES ($1, $2)
+RETURNING id, email, email_verified_at, created_at, updated_at
 `
 
 func (r *userRepo) CreateUser(ctx context.Context, params model.User) (*model.User, error) {
 	var user model.User
 	if err := r.db.QueryRowContext(ctx, CreateUserQuery, params.Email, params.PasswordHash).
-		Scan(&user.ID, &user.Email, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		Scan(&user.ID, &user.Email, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		if db.IsUniqueViolation(err) {
+			return nil, ErrDuplicate
+		}
+
 		return nil, err
 	}
 
@@ -40,7 +50,7 @@ func (r *userRepo) CreateUser(ctx context.Context, params model.User) (*model.Us
 }
 
 const FindUserByEmailQuery = `
-SELECT id, password_hash
+SELECT id, email, password_hash, email_verified_at
 FROM users
 WHERE email = $1
 `
@@ -48,7 +58,76 @@ WHERE email = $1
 func (r *userRepo) FindUserByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
 	if err := r.db.QueryRowContext(ctx, FindUserByEmailQuery, email).
-		Scan(&user.ID, &user.PasswordHash); err != nil {
+		Scan(&user.ID, &user.Email, &user.PasswordHash, &user.EmailVerifiedAt); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+const FindUserByIDQuery = `
+SELECT id, email, email_verified_at, created_at, updated_at
+FROM users
+WHERE id = $1
+`
+
+func (r *userRepo) FindUserByID(ctx context.Context, id string) (*model.User, error) {
+	var user model.User
+	if err := r.db.QueryRowContext(ctx, FindUserByIDQuery, id).
+		Scan(&user.ID, &user.Email, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+const MarkEmailVerifiedQuery = `
+UPDATE users
+SET email_verified_at = $2, updated_at = $2
+WHERE id = $1
+`
+
+func (r *userRepo) MarkEmailVerified(ctx context.Context, id string, verifiedAt time.Time) error {
+	res, err := r.db.ExecContext(ctx, MarkEmailVerifiedQuery, id, verifiedAt)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(res)
+}
+
+const UpdatePasswordHashQuery = `
+UPDATE users
+SET password_hash = $2, updated_at = $3
+WHERE id = $1
+`
+
+func (r *userRepo) UpdatePasswordHash(ctx context.Context, id, passwordHash string, updatedAt time.Time) error {
+	res, err := r.db.ExecContext(ctx, UpdatePasswordHashQuery, id, passwordHash, updatedAt)
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(res)
+}
+
+// UpdateEmailQuery clears email_verified_at since the new address has not
+// been verified.
+const UpdateEmailQuery = `
+UPDATE users
+SET email = $2, email_verified_at = NULL, updated_at = $3
+WHERE id = $1
+RETURNING id, email, email_verified_at, created_at, updated_at
+`
+
+func (r *userRepo) UpdateEmail(ctx context.Context, id, email string, updatedAt time.Time) (*model.User, error) {
+	var user model.User
+	if err := r.db.QueryRowContext(ctx, UpdateEmailQuery, id, email, updatedAt).
+		Scan(&user.ID, &user.Email, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		if db.IsUniqueViolation(err) {
+			return nil, ErrDuplicate
+		}
+
 		return nil, err
 	}
 
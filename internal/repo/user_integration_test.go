@@ -0,0 +1,146 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/db/dbtest"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserRepo_Integration_CreateUser(t *testing.T) {
+	userRepo := repo.NewUserRepo(newTestDB(t))
+
+	user, err := userRepo.CreateUser(context.Background(), model.User{
+		Email:        testEmail,
+		PasswordHash: testPasswordHashed,
+	})
+
+	assert.NoError(t, err, "create user should not return an error")
+	assert.NotEmpty(t, user.ID, "ID should be generated")
+	assert.Equal(t, testEmail, user.Email, "email must match")
+	assert.WithinDuration(t, time.Now(), user.CreatedAt, time.Minute, "CreatedAt should be set")
+	assert.WithinDuration(t, time.Now(), user.UpdatedAt, time.Minute, "UpdatedAt should be set")
+}
+
+func TestUserRepo_Integration_CreateUser_Duplicate(t *testing.T) {
+	userRepo := repo.NewUserRepo(newTestDB(t))
+	ctx := context.Background()
+	params := model.User{Email: testEmail, PasswordHash: testPasswordHashed}
+
+	_, err := userRepo.CreateUser(ctx, params)
+	assert.NoError(t, err, "first create should not return an error")
+
+	_, err = userRepo.CreateUser(ctx, params)
+	assert.ErrorIs(t, err, repo.ErrDuplicate, "errors should match")
+}
+
+func TestUserRepo_Integration_FindUserByEmail(t *testing.T) {
+	userRepo := repo.NewUserRepo(newTestDB(t))
+	ctx := context.Background()
+	created := createTestUser(t, userRepo, testEmail)
+
+	user, err := userRepo.FindUserByEmail(ctx, testEmail)
+	assert.NoError(t, err, "find user should not return an error")
+	assert.Equal(t, created.ID, user.ID, "ID must match")
+	assert.Equal(t, testPasswordHashed, user.PasswordHash, "password hash must match")
+
+	_, err = userRepo.FindUserByEmail(ctx, "missing@example.com")
+	assert.ErrorIs(t, err, sql.ErrNoRows, "missing users should not be found")
+}
+
+func TestUserRepo_Integration_FindUserByID(t *testing.T) {
+	userRepo := repo.NewUserRepo(newTestDB(t))
+	ctx := context.Background()
+	created := createTestUser(t, userRepo, testEmail)
+
+	user, err := userRepo.FindUserByID(ctx, created.ID)
+	assert.NoError(t, err, "find user should not return an error")
+	assert.Equal(t, testEmail, user.Email, "email must match")
+	assert.Equal(t, created.CreatedAt.Unix(), user.CreatedAt.Unix(), "CreatedAt must match")
+	assert.Empty(t, user.PasswordHash, "password hash must not be loaded")
+
+	_, err = userRepo.FindUserByID(ctx, "00000000-0000-0000-0000-000000000000")
+	assert.ErrorIs(t, err, sql.ErrNoRows, "missing users should not be found")
+}
+
+func TestUserRepo_Integration_MarkEmailVerified(t *testing.T) {
+	userRepo := repo.NewUserRepo(newTestDB(t))
+	ctx := context.Background()
+	created := createTestUser(t, userRepo, testEmail)
+	assert.Nil(t, created.EmailVerifiedAt, "new users should not be verified")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	err := userRepo.MarkEmailVerified(ctx, created.ID, now)
+	assert.NoError(t, err, "mark email verified should not return an error")
+
+	user, err := userRepo.FindUserByEmail(ctx, testEmail)
+	assert.NoError(t, err, "find user should not return an error")
+	if assert.NotNil(t, user.EmailVerifiedAt, "email_verified_at should be set") {
+		assert.True(t, now.Equal(*user.EmailVerifiedAt), "email_verified_at must match")
+	}
+
+	err = userRepo.MarkEmailVerified(ctx, "00000000-0000-0000-0000-000000000000", now)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "missing users should not be updated")
+}
+
+func TestUserRepo_Integration_UpdatePasswordHash(t *testing.T) {
+	userRepo := repo.NewUserRepo(newTestDB(t))
+	ctx := context.Background()
+	created := createTestUser(t, userRepo, testEmail)
+	later := created.UpdatedAt.Add(time.Hour).UTC()
+
+	err := userRepo.UpdatePasswordHash(ctx, created.ID, "rehashed", later)
+	assert.NoError(t, err, "update password hash should not return an error")
+
+	user, err := userRepo.FindUserByEmail(ctx, testEmail)
+	assert.NoError(t, err, "find user should not return an error")
+	assert.Equal(t, "rehashed", user.PasswordHash, "password hash must match")
+
+	found, err := userRepo.FindUserByID(ctx, created.ID)
+	assert.NoError(t, err, "find user should not return an error")
+	assert.Equal(t, later.Unix(), found.UpdatedAt.Unix(), "UpdatedAt must be bumped")
+}
+
+func TestUserRepo_Integration_UpdateEmail(t *testing.T) {
+	userRepo := repo.NewUserRepo(newTestDB(t))
+	ctx := context.Background()
+	created := createTestUser(t, userRepo, testEmail)
+	taken := createTestUser(t, userRepo, "taken@example.com")
+	now := time.Now().UTC()
+	assert.NoError(t, userRepo.MarkEmailVerified(ctx, created.ID, now), "mark email verified should not return an error")
+
+	user, err := userRepo.UpdateEmail(ctx, created.ID, "new@example.com", now.Add(time.Hour))
+	assert.NoError(t, err, "update email should not return an error")
+	assert.Equal(t, "new@example.com", user.Email, "email must match")
+	assert.Nil(t, user.EmailVerifiedAt, "new email should not be verified")
+	assert.Equal(t, now.Add(time.Hour).Unix(), user.UpdatedAt.Unix(), "UpdatedAt must be bumped")
+
+	_, err = userRepo.UpdateEmail(ctx, created.ID, taken.Email, now)
+	assert.ErrorIs(t, err, repo.ErrDuplicate, "errors should match")
+
+	_, err = userRepo.UpdateEmail(ctx, "00000000-0000-0000-0000-000000000000", "other@example.com", now)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "missing users should not be updated")
+}
+
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	conn, _ := dbtest.New(t)
+	return conn
+}
+
+func createTestUser(t *testing.T, userRepo repo.UserRepo, email string) *model.User {
+	t.Helper()
+	user, err := userRepo.CreateUser(context.Background(), model.User{
+		Email:        email,
+		PasswordHash: testPasswordHashed,
+	})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
@@ -31,12 +31,12 @@ func TestAuthRepo_SignUpUser_Success(t *testing.T) {
 	}
 
 	now := time.Now().UTC()
-	cols := []string{"id", "email", "created_at", "updated_at"}
+	cols := []string{"id", "email", "email_verified_at", "created_at", "updated_at"}
 
 	mock.ExpectQuery(repo.CreateUserQuery).
 		WithArgs(params.Email, params.PasswordHash).
 		WillReturnRows(sqlmock.NewRows(cols).
-			AddRow(testID, testEmail, now, now))
+			AddRow(testID, testEmail, nil, now, now))
 	user, err := userRepo.CreateUser(context.Background(), params)
 
 	assert.NoError(t, err, "signup should not return an error")
@@ -85,8 +85,8 @@ func TestAuthRepo_FindUserByEmail_Success(t *testing.T) {
 	mock, userRepo := setupMockDB(t)
 	mock.ExpectQuery(repo.FindUserByEmailQuery).
 		WithArgs(testEmail).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "password_hash"}).
-			AddRow(testID, testPasswordHashed))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "email_verified_at"}).
+			AddRow(testID, testEmail, testPasswordHashed, nil))
 
 	user, err := userRepo.FindUserByEmail(context.Background(), testEmail)
 	assert.NoError(t, err, "signin should not return an error")
@@ -113,3 +113,71 @@ func setupMockDB(t *testing.T) (sqlmock.Sqlmock, repo.UserRepo) {
 	t.Cleanup(func() { mockDB.Close() })
 	return mock, repo
 }
+
+func TestAuthRepo_FindUserByID_Success(t *testing.T) {
+	mock, userRepo := setupMockDB(t)
+	now := time.Now().UTC()
+	mock.ExpectQuery(repo.FindUserByIDQuery).
+		WithArgs(testID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "email_verified_at", "created_at", "updated_at"}).
+			AddRow(testID, testEmail, now, now, now))
+
+	user, err := userRepo.FindUserByID(context.Background(), testID)
+	assert.NoError(t, err, "find user should not return an error")
+	assert.Equal(t, testEmail, user.Email, "email must match")
+	assert.Empty(t, user.PasswordHash, "password hash must not be loaded")
+	assert.Equal(t, &now, user.EmailVerifiedAt, "verified at must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestAuthRepo_MarkEmailVerified(t *testing.T) {
+	mock, userRepo := setupMockDB(t)
+	now := time.Now().UTC()
+	mock.ExpectExec(repo.MarkEmailVerifiedQuery).
+		WithArgs(testID, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := userRepo.MarkEmailVerified(context.Background(), testID, now)
+	assert.NoError(t, err, "mark email verified should not return an error")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestAuthRepo_MarkEmailVerified_NotFound(t *testing.T) {
+	mock, userRepo := setupMockDB(t)
+	now := time.Now().UTC()
+	mock.ExpectExec(repo.MarkEmailVerifiedQuery).
+		WithArgs(testID, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := userRepo.MarkEmailVerified(context.Background(), testID, now)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestAuthRepo_UpdatePasswordHash(t *testing.T) {
+	mock, userRepo := setupMockDB(t)
+	now := time.Now().UTC()
+	mock.ExpectExec(repo.UpdatePasswordHashQuery).
+		WithArgs(testID, testPasswordHashed, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := userRepo.UpdatePasswordHash(context.Background(), testID, testPasswordHashed, now)
+	assert.NoError(t, err, "update password hash should not return an error")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestAuthRepo_UpdateEmail(t *testing.T) {
+	mock, userRepo := setupMockDB(t)
+	now := time.Now().UTC()
+	newEmail := "new@example.com"
+	mock.ExpectQuery(repo.UpdateEmailQuery).
+		WithArgs(testID, newEmail, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "email_verified_at", "created_at", "updated_at"}).
+			AddRow(testID, newEmail, nil, now, now))
+
+	user, err := userRepo.UpdateEmail(context.Background(), testID, newEmail, now)
+	assert.NoError(t, err, "update email should not return an error")
+	assert.Equal(t, newEmail, user.Email, "email must match")
+	assert.Nil(t, user.EmailVerifiedAt, "new email should not be verified")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
@@ -0,0 +1,71 @@
+//go:generate mockgen -destination=mocks/user_token_repo_mock.go -package=mocks . UserTokenRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type UserTokenRepo interface {
+	CreateUserToken(ctx context.Context, params model.UserToken) (*model.UserToken, error)
+	ConsumeUserToken(ctx context.Context, id string, purpose model.TokenPurpose) (*model.UserToken, error)
+	DeleteUserTokens(ctx context.Context, userID string, purpose model.TokenPurpose) error
+}
+
+type userTokenRepo struct {
+	db *sql.DB
+}
+
+func NewUserTokenRepo(db *sql.DB) UserTokenRepo {
+	return &userTokenRepo{
+		db: db,
+	}
+}
+
+const CreateUserTokenQuery = `
+INSERT INTO user_tokens (id, user_id, purpose, email, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, purpose, email, expires_at, created_at
+`
+
+func (r *userTokenRepo) CreateUserToken(ctx context.Context, params model.UserToken) (*model.UserToken, error) {
+	var token model.UserToken
+	if err := r.db.QueryRowContext(ctx, CreateUserTokenQuery,
+		params.ID, params.UserID, params.Purpose, params.Email, params.ExpiresAt).
+		Scan(&token.ID, &token.UserID, &token.Purpose, &token.Email, &token.ExpiresAt, &token.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+const ConsumeUserTokenQuery = `
+DELETE FROM user_tokens
+WHERE id = $1 AND purpose = $2
+RETURNING id, user_id, purpose, email, expires_at, created_at
+`
+
+// ConsumeUserToken deletes the token and returns it, so that it can be used
+// at most once even under concurrent requests.
+func (r *userTokenRepo) ConsumeUserToken(ctx context.Context, id string,
+	purpose model.TokenPurpose) (*model.UserToken, error) {
+	var token model.UserToken
+	if err := r.db.QueryRowContext(ctx, ConsumeUserTokenQuery, id, purpose).
+		Scan(&token.ID, &token.UserID, &token.Purpose, &token.Email, &token.ExpiresAt, &token.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+const DeleteUserTokensQuery = `
+DELETE FROM user_tokens
+WHERE user_id = $1 AND purpose = $2
+`
+
+func (r *userTokenRepo) DeleteUserTokens(ctx context.Context, userID string, purpose model.TokenPurpose) error {
+	_, err := r.db.ExecContext(ctx, DeleteUserTokensQuery, userID, purpose)
+	return err
+}
@@ -0,0 +1,59 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserTokenRepo_Integration_ConsumeOnce(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	tokens := repo.NewUserTokenRepo(conn)
+	ctx := context.Background()
+
+	_, err := tokens.CreateUserToken(ctx, model.UserToken{
+		ID:        testUserTokenID,
+		UserID:    user.ID,
+		Purpose:   model.TokenPurposeVerifyEmail,
+		Email:     user.Email,
+		ExpiresAt: time.Now().UTC().Add(time.Hour),
+	})
+	assert.NoError(t, err, "create user token should not return an error")
+
+	_, err = tokens.ConsumeUserToken(ctx, testUserTokenID, "other")
+	assert.ErrorIs(t, err, sql.ErrNoRows, "tokens should not be consumed for another purpose")
+
+	token, err := tokens.ConsumeUserToken(ctx, testUserTokenID, model.TokenPurposeVerifyEmail)
+	assert.NoError(t, err, "consume user token should not return an error")
+	assert.Equal(t, user.ID, token.UserID, "user ID must match")
+
+	_, err = tokens.ConsumeUserToken(ctx, testUserTokenID, model.TokenPurposeVerifyEmail)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "tokens should only be consumed once")
+}
+
+func TestUserTokenRepo_Integration_DeleteUserTokens(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	tokens := repo.NewUserTokenRepo(conn)
+	ctx := context.Background()
+	expires := time.Now().UTC().Add(time.Hour)
+
+	for _, id := range []string{"a", "b"} {
+		_, err := tokens.CreateUserToken(ctx, model.UserToken{
+			ID: id, UserID: user.ID, Purpose: model.TokenPurposeVerifyEmail, Email: user.Email, ExpiresAt: expires,
+		})
+		assert.NoError(t, err, "create user token should not return an error")
+	}
+
+	err := tokens.DeleteUserTokens(ctx, user.ID, model.TokenPurposeVerifyEmail)
+	assert.NoError(t, err, "delete user tokens should not return an error")
+
+	_, err = tokens.ConsumeUserToken(ctx, "a", model.TokenPurposeVerifyEmail)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "deleted tokens should not be found")
+}
@@ -0,0 +1,92 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+const testUserTokenID = "usertokenhash"
+
+var userTokenCols = []string{"id", "user_id", "purpose", "email", "expires_at", "created_at"}
+
+func TestUserTokenRepo_CreateUserToken_Success(t *testing.T) {
+	mock, tokens := setupMockUserTokenRepo(t)
+	now := time.Now().UTC()
+	params := model.UserToken{
+		ID:        testUserTokenID,
+		UserID:    testID,
+		Purpose:   model.TokenPurposeVerifyEmail,
+		Email:     testEmail,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	mock.ExpectQuery(repo.CreateUserTokenQuery).
+		WithArgs(params.ID, params.UserID, params.Purpose, params.Email, params.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows(userTokenCols).
+			AddRow(params.ID, params.UserID, params.Purpose, params.Email, params.ExpiresAt, now))
+
+	token, err := tokens.CreateUserToken(context.Background(), params)
+
+	assert.NoError(t, err, "create user token should not return an error")
+	assert.Equal(t, model.TokenPurposeVerifyEmail, token.Purpose, "purpose must match")
+	assert.Equal(t, testEmail, token.Email, "email must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestUserTokenRepo_ConsumeUserToken_Success(t *testing.T) {
+	mock, tokens := setupMockUserTokenRepo(t)
+	now := time.Now().UTC()
+
+	mock.ExpectQuery(repo.ConsumeUserTokenQuery).
+		WithArgs(testUserTokenID, model.TokenPurposeVerifyEmail).
+		WillReturnRows(sqlmock.NewRows(userTokenCols).
+			AddRow(testUserTokenID, testID, model.TokenPurposeVerifyEmail, testEmail, now.Add(time.Hour), now))
+
+	token, err := tokens.ConsumeUserToken(context.Background(), testUserTokenID, model.TokenPurposeVerifyEmail)
+
+	assert.NoError(t, err, "consume user token should not return an error")
+	assert.Equal(t, testID, token.UserID, "user ID must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestUserTokenRepo_ConsumeUserToken_NotFound(t *testing.T) {
+	mock, tokens := setupMockUserTokenRepo(t)
+	mock.ExpectQuery(repo.ConsumeUserTokenQuery).
+		WithArgs(testUserTokenID, model.TokenPurposeVerifyEmail).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := tokens.ConsumeUserToken(context.Background(), testUserTokenID, model.TokenPurposeVerifyEmail)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestUserTokenRepo_DeleteUserTokens(t *testing.T) {
+	mock, tokens := setupMockUserTokenRepo(t)
+	mock.ExpectExec(repo.DeleteUserTokensQuery).
+		WithArgs(testID, model.TokenPurposeVerifyEmail).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := tokens.DeleteUserTokens(context.Background(), testID, model.TokenPurposeVerifyEmail)
+
+	assert.NoError(t, err, "delete user tokens should not return an error")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockUserTokenRepo(t *testing.T) (sqlmock.Sqlmock, repo.UserTokenRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	tokens := repo.NewUserTokenRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, tokens
+}
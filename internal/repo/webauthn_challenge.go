@@ -0,0 +1,61 @@
+//go:generate mockgen -destination=mocks/webauthn_challenge_repo_mock.go -package=mocks . WebAuthnChallengeRepo
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type WebAuthnChallengeRepo interface {
+	CreateWebAuthnChallenge(ctx context.Context, params model.WebAuthnChallenge) (*model.WebAuthnChallenge, error)
+	ConsumeWebAuthnChallenge(ctx context.Context, id string) (*model.WebAuthnChallenge, error)
+}
+
+type webAuthnChallengeRepo struct {
+	db *sql.DB
+}
+
+func NewWebAuthnChallengeRepo(db *sql.DB) WebAuthnChallengeRepo {
+	return &webAuthnChallengeRepo{
+		db: db,
+	}
+}
+
+const CreateWebAuthnChallengeQuery = `
+INSERT INTO webauthn_challenges (id, user_id, ceremony, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, ceremony, expires_at, created_at
+`
+
+func (r *webAuthnChallengeRepo) CreateWebAuthnChallenge(ctx context.Context,
+	params model.WebAuthnChallenge) (*model.WebAuthnChallenge, error) {
+	var challenge model.WebAuthnChallenge
+	if err := r.db.QueryRowContext(ctx, CreateWebAuthnChallengeQuery, params.ID, params.UserID, params.Ceremony,
+		params.ExpiresAt).
+		Scan(&challenge.ID, &challenge.UserID, &challenge.Ceremony, &challenge.ExpiresAt, &challenge.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+const ConsumeWebAuthnChallengeQuery = `
+DELETE FROM webauthn_challenges
+WHERE id = $1
+RETURNING id, user_id, ceremony, expires_at, created_at
+`
+
+// ConsumeWebAuthnChallenge deletes the challenge and returns it, so that it
+// answers at most one ceremony.
+func (r *webAuthnChallengeRepo) ConsumeWebAuthnChallenge(ctx context.Context,
+	id string) (*model.WebAuthnChallenge, error) {
+	var challenge model.WebAuthnChallenge
+	if err := r.db.QueryRowContext(ctx, ConsumeWebAuthnChallengeQuery, id).
+		Scan(&challenge.ID, &challenge.UserID, &challenge.Ceremony, &challenge.ExpiresAt, &challenge.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
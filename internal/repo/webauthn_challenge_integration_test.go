@@ -0,0 +1,37 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebAuthnChallengeRepo_Integration_Lifecycle(t *testing.T) {
+	conn := newTestDB(t)
+	user := createTestUser(t, repo.NewUserRepo(conn), testEmail)
+	challenges := repo.NewWebAuthnChallengeRepo(conn)
+	ctx := context.Background()
+
+	_, err := challenges.CreateWebAuthnChallenge(ctx, model.WebAuthnChallenge{
+		ID:        testChallengeID,
+		UserID:    &user.ID,
+		Ceremony:  model.CeremonyRegistration,
+		ExpiresAt: time.Now().UTC().Add(5 * time.Minute),
+	})
+	assert.NoError(t, err, "create webauthn challenge should not return an error")
+
+	consumed, err := challenges.ConsumeWebAuthnChallenge(ctx, testChallengeID)
+	assert.NoError(t, err, "consume webauthn challenge should not return an error")
+	if assert.NotNil(t, consumed.UserID, "user ID must be set") {
+		assert.Equal(t, user.ID, *consumed.UserID, "user ID must match")
+	}
+	assert.Equal(t, model.CeremonyRegistration, consumed.Ceremony, "ceremony must match")
+
+	_, err = challenges.ConsumeWebAuthnChallenge(ctx, testChallengeID)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "challenges should only be consumed once")
+}
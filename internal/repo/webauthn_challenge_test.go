@@ -0,0 +1,60 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+var webAuthnChallengeCols = []string{"id", "user_id", "ceremony", "expires_at", "created_at"}
+
+func TestWebAuthnChallengeRepo_CreateWebAuthnChallenge_Success(t *testing.T) {
+	mock, challenges := setupMockWebAuthnChallengeRepo(t)
+	now := time.Now().UTC()
+	params := model.WebAuthnChallenge{
+		ID:        testChallengeID,
+		Ceremony:  model.CeremonyAuthentication,
+		ExpiresAt: now.Add(5 * time.Minute),
+	}
+
+	mock.ExpectQuery(repo.CreateWebAuthnChallengeQuery).
+		WithArgs(params.ID, params.UserID, params.Ceremony, params.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows(webAuthnChallengeCols).
+			AddRow(params.ID, nil, string(params.Ceremony), params.ExpiresAt, now))
+
+	challenge, err := challenges.CreateWebAuthnChallenge(context.Background(), params)
+
+	assert.NoError(t, err, "create webauthn challenge should not return an error")
+	assert.Nil(t, challenge.UserID, "user ID must be empty")
+	assert.Equal(t, model.CeremonyAuthentication, challenge.Ceremony, "ceremony must match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func TestWebAuthnChallengeRepo_ConsumeWebAuthnChallenge_NotFound(t *testing.T) {
+	mock, challenges := setupMockWebAuthnChallengeRepo(t)
+	mock.ExpectQuery(repo.ConsumeWebAuthnChallengeQuery).
+		WithArgs(testChallengeID).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := challenges.ConsumeWebAuthnChallenge(context.Background(), testChallengeID)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows, "errors should match")
+	assert.NoError(t, mock.ExpectationsWereMet(), "some expectations were not met")
+}
+
+func setupMockWebAuthnChallengeRepo(t *testing.T) (sqlmock.Sqlmock, repo.WebAuthnChallengeRepo) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmockOpts)
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	challenges := repo.NewWebAuthnChallengeRepo(mockDB)
+	t.Cleanup(func() { mockDB.Close() })
+	return mock, challenges
+}
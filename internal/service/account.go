@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+)
+
+// ChangePassword replaces the user's password after checking the current one,
+// and signs them out everywhere.
+func (s *authService) ChangePassword(ctx context.Context, user *model.User, params model.ChangePasswordParams) error {
+	if err := s.checkPassword(ctx, user, params.CurrentPassword); err != nil {
+		return err
+	}
+
+	if err := s.checkBreached(ctx, params.Password); err != nil {
+		return err
+	}
+
+	hash, err := security.HashContext(ctx, s.hasher, params.Password)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if err := s.repo.UpdatePasswordHash(ctx, user.ID, hash, now); err != nil {
+		return fmt.Errorf("update password hash: %w", err)
+	}
+
+	return s.revokeUserCredentials(ctx, user.ID, now)
+}
+
+// ChangeEmail replaces the user's email after checking their password. The
+// new address is unverified until the user follows the link sent to it.
+func (s *authService) ChangeEmail(ctx context.Context, user *model.User, params model.ChangeEmailParams) (*model.User, error) {
+	if err := s.checkPassword(ctx, user, params.CurrentPassword); err != nil {
+		return nil, err
+	}
+
+	if params.Email == user.Email {
+		return user, nil
+	}
+
+	updated, err := s.repo.UpdateEmail(ctx, user.ID, params.Email, time.Now().UTC())
+	if err != nil {
+		if errors.Is(err, repo.ErrDuplicate) {
+			return nil, ErrEmailTaken
+		}
+
+		return nil, fmt.Errorf("update email: %w", err)
+	}
+
+	if s.verifier != nil {
+		if err := s.verifier.send(ctx, updated); err != nil {
+			slog.Error("send verification email", "error", err)
+		}
+	}
+
+	return updated, nil
+}
+
+// checkPassword verifies the password of an authenticated user.
+func (s *authService) checkPassword(ctx context.Context, user *model.User, password string) error {
+	current, err := s.repo.FindUserByEmail(ctx, user.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+
+		return fmt.Errorf("find user by email: %w", err)
+	}
+
+	if current.ID != user.ID {
+		return ErrUserNotFound
+	}
+
+	if current.PasswordHash == "" {
+		return ErrPasswordMismatch
+	}
+
+	ok, err := security.VerifyContext(ctx, s.hasher, password, current.PasswordHash)
+	if err != nil {
+		return fmt.Errorf("hasher verify: %w", err)
+	}
+
+	if !ok {
+		return ErrPasswordMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const newEmail = "new@example.com"
+
+var testUser = &model.User{ID: testID, Email: testEmail}
+
+func TestAuthService_ChangePassword_Success(t *testing.T) {
+	m, authService := setupResetMocks(t)
+	ctx := context.Background()
+	params := model.ChangePasswordParams{CurrentPassword: testPassword, Password: newPassword, PasswordConfirm: newPassword}
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(true, nil)
+	m.hasher.EXPECT().Hash(newPassword).Return("rehashed", nil)
+	m.users.EXPECT().UpdatePasswordHash(ctx, testID, "rehashed", gomock.Any()).Return(nil)
+	m.sessions.EXPECT().DeleteUserSessions(ctx, testID).Return(nil)
+	m.refresh.EXPECT().RevokeUserRefreshTokens(ctx, testID, gomock.Any()).Return(nil)
+
+	err := authService.ChangePassword(ctx, testUser, params)
+
+	assert.NoError(t, err, "change password should not return an error")
+}
+
+func TestAuthService_ChangePassword_WrongPassword(t *testing.T) {
+	m, authService := setupResetMocks(t)
+	ctx := context.Background()
+	params := model.ChangePasswordParams{CurrentPassword: "wrong", Password: newPassword, PasswordConfirm: newPassword}
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify("wrong", hashedPassword).Return(false, nil)
+	m.users.EXPECT().UpdatePasswordHash(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := authService.ChangePassword(ctx, testUser, params)
+
+	assert.ErrorIs(t, err, service.ErrPasswordMismatch, "errors should match")
+}
+
+func TestAuthService_ChangeEmail_Success(t *testing.T) {
+	m, authService := setupVerificationMocks(t, testVerificationConfig)
+	ctx := context.Background()
+	params := model.ChangeEmailParams{CurrentPassword: testPassword, Email: newEmail}
+	updated := &model.User{ID: testID, Email: newEmail}
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(true, nil)
+	m.users.EXPECT().UpdateEmail(ctx, testID, newEmail, gomock.Any()).Return(updated, nil)
+	m.tokens.EXPECT().DeleteUserTokens(ctx, testID, model.TokenPurposeVerifyEmail).Return(nil)
+	m.tokens.EXPECT().CreateUserToken(ctx, gomock.Any()).Return(&model.UserToken{}, nil)
+
+	user, err := authService.ChangeEmail(ctx, testUser, params)
+
+	assert.NoError(t, err, "change email should not return an error")
+	assert.Equal(t, newEmail, user.Email, "email should match")
+	msgs := m.mailer.Messages()
+	if assert.Len(t, msgs, 1, "one email should be sent") {
+		assert.Equal(t, newEmail, msgs[0].To, "the new address should be verified")
+	}
+}
+
+func TestAuthService_ChangeEmail_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		verified bool
+		found    *model.User
+		findErr  error
+		err      error
+		updErr   error
+	}{
+		{"wrong password should be rejected", false,
+			&model.User{ID: testID, PasswordHash: hashedPassword}, nil, service.ErrPasswordMismatch, nil},
+		{"taken email should be rejected", true,
+			&model.User{ID: testID, PasswordHash: hashedPassword}, nil, service.ErrEmailTaken, repo.ErrDuplicate},
+		{"missing user should be rejected", false, nil, sql.ErrNoRows, service.ErrUserNotFound, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, authService := setupVerificationMocks(t, testVerificationConfig)
+			ctx := context.Background()
+
+			m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(tt.found, tt.findErr)
+			m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(tt.verified, nil).AnyTimes()
+			m.users.EXPECT().UpdateEmail(ctx, testID, newEmail, gomock.Any()).Return(nil, tt.updErr).AnyTimes()
+
+			_, err := authService.ChangeEmail(ctx, testUser, model.ChangeEmailParams{CurrentPassword: testPassword, Email: newEmail})
+
+			assert.ErrorIs(t, err, tt.err, "errors should match")
+			assert.Empty(t, m.mailer.Messages(), "no email should be sent")
+		})
+	}
+}
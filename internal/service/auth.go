@@ -6,9 +6,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/password"
 	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/webauthn"
 	"github.com/ferdiebergado/fullstackgo/internal/repo"
 )
 
@@ -17,24 +21,80 @@ var ErrPasswordMismatch = errors.New("passwords do not match")
 type AuthService interface {
 	SignUpUser(ctx context.Context, params model.UserSignUpParams) (*model.User, error)
 	SignInUser(ctx context.Context, params model.UserSignInParams) (string, error)
+	SignInWithToken(ctx context.Context, params model.UserSignInParams) (*model.TokenPair, error)
+	RefreshTokens(ctx context.Context, refreshToken string) (*model.TokenPair, error)
+	RevokeRefreshToken(ctx context.Context, refreshToken string) error
+	VerifyAccessToken(ctx context.Context, accessToken string) (string, error)
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, params model.ResetPasswordParams) error
+	ChangePassword(ctx context.Context, user *model.User, params model.ChangePasswordParams) error
+	ChangeEmail(ctx context.Context, user *model.User, params model.ChangeEmailParams) (*model.User, error)
+	EnrollTOTP(ctx context.Context, user *model.User, params model.CurrentPasswordParams) (*model.TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, user *model.User, params model.TOTPCodeParams) (*model.RecoveryCodes, error)
+	DisableTOTP(ctx context.Context, user *model.User, params model.MFACodeParams) error
+	RegenerateRecoveryCodes(ctx context.Context, user *model.User, params model.MFACodeParams) (*model.RecoveryCodes, error)
+	CompleteMFASignIn(ctx context.Context, params model.MFASignInParams) (string, error)
+	CompleteMFATokenSignIn(ctx context.Context, params model.MFASignInParams) (*model.TokenPair, error)
+	BeginPasskeyRegistration(ctx context.Context, user *model.User, params model.CurrentPasswordParams) (*webauthn.CreationOptions, error)
+	FinishPasskeyRegistration(ctx context.Context, user *model.User, params model.PasskeyRegistrationParams) (*model.Passkey, error)
+	ListPasskeys(ctx context.Context, user *model.User) ([]model.Passkey, error)
+	DeletePasskey(ctx context.Context, user *model.User, id string) error
+	BeginPasskeySignIn(ctx context.Context) (*webauthn.RequestOptions, error)
+	FinishPasskeySignIn(ctx context.Context, params model.PasskeySignInParams) (string, error)
+	BeginOIDCSignIn(ctx context.Context, provider string) (*model.OIDCAuthorization, error)
+	FinishOIDCSignIn(ctx context.Context, provider string, params model.OIDCCallbackParams) (string, error)
+	ListIdentities(ctx context.Context, user *model.User) ([]model.Identity, error)
+	RequestMagicLink(ctx context.Context, email string) (string, error)
+	SignInWithMagicLink(ctx context.Context, params model.MagicLinkSignInParams) (string, error)
 }
 
 type authService struct {
-	repo   repo.UserRepo
-	hasher security.Hasher
+	repo       repo.UserRepo
+	hasher     security.Hasher
+	tokens     *tokenIssuer
+	verifier   *emailVerifier
+	resetter   *passwordResetter
+	sessions   repo.SessionStore
+	throttle   *loginThrottle
+	breaches   password.BreachChecker
+	mfa        *mfaVerifier
+	passkeys   *passkeyAuthenticator
+	oidc       *oidcSignIn
+	magicLinks *magicLinkSignIn
+	background func(task func())
 }
 
-func NewAuthService(repo repo.UserRepo, hasher security.Hasher) AuthService {
-	return &authService{
-		repo:   repo,
-		hasher: hasher,
+// AuthOption configures optional capabilities of the AuthService.
+type AuthOption func(*authService)
+
+// WithSessions lets the service end a user's sessions when their
+// credentials change.
+func WithSessions(sessions repo.SessionStore) AuthOption {
+	return func(s *authService) {
+		s.sessions = sessions
+	}
+}
+
+func NewAuthService(repo repo.UserRepo, hasher security.Hasher, opts ...AuthOption) AuthService {
+	s := &authService{
+		repo:       repo,
+		hasher:     hasher,
+		background: func(task func()) { go task() },
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 func (s *authService) SignUpUser(ctx context.Context, params model.UserSignUpParams) (*model.User, error) {
 	existing, err := s.repo.FindUserByEmail(ctx, params.Email)
 
-	if err != nil {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return nil, fmt.Errorf("find user by email: %v", err)
 	}
 
@@ -42,7 +102,11 @@ func (s *authService) SignUpUser(ctx context.Context, params model.UserSignUpPar
 		return nil, ErrEmailTaken
 	}
 
-	hash, err := s.hasher.Hash(params.Password)
+	if err := s.checkBreached(ctx, params.Password); err != nil {
+		return nil, err
+	}
+
+	hash, err := security.HashContext(ctx, s.hasher, params.Password)
 	if err != nil {
 		return nil, fmt.Errorf("hash password: %w", err)
 	}
@@ -52,10 +116,58 @@ func (s *authService) SignUpUser(ctx context.Context, params model.UserSignUpPar
 		PasswordHash: hash,
 	}
 
-	return s.repo.CreateUser(ctx, user)
+	created, err := s.repo.CreateUser(ctx, user)
+	if err != nil {
+		// A concurrent sign-up registered the email after our lookup.
+		if errors.Is(err, repo.ErrDuplicate) {
+			return nil, ErrEmailTaken
+		}
+
+		return nil, err
+	}
+
+	// The account is usable without a verified email unless sign-in requires
+	// one, and the link can be resent, so a mail failure does not fail sign-up.
+	if s.verifier != nil {
+		if err := s.verifier.send(ctx, created); err != nil {
+			slog.Error("send verification email", "error", err)
+		}
+	}
+
+	return created, nil
 }
 
 func (s *authService) SignInUser(ctx context.Context, params model.UserSignInParams) (string, error) {
+	if s.throttle == nil {
+		return s.signIn(ctx, params)
+	}
+
+	now := time.Now().UTC()
+	keys := s.throttle.keys(ctx, params.Email)
+	if err := s.throttle.begin(ctx, keys, now); err != nil {
+		return "", err
+	}
+
+	// A sign-in awaiting its second factor counts as neither, until
+	// CompleteMFASignIn checks the code.
+	userID, err := s.signIn(ctx, params)
+	switch {
+	case errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrPasswordMismatch):
+		// begin already counted the failure.
+	case err == nil:
+		if err := s.throttle.recordSuccess(ctx, keys); err != nil {
+			return "", err
+		}
+	default:
+		if err := s.throttle.abandon(ctx, keys); err != nil {
+			return "", err
+		}
+	}
+
+	return userID, err
+}
+
+func (s *authService) signIn(ctx context.Context, params model.UserSignInParams) (string, error) {
 	user, err := s.repo.FindUserByEmail(ctx, params.Email)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -65,15 +177,71 @@ func (s *authService) SignInUser(ctx context.Context, params model.UserSignInPar
 		return "", err
 	}
 
-	ok, err := s.hasher.Verify(params.Password, user.PasswordHash)
+	// Users created through an identity provider have no password.
+	if user.PasswordHash == "" {
+		return "", ErrPasswordMismatch
+	}
+
+	ok, err := security.VerifyContext(ctx, s.hasher, params.Password, user.PasswordHash)
 
 	if err != nil {
-		return "", fmt.Errorf("hasher verify: %v", err)
+		return "", fmt.Errorf("hasher verify: %w", err)
 	}
 
 	if !ok {
 		return "", ErrPasswordMismatch
 	}
 
+	if s.verifier != nil && s.verifier.cfg.RequireVerified && user.EmailVerifiedAt == nil {
+		return "", ErrEmailNotVerified
+	}
+
+	if security.NeedsRehash(s.hasher, user.PasswordHash) {
+		// The password is correct, so a failed upgrade should not block the
+		// sign-in. It is retried on the next one.
+		if err := s.rehashPassword(ctx, user.ID, params.Password); err != nil {
+			slog.Error("rehash password", "error", err)
+		}
+	}
+
+	if s.mfa != nil {
+		if err := s.mfa.requireSecondFactor(ctx, user.ID); err != nil {
+			return "", err
+		}
+	}
+
 	return user.ID, nil
 }
+
+// rehashPassword replaces the stored hash with one made with the hasher's
+// current parameters.
+func (s *authService) rehashPassword(ctx context.Context, userID, password string) error {
+	hash, err := security.HashContext(ctx, s.hasher, password)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	if err := s.repo.UpdatePasswordHash(ctx, userID, hash, time.Now().UTC()); err != nil {
+		return fmt.Errorf("update password hash: %w", err)
+	}
+
+	return nil
+}
+
+// revokeUserCredentials signs the user out of every session and revokes
+// their refresh tokens.
+func (s *authService) revokeUserCredentials(ctx context.Context, userID string, now time.Time) error {
+	if s.sessions != nil {
+		if err := s.sessions.DeleteUserSessions(ctx, userID); err != nil {
+			return fmt.Errorf("delete user sessions: %w", err)
+		}
+	}
+
+	if s.tokens != nil {
+		if err := s.tokens.repo.RevokeUserRefreshTokens(ctx, userID, now); err != nil {
+			return fmt.Errorf("revoke user refresh tokens: %w", err)
+		}
+	}
+
+	return nil
+}
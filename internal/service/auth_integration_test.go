@@ -0,0 +1,618 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/db/dbtest"
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/mail"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/oidc/oidctest"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/totp"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/webauthn/webauthntest"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthService_Integration_SignUpAndSignIn(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	authService := service.NewAuthService(repo.NewUserRepo(conn), &security.Argon2Hasher{})
+	ctx := context.Background()
+
+	user, err := authService.SignUpUser(ctx, newSignUpParams())
+	assert.NoError(t, err, "signup should not return an error")
+
+	_, err = authService.SignUpUser(ctx, newSignUpParams())
+	assert.ErrorIs(t, err, service.ErrEmailTaken, "duplicate signup should be rejected")
+
+	id, err := authService.SignInUser(ctx, model.UserSignInParams{Email: testEmail, Password: testPassword})
+	assert.NoError(t, err, "signin should not return an error")
+	assert.Equal(t, user.ID, id, "ID should match")
+
+	_, err = authService.SignInUser(ctx, model.UserSignInParams{Email: testEmail, Password: "wrong"})
+	assert.ErrorIs(t, err, service.ErrPasswordMismatch, "wrong password should be rejected")
+
+	_, err = authService.SignInUser(ctx, model.UserSignInParams{Email: "missing@example.com", Password: testPassword})
+	assert.ErrorIs(t, err, service.ErrUserNotFound, "unknown email should be rejected")
+}
+
+func TestAuthService_Integration_EmailVerification(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	mailer := &mail.MemoryMailer{}
+	cfg := testVerificationConfig
+	cfg.RequireVerified = true
+	authService := service.NewAuthService(repo.NewUserRepo(conn), &security.Argon2Hasher{},
+		service.WithBackground(runNow), service.WithEmailVerification(repo.NewUserTokenRepo(conn), mailer, cfg))
+	ctx := context.Background()
+	signIn := model.UserSignInParams{Email: testEmail, Password: testPassword}
+
+	_, err := authService.SignUpUser(ctx, newSignUpParams())
+	assert.NoError(t, err, "signup should not return an error")
+
+	_, err = authService.SignInUser(ctx, signIn)
+	assert.ErrorIs(t, err, service.ErrEmailNotVerified, "unverified user should be rejected")
+
+	assert.NoError(t, authService.ResendVerification(ctx, testEmail), "resend should not return an error")
+	msgs := mailer.Messages()
+	if !assert.Len(t, msgs, 2, "two emails should be sent") {
+		return
+	}
+
+	err = authService.VerifyEmail(ctx, linkToken(t, msgs[0].Body))
+	assert.ErrorIs(t, err, service.ErrInvalidToken, "superseded token should be rejected")
+
+	token := linkToken(t, msgs[1].Body)
+	assert.NoError(t, authService.VerifyEmail(ctx, token), "verify email should not return an error")
+	assert.ErrorIs(t, authService.VerifyEmail(ctx, token), service.ErrInvalidToken, "token should be single use")
+
+	_, err = authService.SignInUser(ctx, signIn)
+	assert.NoError(t, err, "verified user should sign in")
+}
+
+func TestAuthService_Integration_PasswordReset(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	mailer := &mail.MemoryMailer{}
+	sessionStore := repo.NewSessionStore(conn)
+	authService := service.NewAuthService(repo.NewUserRepo(conn), &security.Argon2Hasher{},
+		service.WithSessions(sessionStore),
+		service.WithBackground(runNow),
+		service.WithPasswordReset(repo.NewUserTokenRepo(conn), mailer, testPasswordResetConfig))
+	sessionService := service.NewSessionService(sessionStore, time.Hour)
+	ctx := context.Background()
+
+	user, err := authService.SignUpUser(ctx, newSignUpParams())
+	assert.NoError(t, err, "signup should not return an error")
+	sessionToken, _, err := sessionService.CreateSession(ctx, user.ID)
+	assert.NoError(t, err, "create session should not return an error")
+
+	assert.NoError(t, authService.ForgotPassword(ctx, testEmail), "forgot password should not return an error")
+	assert.NoError(t, authService.ForgotPassword(ctx, "missing@example.com"), "unknown emails should not be reported")
+	msgs := mailer.Messages()
+	if !assert.Len(t, msgs, 1, "one email should be sent") {
+		return
+	}
+
+	token := linkToken(t, msgs[0].Body)
+	params := model.ResetPasswordParams{Token: token, Password: newPassword, PasswordConfirm: newPassword}
+	assert.NoError(t, authService.ResetPassword(ctx, params), "reset password should not return an error")
+	assert.ErrorIs(t, authService.ResetPassword(ctx, params), service.ErrInvalidToken, "token should be single use")
+
+	_, err = sessionService.ValidateSession(ctx, sessionToken)
+	assert.ErrorIs(t, err, service.ErrInvalidSession, "existing sessions should be revoked")
+
+	_, err = authService.SignInUser(ctx, model.UserSignInParams{Email: testEmail, Password: testPassword})
+	assert.ErrorIs(t, err, service.ErrPasswordMismatch, "old password should be rejected")
+
+	_, err = authService.SignInUser(ctx, model.UserSignInParams{Email: testEmail, Password: newPassword})
+	assert.NoError(t, err, "new password should be accepted")
+}
+
+func TestAuthService_Integration_MagicLink(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	mailer := &mail.MemoryMailer{}
+	users := repo.NewUserRepo(conn)
+	authService := service.NewAuthService(users, &security.Argon2Hasher{},
+		service.WithBackground(runNow),
+		service.WithMagicLinks(repo.NewMagicLinkRepo(conn), mailer, testMagicLinkConfig))
+	ctx := context.Background()
+
+	user, err := authService.SignUpUser(ctx, newSignUpParams())
+	assert.NoError(t, err, "signup should not return an error")
+
+	nonce, err := authService.RequestMagicLink(ctx, testEmail)
+	assert.NoError(t, err, "request magic link should not return an error")
+	msgs := mailer.Messages()
+	if !assert.Len(t, msgs, 1, "one email should be sent") {
+		return
+	}
+	params := model.MagicLinkSignInParams{Token: linkToken(t, msgs[0].Body)}
+
+	_, err = authService.SignInWithMagicLink(ctx, params)
+	assert.ErrorIs(t, err, service.ErrInvalidToken, "link should be rejected on another device")
+
+	params.Nonce = nonce
+	id, err := authService.SignInWithMagicLink(ctx, params)
+	assert.NoError(t, err, "sign in with magic link should not return an error")
+	assert.Equal(t, user.ID, id, "ID should match")
+
+	_, err = authService.SignInWithMagicLink(ctx, params)
+	assert.ErrorIs(t, err, service.ErrInvalidToken, "link should be single use")
+
+	verified, err := users.FindUserByID(ctx, user.ID)
+	assert.NoError(t, err, "find user should not return an error")
+	assert.NotNil(t, verified.EmailVerifiedAt, "email should be verified")
+}
+
+func TestAuthService_Integration_ChangeCredentials(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	mailer := &mail.MemoryMailer{}
+	authService := service.NewAuthService(repo.NewUserRepo(conn), &security.Argon2Hasher{},
+		service.WithEmailVerification(repo.NewUserTokenRepo(conn), mailer, testVerificationConfig))
+	ctx := context.Background()
+
+	user, err := authService.SignUpUser(ctx, newSignUpParams())
+	assert.NoError(t, err, "signup should not return an error")
+	msgs := mailer.Messages()
+	if !assert.Len(t, msgs, 1, "one email should be sent") {
+		return
+	}
+	signUpToken := linkToken(t, msgs[0].Body)
+
+	updated, err := authService.ChangeEmail(ctx, user, model.ChangeEmailParams{CurrentPassword: testPassword, Email: newEmail})
+	assert.NoError(t, err, "change email should not return an error")
+	assert.Equal(t, newEmail, updated.Email, "email should match")
+
+	assert.ErrorIs(t, authService.VerifyEmail(ctx, signUpToken), service.ErrInvalidToken,
+		"links sent to the old address should be rejected")
+	msgs = mailer.Messages()
+	if assert.Len(t, msgs, 2, "the new address should be sent a link") {
+		assert.NoError(t, authService.VerifyEmail(ctx, linkToken(t, msgs[1].Body)), "verify email should not return an error")
+	}
+
+	err = authService.ChangePassword(ctx, updated, model.ChangePasswordParams{
+		CurrentPassword: testPassword, Password: newPassword, PasswordConfirm: newPassword,
+	})
+	assert.NoError(t, err, "change password should not return an error")
+
+	_, err = authService.SignInUser(ctx, model.UserSignInParams{Email: newEmail, Password: newPassword})
+	assert.NoError(t, err, "new credentials should be accepted")
+}
+
+func TestAuthService_Integration_RehashOnSignIn(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	userRepo := repo.NewUserRepo(conn)
+	ctx := context.Background()
+	weak := security.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16}
+	strong := security.Argon2Params{Memory: 128, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	signIn := model.UserSignInParams{Email: testEmail, Password: testPassword}
+
+	_, err := service.NewAuthService(userRepo, &security.Argon2Hasher{Params: weak}).SignUpUser(ctx, newSignUpParams())
+	assert.NoError(t, err, "signup should not return an error")
+
+	hasher := &security.Argon2Hasher{Params: strong}
+	authService := service.NewAuthService(userRepo, hasher)
+
+	_, err = authService.SignInUser(ctx, model.UserSignInParams{Email: testEmail, Password: "wrong"})
+	assert.ErrorIs(t, err, service.ErrPasswordMismatch, "wrong password should be rejected")
+	user, err := userRepo.FindUserByEmail(ctx, testEmail)
+	assert.NoError(t, err, "find user should not return an error")
+	assert.True(t, hasher.NeedsRehash(user.PasswordHash), "a failed sign-in should not rehash")
+
+	_, err = authService.SignInUser(ctx, signIn)
+	assert.NoError(t, err, "signin should not return an error")
+	user, err = userRepo.FindUserByEmail(ctx, testEmail)
+	assert.NoError(t, err, "find user should not return an error")
+	assert.False(t, hasher.NeedsRehash(user.PasswordHash), "the hash should be upgraded")
+
+	_, err = authService.SignInUser(ctx, signIn)
+	assert.NoError(t, err, "the upgraded hash should be accepted")
+}
+
+func TestAuthService_Integration_UpgradeLegacyHash(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	userRepo := repo.NewUserRepo(conn)
+	ctx := context.Background()
+	hasher := security.NewMultiHasher(&security.Argon2Hasher{})
+	authService := service.NewAuthService(userRepo, hasher)
+
+	legacyHash, err := (&security.PBKDF2Hasher{Iterations: 1000}).Hash(testPassword)
+	assert.NoError(t, err, "hash should not return an error")
+	_, err = userRepo.CreateUser(ctx, model.User{Email: testEmail, PasswordHash: legacyHash})
+	assert.NoError(t, err, "create user should not return an error")
+
+	_, err = authService.SignInUser(ctx, model.UserSignInParams{Email: testEmail, Password: testPassword})
+	assert.NoError(t, err, "legacy hash should be accepted")
+
+	user, err := userRepo.FindUserByEmail(ctx, testEmail)
+	assert.NoError(t, err, "find user should not return an error")
+	assert.True(t, strings.HasPrefix(user.PasswordHash, "$argon2id$"), "the hash should be upgraded to Argon2id")
+
+	_, err = authService.SignInUser(ctx, model.UserSignInParams{Email: testEmail, Password: testPassword})
+	assert.NoError(t, err, "the upgraded hash should be accepted")
+}
+
+func TestAuthService_Integration_RotatePepper(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	userRepo := repo.NewUserRepo(conn)
+	ctx := context.Background()
+	oldPepper := security.Pepper{ID: "1", Key: []byte(strings.Repeat("a", 32))}
+	newPepper := security.Pepper{ID: "2", Key: []byte(strings.Repeat("b", 32))}
+	signIn := model.UserSignInParams{Email: testEmail, Password: testPassword}
+
+	oldHasher := &security.Argon2Hasher{Peppers: []security.Pepper{oldPepper}}
+	_, err := service.NewAuthService(userRepo, oldHasher).SignUpUser(ctx, newSignUpParams())
+	assert.NoError(t, err, "signup should not return an error")
+
+	rotating := &security.Argon2Hasher{Peppers: []security.Pepper{newPepper, oldPepper}}
+	_, err = service.NewAuthService(userRepo, rotating).SignInUser(ctx, signIn)
+	assert.NoError(t, err, "the old pepper should still be accepted")
+
+	user, err := userRepo.FindUserByEmail(ctx, testEmail)
+	assert.NoError(t, err, "find user should not return an error")
+	assert.False(t, rotating.NeedsRehash(user.PasswordHash), "the hash should use the new pepper")
+
+	rotated := &security.Argon2Hasher{Peppers: []security.Pepper{newPepper}}
+	_, err = service.NewAuthService(userRepo, rotated).SignInUser(ctx, signIn)
+	assert.NoError(t, err, "the old pepper should no longer be needed")
+}
+
+func TestAuthService_Integration_TOTP(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	authService := service.NewAuthService(repo.NewUserRepo(conn), &security.Argon2Hasher{},
+		service.WithMFA(repo.NewTOTPRepo(conn), repo.NewRecoveryCodeRepo(conn), repo.NewMFAChallengeRepo(conn),
+			testMFAConfig),
+		service.WithLockout(repo.NewLoginAttemptStore(conn), testLockoutConfig))
+	ctx := context.Background()
+	signIn := model.UserSignInParams{Email: testEmail, Password: testPassword}
+
+	user, err := authService.SignUpUser(ctx, newSignUpParams())
+	assert.NoError(t, err, "signup should not return an error")
+
+	enrollment, err := authService.EnrollTOTP(ctx, user, model.CurrentPasswordParams{CurrentPassword: testPassword})
+	assert.NoError(t, err, "enroll totp should not return an error")
+	secret, err := totp.DecodeSecret(enrollment.Secret)
+	assert.NoError(t, err, "secret should decode")
+
+	_, err = authService.SignInUser(ctx, signIn)
+	assert.NoError(t, err, "an unconfirmed enrollment should not require a code")
+
+	step := totp.Step(time.Now())
+	codes, err := authService.ConfirmTOTP(ctx, user, model.TOTPCodeParams{Code: totp.Code(secret, step)})
+	assert.NoError(t, err, "confirm totp should not return an error")
+
+	_, err = authService.SignInUser(ctx, signIn)
+	var mfaErr *service.MFARequiredError
+	if !assert.ErrorAs(t, err, &mfaErr, "signin should require a code") {
+		return
+	}
+	token := mfaErr.Challenge.Token
+
+	_, err = authService.CompleteMFASignIn(ctx, model.MFASignInParams{Token: token, Code: totp.Code(secret, step)})
+	assert.ErrorIs(t, err, service.ErrInvalidMFACode, "the confirmation code should not be accepted again")
+
+	id, err := authService.CompleteMFASignIn(ctx, model.MFASignInParams{Token: token, Code: totp.Code(secret, step+1)})
+	assert.NoError(t, err, "complete mfa signin should not return an error")
+	assert.Equal(t, user.ID, id, "ID should match")
+
+	_, err = authService.CompleteMFASignIn(ctx, model.MFASignInParams{Token: token, Code: codes.Codes[0]})
+	assert.ErrorIs(t, err, service.ErrInvalidToken, "a challenge should only complete one signin")
+
+	err = authService.DisableTOTP(ctx, user, model.MFACodeParams{CurrentPassword: testPassword, Code: codes.Codes[0]})
+	assert.NoError(t, err, "disable totp should not return an error")
+
+	id, err = authService.SignInUser(ctx, signIn)
+	assert.NoError(t, err, "signin should not require a code once disabled")
+	assert.Equal(t, user.ID, id, "ID should match")
+}
+
+func TestAuthService_Integration_TOTPLockout(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	authService := service.NewAuthService(repo.NewUserRepo(conn), &security.Argon2Hasher{},
+		service.WithMFA(repo.NewTOTPRepo(conn), repo.NewRecoveryCodeRepo(conn), repo.NewMFAChallengeRepo(conn),
+			testMFAConfig),
+		service.WithLockout(repo.NewLoginAttemptStore(conn), testLockoutConfig))
+	ctx := context.Background()
+	signIn := model.UserSignInParams{Email: testEmail, Password: testPassword}
+
+	user, err := authService.SignUpUser(ctx, newSignUpParams())
+	assert.NoError(t, err, "signup should not return an error")
+	enrollment, err := authService.EnrollTOTP(ctx, user, model.CurrentPasswordParams{CurrentPassword: testPassword})
+	assert.NoError(t, err, "enroll totp should not return an error")
+	secret, err := totp.DecodeSecret(enrollment.Secret)
+	assert.NoError(t, err, "secret should decode")
+	_, err = authService.ConfirmTOTP(ctx, user, model.TOTPCodeParams{Code: totp.Code(secret, totp.Step(time.Now()))})
+	assert.NoError(t, err, "confirm totp should not return an error")
+
+	// Wrong codes count towards the account lockout across challenges.
+	for range testLockoutConfig.AccountThreshold {
+		var mfaErr *service.MFARequiredError
+		_, err = authService.SignInUser(ctx, signIn)
+		if !assert.ErrorAs(t, err, &mfaErr, "signin should require a code") {
+			return
+		}
+
+		_, err = authService.CompleteMFASignIn(ctx, model.MFASignInParams{Token: mfaErr.Challenge.Token, Code: "zzzzz-zzzzz"})
+		assert.ErrorIs(t, err, service.ErrInvalidMFACode, "wrong codes should be rejected")
+	}
+
+	_, err = authService.SignInUser(ctx, signIn)
+	assert.ErrorIs(t, err, service.ErrTooManyAttempts, "the account should be locked")
+}
+
+func TestAuthService_Integration_Passkeys(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	authService := service.NewAuthService(repo.NewUserRepo(conn), &security.Argon2Hasher{},
+		service.WithPasskeys(repo.NewPasskeyRepo(conn), repo.NewWebAuthnChallengeRepo(conn), testPasskeyConfig))
+	ctx := context.Background()
+	auth := webauthntest.New(t)
+	auth.Attestation = webauthntest.AttestationBasic
+
+	user, err := authService.SignUpUser(ctx, newSignUpParams())
+	assert.NoError(t, err, "signup should not return an error")
+
+	creation, err := authService.BeginPasskeyRegistration(ctx, user, model.CurrentPasswordParams{CurrentPassword: testPassword})
+	assert.NoError(t, err, "begin passkey registration should not return an error")
+	registration := model.PasskeyRegistrationParams{Credential: auth.Register(t, creation)}
+
+	passkey, err := authService.FinishPasskeyRegistration(ctx, user, registration)
+	assert.NoError(t, err, "finish passkey registration should not return an error")
+
+	_, err = authService.FinishPasskeyRegistration(ctx, user, registration)
+	assert.ErrorIs(t, err, service.ErrInvalidPasskey, "a challenge should only register one passkey")
+
+	passkeys, err := authService.ListPasskeys(ctx, user)
+	assert.NoError(t, err, "list passkeys should not return an error")
+	assert.Len(t, passkeys, 1, "the passkey should be listed")
+
+	request, err := authService.BeginPasskeySignIn(ctx)
+	assert.NoError(t, err, "begin passkey signin should not return an error")
+	signIn := model.PasskeySignInParams{Credential: auth.Login(t, request)}
+
+	id, err := authService.FinishPasskeySignIn(ctx, signIn)
+	assert.NoError(t, err, "passkey signin should not return an error")
+	assert.Equal(t, user.ID, id, "ID should match")
+
+	_, err = authService.FinishPasskeySignIn(ctx, signIn)
+	assert.ErrorIs(t, err, service.ErrInvalidPasskey, "an assertion should only sign in once")
+
+	assert.NoError(t, authService.DeletePasskey(ctx, user, passkey.ID), "delete passkey should not return an error")
+
+	request, err = authService.BeginPasskeySignIn(ctx)
+	assert.NoError(t, err, "begin passkey signin should not return an error")
+	_, err = authService.FinishPasskeySignIn(ctx, model.PasskeySignInParams{Credential: auth.Login(t, request)})
+	assert.ErrorIs(t, err, service.ErrInvalidPasskey, "a deleted passkey should not sign in")
+}
+
+func TestAuthService_Integration_OIDC(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	idp := oidctest.New(t, testRedirectURL)
+	authService := service.NewAuthService(repo.NewUserRepo(conn), &security.Argon2Hasher{},
+		service.WithOIDC(repo.NewIdentityRepo(conn), repo.NewOIDCStateRepo(conn), newTestProviders(idp), testOIDCConfig))
+	ctx := context.Background()
+
+	signIn := func() (string, error) {
+		t.Helper()
+		auth, err := authService.BeginOIDCSignIn(ctx, testProvider)
+		if err != nil {
+			t.Fatalf("begin oidc signin: %v", err)
+		}
+
+		callback := idp.Authorize(t, auth.URL)
+		params := model.OIDCCallbackParams{State: callback.Get("state"), Code: callback.Get("code")}
+
+		id, err := authService.FinishOIDCSignIn(ctx, testProvider, params)
+		if err == nil {
+			_, replayErr := authService.FinishOIDCSignIn(ctx, testProvider, params)
+			assert.ErrorIs(t, replayErr, service.ErrInvalidOIDCSignIn, "a callback should only sign in once")
+		}
+
+		return id, err
+	}
+
+	id, err := signIn()
+	assert.NoError(t, err, "first oidc signin should not return an error")
+
+	again, err := signIn()
+	assert.NoError(t, err, "second oidc signin should not return an error")
+	assert.Equal(t, id, again, "the linked user should sign in again")
+
+	user := &model.User{ID: id, Email: idp.Email}
+	identities, err := authService.ListIdentities(ctx, user)
+	assert.NoError(t, err, "list identities should not return an error")
+	assert.Len(t, identities, 1, "the identity should be listed")
+
+	_, err = authService.SignInUser(ctx, model.UserSignInParams{Email: idp.Email, Password: testPassword})
+	assert.ErrorIs(t, err, service.ErrPasswordMismatch, "users created by a provider should have no password")
+
+	idp.Subject = "another-account"
+	idp.Email = testEmail
+	_, err = authService.SignUpUser(ctx, newSignUpParams())
+	assert.NoError(t, err, "signup should not return an error")
+
+	_, err = signIn()
+	assert.ErrorIs(t, err, service.ErrIdentityNotLinkable, "unverified accounts should not be linked")
+}
+
+func TestOAuthService_Integration_AuthorizationCode(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	users := repo.NewUserRepo(conn)
+	tokens := repo.NewOAuthTokenRepo(conn)
+	oauthService := service.NewOAuthService(users, repo.NewOAuthClientRepo(conn), repo.NewOAuthCodeRepo(conn),
+		tokens, newOAuthSigner(t), testOAuthConfig)
+	ctx := context.Background()
+
+	user, err := users.CreateUser(ctx, model.User{Email: testEmail, PasswordHash: "hash"})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	reg, err := oauthService.RegisterClient(ctx, model.OAuthClientParams{
+		Name:         "Example",
+		RedirectURIs: []string{testClientRedirect},
+		Scope:        "profile",
+	})
+	assert.NoError(t, err, "register client should not return an error")
+	creds := model.OAuthClientCredentials{ID: reg.ClientID, Secret: reg.ClientSecret}
+
+	params := testAuthorizeParams()
+	params.ClientID = reg.ClientID
+	_, err = oauthService.Authorize(ctx, params)
+	assert.NoError(t, err, "authorize should not return an error")
+
+	redirect, err := oauthService.Decide(ctx, user, model.OAuthDecisionParams{OAuthAuthorizeParams: params, Approve: true})
+	assert.NoError(t, err, "decide should not return an error")
+
+	tokenParams := testTokenParams()
+	tokenParams.Code = redirectQuery(t, redirect.RedirectURI, testClientRedirect).Get("code")
+	res, err := oauthService.Token(ctx, creds, tokenParams)
+	assert.NoError(t, err, "token should not return an error")
+
+	info, err := oauthService.Introspect(ctx, creds, res.AccessToken)
+	assert.NoError(t, err, "introspect should not return an error")
+	assert.True(t, info.Active, "token should be active")
+	assert.Equal(t, testEmail, info.Username, "username should be the user's email")
+
+	_, err = oauthService.Token(ctx, creds, tokenParams)
+	assertOAuthError(t, err, service.OAuthInvalidGrant)
+
+	info, err = oauthService.Introspect(ctx, creds, res.AccessToken)
+	assert.NoError(t, err, "introspect should not return an error")
+	assert.False(t, info.Active, "tokens of a replayed code should be revoked")
+}
+
+func TestOAuthService_Integration_Revoke(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	oauthService := service.NewOAuthService(repo.NewUserRepo(conn), repo.NewOAuthClientRepo(conn),
+		repo.NewOAuthCodeRepo(conn), repo.NewOAuthTokenRepo(conn), newOAuthSigner(t), testOAuthConfig)
+	ctx := context.Background()
+
+	reg, err := oauthService.RegisterClient(ctx, model.OAuthClientParams{
+		Name:       "Service",
+		GrantTypes: []string{service.GrantClientCredentials},
+	})
+	assert.NoError(t, err, "register client should not return an error")
+	creds := model.OAuthClientCredentials{ID: reg.ClientID, Secret: reg.ClientSecret}
+
+	res, err := oauthService.Token(ctx, creds, model.OAuthTokenParams{GrantType: service.GrantClientCredentials})
+	assert.NoError(t, err, "token should not return an error")
+
+	assert.NoError(t, oauthService.Revoke(ctx, creds, res.AccessToken), "revoke should not return an error")
+
+	info, err := oauthService.Introspect(ctx, creds, res.AccessToken)
+	assert.NoError(t, err, "introspect should not return an error")
+	assert.False(t, info.Active, "revoked tokens should be inactive")
+}
+
+func TestAuthorizer_Integration_Roles(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	users := repo.NewUserRepo(conn)
+	roleRepo := repo.NewRoleRepo(conn)
+	roleService := service.NewRoleService(roleRepo, users)
+	authorizer := service.NewAuthorizer(roleRepo)
+	ctx := context.Background()
+
+	user, err := service.NewAuthService(users, &security.Argon2Hasher{}).SignUpUser(ctx, newSignUpParams())
+	assert.NoError(t, err, "signup should not return an error")
+
+	allowed, err := authorizer.Can(ctx, user, service.ActionRead, service.ResourceRoles)
+	assert.NoError(t, err, "can should not return an error")
+	assert.False(t, allowed, "users without roles should be denied")
+
+	_, err = roleService.CreateRole(ctx, model.RoleParams{
+		Name:        testRole,
+		Permissions: []model.Permission{{Action: service.ActionRead, Resource: service.ResourceRoles}},
+	})
+	assert.NoError(t, err, "create role should not return an error")
+
+	_, err = roleService.AssignRole(ctx, user.ID, testRole)
+	assert.NoError(t, err, "assign role should not return an error")
+
+	allowed, err = authorizer.Can(ctx, user, service.ActionRead, service.ResourceRoles)
+	assert.NoError(t, err, "can should not return an error")
+	assert.True(t, allowed, "granted permission should be allowed")
+
+	allowed, err = authorizer.Can(ctx, user, service.ActionWrite, service.ResourceRoles)
+	assert.NoError(t, err, "can should not return an error")
+	assert.False(t, allowed, "other permissions should be denied")
+
+	assigned, err := roleService.AssignRole(ctx, user.ID, service.RoleAdmin)
+	assert.NoError(t, err, "assign role should not return an error")
+	assert.Equal(t, []string{service.RoleAdmin, testRole}, assigned.Roles, "roles should match")
+
+	allowed, err = authorizer.Can(ctx, user, service.ActionWrite, service.ResourceRoles)
+	assert.NoError(t, err, "can should not return an error")
+	assert.True(t, allowed, "admin should be allowed everything")
+}
+
+func TestMembershipService_Integration_Invitation(t *testing.T) {
+	conn, _ := dbtest.New(t)
+	users := repo.NewUserRepo(conn)
+	roleRepo := repo.NewRoleRepo(conn)
+	mailer := &mail.MemoryMailer{}
+	hasher := &security.Argon2Hasher{}
+	orgService := service.NewOrganizationService(repo.NewOrganizationRepo(conn))
+	authorizer := service.NewAuthorizer(roleRepo)
+	memberships := service.NewMembershipService(repo.NewMembershipRepo(conn), repo.NewInvitationRepo(conn), roleRepo,
+		users, authorizer, hasher, nil, mailer, service.InvitationConfig{BaseURL: testBaseURL, TTL: time.Hour})
+	ctx := context.Background()
+
+	owner, err := service.NewAuthService(users, hasher).SignUpUser(ctx, newSignUpParams())
+	assert.NoError(t, err, "signup should not return an error")
+
+	org, err := orgService.CreateOrganization(ctx, owner, model.OrganizationParams{Name: "Acme"})
+	assert.NoError(t, err, "create organization should not return an error")
+
+	orgCtx := service.ContextWithUser(service.ContextWithOrganization(ctx, org), owner)
+	_, err = memberships.Invite(orgCtx, model.InvitationParams{Email: "invitee@example.com", Role: service.RoleMember})
+	assert.NoError(t, err, "invite should not return an error")
+
+	msgs := mailer.Messages()
+	if !assert.Len(t, msgs, 1, "one email should be sent") {
+		return
+	}
+	token := linkToken(t, msgs[0].Body)
+
+	_, err = memberships.AcceptInvitation(ctx, model.AcceptInvitationParams{Token: token})
+	assert.ErrorIs(t, err, service.ErrPasswordRequired, "new users should need a password")
+
+	member, err := memberships.AcceptInvitation(ctx, model.AcceptInvitationParams{Token: token, Password: testPassword})
+	assert.NoError(t, err, "accept invitation should not return an error")
+	assert.Equal(t, service.RoleMember, member.Role, "role should match")
+
+	_, err = memberships.AcceptInvitation(ctx, model.AcceptInvitationParams{Token: token, Password: testPassword})
+	assert.ErrorIs(t, err, service.ErrInvalidToken, "invitations should only be accepted once")
+
+	invitee, err := users.FindUserByEmail(ctx, "invitee@example.com")
+	assert.NoError(t, err, "the invited user should be created")
+	assert.NotNil(t, invitee.EmailVerifiedAt, "the invited email should be verified")
+
+	allowed, err := authorizer.Can(orgCtx, invitee, service.ActionRead, service.ResourceMembers)
+	assert.NoError(t, err, "can should not return an error")
+	assert.True(t, allowed, "members should read the members")
+
+	allowed, err = authorizer.Can(ctx, invitee, service.ActionRead, service.ResourceMembers)
+	assert.NoError(t, err, "can should not return an error")
+	assert.False(t, allowed, "member roles should not apply outside the organization")
+
+	allowed, err = authorizer.Can(orgCtx, invitee, service.ActionWrite, service.ResourceMembers)
+	assert.NoError(t, err, "can should not return an error")
+	assert.False(t, allowed, "members should not manage the members")
+
+	_, err = memberships.Invite(orgCtx, model.InvitationParams{Email: "invitee@example.com", Role: service.RoleMember})
+	assert.ErrorIs(t, err, service.ErrAlreadyMember, "members should not be invited again")
+
+	inviteeCtx := service.ContextWithUser(service.ContextWithOrganization(ctx, org), invitee)
+	_, err = memberships.UpdateMemberRole(inviteeCtx, invitee.ID, model.MembershipParams{Role: service.RoleOwner})
+	assert.ErrorIs(t, err, service.ErrRoleNotGrantable, "members should not make themselves owners")
+
+	err = memberships.RemoveMember(orgCtx, owner.ID)
+	assert.ErrorIs(t, err, service.ErrLastOwner, "the last owner should not be removed")
+
+	list, err := memberships.ListMembers(orgCtx)
+	assert.NoError(t, err, "list members should not return an error")
+	assert.Len(t, list, 2, "members should be listed")
+}
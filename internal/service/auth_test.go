@@ -2,15 +2,18 @@ package service_test
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
 	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
 	"github.com/ferdiebergado/fullstackgo/internal/service"
 	"github.com/stretchr/testify/assert"
 
 	"go.uber.org/mock/gomock"
 
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
 	secMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/security/mocks"
 	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
 )
@@ -91,6 +94,36 @@ func TestAuthService_SignUpUser_Duplicate(t *testing.T) {
 	assert.Nil(t, user, "user should be nil")
 }
 
+func TestAuthService_SignUpUser_NoRowsIsNotTaken(t *testing.T) {
+	mockRepo, mockHasher, authService := setupMocks(t)
+	ctx := context.Background()
+	signUpParams := newSignUpParams()
+
+	mockRepo.EXPECT().FindUserByEmail(ctx, signUpParams.Email).Return(nil, sql.ErrNoRows)
+	mockHasher.EXPECT().Hash(signUpParams.Password).Return(hashedPassword, nil)
+	mockRepo.EXPECT().CreateUser(ctx, gomock.Any()).Return(&model.User{ID: testID, Email: testEmail}, nil)
+
+	user, err := authService.SignUpUser(ctx, signUpParams)
+
+	assert.NoError(t, err, "signup should not return an error")
+	assert.Equal(t, testID, user.ID, "ID should match")
+}
+
+func TestAuthService_SignUpUser_ConcurrentDuplicate(t *testing.T) {
+	mockRepo, mockHasher, authService := setupMocks(t)
+	ctx := context.Background()
+	signUpParams := newSignUpParams()
+
+	mockRepo.EXPECT().FindUserByEmail(ctx, signUpParams.Email).Return(nil, sql.ErrNoRows)
+	mockHasher.EXPECT().Hash(signUpParams.Password).Return(hashedPassword, nil)
+	mockRepo.EXPECT().CreateUser(ctx, gomock.Any()).Return(nil, repo.ErrDuplicate)
+
+	user, err := authService.SignUpUser(ctx, signUpParams)
+
+	assert.ErrorIs(t, err, service.ErrEmailTaken, "errors should match")
+	assert.Nil(t, user, "user should be nil")
+}
+
 func TestAuthService_SignInUser_Success(t *testing.T) {
 	ctx := context.Background()
 	input := model.UserSignInParams{
@@ -127,6 +160,23 @@ func TestAuthService_SignInUser_NotFound(t *testing.T) {
 	assert.Zero(t, id, "ID should be empty")
 }
 
+func TestAuthService_SignInUser_HasherBusy(t *testing.T) {
+	ctx := context.Background()
+	input := model.UserSignInParams{
+		Email:    testEmail,
+		Password: testPassword,
+	}
+	mockRepo, mockHasher, authService := setupMocks(t)
+	mockRepo.EXPECT().FindUserByEmail(ctx, input.Email).Return(&model.User{
+		ID:           testID,
+		PasswordHash: hashedPassword,
+	}, nil)
+	mockHasher.EXPECT().Verify(input.Password, hashedPassword).Return(false, security.ErrHasherBusy)
+
+	_, err := authService.SignInUser(ctx, input)
+	assert.ErrorIs(t, err, security.ErrHasherBusy, "the busy error should be wrapped")
+}
+
 func setupMocks(t *testing.T) (*repoMocks.MockUserRepo, *secMocks.MockHasher, service.AuthService) {
 	t.Helper()
 	ctrl := gomock.NewController(t)
@@ -144,3 +194,27 @@ func newSignUpParams() model.UserSignUpParams {
 		PasswordConfirm: testPassword,
 	}
 }
+
+// taskQueue holds the work an AuthService moves off the request path until
+// the test runs it.
+type taskQueue struct {
+	tasks []func()
+}
+
+func (q *taskQueue) add(task func()) {
+	q.tasks = append(q.tasks, task)
+}
+
+func (q *taskQueue) run() {
+	tasks := q.tasks
+	q.tasks = nil
+	for _, task := range tasks {
+		task()
+	}
+}
+
+// runNow runs background work before the request returns, so that
+// integration tests can read the emails it sends.
+func runNow(task func()) {
+	task()
+}
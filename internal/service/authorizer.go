@@ -0,0 +1,134 @@
+//go:generate mockgen -destination=mocks/authorizer_mock.go -package=mocks . Authorizer
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+)
+
+// Actions and resources checked by the app itself. PermissionAny in a
+// permission matches any action or resource.
+const (
+	PermissionAny        = "*"
+	ActionRead           = "read"
+	ActionWrite          = "write"
+	ResourceRoles        = "roles"
+	ResourceOrganization = "organization"
+	ResourceMembers      = "members"
+	ResourceInvitations  = "invitations"
+	// RoleAdmin is created by the migrations with every permission.
+	RoleAdmin = "admin"
+	// RoleOwner and RoleMember are created by the migrations for members of
+	// organizations. Owners manage the organization, its members and
+	// invitations, and members may read the organization and its members.
+	RoleOwner  = "owner"
+	RoleMember = "member"
+)
+
+// Authorizer decides what users may do from the permissions of their roles.
+// In a context scoped to an organization, the user's role in it counts too.
+type Authorizer interface {
+	// Can reports whether one of the user's roles allows action on resource.
+	Can(ctx context.Context, user *model.User, action, resource string) (bool, error)
+}
+
+type authorizer struct {
+	roles repo.RoleRepo
+}
+
+func NewAuthorizer(roles repo.RoleRepo) Authorizer {
+	return &authorizer{
+		roles: roles,
+	}
+}
+
+func (a *authorizer) Can(ctx context.Context, user *model.User, action, resource string) (bool, error) {
+	if user == nil {
+		return false, nil
+	}
+
+	perms, err := a.permissions(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, perm := range perms {
+		if permits(perm, action, resource) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// permissions returns the user's effective permissions, from the cache in
+// ctx if there is one. They include those of the user's role in the
+// organization ctx is scoped to.
+func (a *authorizer) permissions(ctx context.Context, userID string) ([]model.Permission, error) {
+	key := userID
+	org, scoped := OrganizationFromContext(ctx)
+	if scoped {
+		key = org.ID + "/" + userID
+	}
+
+	cache, cached := ctx.Value(permissionsCtxKey).(*permissionCache)
+	if cached {
+		if perms, ok := cache.get(key); ok {
+			return perms, nil
+		}
+	}
+
+	perms, err := a.roles.ListUserPermissions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user permissions: %w", err)
+	}
+
+	if scoped {
+		memberPerms, err := a.roles.ListMemberPermissions(ctx, org.ID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("list member permissions: %w", err)
+		}
+
+		perms = append(perms, memberPerms...)
+	}
+
+	if cached {
+		cache.set(key, perms)
+	}
+
+	return perms, nil
+}
+
+func permits(perm model.Permission, action, resource string) bool {
+	return (perm.Action == PermissionAny || perm.Action == action) &&
+		(perm.Resource == PermissionAny || perm.Resource == resource)
+}
+
+// permissionCache holds the permissions loaded during a request, by user ID
+// and organization.
+type permissionCache struct {
+	mu    sync.Mutex
+	perms map[string][]model.Permission
+}
+
+func (c *permissionCache) get(userID string) ([]model.Permission, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	perms, ok := c.perms[userID]
+	return perms, ok
+}
+
+func (c *permissionCache) set(userID string, perms []model.Permission) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.perms == nil {
+		c.perms = make(map[string][]model.Permission)
+	}
+	c.perms[userID] = perms
+}
@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+)
+
+func TestAuthorizer_Can(t *testing.T) {
+	perms := []model.Permission{
+		{Action: service.ActionRead, Resource: "posts"},
+		{Action: service.PermissionAny, Resource: "comments"},
+	}
+
+	tests := []struct {
+		name     string
+		action   string
+		resource string
+		allowed  bool
+	}{
+		{"granted permission should be allowed", service.ActionRead, "posts", true},
+		{"other action should be denied", service.ActionWrite, "posts", false},
+		{"any action should match", service.ActionWrite, "comments", true},
+		{"other resource should be denied", service.ActionRead, service.ResourceRoles, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles, authorizer := setupAuthorizer(t)
+			ctx := context.Background()
+			roles.EXPECT().ListUserPermissions(ctx, testID).Return(perms, nil)
+
+			allowed, err := authorizer.Can(ctx, &model.User{ID: testID}, tt.action, tt.resource)
+
+			assert.NoError(t, err, "can should not return an error")
+			assert.Equal(t, tt.allowed, allowed, "decision should match")
+		})
+	}
+}
+
+func TestAuthorizer_Can_Admin(t *testing.T) {
+	roles, authorizer := setupAuthorizer(t)
+	ctx := context.Background()
+	roles.EXPECT().ListUserPermissions(ctx, testID).
+		Return([]model.Permission{{Action: service.PermissionAny, Resource: service.PermissionAny}}, nil)
+
+	allowed, err := authorizer.Can(ctx, &model.User{ID: testID}, service.ActionWrite, service.ResourceRoles)
+
+	assert.NoError(t, err, "can should not return an error")
+	assert.True(t, allowed, "admin should be allowed everything")
+}
+
+func TestAuthorizer_Can_CachesPerRequest(t *testing.T) {
+	roles, authorizer := setupAuthorizer(t)
+	user := &model.User{ID: testID}
+	perms := []model.Permission{{Action: service.ActionRead, Resource: service.ResourceRoles}}
+
+	ctx := service.ContextWithPermissionCache(context.Background())
+	roles.EXPECT().ListUserPermissions(ctx, testID).Return(perms, nil).Times(1)
+
+	for range 3 {
+		allowed, err := authorizer.Can(ctx, user, service.ActionRead, service.ResourceRoles)
+		assert.NoError(t, err, "can should not return an error")
+		assert.True(t, allowed, "permission should be allowed")
+	}
+
+	next := service.ContextWithPermissionCache(context.Background())
+	roles.EXPECT().ListUserPermissions(next, testID).Return(nil, nil).Times(1)
+
+	allowed, err := authorizer.Can(next, user, service.ActionRead, service.ResourceRoles)
+	assert.NoError(t, err, "can should not return an error")
+	assert.False(t, allowed, "permissions should be reloaded for the next request")
+}
+
+func TestAuthorizer_Can_Organization(t *testing.T) {
+	roles, authorizer := setupAuthorizer(t)
+	user := &model.User{ID: testID}
+	base := service.ContextWithPermissionCache(context.Background())
+	acme := service.ContextWithOrganization(base, &model.Organization{ID: "acme"})
+	globex := service.ContextWithOrganization(base, &model.Organization{ID: "globex"})
+
+	roles.EXPECT().ListUserPermissions(gomock.Any(), testID).Return(nil, nil).Times(2)
+	roles.EXPECT().ListMemberPermissions(acme, "acme", testID).
+		Return([]model.Permission{{Action: service.ActionRead, Resource: service.ResourceMembers}}, nil).Times(1)
+	roles.EXPECT().ListMemberPermissions(globex, "globex", testID).Return(nil, nil).Times(1)
+
+	for range 2 {
+		allowed, err := authorizer.Can(acme, user, service.ActionRead, service.ResourceMembers)
+		assert.NoError(t, err, "can should not return an error")
+		assert.True(t, allowed, "the member role should be allowed in its organization")
+	}
+
+	allowed, err := authorizer.Can(globex, user, service.ActionRead, service.ResourceMembers)
+	assert.NoError(t, err, "can should not return an error")
+	assert.False(t, allowed, "the member role should not be allowed in other organizations")
+}
+
+func TestAuthorizer_Can_Errors(t *testing.T) {
+	roles, authorizer := setupAuthorizer(t)
+	ctx := context.Background()
+	dbErr := errors.New("connection lost")
+
+	allowed, err := authorizer.Can(ctx, nil, service.ActionRead, service.ResourceRoles)
+	assert.NoError(t, err, "anonymous users should not cause an error")
+	assert.False(t, allowed, "anonymous users should be denied")
+
+	roles.EXPECT().ListUserPermissions(ctx, testID).Return(nil, dbErr)
+
+	allowed, err = authorizer.Can(ctx, &model.User{ID: testID}, service.ActionRead, service.ResourceRoles)
+	assert.ErrorIs(t, err, dbErr, "errors should match")
+	assert.False(t, allowed, "failed checks should be denied")
+}
+
+func setupAuthorizer(t *testing.T) (*repoMocks.MockRoleRepo, service.Authorizer) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	roles := repoMocks.NewMockRoleRepo(ctrl)
+	return roles, service.NewAuthorizer(roles)
+}
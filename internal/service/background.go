@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithBackground sets how the service runs work it moves off the request
+// path. By default each task runs in its own goroutine; run may track them,
+// to wait for them on shutdown.
+func WithBackground(run func(task func())) AuthOption {
+	return func(s *authService) {
+		s.background = run
+	}
+}
+
+// runInBackground calls work after the request returns, with a context that
+// keeps the request's values but not its cancellation. Requests that only
+// sometimes send email use it, so that how long they take does not reveal
+// whether there was anything to send. A failure is logged as msg.
+func (s *authService) runInBackground(ctx context.Context, msg string, work func(context.Context) error) {
+	ctx = context.WithoutCancel(ctx)
+	s.background(func() {
+		if err := work(ctx); err != nil {
+			slog.Error(msg, "error", err)
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/password"
+)
+
+// WithBreachCheck rejects new passwords that checker reports as breached.
+func WithBreachCheck(checker password.BreachChecker) AuthOption {
+	return func(s *authService) {
+		s.breaches = checker
+	}
+}
+
+func (s *authService) checkBreached(ctx context.Context, plain string) error {
+	return checkBreached(ctx, s.breaches, plain)
+}
+
+// checkBreached returns ErrPasswordBreached if checker knows plain from a
+// breach, and nil without a checker. A failed lookup is logged and the
+// password allowed, so that an unavailable corpus does not lock users out of
+// setting passwords.
+func checkBreached(ctx context.Context, checker password.BreachChecker, plain string) error {
+	if checker == nil {
+		return nil
+	}
+
+	breached, err := checker.Breached(ctx, plain)
+	if err != nil {
+		slog.Error("check breached password", "error", err)
+		return nil
+	}
+
+	if breached {
+		return ErrPasswordBreached
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	passwordMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/password/mocks"
+	secMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/security/mocks"
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func setupBreachMocks(t *testing.T) (*repoMocks.MockUserRepo, *secMocks.MockHasher, *passwordMocks.MockBreachChecker, service.AuthService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockRepo := repoMocks.NewMockUserRepo(ctrl)
+	mockHasher := secMocks.NewMockHasher(ctrl)
+	mockBreaches := passwordMocks.NewMockBreachChecker(ctrl)
+	authService := service.NewAuthService(mockRepo, mockHasher, service.WithBreachCheck(mockBreaches))
+
+	return mockRepo, mockHasher, mockBreaches, authService
+}
+
+func TestAuthService_SignUpUser_Breached(t *testing.T) {
+	mockRepo, mockHasher, mockBreaches, authService := setupBreachMocks(t)
+	ctx := context.Background()
+	params := newSignUpParams()
+
+	mockRepo.EXPECT().FindUserByEmail(ctx, params.Email).Return(nil, nil)
+	mockBreaches.EXPECT().Breached(ctx, params.Password).Return(true, nil)
+	mockHasher.EXPECT().Hash(gomock.Any()).Times(0)
+	mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := authService.SignUpUser(ctx, params)
+
+	assert.ErrorIs(t, err, service.ErrPasswordBreached, "errors should match")
+}
+
+func TestAuthService_SignUpUser_BreachCheckFailsOpen(t *testing.T) {
+	mockRepo, mockHasher, mockBreaches, authService := setupBreachMocks(t)
+	ctx := context.Background()
+	params := newSignUpParams()
+	now := time.Now().UTC()
+
+	mockRepo.EXPECT().FindUserByEmail(ctx, params.Email).Return(nil, nil)
+	mockBreaches.EXPECT().Breached(ctx, params.Password).Return(false, errors.New("corpus unavailable"))
+	mockHasher.EXPECT().Hash(params.Password).Return(hashedPassword, nil)
+	mockRepo.EXPECT().CreateUser(ctx, gomock.Any()).Return(&model.User{ID: testID, CreatedAt: now, UpdatedAt: now}, nil)
+
+	_, err := authService.SignUpUser(ctx, params)
+
+	assert.NoError(t, err, "a failed lookup should not block signup")
+}
+
+func TestAuthService_ChangePassword_Breached(t *testing.T) {
+	mockRepo, mockHasher, mockBreaches, authService := setupBreachMocks(t)
+	ctx := context.Background()
+	params := model.ChangePasswordParams{CurrentPassword: testPassword, Password: newPassword, PasswordConfirm: newPassword}
+
+	mockRepo.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	mockHasher.EXPECT().Verify(testPassword, hashedPassword).Return(true, nil)
+	mockBreaches.EXPECT().Breached(ctx, newPassword).Return(true, nil)
+	mockRepo.EXPECT().UpdatePasswordHash(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := authService.ChangePassword(ctx, testUser, params)
+
+	assert.ErrorIs(t, err, service.ErrPasswordBreached, "errors should match")
+}
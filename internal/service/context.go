@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+)
+
+type ctxKey int
+
+const (
+	userCtxKey ctxKey = iota
+	sessionCtxKey
+	clientIPCtxKey
+	permissionsCtxKey
+	organizationCtxKey
+)
+
+// ContextWithUser returns a copy of ctx carrying the authenticated user.
+func ContextWithUser(ctx context.Context, user *model.User) context.Context {
+	return context.WithValue(ctx, userCtxKey, user)
+}
+
+// UserFromContext returns the authenticated user stored in ctx, if any.
+func UserFromContext(ctx context.Context) (*model.User, bool) {
+	user, ok := ctx.Value(userCtxKey).(*model.User)
+	return user, ok && user != nil
+}
+
+// ContextWithSession returns a copy of ctx carrying the current session.
+func ContextWithSession(ctx context.Context, session *model.Session) context.Context {
+	return context.WithValue(ctx, sessionCtxKey, session)
+}
+
+// SessionFromContext returns the session stored in ctx, if any.
+func SessionFromContext(ctx context.Context) (*model.Session, bool) {
+	session, ok := ctx.Value(sessionCtxKey).(*model.Session)
+	return session, ok && session != nil
+}
+
+// ContextWithClientIP returns a copy of ctx carrying the IP address of the
+// client that made the request.
+func ContextWithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPCtxKey, ip)
+}
+
+// ClientIPFromContext returns the client IP stored in ctx, if any.
+func ClientIPFromContext(ctx context.Context) (string, bool) {
+	ip, ok := ctx.Value(clientIPCtxKey).(string)
+	return ip, ok && ip != ""
+}
+
+// ContextWithPermissionCache returns a copy of ctx in which the Authorizer
+// remembers the permissions it loads, so that a request checking several
+// permissions reads them once.
+func ContextWithPermissionCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, permissionsCtxKey, &permissionCache{})
+}
+
+// ContextWithOrganization returns a copy of ctx scoped to the organization
+// whose data the request may use.
+func ContextWithOrganization(ctx context.Context, org *model.Organization) context.Context {
+	return context.WithValue(ctx, organizationCtxKey, org)
+}
+
+// OrganizationFromContext returns the organization the request is scoped to,
+// if any.
+func OrganizationFromContext(ctx context.Context) (*model.Organization, bool) {
+	org, ok := ctx.Value(organizationCtxKey).(*model.Organization)
+	return org, ok && org != nil
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+)
+
+// LockoutConfig controls how failed sign-ins are throttled. Once an account
+// or client IP reaches its threshold of consecutive failures, it is locked
+// for BaseDelay, doubling with each further failure up to MaxDelay. Counts
+// are forgotten ResetAfter the last failure.
+type LockoutConfig struct {
+	AccountThreshold int
+	IPThreshold      int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	ResetAfter       time.Duration
+}
+
+// LockoutError is returned while sign-ins are locked. It matches
+// ErrTooManyAttempts with errors.Is.
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("%s: retry after %s", ErrTooManyAttempts, e.RetryAfter)
+}
+
+func (e *LockoutError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
+
+type loginThrottle struct {
+	store repo.LoginAttemptStore
+	cfg   LockoutConfig
+}
+
+// WithLockout throttles repeated failed sign-ins per account and client IP.
+func WithLockout(store repo.LoginAttemptStore, cfg LockoutConfig) AuthOption {
+	return func(s *authService) {
+		s.throttle = &loginThrottle{
+			store: store,
+			cfg:   cfg,
+		}
+	}
+}
+
+type throttleKey struct {
+	id        string
+	threshold int
+
+	// failures and lastFailedAt are the key's count before the attempt, as
+	// found by check.
+	failures     int
+	lastFailedAt time.Time
+}
+
+// keys returns the counters a sign-in attempt is tracked under.
+func (t *loginThrottle) keys(ctx context.Context, email string) []throttleKey {
+	keys := []throttleKey{{id: "email:" + strings.ToLower(email), threshold: t.cfg.AccountThreshold}}
+
+	if ip, ok := ClientIPFromContext(ctx); ok {
+		keys = append(keys, throttleKey{id: "ip:" + ip, threshold: t.cfg.IPThreshold})
+	}
+
+	return keys
+}
+
+// begin counts the attempt as a failure on every key before its credentials
+// are verified, so that concurrent attempts cannot all pass the check before
+// any of them fails. It returns a LockoutError if a key is locked, or if
+// other attempts took what the key had left since the check. The failure
+// stays counted unless recordSuccess or abandon takes it back.
+func (t *loginThrottle) begin(ctx context.Context, keys []throttleKey, now time.Time) error {
+	if err := t.check(ctx, keys, now); err != nil {
+		return err
+	}
+
+	for i, key := range keys {
+		attempt, err := t.store.RecordLoginFailure(ctx, key.id, now)
+		if err != nil {
+			return errors.Join(fmt.Errorf("record login failure: %w", err), t.abandon(ctx, keys[:i]))
+		}
+
+		// Once a key reaches its threshold, each lock lets one attempt through.
+		if key.threshold > 0 && attempt.Failures > max(key.failures+1, key.threshold) {
+			if err := t.abandon(ctx, keys[:i+1]); err != nil {
+				return err
+			}
+
+			return &LockoutError{RetryAfter: t.lockedUntil(attempt, key.threshold).Sub(now)}
+		}
+	}
+
+	return nil
+}
+
+// check returns a LockoutError if any of the keys is locked, and notes each
+// key's count. Counts older than ResetAfter are cleared so that the next
+// failure starts afresh.
+func (t *loginThrottle) check(ctx context.Context, keys []throttleKey, now time.Time) error {
+	var retryAfter time.Duration
+
+	for i := range keys {
+		key := &keys[i]
+		key.failures, key.lastFailedAt = 0, now
+
+		attempt, err := t.store.FindLoginAttempt(ctx, key.id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+
+			return fmt.Errorf("find login attempt: %w", err)
+		}
+
+		if now.Sub(attempt.LastFailedAt) > t.cfg.ResetAfter {
+			if err := t.store.ResetLoginAttempts(ctx, key.id); err != nil {
+				return fmt.Errorf("reset login attempts: %w", err)
+			}
+
+			continue
+		}
+
+		key.failures, key.lastFailedAt = attempt.Failures, attempt.LastFailedAt
+
+		if wait := t.lockedUntil(attempt, key.threshold).Sub(now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LockoutError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// recordSuccess clears the account counter. The IP counter is kept so that a
+// client cannot reset it by signing in to an account it controls, but the
+// attempt is taken back from it.
+func (t *loginThrottle) recordSuccess(ctx context.Context, keys []throttleKey) error {
+	if err := t.store.ResetLoginAttempts(ctx, keys[0].id); err != nil {
+		return fmt.Errorf("reset login attempts: %w", err)
+	}
+
+	return t.abandon(ctx, keys[1:])
+}
+
+// abandon takes back the failure begin counted, for attempts that turned out
+// to be neither a failure nor a success.
+func (t *loginThrottle) abandon(ctx context.Context, keys []throttleKey) error {
+	// The count must be fixed even if the client went away.
+	ctx = context.WithoutCancel(ctx)
+
+	for _, key := range keys {
+		if err := t.store.ForgetLoginFailure(ctx, key.id, key.lastFailedAt); err != nil {
+			return fmt.Errorf("forget login failure: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// lockedUntil returns when the key stops being locked, or the zero time if it
+// is not locked. A concurrent failure may be recorded later than now, so the
+// time of the last failure does not do.
+func (t *loginThrottle) lockedUntil(attempt *model.LoginAttempt, threshold int) time.Time {
+	if threshold <= 0 || attempt.Failures < threshold {
+		return time.Time{}
+	}
+
+	delay := t.cfg.BaseDelay
+	for i := threshold; i < attempt.Failures && delay < t.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return attempt.LastFailedAt.Add(min(delay, t.cfg.MaxDelay))
+}
@@ -0,0 +1,238 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	secMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/security/mocks"
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+)
+
+const testClientIP = "192.0.2.1"
+
+var testLockoutConfig = service.LockoutConfig{
+	AccountThreshold: 3,
+	IPThreshold:      5,
+	BaseDelay:        time.Minute,
+	MaxDelay:         10 * time.Minute,
+	ResetAfter:       time.Hour,
+}
+
+type lockoutMocks struct {
+	users    *repoMocks.MockUserRepo
+	hasher   *secMocks.MockHasher
+	attempts repo.LoginAttemptStore
+}
+
+func TestAuthService_SignInUser_LocksAccount(t *testing.T) {
+	m, authService := setupLockoutMocks(t)
+	ctx := service.ContextWithClientIP(context.Background(), testClientIP)
+	params := model.UserSignInParams{Email: testEmail, Password: "wrong"}
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).
+		Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil).Times(testLockoutConfig.AccountThreshold)
+	m.hasher.EXPECT().Verify("wrong", hashedPassword).Return(false, nil).Times(testLockoutConfig.AccountThreshold)
+
+	for range testLockoutConfig.AccountThreshold {
+		_, err := authService.SignInUser(ctx, params)
+		assert.ErrorIs(t, err, service.ErrPasswordMismatch, "errors should match")
+	}
+
+	_, err := authService.SignInUser(ctx, params)
+	assert.ErrorIs(t, err, service.ErrTooManyAttempts, "locked account should be rejected")
+
+	var lockErr *service.LockoutError
+	if assert.True(t, errors.As(err, &lockErr), "error should be a lockout error") {
+		assert.InDelta(t, time.Minute, lockErr.RetryAfter, float64(time.Second), "retry after should match")
+	}
+}
+
+func TestAuthService_SignInUser_LocksIP(t *testing.T) {
+	m, authService := setupLockoutMocks(t)
+	ctx := service.ContextWithClientIP(context.Background(), testClientIP)
+
+	m.users.EXPECT().FindUserByEmail(ctx, gomock.Any()).Return(nil, service.ErrUserNotFound).
+		Times(testLockoutConfig.IPThreshold)
+
+	emails := []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com"}
+	for _, email := range emails {
+		_, err := authService.SignInUser(ctx, model.UserSignInParams{Email: email, Password: testPassword})
+		assert.ErrorIs(t, err, service.ErrUserNotFound, "errors should match")
+	}
+
+	_, err := authService.SignInUser(ctx, model.UserSignInParams{Email: "f@example.com", Password: testPassword})
+	assert.ErrorIs(t, err, service.ErrTooManyAttempts, "locked IP should be rejected")
+
+	other := service.ContextWithClientIP(context.Background(), "198.51.100.1")
+	m.users.EXPECT().FindUserByEmail(other, "f@example.com").Return(nil, service.ErrUserNotFound)
+	_, err = authService.SignInUser(other, model.UserSignInParams{Email: "f@example.com", Password: testPassword})
+	assert.ErrorIs(t, err, service.ErrUserNotFound, "other clients should not be locked")
+}
+
+func TestAuthService_SignInUser_Backoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		want     time.Duration
+	}{
+		{"threshold should lock for the base delay", 3, time.Minute},
+		{"each further failure should double the delay", 5, 4 * time.Minute},
+		{"delay should be capped", 20, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, authService := setupLockoutMocks(t)
+			ctx := context.Background()
+			now := time.Now().UTC()
+
+			for range tt.failures {
+				_, err := m.attempts.RecordLoginFailure(ctx, "email:"+testEmail, now)
+				assert.NoError(t, err, "record login failure should not return an error")
+			}
+
+			_, err := authService.SignInUser(ctx, model.UserSignInParams{Email: testEmail, Password: testPassword})
+
+			var lockErr *service.LockoutError
+			if assert.True(t, errors.As(err, &lockErr), "error should be a lockout error") {
+				assert.InDelta(t, tt.want, lockErr.RetryAfter, float64(time.Second), "retry after should match")
+			}
+		})
+	}
+}
+
+func TestAuthService_SignInUser_SuccessResetsAccount(t *testing.T) {
+	m, authService := setupLockoutMocks(t)
+	ctx := context.Background()
+	id := "email:" + testEmail
+
+	_, err := m.attempts.RecordLoginFailure(ctx, id, time.Now().UTC())
+	assert.NoError(t, err, "record login failure should not return an error")
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(true, nil)
+
+	_, err = authService.SignInUser(ctx, model.UserSignInParams{Email: testEmail, Password: testPassword})
+	assert.NoError(t, err, "signin should not return an error")
+
+	_, err = m.attempts.FindLoginAttempt(ctx, id)
+	assert.Error(t, err, "account failures should be cleared")
+}
+
+func TestAuthService_SignInUser_StaleFailuresExpire(t *testing.T) {
+	m, authService := setupLockoutMocks(t)
+	ctx := context.Background()
+	stale := time.Now().UTC().Add(-2 * testLockoutConfig.ResetAfter)
+
+	for range 10 {
+		_, err := m.attempts.RecordLoginFailure(ctx, "email:"+testEmail, stale)
+		assert.NoError(t, err, "record login failure should not return an error")
+	}
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(false, nil)
+
+	_, err := authService.SignInUser(ctx, model.UserSignInParams{Email: testEmail, Password: testPassword})
+	assert.ErrorIs(t, err, service.ErrPasswordMismatch, "stale failures should not lock the account")
+
+	attempt, err := m.attempts.FindLoginAttempt(ctx, "email:"+testEmail)
+	if assert.NoError(t, err, "find login attempt should not return an error") {
+		assert.Equal(t, 1, attempt.Failures, "count should start afresh")
+	}
+}
+
+// slowLoginAttemptStore delays lookups so that concurrent attempts all pass
+// the lockout check before any of them is counted.
+type slowLoginAttemptStore struct {
+	repo.LoginAttemptStore
+}
+
+func (s slowLoginAttemptStore) FindLoginAttempt(ctx context.Context, id string) (*model.LoginAttempt, error) {
+	time.Sleep(10 * time.Millisecond)
+	return s.LoginAttemptStore.FindLoginAttempt(ctx, id)
+}
+
+func TestAuthService_SignInUser_ConcurrentAttempts(t *testing.T) {
+	m, _ := setupLockoutMocks(t)
+	authService := service.NewAuthService(m.users, m.hasher,
+		service.WithLockout(slowLoginAttemptStore{m.attempts}, testLockoutConfig))
+	ctx := service.ContextWithClientIP(context.Background(), testClientIP)
+	params := model.UserSignInParams{Email: testEmail, Password: "wrong"}
+
+	var verifies atomic.Int32
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).
+		Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil).AnyTimes()
+	m.hasher.EXPECT().Verify("wrong", hashedPassword).DoAndReturn(func(string, string) (bool, error) {
+		verifies.Add(1)
+		// Keep the attempt in flight so that the others overlap it.
+		time.Sleep(10 * time.Millisecond)
+		return false, nil
+	}).AnyTimes()
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := authService.SignInUser(ctx, params)
+			if !errors.Is(err, service.ErrPasswordMismatch) && !errors.Is(err, service.ErrTooManyAttempts) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, int(verifies.Load()), testLockoutConfig.AccountThreshold,
+		"concurrent attempts should not verify past the threshold")
+
+	attempt, err := m.attempts.FindLoginAttempt(ctx, "email:"+testEmail)
+	if assert.NoError(t, err, "find login attempt should not return an error") {
+		assert.Equal(t, int(verifies.Load()), attempt.Failures, "only verified attempts should count")
+	}
+}
+
+func TestAuthService_SignInUser_ErrorsDoNotCount(t *testing.T) {
+	m, authService := setupLockoutMocks(t)
+	ctx := context.Background()
+	id := "email:" + testEmail
+	failedAt := time.Now().UTC().Add(-time.Minute)
+
+	_, err := m.attempts.RecordLoginFailure(ctx, id, failedAt)
+	assert.NoError(t, err, "record login failure should not return an error")
+
+	errVerify := errors.New("verify failed")
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(false, errVerify)
+
+	_, err = authService.SignInUser(ctx, model.UserSignInParams{Email: testEmail, Password: testPassword})
+	assert.ErrorIs(t, err, errVerify, "errors should match")
+
+	attempt, err := m.attempts.FindLoginAttempt(ctx, id)
+	if assert.NoError(t, err, "find login attempt should not return an error") {
+		assert.Equal(t, 1, attempt.Failures, "the attempt should be taken back")
+		assert.True(t, failedAt.Equal(attempt.LastFailedAt), "last failure should be restored")
+	}
+}
+
+func setupLockoutMocks(t *testing.T) (*lockoutMocks, service.AuthService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := &lockoutMocks{
+		users:    repoMocks.NewMockUserRepo(ctrl),
+		hasher:   secMocks.NewMockHasher(ctrl),
+		attempts: repo.NewMemoryLoginAttemptStore(testLockoutConfig.ResetAfter),
+	}
+	authService := service.NewAuthService(m.users, m.hasher, service.WithLockout(m.attempts, testLockoutConfig))
+
+	return m, authService
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/mail"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+)
+
+type MagicLinkConfig struct {
+	// BaseURL is the address of the app the sign-in link points to.
+	BaseURL string
+	TTL     time.Duration
+	// SameDevice only lets a link sign in the browser that asked for it.
+	SameDevice bool
+}
+
+type magicLinkSignIn struct {
+	links  repo.MagicLinkRepo
+	mailer mail.Mailer
+	cfg    MagicLinkConfig
+}
+
+// WithMagicLinks lets users sign in without a password through an emailed
+// link.
+func WithMagicLinks(links repo.MagicLinkRepo, mailer mail.Mailer, cfg MagicLinkConfig) AuthOption {
+	return func(s *authService) {
+		s.magicLinks = &magicLinkSignIn{
+			links:  links,
+			mailer: mailer,
+			cfg:    cfg,
+		}
+	}
+}
+
+// RequestMagicLink emails a sign-in link. It returns the nonce the browser
+// must present with the link when links are bound to the device that asked
+// for them, and an empty string otherwise. It succeeds without sending
+// anything for unknown emails, and returns a nonce all the same. The link is
+// sent in the background, so that neither the response nor its timing lets
+// callers probe for registered accounts.
+func (s *authService) RequestMagicLink(ctx context.Context, email string) (string, error) {
+	if s.magicLinks == nil {
+		return "", ErrMagicLinksDisabled
+	}
+
+	var nonce string
+	if s.magicLinks.cfg.SameDevice {
+		var err error
+		if nonce, err = security.GenerateRandomBytesEncoded(UserTokenLength); err != nil {
+			return "", fmt.Errorf("generate magic link nonce: %w", err)
+		}
+	}
+
+	user, err := s.repo.FindUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nonce, nil
+		}
+
+		return "", fmt.Errorf("find user by email: %w", err)
+	}
+
+	s.runInBackground(ctx, "send magic link email", func(ctx context.Context) error {
+		return s.magicLinks.send(ctx, user, nonce)
+	})
+
+	return nonce, nil
+}
+
+// SignInWithMagicLink redeems a sign-in link and returns the ID of its user.
+// Opening the link proves the user owns the email, so it is marked verified.
+// Users who enabled two-factor authentication still need a code.
+func (s *authService) SignInWithMagicLink(ctx context.Context, params model.MagicLinkSignInParams) (string, error) {
+	if s.magicLinks == nil {
+		return "", ErrMagicLinksDisabled
+	}
+
+	if params.Token == "" {
+		return "", ErrInvalidToken
+	}
+
+	var nonceHash string
+	if params.Nonce != "" {
+		nonceHash = security.HashToken(params.Nonce)
+	}
+
+	link, err := s.magicLinks.links.ConsumeMagicLink(ctx, security.HashToken(params.Token), nonceHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrInvalidToken
+		}
+
+		return "", fmt.Errorf("consume magic link: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if !now.Before(link.ExpiresAt) {
+		return "", ErrInvalidToken
+	}
+
+	user, err := s.repo.FindUserByID(ctx, link.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrInvalidToken
+		}
+
+		return "", fmt.Errorf("find user by id: %w", err)
+	}
+
+	if user.Email != link.Email {
+		return "", ErrInvalidToken
+	}
+
+	if user.EmailVerifiedAt == nil {
+		if err := s.repo.MarkEmailVerified(ctx, user.ID, now); err != nil {
+			return "", fmt.Errorf("mark email verified: %w", err)
+		}
+	}
+
+	if s.mfa != nil {
+		if err := s.mfa.requireSecondFactor(ctx, user.ID); err != nil {
+			return "", err
+		}
+	}
+
+	return user.ID, nil
+}
+
+// send replaces the user's outstanding links with a new one, bound to the
+// nonce if there is one.
+func (m *magicLinkSignIn) send(ctx context.Context, user *model.User, nonce string) error {
+	if err := m.links.DeleteUserMagicLinks(ctx, user.ID); err != nil {
+		return fmt.Errorf("delete user magic links: %w", err)
+	}
+
+	token, err := security.GenerateRandomBytesEncoded(UserTokenLength)
+	if err != nil {
+		return fmt.Errorf("generate magic link token: %w", err)
+	}
+
+	params := model.MagicLink{
+		ID:        security.HashToken(token),
+		UserID:    user.ID,
+		Email:     user.Email,
+		ExpiresAt: time.Now().UTC().Add(m.cfg.TTL),
+	}
+	if nonce != "" {
+		params.NonceHash = security.HashToken(nonce)
+	}
+
+	if _, err := m.links.CreateMagicLink(ctx, params); err != nil {
+		return fmt.Errorf("create magic link: %w", err)
+	}
+
+	body := fmt.Sprintf("Sign in by opening the link below:\n\n%s\n\n"+
+		"The link expires in %s and can only be used once.",
+		tokenLink(m.cfg.BaseURL, "/signin/magic-link", token), m.cfg.TTL)
+	if nonce != "" {
+		body += " Open it in the browser you asked for it from."
+	}
+	body += " If you did not ask to sign in, you can ignore this email.\n"
+
+	msg := mail.Message{
+		To:      user.Email,
+		Subject: "Your sign-in link",
+		Body:    body,
+	}
+
+	if err := m.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+
+	return nil
+}
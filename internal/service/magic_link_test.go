@@ -0,0 +1,193 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/mail"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	secMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/security/mocks"
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+)
+
+var testMagicLinkConfig = service.MagicLinkConfig{
+	BaseURL:    testBaseURL,
+	TTL:        15 * time.Minute,
+	SameDevice: true,
+}
+
+type magicLinkMocks struct {
+	users  *repoMocks.MockUserRepo
+	links  *repoMocks.MockMagicLinkRepo
+	mailer *mail.MemoryMailer
+	tasks  *taskQueue
+}
+
+func TestAuthService_RequestMagicLink_SendsLink(t *testing.T) {
+	m, authService := setupMagicLinkMocks(t, testMagicLinkConfig)
+	ctx := context.Background()
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, Email: testEmail}, nil)
+	m.links.EXPECT().DeleteUserMagicLinks(gomock.Any(), testID).Return(nil)
+
+	var stored model.MagicLink
+	m.links.EXPECT().CreateMagicLink(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, params model.MagicLink) (*model.MagicLink, error) {
+			stored = params
+			return &params, nil
+		})
+
+	nonce, err := authService.RequestMagicLink(ctx, testEmail)
+	m.tasks.run()
+
+	assert.NoError(t, err, "request magic link should not return an error")
+	assert.NotEmpty(t, nonce, "a nonce should be returned")
+	assert.Equal(t, security.HashToken(nonce), stored.NonceHash, "the link should be bound to the nonce")
+	assert.Equal(t, testEmail, stored.Email, "email should match")
+	msgs := m.mailer.Messages()
+	if assert.Len(t, msgs, 1, "one email should be sent") {
+		assert.Contains(t, msgs[0].Body, testBaseURL+"/signin/magic-link?token=", "link should point to the sign-in page")
+		assert.Equal(t, security.HashToken(linkToken(t, msgs[0].Body)), stored.ID, "only the token hash should be stored")
+	}
+}
+
+func TestAuthService_RequestMagicLink_UnknownEmail(t *testing.T) {
+	m, authService := setupMagicLinkMocks(t, testMagicLinkConfig)
+	ctx := context.Background()
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(nil, sql.ErrNoRows)
+	m.links.EXPECT().CreateMagicLink(gomock.Any(), gomock.Any()).Times(0)
+
+	nonce, err := authService.RequestMagicLink(ctx, testEmail)
+	m.tasks.run()
+
+	assert.NoError(t, err, "unknown emails should not be reported")
+	assert.NotEmpty(t, nonce, "a nonce should be returned for unknown emails too")
+	assert.Empty(t, m.mailer.Messages(), "no email should be sent")
+}
+
+func TestAuthService_RequestMagicLink_SameRequestWork(t *testing.T) {
+	tests := []struct {
+		name string
+		user *model.User
+		err  error
+	}{
+		{"known email", &model.User{ID: testID, Email: testEmail}, nil},
+		{"unknown email", nil, sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, authService := setupMagicLinkMocks(t, testMagicLinkConfig)
+			ctx := context.Background()
+
+			// Any other call on the request path fails the test.
+			m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(tt.user, tt.err)
+
+			nonce, err := authService.RequestMagicLink(ctx, testEmail)
+
+			assert.NoError(t, err, "request magic link should not return an error")
+			assert.NotEmpty(t, nonce, "a nonce should be returned")
+			assert.Empty(t, m.mailer.Messages(), "no email should be sent before the request returns")
+		})
+	}
+}
+
+func TestAuthService_RequestMagicLink_AnyDevice(t *testing.T) {
+	cfg := testMagicLinkConfig
+	cfg.SameDevice = false
+	m, authService := setupMagicLinkMocks(t, cfg)
+	ctx := context.Background()
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, Email: testEmail}, nil)
+	m.links.EXPECT().DeleteUserMagicLinks(gomock.Any(), testID).Return(nil)
+	m.links.EXPECT().CreateMagicLink(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, params model.MagicLink) (*model.MagicLink, error) {
+			assert.Empty(t, params.NonceHash, "the link should not be bound to a device")
+			return &params, nil
+		})
+
+	nonce, err := authService.RequestMagicLink(ctx, testEmail)
+	m.tasks.run()
+
+	assert.NoError(t, err, "request magic link should not return an error")
+	assert.Empty(t, nonce, "no nonce should be returned")
+}
+
+func TestAuthService_SignInWithMagicLink_Success(t *testing.T) {
+	m, authService := setupMagicLinkMocks(t, testMagicLinkConfig)
+	ctx := context.Background()
+	params := model.MagicLinkSignInParams{Token: "token", Nonce: "nonce"}
+
+	m.links.EXPECT().ConsumeMagicLink(ctx, security.HashToken(params.Token), security.HashToken(params.Nonce)).
+		Return(&model.MagicLink{UserID: testID, Email: testEmail, ExpiresAt: time.Now().Add(time.Minute)}, nil)
+	m.users.EXPECT().FindUserByID(ctx, testID).Return(&model.User{ID: testID, Email: testEmail}, nil)
+	m.users.EXPECT().MarkEmailVerified(ctx, testID, gomock.Any()).Return(nil)
+
+	userID, err := authService.SignInWithMagicLink(ctx, params)
+
+	assert.NoError(t, err, "sign in with magic link should not return an error")
+	assert.Equal(t, testID, userID, "user ID should match")
+}
+
+func TestAuthService_SignInWithMagicLink_InvalidToken(t *testing.T) {
+	tests := []struct {
+		name string
+		link *model.MagicLink
+		err  error
+	}{
+		{"unknown token or other device should be rejected", nil, sql.ErrNoRows},
+		{"expired link should be rejected",
+			&model.MagicLink{UserID: testID, Email: testEmail, ExpiresAt: time.Now().Add(-time.Minute)}, nil},
+		{"link for a previous email should be rejected",
+			&model.MagicLink{UserID: testID, Email: "old@example.com", ExpiresAt: time.Now().Add(time.Minute)}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, authService := setupMagicLinkMocks(t, testMagicLinkConfig)
+			ctx := context.Background()
+
+			m.links.EXPECT().ConsumeMagicLink(ctx, security.HashToken("token"), "").Return(tt.link, tt.err)
+			m.users.EXPECT().FindUserByID(ctx, testID).Return(&model.User{ID: testID, Email: testEmail}, nil).AnyTimes()
+			m.users.EXPECT().MarkEmailVerified(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			_, err := authService.SignInWithMagicLink(ctx, model.MagicLinkSignInParams{Token: "token"})
+
+			assert.ErrorIs(t, err, service.ErrInvalidToken, "errors should match")
+		})
+	}
+}
+
+func TestAuthService_MagicLinks_Disabled(t *testing.T) {
+	_, _, authService := setupMocks(t)
+	ctx := context.Background()
+
+	_, err := authService.RequestMagicLink(ctx, testEmail)
+	assert.ErrorIs(t, err, service.ErrMagicLinksDisabled, "errors should match")
+	_, err = authService.SignInWithMagicLink(ctx, model.MagicLinkSignInParams{Token: "token"})
+	assert.ErrorIs(t, err, service.ErrMagicLinksDisabled, "errors should match")
+}
+
+func setupMagicLinkMocks(t *testing.T, cfg service.MagicLinkConfig) (*magicLinkMocks, service.AuthService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := &magicLinkMocks{
+		users:  repoMocks.NewMockUserRepo(ctrl),
+		links:  repoMocks.NewMockMagicLinkRepo(ctrl),
+		mailer: &mail.MemoryMailer{},
+		tasks:  &taskQueue{},
+	}
+	authService := service.NewAuthService(m.users, secMocks.NewMockHasher(ctrl),
+		service.WithBackground(m.tasks.add),
+		service.WithMagicLinks(m.links, m.mailer, cfg))
+
+	return m, authService
+}
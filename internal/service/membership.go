@@ -0,0 +1,369 @@
+//go:generate mockgen -destination=mocks/membership_service_mock.go -package=mocks . MembershipService
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/mail"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/password"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+)
+
+// MembershipService manages the members of organizations and invitations to
+// join them. Except for AcceptInvitation, whose token names the organization,
+// its methods act on the organization ctx is scoped to, and return
+// ErrNoOrganization without one.
+type MembershipService interface {
+	ListMembers(ctx context.Context) ([]model.Membership, error)
+	// UpdateMemberRole gives the member another organization role. Like
+	// RemoveMember and Invite, it returns ErrRoleNotGrantable unless the
+	// user in ctx has every permission of the roles given or taken away.
+	UpdateMemberRole(ctx context.Context, userID string, params model.MembershipParams) (*model.Membership, error)
+	RemoveMember(ctx context.Context, userID string) error
+	// Invite emails an invitation to join the organization with the role.
+	Invite(ctx context.Context, params model.InvitationParams) (*model.Invitation, error)
+	// AcceptInvitation redeems an invitation, making the user with the
+	// invited email a member. Without such a user, one is created with the
+	// password in params, or ErrPasswordRequired returned if there is none.
+	// It returns ErrIdentityNotLinkable if the user has not verified the
+	// email.
+	AcceptInvitation(ctx context.Context, params model.AcceptInvitationParams) (*model.Membership, error)
+}
+
+type InvitationConfig struct {
+	// BaseURL is the address of the app the invitation link points to.
+	BaseURL string
+	TTL     time.Duration
+}
+
+type membershipService struct {
+	members     repo.MembershipRepo
+	invitations repo.InvitationRepo
+	roles       repo.RoleRepo
+	users       repo.UserRepo
+	authz       Authorizer
+	hasher      security.Hasher
+	breaches    password.BreachChecker
+	mailer      mail.Mailer
+	cfg         InvitationConfig
+}
+
+// NewMembershipService returns a MembershipService. The passwords of users
+// created by accepting invitations are checked against breaches, if
+// breaches is not nil.
+func NewMembershipService(members repo.MembershipRepo, invitations repo.InvitationRepo, roles repo.RoleRepo,
+	users repo.UserRepo, authz Authorizer, hasher security.Hasher, breaches password.BreachChecker, mailer mail.Mailer,
+	cfg InvitationConfig) MembershipService {
+	return &membershipService{
+		members:     members,
+		invitations: invitations,
+		roles:       roles,
+		users:       users,
+		authz:       authz,
+		hasher:      hasher,
+		breaches:    breaches,
+		mailer:      mailer,
+		cfg:         cfg,
+	}
+}
+
+func (s *membershipService) ListMembers(ctx context.Context) ([]model.Membership, error) {
+	org, ok := OrganizationFromContext(ctx)
+	if !ok {
+		return nil, ErrNoOrganization
+	}
+
+	members, err := s.members.ListMembers(ctx, org.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list members: %w", err)
+	}
+
+	return members, nil
+}
+
+func (s *membershipService) UpdateMemberRole(ctx context.Context, userID string, params model.MembershipParams) (*model.Membership, error) {
+	org, ok := OrganizationFromContext(ctx)
+	if !ok {
+		return nil, ErrNoOrganization
+	}
+
+	if err := s.requireGrantable(ctx, params.Role); err != nil {
+		return nil, err
+	}
+
+	member, err := s.findMember(ctx, org.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requireGrantable(ctx, member.Role); err != nil {
+		return nil, err
+	}
+
+	if member.Role == RoleOwner && params.Role != RoleOwner {
+		if err := s.requireAnotherOwner(ctx, org.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.members.UpdateMemberRole(ctx, org.ID, userID, params.Role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMemberNotFound
+		}
+
+		return nil, fmt.Errorf("update member role: %w", err)
+	}
+
+	member.Role = params.Role
+
+	return member, nil
+}
+
+func (s *membershipService) RemoveMember(ctx context.Context, userID string) error {
+	org, ok := OrganizationFromContext(ctx)
+	if !ok {
+		return ErrNoOrganization
+	}
+
+	member, err := s.findMember(ctx, org.ID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.requireGrantable(ctx, member.Role); err != nil {
+		return err
+	}
+
+	if member.Role == RoleOwner {
+		if err := s.requireAnotherOwner(ctx, org.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.members.RemoveMember(ctx, org.ID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrMemberNotFound
+		}
+
+		return fmt.Errorf("remove member: %w", err)
+	}
+
+	slog.Info("removed member", "organization_id", org.ID, "user_id", userID)
+
+	return nil
+}
+
+// Invite replaces the organization's outstanding invitations to the email
+// with a new one, which is deleted again if it cannot be emailed.
+func (s *membershipService) Invite(ctx context.Context, params model.InvitationParams) (*model.Invitation, error) {
+	org, ok := OrganizationFromContext(ctx)
+	if !ok {
+		return nil, ErrNoOrganization
+	}
+
+	if err := s.requireGrantable(ctx, params.Role); err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.FindUserByEmail(ctx, params.Email)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("find user by email: %w", err)
+	}
+
+	if user != nil {
+		if _, err := s.findMember(ctx, org.ID, user.ID); !errors.Is(err, ErrMemberNotFound) {
+			if err != nil {
+				return nil, err
+			}
+
+			return nil, ErrAlreadyMember
+		}
+	}
+
+	if err := s.invitations.DeleteInvitations(ctx, org.ID, params.Email); err != nil {
+		return nil, fmt.Errorf("delete invitations: %w", err)
+	}
+
+	token, err := security.GenerateRandomBytesEncoded(UserTokenLength)
+	if err != nil {
+		return nil, fmt.Errorf("generate invitation token: %w", err)
+	}
+
+	inv, err := s.invitations.CreateInvitation(ctx, model.Invitation{
+		ID:             security.HashToken(token),
+		OrganizationID: org.ID,
+		Email:          params.Email,
+		Role:           params.Role,
+		ExpiresAt:      time.Now().UTC().Add(s.cfg.TTL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create invitation: %w", err)
+	}
+
+	msg := mail.Message{
+		To:      params.Email,
+		Subject: fmt.Sprintf("You are invited to join %s", org.Name),
+		Body: fmt.Sprintf("You have been invited to join %s. Accept the invitation by opening the link below:\n\n%s\n\n"+
+			"The link expires in %s and can only be used once. If you do not have an account yet, "+
+			"you will be asked to choose a password. If you did not expect this invitation, you can ignore this email.\n",
+			org.Name, tokenLink(s.cfg.BaseURL, "/invitations/accept", token), s.cfg.TTL),
+	}
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		// Nobody received the link, so leave no invitation behind.
+		if err := s.invitations.DeleteInvitations(context.WithoutCancel(ctx), org.ID, params.Email); err != nil {
+			slog.Error("delete unsent invitation", "organization_id", org.ID, "error", err)
+		}
+
+		return nil, fmt.Errorf("send mail: %w", err)
+	}
+
+	return inv, nil
+}
+
+// AcceptInvitation checks that it can attach or create the account before
+// accepting the invitation, which consumes it, creates the account and adds
+// the member in one transaction, so that any failure leaves it usable.
+// Opening the link proves the user owns the email, so a created user's email
+// is verified. An account that has not verified the email may
+// not belong to whoever received the invitation, so it is not attached until
+// it does.
+func (s *membershipService) AcceptInvitation(ctx context.Context, params model.AcceptInvitationParams) (*model.Membership, error) {
+	if params.Token == "" {
+		return nil, ErrInvalidToken
+	}
+
+	id := security.HashToken(params.Token)
+	inv, err := s.invitations.FindInvitation(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+
+		return nil, fmt.Errorf("find invitation: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if !now.Before(inv.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.users.FindUserByEmail(ctx, inv.Email)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("find user by email: %w", err)
+	}
+
+	if user != nil && user.EmailVerifiedAt == nil {
+		return nil, ErrIdentityNotLinkable
+	}
+
+	var hash string
+	if user == nil {
+		if params.Password == "" {
+			return nil, ErrPasswordRequired
+		}
+
+		if err := checkBreached(ctx, s.breaches, params.Password); err != nil {
+			return nil, err
+		}
+
+		if hash, err = security.HashContext(ctx, s.hasher, params.Password); err != nil {
+			return nil, fmt.Errorf("hash password: %w", err)
+		}
+	}
+
+	if user == nil {
+		user = &model.User{Email: inv.Email, PasswordHash: hash}
+	}
+
+	userID, err := s.invitations.AcceptInvitation(ctx, id, *user, now)
+	if err != nil {
+		// A concurrent acceptance consumed the invitation after our lookup.
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+
+		// A concurrent sign-up registered the email after our lookup.
+		if errors.Is(err, repo.ErrDuplicate) {
+			return nil, ErrEmailTaken
+		}
+
+		return nil, fmt.Errorf("accept invitation: %w", err)
+	}
+
+	slog.Info("accepted invitation", "organization_id", inv.OrganizationID, "user_id", userID)
+
+	return s.findMember(ctx, inv.OrganizationID, userID)
+}
+
+func (s *membershipService) findMember(ctx context.Context, orgID, userID string) (*model.Membership, error) {
+	member, err := s.members.FindMember(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMemberNotFound
+		}
+
+		return nil, fmt.Errorf("find member: %w", err)
+	}
+
+	return member, nil
+}
+
+// requireGrantable returns ErrRoleScope unless the role is one for members
+// of organizations, so that owners cannot give out roles across the app, and
+// ErrRoleNotGrantable unless the user in ctx has each of its permissions, so
+// that members cannot give out more than they have.
+func (s *membershipService) requireGrantable(ctx context.Context, role string) error {
+	found, err := s.roles.FindRole(ctx, role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrRoleNotFound
+		}
+
+		return fmt.Errorf("find role: %w", err)
+	}
+
+	if found.Scope != model.RoleScopeOrganization {
+		return ErrRoleScope
+	}
+
+	actor, ok := UserFromContext(ctx)
+	if !ok {
+		return ErrRoleNotGrantable
+	}
+
+	for _, perm := range found.Permissions {
+		allowed, err := s.authz.Can(ctx, actor, perm.Action, perm.Resource)
+		if err != nil {
+			return err
+		}
+
+		if !allowed {
+			return ErrRoleNotGrantable
+		}
+	}
+
+	return nil
+}
+
+// requireAnotherOwner returns ErrLastOwner unless the organization has more
+// than one owner, before one stops being an owner.
+func (s *membershipService) requireAnotherOwner(ctx context.Context, orgID string) error {
+	owners, err := s.members.CountMembersWithRole(ctx, orgID, RoleOwner)
+	if err != nil {
+		return fmt.Errorf("count members with role: %w", err)
+	}
+
+	if owners < 2 {
+		return ErrLastOwner
+	}
+
+	return nil
+}
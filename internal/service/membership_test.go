@@ -0,0 +1,393 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/mail"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	mailMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/mail/mocks"
+	secMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/security/mocks"
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+)
+
+const testOrgID = "6f1c2b9e-4d5a-4e7b-9c3d-2a1b0c9d8e7f"
+
+var testOrg = &model.Organization{ID: testOrgID, Name: "Acme"}
+
+var testMemberRole = &model.Role{Name: service.RoleMember, Scope: model.RoleScopeOrganization,
+	Permissions: []model.Permission{{Action: "read", Resource: "members"}, {Action: "read", Resource: "organization"}}}
+
+var testOwnerRole = &model.Role{Name: service.RoleOwner, Scope: model.RoleScopeOrganization,
+	Permissions: []model.Permission{{Action: "*", Resource: "invitations"}, {Action: "*", Resource: "members"},
+		{Action: "*", Resource: "organization"}}}
+
+var testInvitationConfig = service.InvitationConfig{
+	BaseURL: testBaseURL,
+	TTL:     72 * time.Hour,
+}
+
+type membershipMocks struct {
+	members     *repoMocks.MockMembershipRepo
+	invitations *repoMocks.MockInvitationRepo
+	roles       *repoMocks.MockRoleRepo
+	users       *repoMocks.MockUserRepo
+	hasher      *secMocks.MockHasher
+	mailer      *mail.MemoryMailer
+}
+
+func TestMembershipService_Invite_SendsLink(t *testing.T) {
+	m, memberships := setupMembershipMocks(t)
+	ctx := orgContext()
+
+	m.roles.EXPECT().FindRole(ctx, service.RoleMember).Return(testMemberRole, nil)
+	expectPermissions(m, testOwnerRole.Permissions...)
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(nil, sql.ErrNoRows)
+	m.invitations.EXPECT().DeleteInvitations(ctx, testOrgID, testEmail).Return(nil)
+
+	var stored model.Invitation
+	m.invitations.EXPECT().CreateInvitation(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, params model.Invitation) (*model.Invitation, error) {
+			stored = params
+			return &params, nil
+		})
+
+	_, err := memberships.Invite(ctx, model.InvitationParams{Email: testEmail, Role: service.RoleMember})
+
+	assert.NoError(t, err, "invite should not return an error")
+	assert.Equal(t, testOrgID, stored.OrganizationID, "the invitation should be scoped to the organization")
+	msgs := m.mailer.Messages()
+	if assert.Len(t, msgs, 1, "one email should be sent") {
+		assert.Contains(t, msgs[0].Subject, testOrg.Name, "subject should name the organization")
+		assert.Contains(t, msgs[0].Body, testBaseURL+"/invitations/accept?token=", "link should point to the invitation page")
+		assert.Equal(t, security.HashToken(linkToken(t, msgs[0].Body)), stored.ID, "only the token hash should be stored")
+	}
+}
+
+func TestMembershipService_Invite_AlreadyMember(t *testing.T) {
+	m, memberships := setupMembershipMocks(t)
+	ctx := orgContext()
+
+	m.roles.EXPECT().FindRole(ctx, service.RoleMember).Return(testMemberRole, nil)
+	expectPermissions(m, testOwnerRole.Permissions...)
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, Email: testEmail}, nil)
+	m.members.EXPECT().FindMember(ctx, testOrgID, testID).Return(&model.Membership{UserID: testID}, nil)
+	m.invitations.EXPECT().CreateInvitation(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := memberships.Invite(ctx, model.InvitationParams{Email: testEmail, Role: service.RoleMember})
+
+	assert.ErrorIs(t, err, service.ErrAlreadyMember, "errors should match")
+	assert.Empty(t, m.mailer.Messages(), "no email should be sent")
+}
+
+func TestMembershipService_Invite_SendFails(t *testing.T) {
+	m, _ := setupMembershipMocks(t)
+	ctx := orgContext()
+	mailErr := errors.New("smtp unavailable")
+	mailer := mailMocks.NewMockMailer(gomock.NewController(t))
+	memberships := service.NewMembershipService(m.members, m.invitations, m.roles, m.users,
+		service.NewAuthorizer(m.roles), m.hasher, nil, mailer, testInvitationConfig)
+
+	m.roles.EXPECT().FindRole(ctx, service.RoleMember).Return(testMemberRole, nil)
+	expectPermissions(m, testOwnerRole.Permissions...)
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(nil, sql.ErrNoRows)
+	gomock.InOrder(
+		m.invitations.EXPECT().DeleteInvitations(ctx, testOrgID, testEmail).Return(nil),
+		m.invitations.EXPECT().CreateInvitation(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, params model.Invitation) (*model.Invitation, error) {
+				return &params, nil
+			}),
+		mailer.EXPECT().Send(ctx, gomock.Any()).Return(mailErr),
+		m.invitations.EXPECT().DeleteInvitations(gomock.Any(), testOrgID, testEmail).Return(nil),
+	)
+
+	_, err := memberships.Invite(ctx, model.InvitationParams{Email: testEmail, Role: service.RoleMember})
+
+	assert.ErrorIs(t, err, mailErr, "errors should match")
+}
+
+func TestMembershipService_GlobalRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(context.Context, service.MembershipService) error
+	}{
+		{"inviting with a global role should be rejected", func(ctx context.Context, s service.MembershipService) error {
+			_, err := s.Invite(ctx, model.InvitationParams{Email: testEmail, Role: service.RoleAdmin})
+			return err
+		}},
+		{"promoting to a global role should be rejected", func(ctx context.Context, s service.MembershipService) error {
+			_, err := s.UpdateMemberRole(ctx, testID, model.MembershipParams{Role: service.RoleAdmin})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, memberships := setupMembershipMocks(t)
+			ctx := orgContext()
+
+			m.roles.EXPECT().FindRole(ctx, service.RoleAdmin).
+				Return(&model.Role{Name: service.RoleAdmin, Scope: model.RoleScopeGlobal}, nil)
+			m.invitations.EXPECT().CreateInvitation(gomock.Any(), gomock.Any()).Times(0)
+			m.members.EXPECT().UpdateMemberRole(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			err := tt.change(ctx, memberships)
+
+			assert.ErrorIs(t, err, service.ErrRoleScope, "errors should match")
+			assert.Empty(t, m.mailer.Messages(), "no email should be sent")
+		})
+	}
+}
+
+func TestMembershipService_NoOrganization(t *testing.T) {
+	_, memberships := setupMembershipMocks(t)
+	ctx := context.Background()
+
+	_, err := memberships.ListMembers(ctx)
+	assert.ErrorIs(t, err, service.ErrNoOrganization, "errors should match")
+	_, err = memberships.UpdateMemberRole(ctx, testID, model.MembershipParams{Role: service.RoleMember})
+	assert.ErrorIs(t, err, service.ErrNoOrganization, "errors should match")
+	err = memberships.RemoveMember(ctx, testID)
+	assert.ErrorIs(t, err, service.ErrNoOrganization, "errors should match")
+	_, err = memberships.Invite(ctx, model.InvitationParams{Email: testEmail, Role: service.RoleMember})
+	assert.ErrorIs(t, err, service.ErrNoOrganization, "errors should match")
+}
+
+func TestMembershipService_LastOwner(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(context.Context, service.MembershipService) error
+	}{
+		{"removing the last owner should be rejected", func(ctx context.Context, s service.MembershipService) error {
+			return s.RemoveMember(ctx, testID)
+		}},
+		{"demoting the last owner should be rejected", func(ctx context.Context, s service.MembershipService) error {
+			_, err := s.UpdateMemberRole(ctx, testID, model.MembershipParams{Role: service.RoleMember})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, memberships := setupMembershipMocks(t)
+			ctx := orgContext()
+
+			m.roles.EXPECT().FindRole(ctx, service.RoleMember).Return(testMemberRole, nil).AnyTimes()
+			m.roles.EXPECT().FindRole(ctx, service.RoleOwner).Return(testOwnerRole, nil)
+			expectPermissions(m, testOwnerRole.Permissions...)
+			m.members.EXPECT().FindMember(ctx, testOrgID, testID).
+				Return(&model.Membership{OrganizationID: testOrgID, UserID: testID, Role: service.RoleOwner}, nil)
+			m.members.EXPECT().CountMembersWithRole(ctx, testOrgID, service.RoleOwner).Return(1, nil)
+			m.members.EXPECT().RemoveMember(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			m.members.EXPECT().UpdateMemberRole(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			err := tt.change(ctx, memberships)
+
+			assert.ErrorIs(t, err, service.ErrLastOwner, "errors should match")
+		})
+	}
+}
+
+func TestMembershipService_RoleNotGrantable(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(context.Context, service.MembershipService) error
+	}{
+		{"promoting to owner should be rejected", func(ctx context.Context, s service.MembershipService) error {
+			_, err := s.UpdateMemberRole(ctx, "2", model.MembershipParams{Role: service.RoleOwner})
+			return err
+		}},
+		{"inviting an owner should be rejected", func(ctx context.Context, s service.MembershipService) error {
+			_, err := s.Invite(ctx, model.InvitationParams{Email: testEmail, Role: service.RoleOwner})
+			return err
+		}},
+		{"demoting an owner should be rejected", func(ctx context.Context, s service.MembershipService) error {
+			_, err := s.UpdateMemberRole(ctx, "3", model.MembershipParams{Role: service.RoleMember})
+			return err
+		}},
+		{"removing an owner should be rejected", func(ctx context.Context, s service.MembershipService) error {
+			return s.RemoveMember(ctx, "3")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, memberships := setupMembershipMocks(t)
+			ctx := orgContext()
+
+			// A custom role that manages members and invitations, but is not an owner.
+			expectPermissions(m, model.Permission{Action: "read", Resource: "members"},
+				model.Permission{Action: "write", Resource: "members"},
+				model.Permission{Action: "write", Resource: "invitations"})
+			m.roles.EXPECT().FindRole(ctx, service.RoleOwner).Return(testOwnerRole, nil).AnyTimes()
+			m.roles.EXPECT().FindRole(ctx, service.RoleMember).Return(testMemberRole, nil).AnyTimes()
+			m.members.EXPECT().FindMember(ctx, testOrgID, "3").
+				Return(&model.Membership{OrganizationID: testOrgID, UserID: "3", Role: service.RoleOwner}, nil).AnyTimes()
+			m.members.EXPECT().UpdateMemberRole(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			m.members.EXPECT().RemoveMember(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			m.invitations.EXPECT().CreateInvitation(gomock.Any(), gomock.Any()).Times(0)
+
+			err := tt.change(ctx, memberships)
+
+			assert.ErrorIs(t, err, service.ErrRoleNotGrantable, "errors should match")
+		})
+	}
+}
+
+func TestMembershipService_AcceptInvitation_ExistingUser(t *testing.T) {
+	m, memberships := setupMembershipMocks(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	id := security.HashToken(testToken)
+	inv := &model.Invitation{ID: id, OrganizationID: testOrgID, Email: testEmail, Role: service.RoleMember,
+		ExpiresAt: now.Add(time.Hour)}
+	member := &model.Membership{OrganizationID: testOrgID, UserID: testID, Role: service.RoleMember}
+
+	m.invitations.EXPECT().FindInvitation(ctx, id).Return(inv, nil)
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).
+		Return(&model.User{ID: testID, Email: testEmail, EmailVerifiedAt: &now}, nil)
+	m.invitations.EXPECT().AcceptInvitation(ctx, id, model.User{ID: testID, Email: testEmail, EmailVerifiedAt: &now},
+		gomock.Any()).Return(testID, nil)
+	m.members.EXPECT().FindMember(ctx, testOrgID, testID).Return(member, nil)
+
+	accepted, err := memberships.AcceptInvitation(ctx, model.AcceptInvitationParams{Token: testToken})
+
+	assert.NoError(t, err, "accept invitation should not return an error")
+	assert.Equal(t, member, accepted, "membership should match")
+}
+
+func TestMembershipService_AcceptInvitation_NewUser(t *testing.T) {
+	m, memberships := setupMembershipMocks(t)
+	ctx := context.Background()
+	id := security.HashToken(testToken)
+	inv := &model.Invitation{ID: id, OrganizationID: testOrgID, Email: testEmail, Role: service.RoleMember,
+		ExpiresAt: time.Now().UTC().Add(time.Hour)}
+
+	m.invitations.EXPECT().FindInvitation(ctx, id).Return(inv, nil)
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(nil, sql.ErrNoRows)
+	m.hasher.EXPECT().Hash(testPassword).Return("hashed", nil)
+	m.invitations.EXPECT().AcceptInvitation(ctx, id, model.User{Email: testEmail, PasswordHash: "hashed"}, gomock.Any()).
+		Return(testID, nil)
+	m.members.EXPECT().FindMember(ctx, testOrgID, testID).
+		Return(&model.Membership{OrganizationID: testOrgID, UserID: testID, Role: service.RoleMember}, nil)
+
+	accepted, err := memberships.AcceptInvitation(ctx,
+		model.AcceptInvitationParams{Token: testToken, Password: testPassword, PasswordConfirm: testPassword})
+
+	assert.NoError(t, err, "accept invitation should not return an error")
+	assert.Equal(t, testID, accepted.UserID, "the created user should be the member")
+}
+
+func TestMembershipService_AcceptInvitation_RetryAfterEmailTaken(t *testing.T) {
+	m, memberships := setupMembershipMocks(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	id := security.HashToken(testToken)
+	inv := &model.Invitation{ID: id, OrganizationID: testOrgID, Email: testEmail, Role: service.RoleMember,
+		ExpiresAt: now.Add(time.Hour)}
+	params := model.AcceptInvitationParams{Token: testToken, Password: testPassword, PasswordConfirm: testPassword}
+
+	m.invitations.EXPECT().FindInvitation(ctx, id).Return(inv, nil).Times(2)
+	m.hasher.EXPECT().Hash(testPassword).Return("hashed", nil)
+	gomock.InOrder(
+		m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(nil, sql.ErrNoRows),
+		m.users.EXPECT().FindUserByEmail(ctx, testEmail).
+			Return(&model.User{ID: testID, Email: testEmail, EmailVerifiedAt: &now}, nil),
+	)
+	gomock.InOrder(
+		m.invitations.EXPECT().AcceptInvitation(ctx, id, model.User{Email: testEmail, PasswordHash: "hashed"},
+			gomock.Any()).Return("", repo.ErrDuplicate),
+		m.invitations.EXPECT().AcceptInvitation(ctx, id, gomock.Any(), gomock.Any()).Return(testID, nil),
+	)
+	m.members.EXPECT().FindMember(ctx, testOrgID, testID).
+		Return(&model.Membership{OrganizationID: testOrgID, UserID: testID, Role: service.RoleMember}, nil)
+
+	_, err := memberships.AcceptInvitation(ctx, params)
+	assert.ErrorIs(t, err, service.ErrEmailTaken, "errors should match")
+
+	accepted, err := memberships.AcceptInvitation(ctx, params)
+	assert.NoError(t, err, "accepting again should not return an error")
+	assert.Equal(t, testID, accepted.UserID, "the existing user should be the member")
+}
+
+func TestMembershipService_AcceptInvitation_Rejected(t *testing.T) {
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		findErr   error
+		user      *model.User
+		password  string
+		err       error
+	}{
+		{"unknown invitation should be rejected", now.Add(time.Hour), sql.ErrNoRows, nil, testPassword,
+			service.ErrInvalidToken},
+		{"expired invitation should be rejected", now.Add(-time.Minute), nil, nil, testPassword,
+			service.ErrInvalidToken},
+		{"new user without a password should be rejected", now.Add(time.Hour), nil, nil, "",
+			service.ErrPasswordRequired},
+		{"unverified user should not be attached", now.Add(time.Hour), nil, &model.User{ID: testID, Email: testEmail},
+			"", service.ErrIdentityNotLinkable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, memberships := setupMembershipMocks(t)
+			ctx := context.Background()
+			id := security.HashToken(testToken)
+
+			var inv *model.Invitation
+			if tt.findErr == nil {
+				inv = &model.Invitation{ID: id, OrganizationID: testOrgID, Email: testEmail, Role: service.RoleMember,
+					ExpiresAt: tt.expiresAt}
+			}
+
+			m.invitations.EXPECT().FindInvitation(ctx, id).Return(inv, tt.findErr)
+			m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(tt.user, nil).AnyTimes()
+			m.invitations.EXPECT().AcceptInvitation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			_, err := memberships.AcceptInvitation(ctx, model.AcceptInvitationParams{Token: testToken, Password: tt.password})
+
+			assert.ErrorIs(t, err, tt.err, "errors should match")
+		})
+	}
+}
+
+func setupMembershipMocks(t *testing.T) (*membershipMocks, service.MembershipService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := &membershipMocks{
+		members:     repoMocks.NewMockMembershipRepo(ctrl),
+		invitations: repoMocks.NewMockInvitationRepo(ctrl),
+		roles:       repoMocks.NewMockRoleRepo(ctrl),
+		users:       repoMocks.NewMockUserRepo(ctrl),
+		hasher:      secMocks.NewMockHasher(ctrl),
+		mailer:      &mail.MemoryMailer{},
+	}
+	memberships := service.NewMembershipService(m.members, m.invitations, m.roles, m.users,
+		service.NewAuthorizer(m.roles), m.hasher, nil, m.mailer, testInvitationConfig)
+
+	return m, memberships
+}
+
+// orgContext returns a context scoped to testOrg in which testUser acts.
+func orgContext() context.Context {
+	return service.ContextWithUser(service.ContextWithOrganization(context.Background(), testOrg), testUser)
+}
+
+// expectPermissions gives testUser no global permissions and perms in
+// testOrg.
+func expectPermissions(m *membershipMocks, perms ...model.Permission) {
+	m.roles.EXPECT().ListUserPermissions(gomock.Any(), testID).Return([]model.Permission{}, nil).AnyTimes()
+	m.roles.EXPECT().ListMemberPermissions(gomock.Any(), testOrgID, testID).Return(perms, nil).AnyTimes()
+}
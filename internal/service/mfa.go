@@ -0,0 +1,477 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/totp"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+)
+
+const (
+	// MFAChallengeLength is the length in random bytes of the token that
+	// identifies a sign-in awaiting its second factor.
+	MFAChallengeLength = 32
+	// qrCodeSize is the width and height in pixels of enrollment QR codes.
+	qrCodeSize = 256
+	// recoveryCodeLength is the number of base32 characters in a recovery
+	// code, 50 bits in all.
+	recoveryCodeLength = 10
+)
+
+// MFAConfig configures two-factor authentication with authenticator apps.
+type MFAConfig struct {
+	// Issuer names the app in the user's authenticator.
+	Issuer string
+	// ChallengeTTL is how long a sign-in waits for its second factor.
+	ChallengeTTL time.Duration
+	// MaxAttempts is the number of wrong codes after which a sign-in must
+	// start over with the password.
+	MaxAttempts int
+	// RecoveryCodes is the number of recovery codes issued at a time.
+	RecoveryCodes int
+}
+
+// MFARequiredError is returned instead of a sign-in when the user has enabled
+// two-factor authentication. The challenge token completes the sign-in
+// together with a code. It matches ErrMFARequired with errors.Is.
+type MFARequiredError struct {
+	Challenge model.PendingMFA
+}
+
+func (e *MFARequiredError) Error() string {
+	return ErrMFARequired.Error()
+}
+
+func (e *MFARequiredError) Is(target error) bool {
+	return target == ErrMFARequired
+}
+
+type mfaVerifier struct {
+	totp       repo.TOTPRepo
+	codes      repo.RecoveryCodeRepo
+	challenges repo.MFAChallengeRepo
+	cfg        MFAConfig
+}
+
+// WithMFA lets users protect their sign-ins with an authenticator app.
+func WithMFA(totp repo.TOTPRepo, codes repo.RecoveryCodeRepo, challenges repo.MFAChallengeRepo,
+	cfg MFAConfig) AuthOption {
+	return func(s *authService) {
+		s.mfa = &mfaVerifier{
+			totp:       totp,
+			codes:      codes,
+			challenges: challenges,
+			cfg:        cfg,
+		}
+	}
+}
+
+// EnrollTOTP starts adding an authenticator app after checking the user's
+// password. It replaces an enrollment that was never confirmed, and only
+// takes effect once ConfirmTOTP accepts a code from the app.
+func (s *authService) EnrollTOTP(ctx context.Context, user *model.User,
+	params model.CurrentPasswordParams) (*model.TOTPEnrollment, error) {
+	if s.mfa == nil {
+		return nil, ErrMFADisabled
+	}
+
+	if err := s.checkPassword(ctx, user, params.CurrentPassword); err != nil {
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("generate totp secret: %w", err)
+	}
+
+	if _, err := s.mfa.totp.SaveTOTP(ctx, user.ID, totp.EncodeSecret(secret)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMFAAlreadyEnabled
+		}
+
+		return nil, fmt.Errorf("save totp: %w", err)
+	}
+
+	uri := totp.URI(s.mfa.cfg.Issuer, user.Email, secret)
+	png, err := totp.QRCode(uri, qrCodeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TOTPEnrollment{
+		Secret: totp.EncodeSecret(secret),
+		URI:    uri,
+		QRCode: png,
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication once the user proves their
+// app generates valid codes, and returns their first recovery codes.
+func (s *authService) ConfirmTOTP(ctx context.Context, user *model.User,
+	params model.TOTPCodeParams) (*model.RecoveryCodes, error) {
+	if s.mfa == nil {
+		return nil, ErrMFADisabled
+	}
+
+	enrollment, err := s.mfa.totp.FindTOTP(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMFANotEnrolled
+		}
+
+		return nil, fmt.Errorf("find totp: %w", err)
+	}
+
+	if enrollment.ConfirmedAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.DecodeSecret(enrollment.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("decode totp secret: %w", err)
+	}
+
+	now := time.Now().UTC()
+	step, ok := totp.Validate(secret, normalizeMFACode(params.Code), now)
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	if err := s.mfa.totp.ConfirmTOTP(ctx, user.ID, step, now); err != nil {
+		// A concurrent request confirmed or replaced the enrollment.
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMFAAlreadyEnabled
+		}
+
+		return nil, fmt.Errorf("confirm totp: %w", err)
+	}
+
+	return s.mfa.issueRecoveryCodes(ctx, user.ID)
+}
+
+// DisableTOTP turns two-factor authentication off after checking the user's
+// password and a code. A user who lost their app can use a recovery code and
+// then enroll again.
+func (s *authService) DisableTOTP(ctx context.Context, user *model.User, params model.MFACodeParams) error {
+	if s.mfa == nil {
+		return ErrMFADisabled
+	}
+
+	if err := s.checkPassword(ctx, user, params.CurrentPassword); err != nil {
+		return err
+	}
+
+	if err := s.mfa.verify(ctx, user.ID, params.Code, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if err := s.mfa.totp.DeleteTOTP(ctx, user.ID); err != nil {
+		return fmt.Errorf("delete totp: %w", err)
+	}
+
+	if err := s.mfa.codes.DeleteRecoveryCodes(ctx, user.ID); err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes after checking
+// their password and a code. The old codes stop working.
+func (s *authService) RegenerateRecoveryCodes(ctx context.Context, user *model.User,
+	params model.MFACodeParams) (*model.RecoveryCodes, error) {
+	if s.mfa == nil {
+		return nil, ErrMFADisabled
+	}
+
+	if err := s.checkPassword(ctx, user, params.CurrentPassword); err != nil {
+		return nil, err
+	}
+
+	if err := s.mfa.verify(ctx, user.ID, params.Code, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	return s.mfa.issueRecoveryCodes(ctx, user.ID)
+}
+
+// CompleteMFASignIn finishes a sign-in that returned an MFARequiredError and
+// returns the user's ID. Wrong codes count towards the account lockout, and
+// the challenge is discarded after MaxAttempts of them.
+func (s *authService) CompleteMFASignIn(ctx context.Context, params model.MFASignInParams) (string, error) {
+	if s.mfa == nil {
+		return "", ErrMFADisabled
+	}
+
+	if params.Token == "" {
+		return "", ErrInvalidToken
+	}
+
+	id := security.HashToken(params.Token)
+	challenge, err := s.mfa.challenges.FindMFAChallenge(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrInvalidToken
+		}
+
+		return "", fmt.Errorf("find mfa challenge: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if !now.Before(challenge.ExpiresAt) || challenge.Failures >= s.mfa.cfg.MaxAttempts {
+		return "", ErrInvalidToken
+	}
+
+	user, err := s.repo.FindUserByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrInvalidToken
+		}
+
+		return "", fmt.Errorf("find user by id: %w", err)
+	}
+
+	if s.throttle == nil {
+		if err := s.completeMFAChallenge(ctx, challenge.ID, user.ID, params.Code, now); err != nil {
+			return "", err
+		}
+
+		return user.ID, nil
+	}
+
+	keys := s.throttle.keys(ctx, user.Email)
+	if err := s.throttle.begin(ctx, keys, now); err != nil {
+		return "", err
+	}
+
+	err = s.completeMFAChallenge(ctx, challenge.ID, user.ID, params.Code, now)
+	switch {
+	case errors.Is(err, ErrInvalidMFACode):
+		// begin already counted the failure.
+	case err == nil:
+		if err := s.throttle.recordSuccess(ctx, keys); err != nil {
+			return "", err
+		}
+	default:
+		if err := s.throttle.abandon(ctx, keys); err != nil {
+			return "", err
+		}
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return user.ID, nil
+}
+
+// completeMFAChallenge checks the code and consumes the challenge it answers.
+func (s *authService) completeMFAChallenge(ctx context.Context, id, userID, code string, now time.Time) error {
+	if err := s.mfa.verify(ctx, userID, code, now); err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidMFACode):
+			return s.recordMFAFailure(ctx, id)
+		case errors.Is(err, ErrMFANotEnrolled):
+			// The user turned two-factor authentication off since signing in.
+			return ErrInvalidToken
+		default:
+			return err
+		}
+	}
+
+	if _, err := s.mfa.challenges.ConsumeMFAChallenge(ctx, id); err != nil {
+		// A concurrent request completed or discarded the challenge.
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidToken
+		}
+
+		return fmt.Errorf("consume mfa challenge: %w", err)
+	}
+
+	return nil
+}
+
+// CompleteMFATokenSignIn finishes a sign-in like CompleteMFASignIn and starts
+// a new refresh token family.
+func (s *authService) CompleteMFATokenSignIn(ctx context.Context, params model.MFASignInParams) (*model.TokenPair, error) {
+	if s.tokens == nil {
+		return nil, ErrTokensDisabled
+	}
+
+	userID, err := s.CompleteMFASignIn(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tokens.issueFamily(ctx, userID)
+}
+
+// recordMFAFailure counts a wrong code against the challenge, and returns
+// ErrInvalidMFACode.
+func (s *authService) recordMFAFailure(ctx context.Context, id string) error {
+	failures, err := s.mfa.challenges.RecordMFAFailure(ctx, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("record mfa failure: %w", err)
+	}
+
+	if failures >= s.mfa.cfg.MaxAttempts {
+		if _, err := s.mfa.challenges.ConsumeMFAChallenge(ctx, id); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("consume mfa challenge: %w", err)
+		}
+	}
+
+	return ErrInvalidMFACode
+}
+
+// requireSecondFactor returns an MFARequiredError with a new challenge if
+// the user has enabled two-factor authentication, and nil otherwise.
+func (m *mfaVerifier) requireSecondFactor(ctx context.Context, userID string) error {
+	enrollment, err := m.totp.FindTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return fmt.Errorf("find totp: %w", err)
+	}
+
+	if enrollment.ConfirmedAt == nil {
+		return nil
+	}
+
+	token, err := security.GenerateRandomBytesEncoded(MFAChallengeLength)
+	if err != nil {
+		return fmt.Errorf("generate mfa challenge: %w", err)
+	}
+
+	params := model.MFAChallenge{
+		ID:        security.HashToken(token),
+		UserID:    userID,
+		ExpiresAt: time.Now().UTC().Add(m.cfg.ChallengeTTL),
+	}
+
+	challenge, err := m.challenges.CreateMFAChallenge(ctx, params)
+	if err != nil {
+		return fmt.Errorf("create mfa challenge: %w", err)
+	}
+
+	return &MFARequiredError{
+		Challenge: model.PendingMFA{
+			Token:     token,
+			ExpiresAt: challenge.ExpiresAt,
+		},
+	}
+}
+
+// verify checks a code from the user's authenticator app or one of their
+// recovery codes, and uses it up. It returns ErrMFANotEnrolled unless the
+// user has a confirmed enrollment.
+func (m *mfaVerifier) verify(ctx context.Context, userID, code string, now time.Time) error {
+	enrollment, err := m.totp.FindTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrMFANotEnrolled
+		}
+
+		return fmt.Errorf("find totp: %w", err)
+	}
+
+	if enrollment.ConfirmedAt == nil {
+		return ErrMFANotEnrolled
+	}
+
+	code = normalizeMFACode(code)
+	if !isTOTPCode(code) {
+		return m.useRecoveryCode(ctx, userID, code, now)
+	}
+
+	secret, err := totp.DecodeSecret(enrollment.Secret)
+	if err != nil {
+		return fmt.Errorf("decode totp secret: %w", err)
+	}
+
+	step, ok := totp.Validate(secret, code, now)
+	if !ok || step <= enrollment.LastStep {
+		return ErrInvalidMFACode
+	}
+
+	if err := m.totp.UpdateTOTPStep(ctx, userID, step); err != nil {
+		// A concurrent request accepted this or a later code.
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidMFACode
+		}
+
+		return fmt.Errorf("update totp step: %w", err)
+	}
+
+	return nil
+}
+
+func (m *mfaVerifier) useRecoveryCode(ctx context.Context, userID, code string, now time.Time) error {
+	if len(code) != recoveryCodeLength {
+		return ErrInvalidMFACode
+	}
+
+	if err := m.codes.UseRecoveryCode(ctx, userID, security.HashToken(code), now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidMFACode
+		}
+
+		return fmt.Errorf("use recovery code: %w", err)
+	}
+
+	return nil
+}
+
+// issueRecoveryCodes replaces the user's recovery codes, storing only their
+// hashes. Codes are shown as two groups of five characters.
+func (m *mfaVerifier) issueRecoveryCodes(ctx context.Context, userID string) (*model.RecoveryCodes, error) {
+	codes := make([]string, m.cfg.RecoveryCodes)
+	hashes := make([]string, m.cfg.RecoveryCodes)
+
+	for i := range codes {
+		// 7 bytes encode to 12 base32 characters, of which the first 10 are kept.
+		b, err := security.GenerateRandomBytes(7)
+		if err != nil {
+			return nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(b))[:recoveryCodeLength]
+		codes[i] = code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:]
+		hashes[i] = security.HashToken(code)
+	}
+
+	if err := m.codes.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, fmt.Errorf("replace recovery codes: %w", err)
+	}
+
+	return &model.RecoveryCodes{Codes: codes}, nil
+}
+
+// normalizeMFACode drops the separators users may type within codes, such as
+// "123 456" or "abcde-fghij", and lowercases recovery codes.
+func normalizeMFACode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,341 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/totp"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	secMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/security/mocks"
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+)
+
+const (
+	testMFAToken     = "mfatoken"
+	testRecoveryCode = "abcde-fghij"
+)
+
+var testMFAConfig = service.MFAConfig{
+	Issuer:        "Example",
+	ChallengeTTL:  5 * time.Minute,
+	MaxAttempts:   3,
+	RecoveryCodes: 10,
+}
+
+// testTOTPSecret is the RFC 6238 test key.
+var testTOTPSecret = []byte("12345678901234567890")
+
+type mfaMocks struct {
+	users      *repoMocks.MockUserRepo
+	hasher     *secMocks.MockHasher
+	totp       *repoMocks.MockTOTPRepo
+	codes      *repoMocks.MockRecoveryCodeRepo
+	challenges *repoMocks.MockMFAChallengeRepo
+}
+
+func TestAuthService_SignInUser_MFARequired(t *testing.T) {
+	m, authService := setupMFAMocks(t)
+	ctx := context.Background()
+	var created model.MFAChallenge
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(true, nil)
+	m.totp.EXPECT().FindTOTP(ctx, testID).Return(confirmedTOTP(0), nil)
+	m.challenges.EXPECT().CreateMFAChallenge(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, params model.MFAChallenge) (*model.MFAChallenge, error) {
+			created = params
+			return &params, nil
+		})
+
+	userID, err := authService.SignInUser(ctx, model.UserSignInParams{Email: testEmail, Password: testPassword})
+
+	assert.ErrorIs(t, err, service.ErrMFARequired, "errors should match")
+	assert.Empty(t, userID, "no user should be signed in")
+
+	var mfaErr *service.MFARequiredError
+	if assert.True(t, errors.As(err, &mfaErr), "error should carry a challenge") {
+		assert.NotEmpty(t, mfaErr.Challenge.Token, "challenge token should not be empty")
+		assert.Equal(t, security.HashToken(mfaErr.Challenge.Token), created.ID, "only the token hash should be stored")
+		assert.Equal(t, testID, created.UserID, "user ID should match")
+		assert.WithinDuration(t, time.Now().Add(testMFAConfig.ChallengeTTL), created.ExpiresAt, time.Minute,
+			"expiry should match")
+	}
+}
+
+func TestAuthService_SignInUser_MFANotConfirmed(t *testing.T) {
+	m, authService := setupMFAMocks(t)
+	ctx := context.Background()
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(true, nil)
+	m.totp.EXPECT().FindTOTP(ctx, testID).Return(&model.TOTP{UserID: testID}, nil)
+	m.challenges.EXPECT().CreateMFAChallenge(gomock.Any(), gomock.Any()).Times(0)
+
+	userID, err := authService.SignInUser(ctx, model.UserSignInParams{Email: testEmail, Password: testPassword})
+
+	assert.NoError(t, err, "unconfirmed enrollments should not require a code")
+	assert.Equal(t, testID, userID, "user ID should match")
+}
+
+func TestAuthService_CompleteMFASignIn_TOTP(t *testing.T) {
+	m, authService := setupMFAMocks(t)
+	ctx := context.Background()
+	step := totp.Step(time.Now())
+	id := security.HashToken(testMFAToken)
+
+	expectChallenge(ctx, m, 0)
+	m.totp.EXPECT().FindTOTP(ctx, testID).Return(confirmedTOTP(step-2), nil)
+	m.totp.EXPECT().UpdateTOTPStep(ctx, testID, step).Return(nil)
+	m.challenges.EXPECT().ConsumeMFAChallenge(ctx, id).Return(&model.MFAChallenge{ID: id, UserID: testID}, nil)
+
+	userID, err := authService.CompleteMFASignIn(ctx, model.MFASignInParams{
+		Token: testMFAToken,
+		Code:  totp.Code(testTOTPSecret, step),
+	})
+
+	assert.NoError(t, err, "complete mfa signin should not return an error")
+	assert.Equal(t, testID, userID, "user ID should match")
+}
+
+func TestAuthService_CompleteMFASignIn_RecoveryCode(t *testing.T) {
+	m, authService := setupMFAMocks(t)
+	ctx := context.Background()
+	id := security.HashToken(testMFAToken)
+
+	expectChallenge(ctx, m, 0)
+	m.totp.EXPECT().FindTOTP(ctx, testID).Return(confirmedTOTP(0), nil)
+	m.codes.EXPECT().UseRecoveryCode(ctx, testID, security.HashToken("abcdefghij"), gomock.Any()).Return(nil)
+	m.challenges.EXPECT().ConsumeMFAChallenge(ctx, id).Return(&model.MFAChallenge{ID: id, UserID: testID}, nil)
+
+	userID, err := authService.CompleteMFASignIn(ctx, model.MFASignInParams{
+		Token: testMFAToken,
+		Code:  strings.ToUpper(testRecoveryCode),
+	})
+
+	assert.NoError(t, err, "recovery codes should be accepted in any case")
+	assert.Equal(t, testID, userID, "user ID should match")
+}
+
+func TestAuthService_CompleteMFASignIn_ReplayedCode(t *testing.T) {
+	m, authService := setupMFAMocks(t)
+	ctx := context.Background()
+	step := totp.Step(time.Now())
+	id := security.HashToken(testMFAToken)
+
+	expectChallenge(ctx, m, 0)
+	m.totp.EXPECT().FindTOTP(ctx, testID).Return(confirmedTOTP(step), nil)
+	m.totp.EXPECT().UpdateTOTPStep(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	m.challenges.EXPECT().RecordMFAFailure(ctx, id).Return(1, nil)
+
+	_, err := authService.CompleteMFASignIn(ctx, model.MFASignInParams{
+		Token: testMFAToken,
+		Code:  totp.Code(testTOTPSecret, step),
+	})
+
+	assert.ErrorIs(t, err, service.ErrInvalidMFACode, "an accepted code should not be accepted again")
+}
+
+func TestAuthService_CompleteMFASignIn_DiscardsAfterMaxAttempts(t *testing.T) {
+	m, authService := setupMFAMocks(t)
+	ctx := context.Background()
+	id := security.HashToken(testMFAToken)
+
+	expectChallenge(ctx, m, testMFAConfig.MaxAttempts-1)
+	m.totp.EXPECT().FindTOTP(ctx, testID).Return(confirmedTOTP(0), nil)
+	m.codes.EXPECT().UseRecoveryCode(ctx, testID, gomock.Any(), gomock.Any()).Return(sql.ErrNoRows)
+	m.challenges.EXPECT().RecordMFAFailure(ctx, id).Return(testMFAConfig.MaxAttempts, nil)
+	m.challenges.EXPECT().ConsumeMFAChallenge(ctx, id).Return(&model.MFAChallenge{ID: id}, nil)
+
+	_, err := authService.CompleteMFASignIn(ctx, model.MFASignInParams{Token: testMFAToken, Code: testRecoveryCode})
+
+	assert.ErrorIs(t, err, service.ErrInvalidMFACode, "errors should match")
+}
+
+func TestAuthService_CompleteMFASignIn_InvalidChallenge(t *testing.T) {
+	tests := []struct {
+		name      string
+		challenge *model.MFAChallenge
+		err       error
+	}{
+		{"unknown", nil, sql.ErrNoRows},
+		{"expired", &model.MFAChallenge{UserID: testID, ExpiresAt: time.Now().Add(-time.Second)}, nil},
+		{"too many failures", &model.MFAChallenge{
+			UserID: testID, Failures: testMFAConfig.MaxAttempts, ExpiresAt: time.Now().Add(time.Minute),
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, authService := setupMFAMocks(t)
+			ctx := context.Background()
+
+			m.challenges.EXPECT().FindMFAChallenge(ctx, security.HashToken(testMFAToken)).Return(tt.challenge, tt.err)
+			m.totp.EXPECT().FindTOTP(gomock.Any(), gomock.Any()).Times(0)
+
+			_, err := authService.CompleteMFASignIn(ctx, model.MFASignInParams{Token: testMFAToken, Code: "123456"})
+
+			assert.ErrorIs(t, err, service.ErrInvalidToken, "errors should match")
+		})
+	}
+}
+
+func TestAuthService_EnrollTOTP_Success(t *testing.T) {
+	m, authService := setupMFAMocks(t)
+	ctx := context.Background()
+	var saved string
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(true, nil)
+	m.totp.EXPECT().SaveTOTP(ctx, testID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, userID, secret string) (*model.TOTP, error) {
+			saved = secret
+			return &model.TOTP{UserID: userID, Secret: secret}, nil
+		})
+
+	enrollment, err := authService.EnrollTOTP(ctx, testUser, model.CurrentPasswordParams{CurrentPassword: testPassword})
+
+	assert.NoError(t, err, "enroll totp should not return an error")
+	assert.Equal(t, saved, enrollment.Secret, "secret should match")
+	assert.Contains(t, enrollment.URI, "otpauth://totp/Example:"+testEmail, "uri should match")
+	assert.NotEmpty(t, enrollment.QRCode, "qr code should not be empty")
+}
+
+func TestAuthService_EnrollTOTP_AlreadyEnabled(t *testing.T) {
+	m, authService := setupMFAMocks(t)
+	ctx := context.Background()
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(true, nil)
+	m.totp.EXPECT().SaveTOTP(ctx, testID, gomock.Any()).Return(nil, sql.ErrNoRows)
+
+	_, err := authService.EnrollTOTP(ctx, testUser, model.CurrentPasswordParams{CurrentPassword: testPassword})
+
+	assert.ErrorIs(t, err, service.ErrMFAAlreadyEnabled, "errors should match")
+}
+
+func TestAuthService_ConfirmTOTP_IssuesRecoveryCodes(t *testing.T) {
+	m, authService := setupMFAMocks(t)
+	ctx := context.Background()
+	step := totp.Step(time.Now())
+	var hashes []string
+
+	m.totp.EXPECT().FindTOTP(ctx, testID).
+		Return(&model.TOTP{UserID: testID, Secret: totp.EncodeSecret(testTOTPSecret)}, nil)
+	m.totp.EXPECT().ConfirmTOTP(ctx, testID, step, gomock.Any()).Return(nil)
+	m.codes.EXPECT().ReplaceRecoveryCodes(ctx, testID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, codeHashes []string) error {
+			hashes = codeHashes
+			return nil
+		})
+
+	codes, err := authService.ConfirmTOTP(ctx, testUser, model.TOTPCodeParams{Code: totp.Code(testTOTPSecret, step)})
+
+	assert.NoError(t, err, "confirm totp should not return an error")
+	if assert.Len(t, codes.Codes, testMFAConfig.RecoveryCodes, "recovery code count should match") {
+		assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, codes.Codes[0], "recovery code format should match")
+		assert.Equal(t, security.HashToken(strings.ReplaceAll(codes.Codes[0], "-", "")), hashes[0],
+			"only code hashes should be stored")
+	}
+}
+
+func TestAuthService_ConfirmTOTP_WrongCode(t *testing.T) {
+	m, authService := setupMFAMocks(t)
+	ctx := context.Background()
+	step := totp.Step(time.Now())
+
+	m.totp.EXPECT().FindTOTP(ctx, testID).
+		Return(&model.TOTP{UserID: testID, Secret: totp.EncodeSecret(testTOTPSecret)}, nil)
+	m.totp.EXPECT().ConfirmTOTP(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := authService.ConfirmTOTP(ctx, testUser, model.TOTPCodeParams{Code: totp.Code(testTOTPSecret, step+5)})
+
+	assert.ErrorIs(t, err, service.ErrInvalidMFACode, "errors should match")
+}
+
+func TestAuthService_DisableTOTP_Success(t *testing.T) {
+	m, authService := setupMFAMocks(t)
+	ctx := context.Background()
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(true, nil)
+	m.totp.EXPECT().FindTOTP(ctx, testID).Return(confirmedTOTP(0), nil)
+	m.codes.EXPECT().UseRecoveryCode(ctx, testID, security.HashToken("abcdefghij"), gomock.Any()).Return(nil)
+	m.totp.EXPECT().DeleteTOTP(ctx, testID).Return(nil)
+	m.codes.EXPECT().DeleteRecoveryCodes(ctx, testID).Return(nil)
+
+	err := authService.DisableTOTP(ctx, testUser, model.MFACodeParams{CurrentPassword: testPassword, Code: testRecoveryCode})
+
+	assert.NoError(t, err, "disable totp should not return an error")
+}
+
+func TestAuthService_DisableTOTP_NotEnrolled(t *testing.T) {
+	m, authService := setupMFAMocks(t)
+	ctx := context.Background()
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(true, nil)
+	m.totp.EXPECT().FindTOTP(ctx, testID).Return(nil, sql.ErrNoRows)
+	m.totp.EXPECT().DeleteTOTP(gomock.Any(), gomock.Any()).Times(0)
+
+	err := authService.DisableTOTP(ctx, testUser, model.MFACodeParams{CurrentPassword: testPassword, Code: "123456"})
+
+	assert.ErrorIs(t, err, service.ErrMFANotEnrolled, "errors should match")
+}
+
+func TestAuthService_MFA_Disabled(t *testing.T) {
+	_, _, authService := setupMocks(t)
+	ctx := context.Background()
+
+	_, err := authService.EnrollTOTP(ctx, testUser, model.CurrentPasswordParams{CurrentPassword: testPassword})
+	assert.ErrorIs(t, err, service.ErrMFADisabled, "errors should match")
+
+	_, err = authService.CompleteMFASignIn(ctx, model.MFASignInParams{Token: testMFAToken, Code: "123456"})
+	assert.ErrorIs(t, err, service.ErrMFADisabled, "errors should match")
+}
+
+func setupMFAMocks(t *testing.T) (*mfaMocks, service.AuthService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := &mfaMocks{
+		users:      repoMocks.NewMockUserRepo(ctrl),
+		hasher:     secMocks.NewMockHasher(ctrl),
+		totp:       repoMocks.NewMockTOTPRepo(ctrl),
+		codes:      repoMocks.NewMockRecoveryCodeRepo(ctrl),
+		challenges: repoMocks.NewMockMFAChallengeRepo(ctrl),
+	}
+	authService := service.NewAuthService(m.users, m.hasher,
+		service.WithMFA(m.totp, m.codes, m.challenges, testMFAConfig))
+
+	return m, authService
+}
+
+// expectChallenge expects the lookup of a live challenge for testMFAToken
+// and of the user it was issued to.
+func expectChallenge(ctx context.Context, m *mfaMocks, failures int) {
+	m.challenges.EXPECT().FindMFAChallenge(ctx, security.HashToken(testMFAToken)).Return(&model.MFAChallenge{
+		ID:        security.HashToken(testMFAToken),
+		UserID:    testID,
+		Failures:  failures,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, nil)
+	m.users.EXPECT().FindUserByID(ctx, testID).Return(&model.User{ID: testID, Email: testEmail}, nil)
+}
+
+func confirmedTOTP(lastStep int64) *model.TOTP {
+	confirmedAt := time.Now().Add(-time.Hour)
+	return &model.TOTP{
+		UserID:      testID,
+		Secret:      totp.EncodeSecret(testTOTPSecret),
+		LastStep:    lastStep,
+		ConfirmedAt: &confirmedAt,
+	}
+}
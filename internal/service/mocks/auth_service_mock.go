@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	webauthn "github.com/ferdiebergado/fullstackgo/internal/pkg/webauthn"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,6 +42,344 @@ func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
 	return m.recorder
 }
 
+// BeginOIDCSignIn mocks base method.
+func (m *MockAuthService) BeginOIDCSignIn(ctx context.Context, provider string) (*model.OIDCAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginOIDCSignIn", ctx, provider)
+	ret0, _ := ret[0].(*model.OIDCAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginOIDCSignIn indicates an expected call of BeginOIDCSignIn.
+func (mr *MockAuthServiceMockRecorder) BeginOIDCSignIn(ctx, provider any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginOIDCSignIn", reflect.TypeOf((*MockAuthService)(nil).BeginOIDCSignIn), ctx, provider)
+}
+
+// BeginPasskeyRegistration mocks base method.
+func (m *MockAuthService) BeginPasskeyRegistration(ctx context.Context, user *model.User, params model.CurrentPasswordParams) (*webauthn.CreationOptions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginPasskeyRegistration", ctx, user, params)
+	ret0, _ := ret[0].(*webauthn.CreationOptions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginPasskeyRegistration indicates an expected call of BeginPasskeyRegistration.
+func (mr *MockAuthServiceMockRecorder) BeginPasskeyRegistration(ctx, user, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginPasskeyRegistration", reflect.TypeOf((*MockAuthService)(nil).BeginPasskeyRegistration), ctx, user, params)
+}
+
+// BeginPasskeySignIn mocks base method.
+func (m *MockAuthService) BeginPasskeySignIn(ctx context.Context) (*webauthn.RequestOptions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginPasskeySignIn", ctx)
+	ret0, _ := ret[0].(*webauthn.RequestOptions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginPasskeySignIn indicates an expected call of BeginPasskeySignIn.
+func (mr *MockAuthServiceMockRecorder) BeginPasskeySignIn(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginPasskeySignIn", reflect.TypeOf((*MockAuthService)(nil).BeginPasskeySignIn), ctx)
+}
+
+// ChangeEmail mocks base method.
+func (m *MockAuthService) ChangeEmail(ctx context.Context, user *model.User, params model.ChangeEmailParams) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeEmail", ctx, user, params)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeEmail indicates an expected call of ChangeEmail.
+func (mr *MockAuthServiceMockRecorder) ChangeEmail(ctx, user, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeEmail", reflect.TypeOf((*MockAuthService)(nil).ChangeEmail), ctx, user, params)
+}
+
+// ChangePassword mocks base method.
+func (m *MockAuthService) ChangePassword(ctx context.Context, user *model.User, params model.ChangePasswordParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, user, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockAuthServiceMockRecorder) ChangePassword(ctx, user, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAuthService)(nil).ChangePassword), ctx, user, params)
+}
+
+// CompleteMFASignIn mocks base method.
+func (m *MockAuthService) CompleteMFASignIn(ctx context.Context, params model.MFASignInParams) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteMFASignIn", ctx, params)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteMFASignIn indicates an expected call of CompleteMFASignIn.
+func (mr *MockAuthServiceMockRecorder) CompleteMFASignIn(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteMFASignIn", reflect.TypeOf((*MockAuthService)(nil).CompleteMFASignIn), ctx, params)
+}
+
+// CompleteMFATokenSignIn mocks base method.
+func (m *MockAuthService) CompleteMFATokenSignIn(ctx context.Context, params model.MFASignInParams) (*model.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteMFATokenSignIn", ctx, params)
+	ret0, _ := ret[0].(*model.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteMFATokenSignIn indicates an expected call of CompleteMFATokenSignIn.
+func (mr *MockAuthServiceMockRecorder) CompleteMFATokenSignIn(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteMFATokenSignIn", reflect.TypeOf((*MockAuthService)(nil).CompleteMFATokenSignIn), ctx, params)
+}
+
+// ConfirmTOTP mocks base method.
+func (m *MockAuthService) ConfirmTOTP(ctx context.Context, user *model.User, params model.TOTPCodeParams) (*model.RecoveryCodes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTP", ctx, user, params)
+	ret0, _ := ret[0].(*model.RecoveryCodes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTOTP indicates an expected call of ConfirmTOTP.
+func (mr *MockAuthServiceMockRecorder) ConfirmTOTP(ctx, user, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTP", reflect.TypeOf((*MockAuthService)(nil).ConfirmTOTP), ctx, user, params)
+}
+
+// DeletePasskey mocks base method.
+func (m *MockAuthService) DeletePasskey(ctx context.Context, user *model.User, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePasskey", ctx, user, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePasskey indicates an expected call of DeletePasskey.
+func (mr *MockAuthServiceMockRecorder) DeletePasskey(ctx, user, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePasskey", reflect.TypeOf((*MockAuthService)(nil).DeletePasskey), ctx, user, id)
+}
+
+// DisableTOTP mocks base method.
+func (m *MockAuthService) DisableTOTP(ctx context.Context, user *model.User, params model.MFACodeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTP", ctx, user, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockAuthServiceMockRecorder) DisableTOTP(ctx, user, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockAuthService)(nil).DisableTOTP), ctx, user, params)
+}
+
+// EnrollTOTP mocks base method.
+func (m *MockAuthService) EnrollTOTP(ctx context.Context, user *model.User, params model.CurrentPasswordParams) (*model.TOTPEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTOTP", ctx, user, params)
+	ret0, _ := ret[0].(*model.TOTPEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTOTP indicates an expected call of EnrollTOTP.
+func (mr *MockAuthServiceMockRecorder) EnrollTOTP(ctx, user, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTOTP", reflect.TypeOf((*MockAuthService)(nil).EnrollTOTP), ctx, user, params)
+}
+
+// FinishOIDCSignIn mocks base method.
+func (m *MockAuthService) FinishOIDCSignIn(ctx context.Context, provider string, params model.OIDCCallbackParams) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishOIDCSignIn", ctx, provider, params)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishOIDCSignIn indicates an expected call of FinishOIDCSignIn.
+func (mr *MockAuthServiceMockRecorder) FinishOIDCSignIn(ctx, provider, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishOIDCSignIn", reflect.TypeOf((*MockAuthService)(nil).FinishOIDCSignIn), ctx, provider, params)
+}
+
+// FinishPasskeyRegistration mocks base method.
+func (m *MockAuthService) FinishPasskeyRegistration(ctx context.Context, user *model.User, params model.PasskeyRegistrationParams) (*model.Passkey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishPasskeyRegistration", ctx, user, params)
+	ret0, _ := ret[0].(*model.Passkey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishPasskeyRegistration indicates an expected call of FinishPasskeyRegistration.
+func (mr *MockAuthServiceMockRecorder) FinishPasskeyRegistration(ctx, user, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishPasskeyRegistration", reflect.TypeOf((*MockAuthService)(nil).FinishPasskeyRegistration), ctx, user, params)
+}
+
+// FinishPasskeySignIn mocks base method.
+func (m *MockAuthService) FinishPasskeySignIn(ctx context.Context, params model.PasskeySignInParams) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishPasskeySignIn", ctx, params)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishPasskeySignIn indicates an expected call of FinishPasskeySignIn.
+func (mr *MockAuthServiceMockRecorder) FinishPasskeySignIn(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishPasskeySignIn", reflect.TypeOf((*MockAuthService)(nil).FinishPasskeySignIn), ctx, params)
+}
+
+// ForgotPassword mocks base method.
+func (m *MockAuthService) ForgotPassword(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockAuthServiceMockRecorder) ForgotPassword(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockAuthService)(nil).ForgotPassword), ctx, email)
+}
+
+// ListIdentities mocks base method.
+func (m *MockAuthService) ListIdentities(ctx context.Context, user *model.User) ([]model.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIdentities", ctx, user)
+	ret0, _ := ret[0].([]model.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIdentities indicates an expected call of ListIdentities.
+func (mr *MockAuthServiceMockRecorder) ListIdentities(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIdentities", reflect.TypeOf((*MockAuthService)(nil).ListIdentities), ctx, user)
+}
+
+// ListPasskeys mocks base method.
+func (m *MockAuthService) ListPasskeys(ctx context.Context, user *model.User) ([]model.Passkey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPasskeys", ctx, user)
+	ret0, _ := ret[0].([]model.Passkey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPasskeys indicates an expected call of ListPasskeys.
+func (mr *MockAuthServiceMockRecorder) ListPasskeys(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPasskeys", reflect.TypeOf((*MockAuthService)(nil).ListPasskeys), ctx, user)
+}
+
+// RefreshTokens mocks base method.
+func (m *MockAuthService) RefreshTokens(ctx context.Context, refreshToken string) (*model.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTokens", ctx, refreshToken)
+	ret0, _ := ret[0].(*model.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshTokens indicates an expected call of RefreshTokens.
+func (mr *MockAuthServiceMockRecorder) RefreshTokens(ctx, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokens", reflect.TypeOf((*MockAuthService)(nil).RefreshTokens), ctx, refreshToken)
+}
+
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockAuthService) RegenerateRecoveryCodes(ctx context.Context, user *model.User, params model.MFACodeParams) (*model.RecoveryCodes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", ctx, user, params)
+	ret0, _ := ret[0].(*model.RecoveryCodes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockAuthServiceMockRecorder) RegenerateRecoveryCodes(ctx, user, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockAuthService)(nil).RegenerateRecoveryCodes), ctx, user, params)
+}
+
+// RequestMagicLink mocks base method.
+func (m *MockAuthService) RequestMagicLink(ctx context.Context, email string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestMagicLink", ctx, email)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestMagicLink indicates an expected call of RequestMagicLink.
+func (mr *MockAuthServiceMockRecorder) RequestMagicLink(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestMagicLink", reflect.TypeOf((*MockAuthService)(nil).RequestMagicLink), ctx, email)
+}
+
+// ResendVerification mocks base method.
+func (m *MockAuthService) ResendVerification(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerification", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendVerification indicates an expected call of ResendVerification.
+func (mr *MockAuthServiceMockRecorder) ResendVerification(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerification", reflect.TypeOf((*MockAuthService)(nil).ResendVerification), ctx, email)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAuthService) ResetPassword(ctx context.Context, params model.ResetPasswordParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAuthServiceMockRecorder) ResetPassword(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthService)(nil).ResetPassword), ctx, params)
+}
+
+// RevokeRefreshToken mocks base method.
+func (m *MockAuthService) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshToken", ctx, refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshToken indicates an expected call of RevokeRefreshToken.
+func (mr *MockAuthServiceMockRecorder) RevokeRefreshToken(ctx, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshToken", reflect.TypeOf((*MockAuthService)(nil).RevokeRefreshToken), ctx, refreshToken)
+}
+
 // SignInUser mocks base method.
 func (m *MockAuthService) SignInUser(ctx context.Context, params model.UserSignInParams) (string, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +395,36 @@ func (mr *MockAuthServiceMockRecorder) SignInUser(ctx, params any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignInUser", reflect.TypeOf((*MockAuthService)(nil).SignInUser), ctx, params)
 }
 
+// SignInWithMagicLink mocks base method.
+func (m *MockAuthService) SignInWithMagicLink(ctx context.Context, params model.MagicLinkSignInParams) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignInWithMagicLink", ctx, params)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignInWithMagicLink indicates an expected call of SignInWithMagicLink.
+func (mr *MockAuthServiceMockRecorder) SignInWithMagicLink(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignInWithMagicLink", reflect.TypeOf((*MockAuthService)(nil).SignInWithMagicLink), ctx, params)
+}
+
+// SignInWithToken mocks base method.
+func (m *MockAuthService) SignInWithToken(ctx context.Context, params model.UserSignInParams) (*model.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignInWithToken", ctx, params)
+	ret0, _ := ret[0].(*model.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignInWithToken indicates an expected call of SignInWithToken.
+func (mr *MockAuthServiceMockRecorder) SignInWithToken(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignInWithToken", reflect.TypeOf((*MockAuthService)(nil).SignInWithToken), ctx, params)
+}
+
 // SignUpUser mocks base method.
 func (m *MockAuthService) SignUpUser(ctx context.Context, params model.UserSignUpParams) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -70,3 +439,32 @@ func (mr *MockAuthServiceMockRecorder) SignUpUser(ctx, params any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignUpUser", reflect.TypeOf((*MockAuthService)(nil).SignUpUser), ctx, params)
 }
+
+// VerifyAccessToken mocks base method.
+func (m *MockAuthService) VerifyAccessToken(ctx context.Context, accessToken string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAccessToken", ctx, accessToken)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAccessToken indicates an expected call of VerifyAccessToken.
+func (mr *MockAuthServiceMockRecorder) VerifyAccessToken(ctx, accessToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAccessToken", reflect.TypeOf((*MockAuthService)(nil).VerifyAccessToken), ctx, accessToken)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockAuthService) VerifyEmail(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockAuthServiceMockRecorder) VerifyEmail(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockAuthService)(nil).VerifyEmail), ctx, token)
+}
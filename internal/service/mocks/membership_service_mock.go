@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/fullstackgo/internal/service (interfaces: MembershipService)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/membership_service_mock.go -package=mocks . MembershipService
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ferdiebergado/fullstackgo/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMembershipService is a mock of MembershipService interface.
+type MockMembershipService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMembershipServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockMembershipServiceMockRecorder is the mock recorder for MockMembershipService.
+type MockMembershipServiceMockRecorder struct {
+	mock *MockMembershipService
+}
+
+// NewMockMembershipService creates a new mock instance.
+func NewMockMembershipService(ctrl *gomock.Controller) *MockMembershipService {
+	mock := &MockMembershipService{ctrl: ctrl}
+	mock.recorder = &MockMembershipServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMembershipService) EXPECT() *MockMembershipServiceMockRecorder {
+	return m.recorder
+}
+
+// AcceptInvitation mocks base method.
+func (m *MockMembershipService) AcceptInvitation(ctx context.Context, params model.AcceptInvitationParams) (*model.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptInvitation", ctx, params)
+	ret0, _ := ret[0].(*model.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptInvitation indicates an expected call of AcceptInvitation.
+func (mr *MockMembershipServiceMockRecorder) AcceptInvitation(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptInvitation", reflect.TypeOf((*MockMembershipService)(nil).AcceptInvitation), ctx, params)
+}
+
+// Invite mocks base method.
+func (m *MockMembershipService) Invite(ctx context.Context, params model.InvitationParams) (*model.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invite", ctx, params)
+	ret0, _ := ret[0].(*model.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Invite indicates an expected call of Invite.
+func (mr *MockMembershipServiceMockRecorder) Invite(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invite", reflect.TypeOf((*MockMembershipService)(nil).Invite), ctx, params)
+}
+
+// ListMembers mocks base method.
+func (m *MockMembershipService) ListMembers(ctx context.Context) ([]model.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", ctx)
+	ret0, _ := ret[0].([]model.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockMembershipServiceMockRecorder) ListMembers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockMembershipService)(nil).ListMembers), ctx)
+}
+
+// RemoveMember mocks base method.
+func (m *MockMembershipService) RemoveMember(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockMembershipServiceMockRecorder) RemoveMember(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockMembershipService)(nil).RemoveMember), ctx, userID)
+}
+
+// UpdateMemberRole mocks base method.
+func (m *MockMembershipService) UpdateMemberRole(ctx context.Context, userID string, params model.MembershipParams) (*model.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMemberRole", ctx, userID, params)
+	ret0, _ := ret[0].(*model.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMemberRole indicates an expected call of UpdateMemberRole.
+func (mr *MockMembershipServiceMockRecorder) UpdateMemberRole(ctx, userID, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRole", reflect.TypeOf((*MockMembershipService)(nil).UpdateMemberRole), ctx, userID, params)
+}
@@ -0,0 +1,738 @@
+//go:generate mockgen -destination=mocks/oauth_service_mock.go -package=mocks . OAuthService
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/oidc"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// OAuthClientIDLength is the number of random bytes in a client ID.
+	OAuthClientIDLength = 16
+	// OAuthSecretLength is the number of random bytes in client secrets and
+	// authorization codes.
+	OAuthSecretLength = 32
+	// minCodeVerifier and maxCodeVerifier bound the length of PKCE code
+	// verifiers (RFC 7636 section 4.1).
+	minCodeVerifier = 43
+	maxCodeVerifier = 128
+	// codeChallengeLength is the length of an encoded S256 code challenge.
+	codeChallengeLength = 43
+)
+
+// Grant types and client authentication methods supported by the
+// authorization server.
+const (
+	GrantAuthorizationCode = "authorization_code"
+	GrantClientCredentials = "client_credentials"
+	AuthMethodNone         = "none"
+	AuthMethodSecretBasic  = "client_secret_basic"
+	AuthMethodSecretPost   = "client_secret_post"
+)
+
+// Error codes of OAuth error responses (RFC 6749 section 5.2, RFC 6750
+// section 3.1 and RFC 7591 section 3.2.2).
+const (
+	OAuthInvalidRequest          = "invalid_request"
+	OAuthInvalidClient           = "invalid_client"
+	OAuthInvalidGrant            = "invalid_grant"
+	OAuthUnauthorizedClient      = "unauthorized_client"
+	OAuthUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthUnsupportedResponseType = "unsupported_response_type"
+	OAuthInvalidScope            = "invalid_scope"
+	OAuthAccessDenied            = "access_denied"
+	OAuthInvalidRedirectURI      = "invalid_redirect_uri"
+	OAuthInvalidClientMetadata   = "invalid_client_metadata"
+	OAuthInvalidToken            = "invalid_token"
+)
+
+// OAuthError is an error to report to an OAuth client in the format of RFC
+// 6749. RedirectURI is set for errors of authorization requests that are
+// reported by sending the user back to the client.
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+	RedirectURI string `json:"redirect_uri,omitempty"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+
+	return e.Code + ": " + e.Description
+}
+
+func oauthError(code, description string) *OAuthError {
+	return &OAuthError{Code: code, Description: description}
+}
+
+// OAuthConfig configures the authorization server.
+type OAuthConfig struct {
+	// AuthorizeURL is the consent page of the app that clients send users
+	// to. It passes the authorization request on to the API.
+	AuthorizeURL string
+	// CodeTTL is how long a client has to exchange an authorization code.
+	CodeTTL time.Duration
+	// AccessTokenTTL is the lifetime of issued access tokens.
+	AccessTokenTTL time.Duration
+}
+
+// OAuthService lets third-party applications act on behalf of users who
+// consent to it, or on their own behalf, with access tokens signed by the
+// app.
+type OAuthService interface {
+	Metadata() model.OAuthMetadata
+	JWKS() security.JWKSet
+	RegisterClient(ctx context.Context, params model.OAuthClientParams) (*model.OAuthClientRegistration, error)
+	Authorize(ctx context.Context, params model.OAuthAuthorizeParams) (*model.OAuthConsent, error)
+	Decide(ctx context.Context, user *model.User, params model.OAuthDecisionParams) (*model.OAuthRedirect, error)
+	Token(ctx context.Context, client model.OAuthClientCredentials,
+		params model.OAuthTokenParams) (*model.OAuthTokenResponse, error)
+	Introspect(ctx context.Context, client model.OAuthClientCredentials, token string) (*model.OAuthIntrospection, error)
+	Revoke(ctx context.Context, client model.OAuthClientCredentials, token string) error
+}
+
+type oauthService struct {
+	users   repo.UserRepo
+	clients repo.OAuthClientRepo
+	codes   repo.OAuthCodeRepo
+	tokens  repo.OAuthTokenRepo
+	signer  *security.JWTSigner
+	cfg     OAuthConfig
+}
+
+// NewOAuthService returns an authorization server issuing tokens with
+// signer, whose issuer identifies the server. The signer must not be the one
+// of the app's own access tokens, or clients could use their tokens beyond
+// the scopes granted to them.
+func NewOAuthService(users repo.UserRepo, clients repo.OAuthClientRepo, codes repo.OAuthCodeRepo,
+	tokens repo.OAuthTokenRepo, signer *security.JWTSigner, cfg OAuthConfig) OAuthService {
+	return &oauthService{
+		users:   users,
+		clients: clients,
+		codes:   codes,
+		tokens:  tokens,
+		signer:  signer,
+		cfg:     cfg,
+	}
+}
+
+// accessTokenClaims are the claims of issued access tokens, after the JWT
+// profile of RFC 9068.
+type accessTokenClaims struct {
+	jwt.RegisteredClaims
+	ClientID string `json:"client_id"`
+	Scope    string `json:"scope,omitempty"`
+}
+
+// Metadata describes the authorization server to clients (RFC 8414).
+func (s *oauthService) Metadata() model.OAuthMetadata {
+	base := strings.TrimSuffix(s.signer.Issuer(), "/")
+
+	return model.OAuthMetadata{
+		Issuer:                            s.signer.Issuer(),
+		AuthorizationEndpoint:             s.cfg.AuthorizeURL,
+		TokenEndpoint:                     base + "/oauth/token",
+		JWKSURI:                           base + "/oauth/jwks",
+		RegistrationEndpoint:              base + "/oauth/register",
+		IntrospectionEndpoint:             base + "/oauth/introspect",
+		RevocationEndpoint:                base + "/oauth/revoke",
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{GrantAuthorizationCode, GrantClientCredentials},
+		TokenEndpointAuthMethodsSupported: []string{AuthMethodSecretBasic, AuthMethodSecretPost, AuthMethodNone},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		AuthorizationResponseIssParameter: true,
+	}
+}
+
+// JWKS returns the keys that verify issued access tokens.
+func (s *oauthService) JWKS() security.JWKSet {
+	return s.signer.JWKS()
+}
+
+// RegisterClient registers a client (RFC 7591). Clients without a secret,
+// registered with the "none" authentication method, can only use the
+// authorization code grant.
+func (s *oauthService) RegisterClient(ctx context.Context,
+	params model.OAuthClientParams) (*model.OAuthClientRegistration, error) {
+	grantTypes := params.GrantTypes
+	if len(grantTypes) == 0 {
+		grantTypes = []string{GrantAuthorizationCode}
+	}
+	grantTypes = slices.Compact(slices.Sorted(slices.Values(grantTypes)))
+
+	for _, grant := range grantTypes {
+		if grant != GrantAuthorizationCode && grant != GrantClientCredentials {
+			return nil, oauthError(OAuthInvalidClientMetadata, "unsupported grant type "+grant)
+		}
+	}
+
+	authMethod := params.TokenEndpointAuthMethod
+	switch authMethod {
+	case "":
+		authMethod = AuthMethodSecretBasic
+	case AuthMethodSecretBasic, AuthMethodSecretPost, AuthMethodNone:
+	default:
+		return nil, oauthError(OAuthInvalidClientMetadata, "unsupported token endpoint auth method")
+	}
+
+	if authMethod == AuthMethodNone && slices.Contains(grantTypes, GrantClientCredentials) {
+		return nil, oauthError(OAuthInvalidClientMetadata, "client credentials require a client secret")
+	}
+
+	if slices.Contains(grantTypes, GrantAuthorizationCode) && len(params.RedirectURIs) == 0 {
+		return nil, oauthError(OAuthInvalidRedirectURI, "a redirect URI is required")
+	}
+
+	for _, uri := range params.RedirectURIs {
+		if !validRedirectURI(uri) {
+			return nil, oauthError(OAuthInvalidRedirectURI, "invalid redirect URI "+uri)
+		}
+	}
+
+	clientID, err := security.GenerateRandomBytesEncoded(OAuthClientIDLength)
+	if err != nil {
+		return nil, fmt.Errorf("generate client id: %w", err)
+	}
+
+	var secret, secretHash string
+	if authMethod != AuthMethodNone {
+		secret, err = security.GenerateRandomBytesEncoded(OAuthSecretLength)
+		if err != nil {
+			return nil, fmt.Errorf("generate client secret: %w", err)
+		}
+		secretHash = security.HashToken(secret)
+	}
+
+	client, err := s.clients.CreateOAuthClient(ctx, model.OAuthClient{
+		ID:           clientID,
+		SecretHash:   secretHash,
+		Name:         params.Name,
+		RedirectURIs: params.RedirectURIs,
+		GrantTypes:   grantTypes,
+		Scope:        strings.Join(strings.Fields(params.Scope), " "),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create oauth client: %w", err)
+	}
+
+	registration := &model.OAuthClientRegistration{
+		ClientID:                client.ID,
+		ClientSecret:            secret,
+		ClientIDIssuedAt:        client.CreatedAt.Unix(),
+		Name:                    client.Name,
+		RedirectURIs:            client.RedirectURIs,
+		GrantTypes:              client.GrantTypes,
+		Scope:                   client.Scope,
+		TokenEndpointAuthMethod: authMethod,
+	}
+
+	if secret != "" {
+		var never int64
+		registration.ClientSecretExpiresAt = &never
+	}
+
+	return registration, nil
+}
+
+// Authorize validates an authorization request and returns what the user is
+// asked to consent to.
+func (s *oauthService) Authorize(ctx context.Context, params model.OAuthAuthorizeParams) (*model.OAuthConsent, error) {
+	client, scope, err := s.authorizationRequest(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.OAuthConsent{
+		ClientID:    client.ID,
+		ClientName:  client.Name,
+		RedirectURI: params.RedirectURI,
+		Scopes:      strings.Fields(scope),
+	}, nil
+}
+
+// Decide records the user's answer to an authorization request and returns
+// where to send the user back to the client, with an authorization code if
+// the user approved.
+func (s *oauthService) Decide(ctx context.Context, user *model.User,
+	params model.OAuthDecisionParams) (*model.OAuthRedirect, error) {
+	client, scope, err := s.authorizationRequest(ctx, params.OAuthAuthorizeParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if !params.Approve {
+		return &model.OAuthRedirect{
+			RedirectURI: s.authorizationError(params.OAuthAuthorizeParams,
+				oauthError(OAuthAccessDenied, "the user denied the request")).RedirectURI,
+		}, nil
+	}
+
+	code, err := security.GenerateRandomBytesEncoded(OAuthSecretLength)
+	if err != nil {
+		return nil, fmt.Errorf("generate authorization code: %w", err)
+	}
+
+	if _, err := s.codes.CreateOAuthCode(ctx, model.OAuthCode{
+		ID:            security.HashToken(code),
+		ClientID:      client.ID,
+		UserID:        user.ID,
+		RedirectURI:   params.RedirectURI,
+		Scope:         scope,
+		CodeChallenge: params.CodeChallenge,
+		ExpiresAt:     time.Now().UTC().Add(s.cfg.CodeTTL),
+	}); err != nil {
+		return nil, fmt.Errorf("create oauth code: %w", err)
+	}
+
+	return &model.OAuthRedirect{
+		RedirectURI: withQuery(params.RedirectURI, url.Values{
+			"code":  {code},
+			"state": {params.State},
+			"iss":   {s.signer.Issuer()},
+		}),
+	}, nil
+}
+
+// authorizationRequest returns the client of a valid authorization request
+// and the scope it asks for. Errors about the client or the redirect URI
+// must be shown to the user, as the user cannot safely be sent back to the
+// client; the client learns of other errors through its redirect URI.
+func (s *oauthService) authorizationRequest(ctx context.Context,
+	params model.OAuthAuthorizeParams) (*model.OAuthClient, string, error) {
+	if params.ClientID == "" {
+		return nil, "", oauthError(OAuthInvalidRequest, "client_id is required")
+	}
+
+	client, err := s.clients.FindOAuthClient(ctx, params.ClientID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", oauthError(OAuthInvalidRequest, "unknown client")
+		}
+
+		return nil, "", fmt.Errorf("find oauth client: %w", err)
+	}
+
+	if !slices.Contains(client.RedirectURIs, params.RedirectURI) {
+		return nil, "", oauthError(OAuthInvalidRequest, "redirect_uri is not registered for the client")
+	}
+
+	if params.ResponseType != "code" {
+		return nil, "", s.authorizationError(params,
+			oauthError(OAuthUnsupportedResponseType, "only the code response type is supported"))
+	}
+
+	if !slices.Contains(client.GrantTypes, GrantAuthorizationCode) {
+		return nil, "", s.authorizationError(params, oauthError(OAuthUnauthorizedClient, ""))
+	}
+
+	if params.CodeChallengeMethod != "S256" || len(params.CodeChallenge) != codeChallengeLength {
+		return nil, "", s.authorizationError(params,
+			oauthError(OAuthInvalidRequest, "a PKCE code challenge with the S256 method is required"))
+	}
+
+	scope, ok := grantedScope(client, params.Scope)
+	if !ok {
+		return nil, "", s.authorizationError(params, oauthError(OAuthInvalidScope, ""))
+	}
+
+	return client, scope, nil
+}
+
+// authorizationError sets the redirect URI that reports err to the client.
+func (s *oauthService) authorizationError(params model.OAuthAuthorizeParams, err *OAuthError) *OAuthError {
+	query := url.Values{
+		"error": {err.Code},
+		"iss":   {s.signer.Issuer()},
+	}
+	if err.Description != "" {
+		query.Set("error_description", err.Description)
+	}
+	if params.State != "" {
+		query.Set("state", params.State)
+	}
+
+	err.RedirectURI = withQuery(params.RedirectURI, query)
+
+	return err
+}
+
+// Token exchanges a grant for an access token (RFC 6749 sections 4.1.3 and
+// 4.4.2).
+func (s *oauthService) Token(ctx context.Context, creds model.OAuthClientCredentials,
+	params model.OAuthTokenParams) (*model.OAuthTokenResponse, error) {
+	client, err := s.authenticateClient(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.GrantType != GrantAuthorizationCode && params.GrantType != GrantClientCredentials {
+		return nil, oauthError(OAuthUnsupportedGrantType, "")
+	}
+
+	if !slices.Contains(client.GrantTypes, params.GrantType) {
+		return nil, oauthError(OAuthUnauthorizedClient, "")
+	}
+
+	if params.GrantType == GrantClientCredentials {
+		return s.clientCredentialsToken(ctx, client, params)
+	}
+
+	return s.authorizationCodeToken(ctx, client, params)
+}
+
+func (s *oauthService) authorizationCodeToken(ctx context.Context, client *model.OAuthClient,
+	params model.OAuthTokenParams) (*model.OAuthTokenResponse, error) {
+	if params.Code == "" || params.CodeVerifier == "" {
+		return nil, oauthError(OAuthInvalidRequest, "code and code_verifier are required")
+	}
+
+	now := time.Now().UTC()
+	codeID := security.HashToken(params.Code)
+
+	code, err := s.codes.UseOAuthCode(ctx, codeID, client.ID, params.RedirectURI, now)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if err := s.revokeReplayedCode(ctx, client, codeID, now); err != nil {
+				return nil, err
+			}
+
+			return nil, oauthError(OAuthInvalidGrant, "")
+		}
+
+		return nil, fmt.Errorf("use oauth code: %w", err)
+	}
+
+	if !now.Before(code.ExpiresAt) {
+		return nil, oauthError(OAuthInvalidGrant, "")
+	}
+
+	if len(params.CodeVerifier) < minCodeVerifier || len(params.CodeVerifier) > maxCodeVerifier ||
+		subtle.ConstantTimeCompare([]byte(oidc.CodeChallenge(params.CodeVerifier)), []byte(code.CodeChallenge)) != 1 {
+		return nil, oauthError(OAuthInvalidGrant, "code_verifier does not match")
+	}
+
+	user, err := s.users.FindUserByID(ctx, code.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, oauthError(OAuthInvalidGrant, "")
+		}
+
+		return nil, fmt.Errorf("find user by id: %w", err)
+	}
+
+	return s.issueToken(ctx, client, user.ID, &codeID, code.Scope)
+}
+
+// revokeReplayedCode revokes the tokens issued for an authorization code that
+// is presented again, as the code may have been stolen (RFC 6749 section
+// 4.1.2). A code that is still unused was presented with the wrong client or
+// redirect URI and is left alone.
+func (s *oauthService) revokeReplayedCode(ctx context.Context, client *model.OAuthClient, codeID string,
+	now time.Time) error {
+	code, err := s.codes.FindOAuthCode(ctx, codeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return fmt.Errorf("find oauth code: %w", err)
+	}
+
+	if code.ClientID != client.ID || code.UsedAt == nil {
+		return nil
+	}
+
+	slog.Warn("oauth authorization code replayed", "client_id", client.ID)
+
+	if err := s.tokens.RevokeOAuthCodeTokens(ctx, codeID, now); err != nil {
+		return fmt.Errorf("revoke oauth code tokens: %w", err)
+	}
+
+	return nil
+}
+
+func (s *oauthService) clientCredentialsToken(ctx context.Context, client *model.OAuthClient,
+	params model.OAuthTokenParams) (*model.OAuthTokenResponse, error) {
+	if client.SecretHash == "" {
+		return nil, oauthError(OAuthUnauthorizedClient, "public clients cannot use client credentials")
+	}
+
+	scope, ok := grantedScope(client, params.Scope)
+	if !ok {
+		return nil, oauthError(OAuthInvalidScope, "")
+	}
+
+	return s.issueToken(ctx, client, "", nil, scope)
+}
+
+// issueToken signs an access token for the user, or for the client itself
+// when userID is empty, and records it so that it can be revoked.
+func (s *oauthService) issueToken(ctx context.Context, client *model.OAuthClient, userID string, codeID *string,
+	scope string) (*model.OAuthTokenResponse, error) {
+	jti, err := security.GenerateRandomBytesEncoded(OAuthClientIDLength)
+	if err != nil {
+		return nil, fmt.Errorf("generate token id: %w", err)
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(s.cfg.AccessTokenTTL)
+
+	subject := client.ID
+	params := model.OAuthToken{
+		ID:        jti,
+		ClientID:  client.ID,
+		CodeID:    codeID,
+		Scope:     scope,
+		ExpiresAt: expiresAt,
+	}
+	if userID != "" {
+		subject = userID
+		params.UserID = &userID
+	}
+
+	if _, err := s.tokens.CreateOAuthToken(ctx, params); err != nil {
+		return nil, fmt.Errorf("create oauth token: %w", err)
+	}
+
+	token, err := s.signer.SignClaims(accessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Issuer:    s.signer.Issuer(),
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{s.signer.Issuer()},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		ClientID: client.ID,
+		Scope:    scope,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.OAuthTokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(s.cfg.AccessTokenTTL.Seconds()),
+		Scope:       scope,
+	}, nil
+}
+
+// Introspect reports whether an access token is active, and to whom it was
+// issued (RFC 7662). Only confidential clients, such as resource servers,
+// may introspect tokens.
+func (s *oauthService) Introspect(ctx context.Context, creds model.OAuthClientCredentials,
+	token string) (*model.OAuthIntrospection, error) {
+	client, err := s.authenticateClient(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.SecretHash == "" {
+		return nil, oauthError(OAuthInvalidClient, "public clients cannot introspect tokens")
+	}
+
+	claims, stored, err := s.activeToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if stored == nil {
+		return &model.OAuthIntrospection{Active: false}, nil
+	}
+
+	res := &model.OAuthIntrospection{
+		Active:    true,
+		Scope:     claims.Scope,
+		ClientID:  claims.ClientID,
+		TokenType: "Bearer",
+		ExpiresAt: claims.ExpiresAt.Unix(),
+		Subject:   claims.Subject,
+		Issuer:    claims.Issuer,
+		ID:        claims.ID,
+	}
+	if claims.IssuedAt != nil {
+		res.IssuedAt = claims.IssuedAt.Unix()
+	}
+	if claims.NotBefore != nil {
+		res.NotBefore = claims.NotBefore.Unix()
+	}
+	if len(claims.Audience) != 0 {
+		res.Audience = claims.Audience[0]
+	}
+
+	if stored.UserID != nil {
+		user, err := s.users.FindUserByID(ctx, *stored.UserID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return &model.OAuthIntrospection{Active: false}, nil
+			}
+
+			return nil, fmt.Errorf("find user by id: %w", err)
+		}
+
+		res.Username = user.Email
+	}
+
+	return res, nil
+}
+
+// Revoke revokes an access token issued to the client (RFC 7009). Tokens
+// that are invalid, expired or issued to other clients are ignored, so that
+// clients learn nothing about them.
+func (s *oauthService) Revoke(ctx context.Context, creds model.OAuthClientCredentials, token string) error {
+	client, err := s.authenticateClient(ctx, creds)
+	if err != nil {
+		return err
+	}
+
+	_, stored, err := s.activeToken(ctx, token)
+	if err != nil || stored == nil || stored.ClientID != client.ID {
+		return err
+	}
+
+	if err := s.tokens.RevokeOAuthToken(ctx, stored.ID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("revoke oauth token: %w", err)
+	}
+
+	return nil
+}
+
+// activeToken verifies an access token and returns its claims and record.
+// The record is nil if the token is invalid, expired or revoked.
+func (s *oauthService) activeToken(ctx context.Context, token string) (*accessTokenClaims, *model.OAuthToken, error) {
+	var claims accessTokenClaims
+	if s.signer.ParseClaims(token, &claims) != nil {
+		return nil, nil, nil
+	}
+
+	stored, err := s.tokens.FindOAuthToken(ctx, claims.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, nil
+		}
+
+		return nil, nil, fmt.Errorf("find oauth token: %w", err)
+	}
+
+	if stored.RevokedAt != nil {
+		return nil, nil, nil
+	}
+
+	return &claims, stored, nil
+}
+
+// authenticateClient returns the client the credentials belong to. Public
+// clients identify themselves by ID alone, and must not send a secret.
+func (s *oauthService) authenticateClient(ctx context.Context,
+	creds model.OAuthClientCredentials) (*model.OAuthClient, error) {
+	if creds.ID == "" {
+		return nil, oauthError(OAuthInvalidClient, "client authentication is required")
+	}
+
+	client, err := s.clients.FindOAuthClient(ctx, creds.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, oauthError(OAuthInvalidClient, "")
+		}
+
+		return nil, fmt.Errorf("find oauth client: %w", err)
+	}
+
+	if client.SecretHash == "" {
+		if creds.Secret != "" {
+			return nil, oauthError(OAuthInvalidClient, "")
+		}
+
+		return client, nil
+	}
+
+	if subtle.ConstantTimeCompare([]byte(security.HashToken(creds.Secret)), []byte(client.SecretHash)) != 1 {
+		return nil, oauthError(OAuthInvalidClient, "")
+	}
+
+	return client, nil
+}
+
+// grantedScope returns the scope to grant a client that requested scope,
+// which must be among those registered for it. Clients that request none get
+// all of them.
+func grantedScope(client *model.OAuthClient, scope string) (string, bool) {
+	registered := strings.Fields(client.Scope)
+
+	requested := strings.Fields(scope)
+	if len(requested) == 0 {
+		return strings.Join(registered, " "), true
+	}
+
+	for _, s := range requested {
+		if !slices.Contains(registered, s) {
+			return "", false
+		}
+	}
+
+	return strings.Join(slices.Compact(slices.Sorted(slices.Values(requested))), " "), true
+}
+
+// validRedirectURI reports whether uri may be registered as a redirect URI:
+// an absolute URI without a fragment, using TLS unless it points at the
+// loopback interface of a native app. Custom schemes of native apps are
+// allowed.
+func validRedirectURI(uri string) bool {
+	u, err := url.Parse(uri)
+	if err != nil || !u.IsAbs() || u.Fragment != "" || strings.Contains(uri, " ") {
+		return false
+	}
+
+	switch u.Scheme {
+	case "https":
+		return u.Host != ""
+	case "http":
+		host := u.Hostname()
+		if host == "localhost" {
+			return true
+		}
+
+		ip := net.ParseIP(host)
+		return ip != nil && ip.IsLoopback()
+	case "javascript", "data", "file":
+		return false
+	default:
+		return true
+	}
+}
+
+// withQuery adds query parameters to a registered redirect URI, keeping
+// those it has.
+func withQuery(uri string, params url.Values) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
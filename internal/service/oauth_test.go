@@ -0,0 +1,621 @@
+package service_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"database/sql"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/oidc"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+)
+
+const (
+	testIssuer         = "https://auth.example.com"
+	testClientID       = "client"
+	testClientSecret   = "secret"
+	testClientRedirect = "https://client.example.com/callback"
+	testCodeVerifier   = "verifier-verifier-verifier-verifier-verifier"
+	testOAuthState     = "xyz"
+)
+
+var testOAuthConfig = service.OAuthConfig{
+	AuthorizeURL:   testIssuer + "/oauth/authorize",
+	CodeTTL:        time.Minute,
+	AccessTokenTTL: time.Hour,
+}
+
+type oauthMocks struct {
+	users   *repoMocks.MockUserRepo
+	clients *repoMocks.MockOAuthClientRepo
+	codes   *repoMocks.MockOAuthCodeRepo
+	tokens  *repoMocks.MockOAuthTokenRepo
+	signer  *security.JWTSigner
+}
+
+func TestOAuthService_RegisterClient_Public(t *testing.T) {
+	m, oauthService := setupOAuthMocks(t)
+	ctx := context.Background()
+
+	m.clients.EXPECT().CreateOAuthClient(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, params model.OAuthClient) (*model.OAuthClient, error) {
+			assert.Empty(t, params.SecretHash, "public clients should have no secret")
+			assert.Equal(t, []string{service.GrantAuthorizationCode}, params.GrantTypes, "grant types should default")
+			assert.Equal(t, "profile email", params.Scope, "scope should be normalized")
+			return &params, nil
+		})
+
+	reg, err := oauthService.RegisterClient(ctx, model.OAuthClientParams{
+		Name:                    "Example",
+		RedirectURIs:            []string{testClientRedirect, "http://127.0.0.1:8080/callback", "com.example.app:/callback"},
+		Scope:                   " profile  email ",
+		TokenEndpointAuthMethod: service.AuthMethodNone,
+	})
+
+	assert.NoError(t, err, "register client should not return an error")
+	assert.NotEmpty(t, reg.ClientID, "a client ID should be issued")
+	assert.Empty(t, reg.ClientSecret, "no secret should be issued")
+	assert.Nil(t, reg.ClientSecretExpiresAt, "no secret expiry should be returned")
+}
+
+func TestOAuthService_RegisterClient_Confidential(t *testing.T) {
+	m, oauthService := setupOAuthMocks(t)
+	ctx := context.Background()
+	var stored model.OAuthClient
+
+	m.clients.EXPECT().CreateOAuthClient(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, params model.OAuthClient) (*model.OAuthClient, error) {
+			stored = params
+			return &params, nil
+		})
+
+	reg, err := oauthService.RegisterClient(ctx, model.OAuthClientParams{
+		Name:       "Service",
+		GrantTypes: []string{service.GrantClientCredentials},
+	})
+
+	assert.NoError(t, err, "register client should not return an error")
+	assert.NotEmpty(t, reg.ClientSecret, "a secret should be issued")
+	assert.Equal(t, security.HashToken(reg.ClientSecret), stored.SecretHash, "only the secret hash should be stored")
+	assert.Equal(t, service.AuthMethodSecretBasic, reg.TokenEndpointAuthMethod, "auth method should default")
+}
+
+func TestOAuthService_RegisterClient_InvalidMetadata(t *testing.T) {
+	tests := []struct {
+		name   string
+		params model.OAuthClientParams
+		code   string
+	}{
+		{"unsupported grant", model.OAuthClientParams{GrantTypes: []string{"password"}},
+			service.OAuthInvalidClientMetadata},
+		{"public client credentials", model.OAuthClientParams{
+			GrantTypes:              []string{service.GrantClientCredentials},
+			TokenEndpointAuthMethod: service.AuthMethodNone,
+		}, service.OAuthInvalidClientMetadata},
+		{"missing redirect URI", model.OAuthClientParams{}, service.OAuthInvalidRedirectURI},
+		{"plain http redirect URI", model.OAuthClientParams{RedirectURIs: []string{"http://client.example.com/cb"}},
+			service.OAuthInvalidRedirectURI},
+		{"redirect URI with fragment", model.OAuthClientParams{RedirectURIs: []string{testClientRedirect + "#x"}},
+			service.OAuthInvalidRedirectURI},
+		{"relative redirect URI", model.OAuthClientParams{RedirectURIs: []string{"/callback"}},
+			service.OAuthInvalidRedirectURI},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" should be rejected", func(t *testing.T) {
+			m, oauthService := setupOAuthMocks(t)
+			m.clients.EXPECT().CreateOAuthClient(gomock.Any(), gomock.Any()).Times(0)
+
+			tt.params.Name = "Example"
+			_, err := oauthService.RegisterClient(context.Background(), tt.params)
+
+			assertOAuthError(t, err, tt.code)
+		})
+	}
+}
+
+func TestOAuthService_Authorize_Success(t *testing.T) {
+	m, oauthService := setupOAuthMocks(t)
+	ctx := context.Background()
+	m.clients.EXPECT().FindOAuthClient(ctx, testClientID).Return(testPublicClient(), nil)
+
+	params := testAuthorizeParams()
+	params.Scope = "profile"
+	consent, err := oauthService.Authorize(ctx, params)
+
+	assert.NoError(t, err, "authorize should not return an error")
+	assert.Equal(t, "Example", consent.ClientName, "client name should match")
+	assert.Equal(t, []string{"profile"}, consent.Scopes, "scopes should match")
+}
+
+func TestOAuthService_Authorize_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*model.OAuthAuthorizeParams)
+		code     string
+		redirect bool
+	}{
+		{"unknown client", func(p *model.OAuthAuthorizeParams) { p.ClientID = "other" },
+			service.OAuthInvalidRequest, false},
+		{"unregistered redirect URI", func(p *model.OAuthAuthorizeParams) { p.RedirectURI = "https://evil.example.com" },
+			service.OAuthInvalidRequest, false},
+		{"missing redirect URI", func(p *model.OAuthAuthorizeParams) { p.RedirectURI = "" },
+			service.OAuthInvalidRequest, false},
+		{"token response type", func(p *model.OAuthAuthorizeParams) { p.ResponseType = "token" },
+			service.OAuthUnsupportedResponseType, true},
+		{"missing code challenge", func(p *model.OAuthAuthorizeParams) { p.CodeChallenge = "" },
+			service.OAuthInvalidRequest, true},
+		{"plain code challenge", func(p *model.OAuthAuthorizeParams) { p.CodeChallengeMethod = "plain" },
+			service.OAuthInvalidRequest, true},
+		{"unregistered scope", func(p *model.OAuthAuthorizeParams) { p.Scope = "admin" },
+			service.OAuthInvalidScope, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" should be rejected", func(t *testing.T) {
+			m, oauthService := setupOAuthMocks(t)
+			m.clients.EXPECT().FindOAuthClient(gomock.Any(), testClientID).Return(testPublicClient(), nil).AnyTimes()
+			m.clients.EXPECT().FindOAuthClient(gomock.Any(), "other").Return(nil, sql.ErrNoRows).AnyTimes()
+
+			params := testAuthorizeParams()
+			tt.modify(&params)
+			_, err := oauthService.Authorize(context.Background(), params)
+
+			oauthErr := assertOAuthError(t, err, tt.code)
+			if oauthErr == nil {
+				return
+			}
+
+			if !tt.redirect {
+				assert.Empty(t, oauthErr.RedirectURI, "the user should not be sent to the client")
+				return
+			}
+
+			query := redirectQuery(t, oauthErr.RedirectURI, testClientRedirect)
+			assert.Equal(t, tt.code, query.Get("error"), "error should be sent to the client")
+			assert.Equal(t, testOAuthState, query.Get("state"), "state should be sent back")
+			assert.Equal(t, testIssuer, query.Get("iss"), "issuer should be sent")
+		})
+	}
+}
+
+func TestOAuthService_Decide_Approved(t *testing.T) {
+	m, oauthService := setupOAuthMocks(t)
+	ctx := context.Background()
+	var stored model.OAuthCode
+
+	m.clients.EXPECT().FindOAuthClient(ctx, testClientID).Return(testPublicClient(), nil)
+	m.codes.EXPECT().CreateOAuthCode(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, params model.OAuthCode) (*model.OAuthCode, error) {
+			stored = params
+			return &params, nil
+		})
+
+	redirect, err := oauthService.Decide(ctx, &model.User{ID: testID},
+		model.OAuthDecisionParams{OAuthAuthorizeParams: testAuthorizeParams(), Approve: true})
+
+	assert.NoError(t, err, "decide should not return an error")
+	query := redirectQuery(t, redirect.RedirectURI, testClientRedirect)
+	assert.Equal(t, security.HashToken(query.Get("code")), stored.ID, "only the code hash should be stored")
+	assert.Equal(t, testOAuthState, query.Get("state"), "state should be sent back")
+	assert.Equal(t, testID, stored.UserID, "user ID should match")
+	assert.Equal(t, "profile email", stored.Scope, "scope should default to the client's")
+}
+
+func TestOAuthService_Decide_Denied(t *testing.T) {
+	m, oauthService := setupOAuthMocks(t)
+	ctx := context.Background()
+
+	m.clients.EXPECT().FindOAuthClient(ctx, testClientID).Return(testPublicClient(), nil)
+	m.codes.EXPECT().CreateOAuthCode(gomock.Any(), gomock.Any()).Times(0)
+
+	redirect, err := oauthService.Decide(ctx, &model.User{ID: testID},
+		model.OAuthDecisionParams{OAuthAuthorizeParams: testAuthorizeParams()})
+
+	assert.NoError(t, err, "decide should not return an error")
+	query := redirectQuery(t, redirect.RedirectURI, testClientRedirect)
+	assert.Equal(t, service.OAuthAccessDenied, query.Get("error"), "access should be denied")
+	assert.Empty(t, query.Get("code"), "no code should be issued")
+}
+
+func TestOAuthService_Token_AuthorizationCode(t *testing.T) {
+	m, oauthService := setupOAuthMocks(t)
+	ctx := context.Background()
+	code := testOAuthCode()
+
+	m.clients.EXPECT().FindOAuthClient(ctx, testClientID).Return(testPublicClient(), nil)
+	m.codes.EXPECT().UseOAuthCode(ctx, code.ID, testClientID, testClientRedirect, gomock.Any()).Return(code, nil)
+	m.users.EXPECT().FindUserByID(ctx, testID).Return(&model.User{ID: testID, Email: testEmail}, nil)
+	m.tokens.EXPECT().CreateOAuthToken(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, params model.OAuthToken) (*model.OAuthToken, error) {
+			if assert.NotNil(t, params.CodeID, "the code should be recorded") {
+				assert.Equal(t, code.ID, *params.CodeID, "code ID should match")
+			}
+			return &params, nil
+		})
+
+	res, err := oauthService.Token(ctx, model.OAuthClientCredentials{ID: testClientID}, testTokenParams())
+
+	assert.NoError(t, err, "token should not return an error")
+	assert.Equal(t, "Bearer", res.TokenType, "token type should match")
+	assert.Equal(t, "profile", res.Scope, "scope should match")
+
+	var claims jwt.MapClaims
+	assert.NoError(t, m.signer.ParseClaims(res.AccessToken, &claims, jwt.WithAudience(testIssuer)),
+		"access token should verify")
+	assert.Equal(t, testID, claims["sub"], "subject should be the user")
+	assert.Equal(t, testClientID, claims["client_id"], "client ID should match")
+}
+
+func TestOAuthService_Token_InvalidGrant(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   func(*model.OAuthCode)
+		params func(*model.OAuthTokenParams)
+	}{
+		{"wrong code verifier", nil, func(p *model.OAuthTokenParams) {
+			p.CodeVerifier = "other-verifier-other-verifier-other-verifier"
+		}},
+		{"short code verifier", nil, func(p *model.OAuthTokenParams) { p.CodeVerifier = "short" }},
+		{"expired code", func(c *model.OAuthCode) { c.ExpiresAt = time.Now().Add(-time.Second) }, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" should be rejected", func(t *testing.T) {
+			m, oauthService := setupOAuthMocks(t)
+			code := testOAuthCode()
+			if tt.code != nil {
+				tt.code(code)
+			}
+			params := testTokenParams()
+			if tt.params != nil {
+				tt.params(&params)
+			}
+
+			m.clients.EXPECT().FindOAuthClient(gomock.Any(), testClientID).Return(testPublicClient(), nil)
+			m.codes.EXPECT().UseOAuthCode(gomock.Any(), code.ID, testClientID, testClientRedirect, gomock.Any()).
+				Return(code, nil)
+			m.tokens.EXPECT().CreateOAuthToken(gomock.Any(), gomock.Any()).Times(0)
+
+			_, err := oauthService.Token(context.Background(), model.OAuthClientCredentials{ID: testClientID}, params)
+
+			assertOAuthError(t, err, service.OAuthInvalidGrant)
+		})
+	}
+}
+
+func TestOAuthService_Token_ReplayedCode(t *testing.T) {
+	m, oauthService := setupOAuthMocks(t)
+	ctx := context.Background()
+	code := testOAuthCode()
+	usedAt := time.Now()
+	code.UsedAt = &usedAt
+
+	m.clients.EXPECT().FindOAuthClient(ctx, testClientID).Return(testPublicClient(), nil)
+	m.codes.EXPECT().UseOAuthCode(ctx, code.ID, testClientID, testClientRedirect, gomock.Any()).
+		Return(nil, sql.ErrNoRows)
+	m.codes.EXPECT().FindOAuthCode(ctx, code.ID).Return(code, nil)
+	m.tokens.EXPECT().RevokeOAuthCodeTokens(ctx, code.ID, gomock.Any()).Return(nil)
+
+	_, err := oauthService.Token(ctx, model.OAuthClientCredentials{ID: testClientID}, testTokenParams())
+
+	assertOAuthError(t, err, service.OAuthInvalidGrant)
+}
+
+func TestOAuthService_Token_MismatchedCodeNotRevoked(t *testing.T) {
+	tests := []struct {
+		name        string
+		codeClient  string
+		redirectURI string
+	}{
+		{"code of another client", "other", testClientRedirect},
+		{"other redirect URI", testClientID, "https://client.example.com/other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" should be rejected without revoking", func(t *testing.T) {
+			m, oauthService := setupOAuthMocks(t)
+			code := testOAuthCode()
+			code.ClientID = tt.codeClient
+			params := testTokenParams()
+			params.RedirectURI = tt.redirectURI
+
+			m.clients.EXPECT().FindOAuthClient(gomock.Any(), testClientID).Return(testPublicClient(), nil)
+			m.codes.EXPECT().UseOAuthCode(gomock.Any(), code.ID, testClientID, tt.redirectURI, gomock.Any()).
+				Return(nil, sql.ErrNoRows)
+			m.codes.EXPECT().FindOAuthCode(gomock.Any(), code.ID).Return(code, nil)
+			m.tokens.EXPECT().RevokeOAuthCodeTokens(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			m.tokens.EXPECT().CreateOAuthToken(gomock.Any(), gomock.Any()).Times(0)
+
+			_, err := oauthService.Token(context.Background(), model.OAuthClientCredentials{ID: testClientID}, params)
+
+			assertOAuthError(t, err, service.OAuthInvalidGrant)
+		})
+	}
+}
+
+func TestOAuthService_Token_ClientAuthentication(t *testing.T) {
+	tests := []struct {
+		name   string
+		client *model.OAuthClient
+		creds  model.OAuthClientCredentials
+		grant  string
+		code   string
+	}{
+		{"wrong secret", testConfidentialClient(), model.OAuthClientCredentials{ID: testClientID, Secret: "wrong"},
+			service.GrantClientCredentials, service.OAuthInvalidClient},
+		{"missing secret", testConfidentialClient(), model.OAuthClientCredentials{ID: testClientID},
+			service.GrantClientCredentials, service.OAuthInvalidClient},
+		{"secret of a public client", testPublicClient(),
+			model.OAuthClientCredentials{ID: testClientID, Secret: testClientSecret},
+			service.GrantAuthorizationCode, service.OAuthInvalidClient},
+		{"unregistered grant", testPublicClient(), model.OAuthClientCredentials{ID: testClientID},
+			service.GrantClientCredentials, service.OAuthUnauthorizedClient},
+		{"unsupported grant", testPublicClient(), model.OAuthClientCredentials{ID: testClientID},
+			"password", service.OAuthUnsupportedGrantType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" should be rejected", func(t *testing.T) {
+			m, oauthService := setupOAuthMocks(t)
+			m.clients.EXPECT().FindOAuthClient(gomock.Any(), testClientID).Return(tt.client, nil)
+			m.tokens.EXPECT().CreateOAuthToken(gomock.Any(), gomock.Any()).Times(0)
+
+			_, err := oauthService.Token(context.Background(), tt.creds, model.OAuthTokenParams{GrantType: tt.grant})
+
+			assertOAuthError(t, err, tt.code)
+		})
+	}
+}
+
+func TestOAuthService_Token_ClientCredentials(t *testing.T) {
+	m, oauthService := setupOAuthMocks(t)
+	ctx := context.Background()
+
+	m.clients.EXPECT().FindOAuthClient(ctx, testClientID).Return(testConfidentialClient(), nil)
+	m.tokens.EXPECT().CreateOAuthToken(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, params model.OAuthToken) (*model.OAuthToken, error) {
+			assert.Nil(t, params.UserID, "client tokens should have no user")
+			return &params, nil
+		})
+
+	res, err := oauthService.Token(ctx, model.OAuthClientCredentials{ID: testClientID, Secret: testClientSecret},
+		model.OAuthTokenParams{GrantType: service.GrantClientCredentials, Scope: "reports"})
+
+	assert.NoError(t, err, "token should not return an error")
+	assert.Equal(t, "reports", res.Scope, "scope should match")
+}
+
+func TestOAuthService_Token_DuplicateScope(t *testing.T) {
+	m, oauthService := setupOAuthMocks(t)
+	ctx := context.Background()
+	client := testConfidentialClient()
+	client.Scope = "reports audit"
+
+	m.clients.EXPECT().FindOAuthClient(ctx, testClientID).Return(client, nil)
+	m.tokens.EXPECT().CreateOAuthToken(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, params model.OAuthToken) (*model.OAuthToken, error) {
+			return &params, nil
+		})
+
+	res, err := oauthService.Token(ctx, model.OAuthClientCredentials{ID: testClientID, Secret: testClientSecret},
+		model.OAuthTokenParams{GrantType: service.GrantClientCredentials, Scope: "reports audit reports"})
+
+	assert.NoError(t, err, "token should not return an error")
+	assert.Equal(t, "audit reports", res.Scope, "scope should be granted once each")
+}
+
+func TestOAuthService_Introspect(t *testing.T) {
+	m, oauthService := setupOAuthMocks(t)
+	ctx := context.Background()
+	token, stored := issueTestToken(t, m, oauthService)
+	creds := model.OAuthClientCredentials{ID: testClientID, Secret: testClientSecret}
+
+	m.clients.EXPECT().FindOAuthClient(ctx, testClientID).Return(testConfidentialClient(), nil).Times(3)
+	m.tokens.EXPECT().FindOAuthToken(ctx, stored.ID).Return(stored, nil)
+
+	res, err := oauthService.Introspect(ctx, creds, token)
+	assert.NoError(t, err, "introspect should not return an error")
+	assert.True(t, res.Active, "token should be active")
+	assert.Equal(t, testClientID, res.Subject, "subject should match")
+	assert.Equal(t, "reports", res.Scope, "scope should match")
+
+	revokedAt := time.Now()
+	revoked := *stored
+	revoked.RevokedAt = &revokedAt
+	m.tokens.EXPECT().FindOAuthToken(ctx, stored.ID).Return(&revoked, nil)
+
+	res, err = oauthService.Introspect(ctx, creds, token)
+	assert.NoError(t, err, "introspect should not return an error")
+	assert.Equal(t, model.OAuthIntrospection{Active: false}, *res, "revoked tokens should be inactive")
+
+	res, err = oauthService.Introspect(ctx, creds, "garbage")
+	assert.NoError(t, err, "introspect should not return an error")
+	assert.False(t, res.Active, "invalid tokens should be inactive")
+}
+
+func TestOAuthService_Introspect_PublicClient(t *testing.T) {
+	m, oauthService := setupOAuthMocks(t)
+	m.clients.EXPECT().FindOAuthClient(gomock.Any(), testClientID).Return(testPublicClient(), nil)
+	m.tokens.EXPECT().FindOAuthToken(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := oauthService.Introspect(context.Background(), model.OAuthClientCredentials{ID: testClientID}, "token")
+
+	assertOAuthError(t, err, service.OAuthInvalidClient)
+}
+
+func TestOAuthService_Revoke(t *testing.T) {
+	tests := []struct {
+		name     string
+		clientID string
+		revoked  bool
+	}{
+		{"token of the client should be revoked", testClientID, true},
+		{"token of another client should be ignored", "other", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, oauthService := setupOAuthMocks(t)
+			ctx := context.Background()
+			token, stored := issueTestToken(t, m, oauthService)
+
+			client := testConfidentialClient()
+			client.ID = tt.clientID
+			m.clients.EXPECT().FindOAuthClient(ctx, tt.clientID).Return(client, nil)
+			m.tokens.EXPECT().FindOAuthToken(ctx, stored.ID).Return(stored, nil)
+			if tt.revoked {
+				m.tokens.EXPECT().RevokeOAuthToken(ctx, stored.ID, gomock.Any()).Return(nil)
+			}
+
+			err := oauthService.Revoke(ctx, model.OAuthClientCredentials{ID: tt.clientID, Secret: testClientSecret}, token)
+
+			assert.NoError(t, err, "revoke should not return an error")
+		})
+	}
+}
+
+func TestOAuthService_Metadata(t *testing.T) {
+	_, oauthService := setupOAuthMocks(t)
+
+	meta := oauthService.Metadata()
+
+	assert.Equal(t, testIssuer, meta.Issuer, "issuer should match")
+	assert.Equal(t, testIssuer+"/oauth/token", meta.TokenEndpoint, "token endpoint should match")
+	assert.Equal(t, []string{"S256"}, meta.CodeChallengeMethodsSupported, "only S256 should be supported")
+	assert.Len(t, oauthService.JWKS().Keys, 1, "the signing key should be published")
+}
+
+func setupOAuthMocks(t *testing.T) (*oauthMocks, service.OAuthService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := &oauthMocks{
+		users:   repoMocks.NewMockUserRepo(ctrl),
+		clients: repoMocks.NewMockOAuthClientRepo(ctrl),
+		codes:   repoMocks.NewMockOAuthCodeRepo(ctrl),
+		tokens:  repoMocks.NewMockOAuthTokenRepo(ctrl),
+		signer:  newOAuthSigner(t),
+	}
+	oauthService := service.NewOAuthService(m.users, m.clients, m.codes, m.tokens, m.signer, testOAuthConfig)
+
+	return m, oauthService
+}
+
+func newOAuthSigner(t *testing.T) *security.JWTSigner {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+
+	return security.NewEdDSASigner(key, testIssuer)
+}
+
+func testPublicClient() *model.OAuthClient {
+	return &model.OAuthClient{
+		ID:           testClientID,
+		Name:         "Example",
+		RedirectURIs: []string{testClientRedirect},
+		GrantTypes:   []string{service.GrantAuthorizationCode},
+		Scope:        "profile email",
+	}
+}
+
+func testConfidentialClient() *model.OAuthClient {
+	return &model.OAuthClient{
+		ID:         testClientID,
+		SecretHash: security.HashToken(testClientSecret),
+		Name:       "Service",
+		GrantTypes: []string{service.GrantClientCredentials},
+		Scope:      "reports",
+	}
+}
+
+func testAuthorizeParams() model.OAuthAuthorizeParams {
+	return model.OAuthAuthorizeParams{
+		ResponseType:        "code",
+		ClientID:            testClientID,
+		RedirectURI:         testClientRedirect,
+		State:               testOAuthState,
+		CodeChallenge:       oidc.CodeChallenge(testCodeVerifier),
+		CodeChallengeMethod: "S256",
+	}
+}
+
+func testOAuthCode() *model.OAuthCode {
+	return &model.OAuthCode{
+		ID:            security.HashToken("code"),
+		ClientID:      testClientID,
+		UserID:        testID,
+		RedirectURI:   testClientRedirect,
+		Scope:         "profile",
+		CodeChallenge: oidc.CodeChallenge(testCodeVerifier),
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+}
+
+func testTokenParams() model.OAuthTokenParams {
+	return model.OAuthTokenParams{
+		GrantType:    service.GrantAuthorizationCode,
+		Code:         "code",
+		RedirectURI:  testClientRedirect,
+		CodeVerifier: testCodeVerifier,
+	}
+}
+
+// issueTestToken issues a client credentials token and returns it with its
+// record.
+func issueTestToken(t *testing.T, m *oauthMocks, oauthService service.OAuthService) (string, *model.OAuthToken) {
+	t.Helper()
+	ctx := context.Background()
+	var stored model.OAuthToken
+
+	m.clients.EXPECT().FindOAuthClient(ctx, testClientID).Return(testConfidentialClient(), nil)
+	m.tokens.EXPECT().CreateOAuthToken(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, params model.OAuthToken) (*model.OAuthToken, error) {
+			stored = params
+			return &params, nil
+		})
+
+	res, err := oauthService.Token(ctx, model.OAuthClientCredentials{ID: testClientID, Secret: testClientSecret},
+		model.OAuthTokenParams{GrantType: service.GrantClientCredentials})
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+
+	return res.AccessToken, &stored
+}
+
+func assertOAuthError(t *testing.T, err error, code string) *service.OAuthError {
+	t.Helper()
+	var oauthErr *service.OAuthError
+	if !assert.ErrorAs(t, err, &oauthErr, "error should be an oauth error") {
+		return nil
+	}
+
+	assert.Equal(t, code, oauthErr.Code, "error code should match")
+	return oauthErr
+}
+
+// redirectQuery checks that uri leads back to the client and returns its
+// query.
+func redirectQuery(t *testing.T, uri, client string) url.Values {
+	t.Helper()
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatalf("parse redirect uri: %v", err)
+	}
+
+	assert.Equal(t, client, u.Scheme+"://"+u.Host+u.Path, "user should be sent back to the client")
+	return u.Query()
+}
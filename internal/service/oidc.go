@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/oidc"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+)
+
+// OIDCConfig configures sign-in with OpenID providers.
+type OIDCConfig struct {
+	// StateTTL is how long the user has to come back from the provider.
+	StateTTL time.Duration
+}
+
+type oidcSignIn struct {
+	providers  map[string]*oidc.Provider
+	identities repo.IdentityRepo
+	states     repo.OIDCStateRepo
+	cfg        OIDCConfig
+}
+
+// WithOIDC lets users sign in with OpenID providers, keyed by the name used
+// in their URLs. A provider account is linked to the user with the same
+// verified email, or to a new user if there is none.
+func WithOIDC(identities repo.IdentityRepo, states repo.OIDCStateRepo, providers map[string]*oidc.Provider,
+	cfg OIDCConfig) AuthOption {
+	return func(s *authService) {
+		s.oidc = &oidcSignIn{
+			providers:  providers,
+			identities: identities,
+			states:     states,
+			cfg:        cfg,
+		}
+	}
+}
+
+// BeginOIDCSignIn returns the URL that sends the user to the provider, and
+// the state the callback must come back with.
+func (s *authService) BeginOIDCSignIn(ctx context.Context, provider string) (*model.OIDCAuthorization, error) {
+	p, err := s.oidc.provider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := oidc.NewAuthRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, err := p.AuthCodeURL(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrProviderUnavailable, err)
+	}
+
+	params := model.OIDCState{
+		ID:           security.HashToken(req.State),
+		Provider:     provider,
+		Nonce:        req.Nonce,
+		CodeVerifier: req.CodeVerifier,
+		ExpiresAt:    time.Now().UTC().Add(s.oidc.cfg.StateTTL),
+	}
+
+	if _, err := s.oidc.states.CreateOIDCState(ctx, params); err != nil {
+		return nil, fmt.Errorf("create oidc state: %w", err)
+	}
+
+	return &model.OIDCAuthorization{
+		URL:   authURL,
+		State: req.State,
+	}, nil
+}
+
+// FinishOIDCSignIn exchanges the code the provider redirected the user back
+// with, and returns the ID of the user the provider account is linked to.
+// Users who enabled two-factor authentication still need a code.
+func (s *authService) FinishOIDCSignIn(ctx context.Context, provider string,
+	params model.OIDCCallbackParams) (string, error) {
+	p, err := s.oidc.provider(provider)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := s.oidc.states.ConsumeOIDCState(ctx, security.HashToken(params.State))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrInvalidOIDCSignIn
+		}
+
+		return "", fmt.Errorf("consume oidc state: %w", err)
+	}
+
+	if state.Provider != provider || !time.Now().UTC().Before(state.ExpiresAt) {
+		return "", ErrInvalidOIDCSignIn
+	}
+
+	token, err := p.Exchange(ctx, params.Code, state.CodeVerifier)
+	if err != nil {
+		if errors.Is(err, oidc.ErrTokenRequest) {
+			slog.Info("oidc code exchange refused", "provider", provider, "error", err)
+			return "", ErrInvalidOIDCSignIn
+		}
+
+		return "", fmt.Errorf("%w: %w", ErrProviderUnavailable, err)
+	}
+
+	idToken, err := p.VerifyIDToken(ctx, token.IDToken, state.Nonce)
+	if err != nil {
+		slog.Warn("oidc id token rejected", "provider", provider, "error", err)
+		return "", ErrInvalidOIDCSignIn
+	}
+
+	user, err := s.oidc.linkedUser(ctx, s.repo, provider, idToken)
+	if err != nil {
+		return "", err
+	}
+
+	if s.verifier != nil && s.verifier.cfg.RequireVerified && user.EmailVerifiedAt == nil {
+		return "", ErrEmailNotVerified
+	}
+
+	if s.mfa != nil {
+		if err := s.mfa.requireSecondFactor(ctx, user.ID); err != nil {
+			return "", err
+		}
+	}
+
+	return user.ID, nil
+}
+
+// ListIdentities returns the provider accounts linked to the user.
+func (s *authService) ListIdentities(ctx context.Context, user *model.User) ([]model.Identity, error) {
+	if s.oidc == nil {
+		return nil, ErrOIDCDisabled
+	}
+
+	identities, err := s.oidc.identities.ListUserIdentities(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list user identities: %w", err)
+	}
+
+	return identities, nil
+}
+
+func (o *oidcSignIn) provider(name string) (*oidc.Provider, error) {
+	if o == nil {
+		return nil, ErrOIDCDisabled
+	}
+
+	p, ok := o.providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	return p, nil
+}
+
+// linkedUser returns the user the provider account is linked to. An account
+// that is not linked yet is linked to the user with its email, who is created
+// if needed. Both the provider and the user must have verified the email, or
+// whoever registered an address first could take over the other account.
+func (o *oidcSignIn) linkedUser(ctx context.Context, users repo.UserRepo, provider string,
+	idToken *oidc.IDToken) (*model.User, error) {
+	now := time.Now().UTC()
+
+	identity, err := o.identities.FindIdentity(ctx, provider, idToken.Subject)
+	if err == nil {
+		email := idToken.Email
+		if email == "" {
+			email = identity.Email
+		}
+
+		if err := o.identities.UpdateIdentityUse(ctx, provider, idToken.Subject, email, now); err != nil {
+			return nil, fmt.Errorf("update identity use: %w", err)
+		}
+
+		user, err := users.FindUserByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("find user by id: %w", err)
+		}
+
+		return user, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("find identity: %w", err)
+	}
+
+	if idToken.Email == "" || !idToken.EmailVerified {
+		return nil, ErrProviderEmailNotVerified
+	}
+
+	user, err := users.FindUserByEmail(ctx, idToken.Email)
+	switch {
+	case err == nil:
+		if user.EmailVerifiedAt == nil {
+			return nil, ErrIdentityNotLinkable
+		}
+	case errors.Is(err, sql.ErrNoRows):
+		if user, err = createProviderUser(ctx, users, idToken.Email, now); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("find user by email: %w", err)
+	}
+
+	params := model.Identity{
+		Provider:   provider,
+		Subject:    idToken.Subject,
+		UserID:     user.ID,
+		Email:      idToken.Email,
+		LastUsedAt: &now,
+	}
+
+	if _, err := o.identities.CreateIdentity(ctx, params); err != nil {
+		// A concurrent sign-in linked the account first. Signing in again
+		// finds the link.
+		if errors.Is(err, repo.ErrDuplicate) {
+			return nil, ErrInvalidOIDCSignIn
+		}
+
+		return nil, fmt.Errorf("create identity: %w", err)
+	}
+
+	slog.Info("linked identity", "provider", provider, "user_id", user.ID)
+
+	return user, nil
+}
+
+// createProviderUser creates a user without a password, whose email the
+// provider has verified. The user can set a password with a password reset.
+func createProviderUser(ctx context.Context, users repo.UserRepo, email string, now time.Time) (*model.User, error) {
+	user, err := users.CreateUser(ctx, model.User{Email: email})
+	if err != nil {
+		// A concurrent sign-up registered the email after our lookup.
+		if errors.Is(err, repo.ErrDuplicate) {
+			return nil, ErrInvalidOIDCSignIn
+		}
+
+		return nil, fmt.Errorf("create user: %w", err)
+	}
+
+	if err := users.MarkEmailVerified(ctx, user.ID, now); err != nil {
+		return nil, fmt.Errorf("mark email verified: %w", err)
+	}
+
+	user.EmailVerifiedAt = &now
+
+	return user, nil
+}
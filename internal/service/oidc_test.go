@@ -0,0 +1,293 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/oidc"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/oidc/oidctest"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	secMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/security/mocks"
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+)
+
+const (
+	testProvider    = "test"
+	testRedirectURL = "https://app.example.com/signin/oidc/test/callback"
+)
+
+var testOIDCConfig = service.OIDCConfig{
+	StateTTL: 10 * time.Minute,
+}
+
+type oidcMocks struct {
+	users      *repoMocks.MockUserRepo
+	hasher     *secMocks.MockHasher
+	identities *repoMocks.MockIdentityRepo
+	states     *repoMocks.MockOIDCStateRepo
+	idp        *oidctest.Provider
+}
+
+func TestAuthService_FinishOIDCSignIn_LinkedIdentity(t *testing.T) {
+	m, authService := setupOIDCMocks(t)
+	ctx := context.Background()
+	params := completeOIDCAuthorization(t, m, authService)
+
+	m.identities.EXPECT().FindIdentity(ctx, testProvider, m.idp.Subject).Return(&model.Identity{
+		Provider: testProvider,
+		Subject:  m.idp.Subject,
+		UserID:   testID,
+	}, nil)
+	m.identities.EXPECT().UpdateIdentityUse(ctx, testProvider, m.idp.Subject, m.idp.Email, gomock.Any()).Return(nil)
+	m.users.EXPECT().FindUserByID(ctx, testID).Return(&model.User{ID: testID, Email: testEmail}, nil)
+
+	id, err := authService.FinishOIDCSignIn(ctx, testProvider, params)
+
+	assert.NoError(t, err, "oidc signin should not return an error")
+	assert.Equal(t, testID, id, "ID should match")
+}
+
+func TestAuthService_FinishOIDCSignIn_CreatesUser(t *testing.T) {
+	m, authService := setupOIDCMocks(t)
+	ctx := context.Background()
+	params := completeOIDCAuthorization(t, m, authService)
+
+	m.identities.EXPECT().FindIdentity(ctx, testProvider, m.idp.Subject).Return(nil, sql.ErrNoRows)
+	m.users.EXPECT().FindUserByEmail(ctx, m.idp.Email).Return(nil, sql.ErrNoRows)
+	m.users.EXPECT().CreateUser(ctx, model.User{Email: m.idp.Email}).Return(&model.User{ID: testID, Email: m.idp.Email}, nil)
+	m.users.EXPECT().MarkEmailVerified(ctx, testID, gomock.Any()).Return(nil)
+	m.identities.EXPECT().CreateIdentity(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, params model.Identity) (*model.Identity, error) {
+			assert.Equal(t, testID, params.UserID, "user ID should match")
+			assert.Equal(t, m.idp.Subject, params.Subject, "subject should match")
+			return &params, nil
+		})
+
+	id, err := authService.FinishOIDCSignIn(ctx, testProvider, params)
+
+	assert.NoError(t, err, "oidc signin should not return an error")
+	assert.Equal(t, testID, id, "ID should match")
+}
+
+func TestAuthService_FinishOIDCSignIn_LinksVerifiedUser(t *testing.T) {
+	m, authService := setupOIDCMocks(t)
+	ctx := context.Background()
+	params := completeOIDCAuthorization(t, m, authService)
+	verifiedAt := time.Now()
+
+	m.identities.EXPECT().FindIdentity(ctx, testProvider, m.idp.Subject).Return(nil, sql.ErrNoRows)
+	m.users.EXPECT().FindUserByEmail(ctx, m.idp.Email).Return(&model.User{
+		ID:              testID,
+		Email:           m.idp.Email,
+		EmailVerifiedAt: &verifiedAt,
+	}, nil)
+	m.users.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+	m.identities.EXPECT().CreateIdentity(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, params model.Identity) (*model.Identity, error) {
+			return &params, nil
+		})
+
+	id, err := authService.FinishOIDCSignIn(ctx, testProvider, params)
+
+	assert.NoError(t, err, "oidc signin should not return an error")
+	assert.Equal(t, testID, id, "ID should match")
+}
+
+func TestAuthService_FinishOIDCSignIn_DoesNotLink(t *testing.T) {
+	tests := []struct {
+		name          string
+		emailVerified bool
+		user          *model.User
+		err           error
+	}{
+		{"unverified provider email", false, nil, service.ErrProviderEmailNotVerified},
+		{"unverified account email", true, &model.User{ID: testID, Email: testEmail}, service.ErrIdentityNotLinkable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" should not be linked", func(t *testing.T) {
+			m, authService := setupOIDCMocks(t)
+			ctx := context.Background()
+			m.idp.EmailVerified = tt.emailVerified
+			params := completeOIDCAuthorization(t, m, authService)
+
+			m.identities.EXPECT().FindIdentity(ctx, testProvider, m.idp.Subject).Return(nil, sql.ErrNoRows)
+			if tt.user != nil {
+				m.users.EXPECT().FindUserByEmail(ctx, m.idp.Email).Return(tt.user, nil)
+			}
+			m.identities.EXPECT().CreateIdentity(gomock.Any(), gomock.Any()).Times(0)
+
+			_, err := authService.FinishOIDCSignIn(ctx, testProvider, params)
+
+			assert.ErrorIs(t, err, tt.err, "errors should match")
+		})
+	}
+}
+
+func TestAuthService_FinishOIDCSignIn_Rejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		state func(model.OIDCState) (*model.OIDCState, error)
+		code  string
+	}{
+		{
+			name: "used state",
+			state: func(model.OIDCState) (*model.OIDCState, error) {
+				return nil, sql.ErrNoRows
+			},
+		},
+		{
+			name: "expired state",
+			state: func(s model.OIDCState) (*model.OIDCState, error) {
+				s.ExpiresAt = time.Now().Add(-time.Second)
+				return &s, nil
+			},
+		},
+		{
+			name: "state of another provider",
+			state: func(s model.OIDCState) (*model.OIDCState, error) {
+				s.Provider = "other"
+				return &s, nil
+			},
+		},
+		{
+			name: "wrong code verifier",
+			state: func(s model.OIDCState) (*model.OIDCState, error) {
+				s.CodeVerifier = "wrong-verifier-wrong-verifier-wrong-verifier"
+				return &s, nil
+			},
+		},
+		{
+			name: "wrong nonce",
+			state: func(s model.OIDCState) (*model.OIDCState, error) {
+				s.Nonce = "other"
+				return &s, nil
+			},
+		},
+		{
+			name: "unknown code",
+			state: func(s model.OIDCState) (*model.OIDCState, error) {
+				return &s, nil
+			},
+			code: "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" should be rejected", func(t *testing.T) {
+			m, authService := setupOIDCMocks(t)
+			ctx := context.Background()
+			auth, stored := beginOIDCSignIn(t, m, authService)
+			callback := m.idp.Authorize(t, auth.URL)
+
+			m.states.EXPECT().ConsumeOIDCState(ctx, stored.ID).Return(tt.state(*stored))
+			m.identities.EXPECT().FindIdentity(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			code := callback.Get("code")
+			if tt.code != "" {
+				code = tt.code
+			}
+
+			_, err := authService.FinishOIDCSignIn(ctx, testProvider, model.OIDCCallbackParams{
+				State: callback.Get("state"),
+				Code:  code,
+			})
+
+			assert.ErrorIs(t, err, service.ErrInvalidOIDCSignIn, "errors should match")
+		})
+	}
+}
+
+func TestAuthService_BeginOIDCSignIn_UnknownProvider(t *testing.T) {
+	m, authService := setupOIDCMocks(t)
+	m.states.EXPECT().CreateOIDCState(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := authService.BeginOIDCSignIn(context.Background(), "other")
+	assert.ErrorIs(t, err, service.ErrUnknownProvider, "errors should match")
+
+	_, err = service.NewAuthService(m.users, m.hasher).BeginOIDCSignIn(context.Background(), testProvider)
+	assert.ErrorIs(t, err, service.ErrOIDCDisabled, "errors should match")
+}
+
+func TestAuthService_SignInUser_WithoutPassword(t *testing.T) {
+	m, authService := setupOIDCMocks(t)
+	ctx := context.Background()
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, Email: testEmail}, nil)
+	m.hasher.EXPECT().Verify(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := authService.SignInUser(ctx, model.UserSignInParams{Email: testEmail, Password: testPassword})
+
+	assert.ErrorIs(t, err, service.ErrPasswordMismatch, "users without a password should not sign in with one")
+}
+
+func setupOIDCMocks(t *testing.T) (*oidcMocks, service.AuthService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := &oidcMocks{
+		users:      repoMocks.NewMockUserRepo(ctrl),
+		hasher:     secMocks.NewMockHasher(ctrl),
+		identities: repoMocks.NewMockIdentityRepo(ctrl),
+		states:     repoMocks.NewMockOIDCStateRepo(ctrl),
+		idp:        oidctest.New(t, testRedirectURL),
+	}
+	authService := service.NewAuthService(m.users, m.hasher,
+		service.WithOIDC(m.identities, m.states, newTestProviders(m.idp), testOIDCConfig))
+
+	return m, authService
+}
+
+func newTestProviders(idp *oidctest.Provider) map[string]*oidc.Provider {
+	return map[string]*oidc.Provider{
+		testProvider: oidc.NewProvider(oidc.Config{
+			Issuer:       idp.Issuer(),
+			ClientID:     oidctest.ClientID,
+			ClientSecret: oidctest.ClientSecret,
+			RedirectURL:  testRedirectURL,
+			HTTPClient:   idp.Client(),
+		}),
+	}
+}
+
+// beginOIDCSignIn starts a sign-in with the test provider and returns the
+// authorization and the state stored for it.
+func beginOIDCSignIn(t *testing.T, m *oidcMocks,
+	authService service.AuthService) (*model.OIDCAuthorization, *model.OIDCState) {
+	t.Helper()
+	ctx := context.Background()
+	var stored model.OIDCState
+
+	m.states.EXPECT().CreateOIDCState(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, params model.OIDCState) (*model.OIDCState, error) {
+			stored = params
+			return &params, nil
+		})
+
+	auth, err := authService.BeginOIDCSignIn(ctx, testProvider)
+	if err != nil {
+		t.Fatalf("begin oidc signin: %v", err)
+	}
+
+	return auth, &stored
+}
+
+// completeOIDCAuthorization signs in at the test provider and returns the
+// callback parameters, expecting their state to be consumed.
+func completeOIDCAuthorization(t *testing.T, m *oidcMocks, authService service.AuthService) model.OIDCCallbackParams {
+	t.Helper()
+	auth, stored := beginOIDCSignIn(t, m, authService)
+	callback := m.idp.Authorize(t, auth.URL)
+
+	m.states.EXPECT().ConsumeOIDCState(context.Background(), stored.ID).Return(stored, nil)
+
+	return model.OIDCCallbackParams{
+		State: callback.Get("state"),
+		Code:  callback.Get("code"),
+	}
+}
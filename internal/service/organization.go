@@ -0,0 +1,65 @@
+//go:generate mockgen -destination=mocks/organization_service_mock.go -package=mocks . OrganizationService
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+)
+
+// OrganizationService manages the organizations users belong to.
+type OrganizationService interface {
+	// CreateOrganization creates an organization owned by the user.
+	CreateOrganization(ctx context.Context, user *model.User, params model.OrganizationParams) (*model.Organization, error)
+	ListOrganizations(ctx context.Context, user *model.User) ([]model.Organization, error)
+	// MemberOrganization returns the organization if the user is one of its
+	// members, and ErrOrganizationNotFound otherwise, so that non-members
+	// cannot tell which organizations exist.
+	MemberOrganization(ctx context.Context, id string, user *model.User) (*model.Organization, error)
+}
+
+type organizationService struct {
+	orgs repo.OrganizationRepo
+}
+
+func NewOrganizationService(orgs repo.OrganizationRepo) OrganizationService {
+	return &organizationService{
+		orgs: orgs,
+	}
+}
+
+func (s *organizationService) CreateOrganization(ctx context.Context, user *model.User, params model.OrganizationParams) (*model.Organization, error) {
+	org, err := s.orgs.CreateOrganization(ctx, model.Organization{Name: params.Name},
+		model.Membership{UserID: user.ID, Role: RoleOwner})
+	if err != nil {
+		return nil, fmt.Errorf("create organization: %w", err)
+	}
+
+	return org, nil
+}
+
+func (s *organizationService) ListOrganizations(ctx context.Context, user *model.User) ([]model.Organization, error) {
+	orgs, err := s.orgs.ListUserOrganizations(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list user organizations: %w", err)
+	}
+
+	return orgs, nil
+}
+
+func (s *organizationService) MemberOrganization(ctx context.Context, id string, user *model.User) (*model.Organization, error) {
+	org, err := s.orgs.FindMemberOrganization(ctx, id, user.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrganizationNotFound
+		}
+
+		return nil, fmt.Errorf("find member organization: %w", err)
+	}
+
+	return org, nil
+}
@@ -0,0 +1,45 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+)
+
+func TestOrganizationService_CreateOrganization_CreatorOwns(t *testing.T) {
+	orgs, orgService := setupOrganizationService(t)
+	ctx := context.Background()
+
+	orgs.EXPECT().CreateOrganization(ctx, model.Organization{Name: testOrg.Name},
+		model.Membership{UserID: testID, Role: service.RoleOwner}).Return(testOrg, nil)
+
+	org, err := orgService.CreateOrganization(ctx, &model.User{ID: testID}, model.OrganizationParams{Name: testOrg.Name})
+
+	assert.NoError(t, err, "create organization should not return an error")
+	assert.Equal(t, testOrg, org, "organization should match")
+}
+
+func TestOrganizationService_MemberOrganization_NotMember(t *testing.T) {
+	orgs, orgService := setupOrganizationService(t)
+	ctx := context.Background()
+
+	orgs.EXPECT().FindMemberOrganization(ctx, testOrgID, testID).Return(nil, sql.ErrNoRows)
+
+	_, err := orgService.MemberOrganization(ctx, testOrgID, &model.User{ID: testID})
+
+	assert.ErrorIs(t, err, service.ErrOrganizationNotFound, "errors should match")
+}
+
+func setupOrganizationService(t *testing.T) (*repoMocks.MockOrganizationRepo, service.OrganizationService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	orgs := repoMocks.NewMockOrganizationRepo(ctrl)
+	return orgs, service.NewOrganizationService(orgs)
+}
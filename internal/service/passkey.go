@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/webauthn"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+)
+
+// defaultPasskeyName names passkeys registered without one.
+const defaultPasskeyName = "Passkey"
+
+// PasskeyConfig configures sign-in with passkeys.
+type PasskeyConfig struct {
+	// RPID is the domain passkeys are scoped to, such as "example.com".
+	RPID string
+	// RPName is shown to the user when creating a passkey.
+	RPName string
+	// Origins are the allowed origins of the pages that use passkeys.
+	Origins []string
+	// ChallengeTTL is how long the user has to finish a ceremony.
+	ChallengeTTL time.Duration
+	// RequireUserVerification rejects passkeys that were used without a PIN
+	// or biometric check.
+	RequireUserVerification bool
+}
+
+type passkeyAuthenticator struct {
+	passkeys   repo.PasskeyRepo
+	challenges repo.WebAuthnChallengeRepo
+	rp         *webauthn.RelyingParty
+	ttl        time.Duration
+}
+
+// WithPasskeys lets users register passkeys and sign in with them instead
+// of a password.
+func WithPasskeys(passkeys repo.PasskeyRepo, challenges repo.WebAuthnChallengeRepo, cfg PasskeyConfig) AuthOption {
+	return func(s *authService) {
+		s.passkeys = &passkeyAuthenticator{
+			passkeys:   passkeys,
+			challenges: challenges,
+			rp: &webauthn.RelyingParty{
+				ID:                      cfg.RPID,
+				Name:                    cfg.RPName,
+				Origins:                 cfg.Origins,
+				Timeout:                 cfg.ChallengeTTL,
+				RequireUserVerification: cfg.RequireUserVerification,
+			},
+			ttl: cfg.ChallengeTTL,
+		}
+	}
+}
+
+// BeginPasskeyRegistration checks the user's password and returns the
+// options for navigator.credentials.create().
+func (s *authService) BeginPasskeyRegistration(ctx context.Context, user *model.User,
+	params model.CurrentPasswordParams) (*webauthn.CreationOptions, error) {
+	if s.passkeys == nil {
+		return nil, ErrPasskeysDisabled
+	}
+
+	if err := s.checkPassword(ctx, user, params.CurrentPassword); err != nil {
+		return nil, err
+	}
+
+	registered, err := s.passkeys.passkeys.ListUserPasskeys(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list user passkeys: %w", err)
+	}
+
+	exclude := make([]webauthn.CredentialDescriptor, 0, len(registered))
+	for _, passkey := range registered {
+		id, err := base64.RawURLEncoding.DecodeString(passkey.ID)
+		if err != nil {
+			return nil, fmt.Errorf("decode passkey id: %w", err)
+		}
+
+		exclude = append(exclude, webauthn.CredentialDescriptor{
+			Type:       webauthn.CredentialTypePublicKey,
+			ID:         id,
+			Transports: passkey.Transports,
+		})
+	}
+
+	challenge, err := s.passkeys.newChallenge(ctx, model.CeremonyRegistration, &user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	entity := webauthn.UserEntity{
+		ID:          []byte(user.ID),
+		Name:        user.Email,
+		DisplayName: user.Email,
+	}
+
+	return s.passkeys.rp.CreationOptions(challenge, entity, exclude), nil
+}
+
+// FinishPasskeyRegistration verifies the browser's response to the options
+// of BeginPasskeyRegistration and stores the new passkey.
+func (s *authService) FinishPasskeyRegistration(ctx context.Context, user *model.User,
+	params model.PasskeyRegistrationParams) (*model.Passkey, error) {
+	if s.passkeys == nil {
+		return nil, ErrPasskeysDisabled
+	}
+
+	resp := params.Credential
+	challenge, err := s.passkeys.consumeChallenge(ctx, resp.Response.ClientDataJSON, model.CeremonyRegistration, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := s.passkeys.rp.VerifyRegistration(challenge, resp)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrInvalidResponse) {
+			slog.Info("passkey registration rejected", "user_id", user.ID, "error", err)
+			return nil, ErrInvalidPasskey
+		}
+
+		return nil, fmt.Errorf("verify registration: %w", err)
+	}
+
+	name := params.Name
+	if name == "" {
+		name = defaultPasskeyName
+	}
+
+	passkey, err := s.passkeys.passkeys.CreatePasskey(ctx, model.Passkey{
+		ID:                webauthn.Base64URL(cred.ID).String(),
+		UserID:            user.ID,
+		Name:              name,
+		PublicKey:         cred.PublicKey,
+		SignCount:         cred.SignCount,
+		AAGUID:            cred.AAGUID,
+		AttestationFormat: cred.AttestationFormat,
+		Transports:        cred.Transports,
+		BackupEligible:    cred.BackupEligible,
+		BackupState:       cred.BackupState,
+	})
+	if err != nil {
+		if errors.Is(err, repo.ErrDuplicate) {
+			return nil, ErrPasskeyRegistered
+		}
+
+		return nil, fmt.Errorf("create passkey: %w", err)
+	}
+
+	return passkey, nil
+}
+
+func (s *authService) ListPasskeys(ctx context.Context, user *model.User) ([]model.Passkey, error) {
+	if s.passkeys == nil {
+		return nil, ErrPasskeysDisabled
+	}
+
+	passkeys, err := s.passkeys.passkeys.ListUserPasskeys(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list user passkeys: %w", err)
+	}
+
+	return passkeys, nil
+}
+
+func (s *authService) DeletePasskey(ctx context.Context, user *model.User, id string) error {
+	if s.passkeys == nil {
+		return ErrPasskeysDisabled
+	}
+
+	if err := s.passkeys.passkeys.DeletePasskey(ctx, user.ID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrPasskeyNotFound
+		}
+
+		return fmt.Errorf("delete passkey: %w", err)
+	}
+
+	return nil
+}
+
+// BeginPasskeySignIn returns the options for navigator.credentials.get().
+// They allow any passkey for the site, so the user is identified by the
+// passkey they pick and no email is needed.
+func (s *authService) BeginPasskeySignIn(ctx context.Context) (*webauthn.RequestOptions, error) {
+	if s.passkeys == nil {
+		return nil, ErrPasskeysDisabled
+	}
+
+	challenge, err := s.passkeys.newChallenge(ctx, model.CeremonyAuthentication, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.passkeys.rp.RequestOptions(challenge, nil), nil
+}
+
+// FinishPasskeySignIn verifies the browser's response to the options of
+// BeginPasskeySignIn and returns the ID of the user it signs in. A passkey
+// whose authenticator verified the user, by PIN or biometric, is a second
+// factor in itself. Without that it only proves possession, so users who
+// enabled two-factor authentication must still enter a code.
+func (s *authService) FinishPasskeySignIn(ctx context.Context, params model.PasskeySignInParams) (string, error) {
+	if s.passkeys == nil {
+		return "", ErrPasskeysDisabled
+	}
+
+	resp := params.Credential
+	challenge, err := s.passkeys.consumeChallenge(ctx, resp.Response.ClientDataJSON, model.CeremonyAuthentication, "")
+	if err != nil {
+		return "", err
+	}
+
+	passkey, err := s.passkeys.passkeys.FindPasskey(ctx, resp.RawID.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrInvalidPasskey
+		}
+
+		return "", fmt.Errorf("find passkey: %w", err)
+	}
+
+	if len(resp.Response.UserHandle) != 0 && string(resp.Response.UserHandle) != passkey.UserID {
+		return "", ErrInvalidPasskey
+	}
+
+	cred := &webauthn.Credential{
+		ID:        resp.RawID,
+		PublicKey: passkey.PublicKey,
+		SignCount: passkey.SignCount,
+	}
+
+	assertion, err := s.passkeys.rp.VerifyAssertion(challenge, cred, resp)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrSignCountRegression) {
+			slog.Warn("passkey signature counter did not increase, it may have been cloned",
+				"user_id", passkey.UserID, "passkey_id", passkey.ID)
+			return "", ErrInvalidPasskey
+		}
+
+		if errors.Is(err, webauthn.ErrInvalidResponse) {
+			return "", ErrInvalidPasskey
+		}
+
+		return "", fmt.Errorf("verify assertion: %w", err)
+	}
+
+	if err := s.passkeys.passkeys.UpdatePasskeyUse(ctx, passkey.ID, assertion.SignCount, assertion.BackupState,
+		time.Now().UTC()); err != nil {
+		// A concurrent sign-in stored the same or a later counter.
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrInvalidPasskey
+		}
+
+		return "", fmt.Errorf("update passkey use: %w", err)
+	}
+
+	user, err := s.repo.FindUserByID(ctx, passkey.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrInvalidPasskey
+		}
+
+		return "", fmt.Errorf("find user by id: %w", err)
+	}
+
+	if s.verifier != nil && s.verifier.cfg.RequireVerified && user.EmailVerifiedAt == nil {
+		return "", ErrEmailNotVerified
+	}
+
+	if s.mfa != nil && !assertion.UserVerified {
+		if err := s.mfa.requireSecondFactor(ctx, user.ID); err != nil {
+			return "", err
+		}
+	}
+
+	return user.ID, nil
+}
+
+// newChallenge stores a random challenge for a ceremony and returns it.
+func (p *passkeyAuthenticator) newChallenge(ctx context.Context, ceremony model.WebAuthnCeremony,
+	userID *string) ([]byte, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, fmt.Errorf("generate webauthn challenge: %w", err)
+	}
+
+	params := model.WebAuthnChallenge{
+		ID:        security.HashToken(webauthn.Base64URL(challenge).String()),
+		UserID:    userID,
+		Ceremony:  ceremony,
+		ExpiresAt: time.Now().UTC().Add(p.ttl),
+	}
+
+	if _, err := p.challenges.CreateWebAuthnChallenge(ctx, params); err != nil {
+		return nil, fmt.Errorf("create webauthn challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// consumeChallenge uses up the challenge a response claims to answer, and
+// returns it if it was issued for the ceremony and user and has not expired.
+// An empty userID expects a challenge issued to nobody in particular.
+func (p *passkeyAuthenticator) consumeChallenge(ctx context.Context, clientDataJSON []byte,
+	ceremony model.WebAuthnCeremony, userID string) ([]byte, error) {
+	data, err := webauthn.ParseClientData(clientDataJSON)
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+
+	challenge, err := base64.RawURLEncoding.DecodeString(data.Challenge)
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+
+	stored, err := p.challenges.ConsumeWebAuthnChallenge(ctx, security.HashToken(data.Challenge))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidPasskey
+		}
+
+		return nil, fmt.Errorf("consume webauthn challenge: %w", err)
+	}
+
+	owner := ""
+	if stored.UserID != nil {
+		owner = *stored.UserID
+	}
+
+	if stored.Ceremony != ceremony || owner != userID || !time.Now().UTC().Before(stored.ExpiresAt) {
+		return nil, ErrInvalidPasskey
+	}
+
+	return challenge, nil
+}
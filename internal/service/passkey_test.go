@@ -0,0 +1,351 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/webauthn"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/webauthn/webauthntest"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	secMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/security/mocks"
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+)
+
+var testPasskeyConfig = service.PasskeyConfig{
+	RPID:         "example.com",
+	RPName:       "Example",
+	Origins:      []string{"https://example.com"},
+	ChallengeTTL: 5 * time.Minute,
+}
+
+type passkeyMocks struct {
+	users      *repoMocks.MockUserRepo
+	hasher     *secMocks.MockHasher
+	passkeys   *repoMocks.MockPasskeyRepo
+	challenges *repoMocks.MockWebAuthnChallengeRepo
+	totp       *repoMocks.MockTOTPRepo
+	mfa        *repoMocks.MockMFAChallengeRepo
+}
+
+func TestAuthService_PasskeyRegistration(t *testing.T) {
+	m, authService := setupPasskeyMocks(t)
+	auth := webauthntest.New(t)
+	auth.Attestation = webauthntest.AttestationSelf
+
+	passkey := registerPasskey(t, m, authService, auth)
+
+	assert.Equal(t, webauthn.Base64URL(auth.CredentialID()).String(), passkey.ID, "passkey ID should match")
+	assert.Equal(t, testID, passkey.UserID, "user ID should match")
+	assert.Equal(t, "Laptop", passkey.Name, "name should match")
+	assert.Equal(t, webauthn.FormatPacked, passkey.AttestationFormat, "attestation format should match")
+}
+
+func TestAuthService_BeginPasskeyRegistration_WrongPassword(t *testing.T) {
+	m, authService := setupPasskeyMocks(t)
+	ctx := context.Background()
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify("wrong", hashedPassword).Return(false, nil)
+	m.challenges.EXPECT().CreateWebAuthnChallenge(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := authService.BeginPasskeyRegistration(ctx, testUser, model.CurrentPasswordParams{CurrentPassword: "wrong"})
+
+	assert.ErrorIs(t, err, service.ErrPasswordMismatch, "errors should match")
+}
+
+func TestAuthService_FinishPasskeyRegistration_Rejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		challenge func(model.WebAuthnChallenge) (*model.WebAuthnChallenge, error)
+	}{
+		{
+			name: "used challenge",
+			challenge: func(model.WebAuthnChallenge) (*model.WebAuthnChallenge, error) {
+				return nil, sql.ErrNoRows
+			},
+		},
+		{
+			name: "expired challenge",
+			challenge: func(c model.WebAuthnChallenge) (*model.WebAuthnChallenge, error) {
+				c.ExpiresAt = time.Now().Add(-time.Second)
+				return &c, nil
+			},
+		},
+		{
+			name: "challenge of another user",
+			challenge: func(c model.WebAuthnChallenge) (*model.WebAuthnChallenge, error) {
+				other := "2"
+				c.UserID = &other
+				return &c, nil
+			},
+		},
+		{
+			name: "sign-in challenge",
+			challenge: func(c model.WebAuthnChallenge) (*model.WebAuthnChallenge, error) {
+				c.Ceremony = model.CeremonyAuthentication
+				return &c, nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" should be rejected", func(t *testing.T) {
+			m, authService := setupPasskeyMocks(t)
+			ctx := context.Background()
+			opts, stored := beginPasskeyRegistration(t, m, authService)
+
+			m.challenges.EXPECT().ConsumeWebAuthnChallenge(ctx, stored.ID).Return(tt.challenge(*stored))
+			m.passkeys.EXPECT().CreatePasskey(gomock.Any(), gomock.Any()).Times(0)
+
+			_, err := authService.FinishPasskeyRegistration(ctx, testUser, model.PasskeyRegistrationParams{
+				Credential: webauthntest.New(t).Register(t, opts),
+			})
+
+			assert.ErrorIs(t, err, service.ErrInvalidPasskey, "errors should match")
+		})
+	}
+}
+
+func TestAuthService_FinishPasskeySignIn_Success(t *testing.T) {
+	m, authService := setupPasskeyMocks(t)
+	ctx := context.Background()
+	auth := webauthntest.New(t)
+	passkey := registerPasskey(t, m, authService, auth)
+
+	opts, stored := beginPasskeySignIn(t, m, authService)
+	m.challenges.EXPECT().ConsumeWebAuthnChallenge(ctx, stored.ID).Return(stored, nil)
+	m.passkeys.EXPECT().FindPasskey(ctx, passkey.ID).Return(passkey, nil)
+	m.passkeys.EXPECT().UpdatePasskeyUse(ctx, passkey.ID, uint32(1), false, gomock.Any()).Return(nil)
+	m.users.EXPECT().FindUserByID(ctx, testID).Return(&model.User{ID: testID, Email: testEmail}, nil)
+
+	userID, err := authService.FinishPasskeySignIn(ctx, model.PasskeySignInParams{Credential: auth.Login(t, opts)})
+
+	assert.NoError(t, err, "passkey signin should not return an error")
+	assert.Equal(t, testID, userID, "user ID should match")
+}
+
+func TestAuthService_FinishPasskeySignIn_MFA(t *testing.T) {
+	tests := []struct {
+		name         string
+		userVerified bool
+		err          error
+	}{
+		{"verified user should be signed in", true, nil},
+		{"unverified user should be asked for a code", false, service.ErrMFARequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, authService := setupPasskeyMocks(t)
+			ctx := context.Background()
+			auth := webauthntest.New(t)
+			passkey := registerPasskey(t, m, authService, auth)
+			auth.UserVerified = tt.userVerified
+
+			opts, stored := beginPasskeySignIn(t, m, authService)
+			m.challenges.EXPECT().ConsumeWebAuthnChallenge(ctx, stored.ID).Return(stored, nil)
+			m.passkeys.EXPECT().FindPasskey(ctx, passkey.ID).Return(passkey, nil)
+			m.passkeys.EXPECT().UpdatePasskeyUse(ctx, passkey.ID, uint32(1), false, gomock.Any()).Return(nil)
+			m.users.EXPECT().FindUserByID(ctx, testID).Return(&model.User{ID: testID, Email: testEmail}, nil)
+			if tt.userVerified {
+				m.totp.EXPECT().FindTOTP(gomock.Any(), gomock.Any()).Times(0)
+			} else {
+				m.totp.EXPECT().FindTOTP(ctx, testID).Return(confirmedTOTP(0), nil)
+				m.mfa.EXPECT().CreateMFAChallenge(ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, params model.MFAChallenge) (*model.MFAChallenge, error) {
+						return &params, nil
+					})
+			}
+
+			userID, err := authService.FinishPasskeySignIn(ctx, model.PasskeySignInParams{Credential: auth.Login(t, opts)})
+
+			assert.ErrorIs(t, err, tt.err, "errors should match")
+			if tt.err == nil {
+				assert.Equal(t, testID, userID, "user ID should match")
+			} else {
+				assert.Empty(t, userID, "no user should be signed in")
+			}
+		})
+	}
+}
+
+func TestAuthService_FinishPasskeySignIn_Rejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(passkey *model.Passkey, resp *webauthn.AssertionResponse)
+	}{
+		{
+			name:   "cloned authenticator",
+			tamper: func(passkey *model.Passkey, _ *webauthn.AssertionResponse) { passkey.SignCount = 5 },
+		},
+		{
+			name:   "passkey of another user",
+			tamper: func(_ *model.Passkey, resp *webauthn.AssertionResponse) { resp.Response.UserHandle = []byte("2") },
+		},
+		{
+			name: "bad signature",
+			tamper: func(_ *model.Passkey, resp *webauthn.AssertionResponse) {
+				resp.Response.Signature[len(resp.Response.Signature)-1] ^= 0xff
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" should be rejected", func(t *testing.T) {
+			m, authService := setupPasskeyMocks(t)
+			ctx := context.Background()
+			auth := webauthntest.New(t)
+			passkey := registerPasskey(t, m, authService, auth)
+
+			opts, stored := beginPasskeySignIn(t, m, authService)
+			resp := auth.Login(t, opts)
+			tt.tamper(passkey, resp)
+
+			m.challenges.EXPECT().ConsumeWebAuthnChallenge(ctx, stored.ID).Return(stored, nil)
+			m.passkeys.EXPECT().FindPasskey(ctx, passkey.ID).Return(passkey, nil)
+			m.passkeys.EXPECT().UpdatePasskeyUse(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(0)
+
+			_, err := authService.FinishPasskeySignIn(ctx, model.PasskeySignInParams{Credential: resp})
+
+			assert.ErrorIs(t, err, service.ErrInvalidPasskey, "errors should match")
+		})
+	}
+}
+
+func TestAuthService_FinishPasskeySignIn_UnknownPasskey(t *testing.T) {
+	m, authService := setupPasskeyMocks(t)
+	ctx := context.Background()
+	auth := webauthntest.New(t)
+	passkey := registerPasskey(t, m, authService, auth)
+
+	opts, stored := beginPasskeySignIn(t, m, authService)
+	m.challenges.EXPECT().ConsumeWebAuthnChallenge(ctx, stored.ID).Return(stored, nil)
+	m.passkeys.EXPECT().FindPasskey(ctx, passkey.ID).Return(nil, sql.ErrNoRows)
+
+	_, err := authService.FinishPasskeySignIn(ctx, model.PasskeySignInParams{Credential: auth.Login(t, opts)})
+
+	assert.ErrorIs(t, err, service.ErrInvalidPasskey, "errors should match")
+}
+
+func TestAuthService_DeletePasskey_NotFound(t *testing.T) {
+	m, authService := setupPasskeyMocks(t)
+	ctx := context.Background()
+	m.passkeys.EXPECT().DeletePasskey(ctx, testID, "missing").Return(sql.ErrNoRows)
+
+	err := authService.DeletePasskey(ctx, testUser, "missing")
+
+	assert.ErrorIs(t, err, service.ErrPasskeyNotFound, "errors should match")
+}
+
+func TestAuthService_Passkeys_Disabled(t *testing.T) {
+	_, _, authService := setupMocks(t)
+	ctx := context.Background()
+
+	_, err := authService.BeginPasskeyRegistration(ctx, testUser, model.CurrentPasswordParams{CurrentPassword: testPassword})
+	assert.ErrorIs(t, err, service.ErrPasskeysDisabled, "errors should match")
+
+	_, err = authService.BeginPasskeySignIn(ctx)
+	assert.ErrorIs(t, err, service.ErrPasskeysDisabled, "errors should match")
+}
+
+func setupPasskeyMocks(t *testing.T) (*passkeyMocks, service.AuthService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := &passkeyMocks{
+		users:      repoMocks.NewMockUserRepo(ctrl),
+		hasher:     secMocks.NewMockHasher(ctrl),
+		passkeys:   repoMocks.NewMockPasskeyRepo(ctrl),
+		challenges: repoMocks.NewMockWebAuthnChallengeRepo(ctrl),
+		totp:       repoMocks.NewMockTOTPRepo(ctrl),
+		mfa:        repoMocks.NewMockMFAChallengeRepo(ctrl),
+	}
+	authService := service.NewAuthService(m.users, m.hasher,
+		service.WithPasskeys(m.passkeys, m.challenges, testPasskeyConfig),
+		service.WithMFA(m.totp, repoMocks.NewMockRecoveryCodeRepo(ctrl), m.mfa, testMFAConfig))
+
+	return m, authService
+}
+
+// beginPasskeyRegistration starts a registration for testUser and returns
+// the options and the challenge stored for them.
+func beginPasskeyRegistration(t *testing.T, m *passkeyMocks,
+	authService service.AuthService) (*webauthn.CreationOptions, *model.WebAuthnChallenge) {
+	t.Helper()
+	ctx := context.Background()
+	var stored model.WebAuthnChallenge
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(true, nil)
+	m.passkeys.EXPECT().ListUserPasskeys(ctx, testID).Return([]model.Passkey{}, nil)
+	m.challenges.EXPECT().CreateWebAuthnChallenge(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, params model.WebAuthnChallenge) (*model.WebAuthnChallenge, error) {
+			stored = params
+			return &params, nil
+		})
+
+	opts, err := authService.BeginPasskeyRegistration(ctx, testUser, model.CurrentPasswordParams{CurrentPassword: testPassword})
+	if err != nil {
+		t.Fatalf("begin passkey registration should not return an error: %v", err)
+	}
+	assert.Equal(t, security.HashToken(opts.Challenge.String()), stored.ID, "only the challenge hash should be stored")
+	assert.Equal(t, []byte(testID), []byte(opts.User.ID), "user handle should be the user ID")
+
+	return opts, &stored
+}
+
+// registerPasskey registers a passkey on auth for testUser and returns it as
+// stored.
+func registerPasskey(t *testing.T, m *passkeyMocks, authService service.AuthService,
+	auth *webauthntest.Authenticator) *model.Passkey {
+	t.Helper()
+	ctx := context.Background()
+	opts, stored := beginPasskeyRegistration(t, m, authService)
+
+	m.challenges.EXPECT().ConsumeWebAuthnChallenge(ctx, stored.ID).Return(stored, nil)
+	m.passkeys.EXPECT().CreatePasskey(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, params model.Passkey) (*model.Passkey, error) {
+			return &params, nil
+		})
+
+	passkey, err := authService.FinishPasskeyRegistration(ctx, testUser, model.PasskeyRegistrationParams{
+		Name:       "Laptop",
+		Credential: auth.Register(t, opts),
+	})
+	if err != nil {
+		t.Fatalf("finish passkey registration should not return an error: %v", err)
+	}
+
+	return passkey
+}
+
+// beginPasskeySignIn starts a passkey sign-in and returns the options and
+// the challenge stored for them.
+func beginPasskeySignIn(t *testing.T, m *passkeyMocks,
+	authService service.AuthService) (*webauthn.RequestOptions, *model.WebAuthnChallenge) {
+	t.Helper()
+	ctx := context.Background()
+	var stored model.WebAuthnChallenge
+
+	m.challenges.EXPECT().CreateWebAuthnChallenge(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, params model.WebAuthnChallenge) (*model.WebAuthnChallenge, error) {
+			stored = params
+			return &params, nil
+		})
+
+	opts, err := authService.BeginPasskeySignIn(ctx)
+	if err != nil {
+		t.Fatalf("begin passkey signin should not return an error: %v", err)
+	}
+	assert.Nil(t, stored.UserID, "signin challenges should not name a user")
+	assert.Empty(t, opts.AllowCredentials, "any passkey should be allowed")
+
+	return opts, &stored
+}
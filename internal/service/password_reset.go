@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/mail"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+)
+
+type PasswordResetConfig struct {
+	// BaseURL is the address of the app the reset link points to.
+	BaseURL string
+	TTL     time.Duration
+}
+
+type passwordResetter struct {
+	tokens repo.UserTokenRepo
+	mailer mail.Mailer
+	cfg    PasswordResetConfig
+}
+
+// WithPasswordReset lets users reset a forgotten password through an emailed link.
+func WithPasswordReset(tokens repo.UserTokenRepo, mailer mail.Mailer, cfg PasswordResetConfig) AuthOption {
+	return func(s *authService) {
+		s.resetter = &passwordResetter{
+			tokens: tokens,
+			mailer: mailer,
+			cfg:    cfg,
+		}
+	}
+}
+
+// ForgotPassword emails a password reset link. It succeeds without sending
+// anything for unknown emails, and sends in the background otherwise, so that
+// neither the response nor its timing lets callers probe for registered
+// accounts.
+func (s *authService) ForgotPassword(ctx context.Context, email string) error {
+	if s.resetter == nil {
+		return ErrPasswordResetDisabled
+	}
+
+	user, err := s.repo.FindUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return fmt.Errorf("find user by email: %w", err)
+	}
+
+	s.runInBackground(ctx, "send password reset email", func(ctx context.Context) error {
+		return s.resetter.send(ctx, user)
+	})
+
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token and signs the user
+// out everywhere.
+func (s *authService) ResetPassword(ctx context.Context, params model.ResetPasswordParams) error {
+	if s.resetter == nil {
+		return ErrPasswordResetDisabled
+	}
+
+	// Checked before the token is used up, so that the link can be retried
+	// with another password.
+	if err := s.checkBreached(ctx, params.Password); err != nil {
+		return err
+	}
+
+	userToken, err := consumeUserToken(ctx, s.resetter.tokens, params.Token, model.TokenPurposeResetPassword)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.repo.FindUserByID(ctx, userToken.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidToken
+		}
+
+		return fmt.Errorf("find user by id: %w", err)
+	}
+
+	if user.Email != userToken.Email {
+		return ErrInvalidToken
+	}
+
+	hash, err := security.HashContext(ctx, s.hasher, params.Password)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if err := s.repo.UpdatePasswordHash(ctx, user.ID, hash, now); err != nil {
+		return fmt.Errorf("update password hash: %w", err)
+	}
+
+	return s.revokeUserCredentials(ctx, user.ID, now)
+}
+
+func (r *passwordResetter) send(ctx context.Context, user *model.User) error {
+	token, err := issueUserToken(ctx, r.tokens, user, model.TokenPurposeResetPassword, r.cfg.TTL)
+	if err != nil {
+		return err
+	}
+
+	msg := mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Choose a new password by opening the link below:\n\n%s\n\n"+
+			"The link expires in %s. If you did not ask to reset your password, you can ignore this email.\n",
+			tokenLink(r.cfg.BaseURL, "/reset-password", token), r.cfg.TTL),
+	}
+
+	if err := r.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/mail"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	secMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/security/mocks"
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+)
+
+const newPassword = "new password"
+
+var testPasswordResetConfig = service.PasswordResetConfig{
+	BaseURL: testBaseURL,
+	TTL:     time.Hour,
+}
+
+type resetMocks struct {
+	users    *repoMocks.MockUserRepo
+	hasher   *secMocks.MockHasher
+	tokens   *repoMocks.MockUserTokenRepo
+	sessions *repoMocks.MockSessionStore
+	refresh  *repoMocks.MockRefreshTokenRepo
+	mailer   *mail.MemoryMailer
+	tasks    *taskQueue
+}
+
+func TestAuthService_ForgotPassword_SendsResetLink(t *testing.T) {
+	m, authService := setupResetMocks(t)
+	ctx := context.Background()
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, Email: testEmail}, nil)
+	m.tokens.EXPECT().DeleteUserTokens(gomock.Any(), testID, model.TokenPurposeResetPassword).Return(nil)
+
+	var stored model.UserToken
+	m.tokens.EXPECT().CreateUserToken(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, params model.UserToken) (*model.UserToken, error) {
+			stored = params
+			return &params, nil
+		})
+
+	err := authService.ForgotPassword(ctx, testEmail)
+	m.tasks.run()
+
+	assert.NoError(t, err, "forgot password should not return an error")
+	assert.Equal(t, model.TokenPurposeResetPassword, stored.Purpose, "purpose should match")
+	msgs := m.mailer.Messages()
+	if assert.Len(t, msgs, 1, "one email should be sent") {
+		assert.Contains(t, msgs[0].Body, testBaseURL+"/reset-password?token=", "link should point to the reset page")
+		assert.Equal(t, security.HashToken(linkToken(t, msgs[0].Body)), stored.ID, "only the token hash should be stored")
+	}
+}
+
+func TestAuthService_ForgotPassword_UnknownEmail(t *testing.T) {
+	m, authService := setupResetMocks(t)
+	ctx := context.Background()
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(nil, sql.ErrNoRows)
+	m.tokens.EXPECT().CreateUserToken(gomock.Any(), gomock.Any()).Times(0)
+
+	err := authService.ForgotPassword(ctx, testEmail)
+	m.tasks.run()
+
+	assert.NoError(t, err, "unknown emails should not be reported")
+	assert.Empty(t, m.mailer.Messages(), "no email should be sent")
+}
+
+func TestAuthService_ForgotPassword_SameRequestWork(t *testing.T) {
+	tests := []struct {
+		name string
+		user *model.User
+		err  error
+	}{
+		{"known email", &model.User{ID: testID, Email: testEmail}, nil},
+		{"unknown email", nil, sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, authService := setupResetMocks(t)
+			ctx := context.Background()
+
+			// Any other call on the request path fails the test.
+			m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(tt.user, tt.err)
+
+			err := authService.ForgotPassword(ctx, testEmail)
+
+			assert.NoError(t, err, "forgot password should not return an error")
+			assert.Empty(t, m.mailer.Messages(), "no email should be sent before the request returns")
+		})
+	}
+}
+
+func TestAuthService_ResetPassword_Success(t *testing.T) {
+	m, authService := setupResetMocks(t)
+	ctx := context.Background()
+	params := model.ResetPasswordParams{Token: "token", Password: newPassword, PasswordConfirm: newPassword}
+
+	m.tokens.EXPECT().ConsumeUserToken(ctx, security.HashToken(params.Token), model.TokenPurposeResetPassword).
+		Return(&model.UserToken{UserID: testID, Email: testEmail, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	m.users.EXPECT().FindUserByID(ctx, testID).Return(&model.User{ID: testID, Email: testEmail}, nil)
+	m.hasher.EXPECT().Hash(newPassword).Return(hashedPassword, nil)
+	m.users.EXPECT().UpdatePasswordHash(ctx, testID, hashedPassword, gomock.Any()).Return(nil)
+	m.sessions.EXPECT().DeleteUserSessions(ctx, testID).Return(nil)
+	m.refresh.EXPECT().RevokeUserRefreshTokens(ctx, testID, gomock.Any()).Return(nil)
+
+	err := authService.ResetPassword(ctx, params)
+
+	assert.NoError(t, err, "reset password should not return an error")
+}
+
+func TestAuthService_ResetPassword_InvalidToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		token *model.UserToken
+		err   error
+	}{
+		{"unknown token should be rejected", nil, sql.ErrNoRows},
+		{"expired token should be rejected",
+			&model.UserToken{UserID: testID, Email: testEmail, ExpiresAt: time.Now().Add(-time.Minute)}, nil},
+		{"token for a previous email should be rejected",
+			&model.UserToken{UserID: testID, Email: "old@example.com", ExpiresAt: time.Now().Add(time.Hour)}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, authService := setupResetMocks(t)
+			ctx := context.Background()
+
+			m.tokens.EXPECT().ConsumeUserToken(ctx, gomock.Any(), model.TokenPurposeResetPassword).Return(tt.token, tt.err)
+			m.users.EXPECT().FindUserByID(ctx, testID).Return(&model.User{ID: testID, Email: testEmail}, nil).AnyTimes()
+			m.users.EXPECT().UpdatePasswordHash(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			err := authService.ResetPassword(ctx, model.ResetPasswordParams{Token: "token", Password: newPassword})
+
+			assert.ErrorIs(t, err, service.ErrInvalidToken, "errors should match")
+		})
+	}
+}
+
+func TestAuthService_PasswordReset_Disabled(t *testing.T) {
+	_, _, authService := setupMocks(t)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, authService.ForgotPassword(ctx, testEmail), service.ErrPasswordResetDisabled, "errors should match")
+	assert.ErrorIs(t, authService.ResetPassword(ctx, model.ResetPasswordParams{}), service.ErrPasswordResetDisabled,
+		"errors should match")
+}
+
+func setupResetMocks(t *testing.T) (*resetMocks, service.AuthService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := &resetMocks{
+		users:    repoMocks.NewMockUserRepo(ctrl),
+		hasher:   secMocks.NewMockHasher(ctrl),
+		tokens:   repoMocks.NewMockUserTokenRepo(ctrl),
+		sessions: repoMocks.NewMockSessionStore(ctrl),
+		refresh:  repoMocks.NewMockRefreshTokenRepo(ctrl),
+		mailer:   &mail.MemoryMailer{},
+		tasks:    &taskQueue{},
+	}
+	authService := service.NewAuthService(m.users, m.hasher,
+		service.WithBackground(m.tasks.add),
+		service.WithTokens(m.refresh, secMocks.NewMockTokenSigner(ctrl), testTokenConfig),
+		service.WithSessions(m.sessions),
+		service.WithPasswordReset(m.tokens, m.mailer, testPasswordResetConfig))
+
+	return m, authService
+}
@@ -0,0 +1,138 @@
+//go:generate mockgen -destination=mocks/role_service_mock.go -package=mocks . RoleService
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+)
+
+// RoleService manages roles and their assignment to users.
+type RoleService interface {
+	ListRoles(ctx context.Context) ([]model.Role, error)
+	CreateRole(ctx context.Context, params model.RoleParams) (*model.Role, error)
+	ListUserRoles(ctx context.Context, userID string) (*model.UserRoles, error)
+	AssignRole(ctx context.Context, userID, role string) (*model.UserRoles, error)
+	UnassignRole(ctx context.Context, userID, role string) (*model.UserRoles, error)
+}
+
+type roleService struct {
+	roles repo.RoleRepo
+	users repo.UserRepo
+}
+
+func NewRoleService(roles repo.RoleRepo, users repo.UserRepo) RoleService {
+	return &roleService{
+		roles: roles,
+		users: users,
+	}
+}
+
+func (s *roleService) ListRoles(ctx context.Context) ([]model.Role, error) {
+	roles, err := s.roles.ListRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// CreateRole creates a role given to users across the app, unless the params
+// scope it to the members of organizations.
+func (s *roleService) CreateRole(ctx context.Context, params model.RoleParams) (*model.Role, error) {
+	scope := params.Scope
+	if scope == "" {
+		scope = model.RoleScopeGlobal
+	}
+
+	role, err := s.roles.CreateRole(ctx, model.Role{
+		Name:        params.Name,
+		Description: params.Description,
+		Scope:       scope,
+		Permissions: params.Permissions,
+	})
+	if err != nil {
+		if errors.Is(err, repo.ErrDuplicate) {
+			return nil, ErrRoleExists
+		}
+
+		return nil, fmt.Errorf("create role: %w", err)
+	}
+
+	return role, nil
+}
+
+func (s *roleService) ListUserRoles(ctx context.Context, userID string) (*model.UserRoles, error) {
+	if err := s.requireUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.userRoles(ctx, userID)
+}
+
+// AssignRole gives the user the role and returns all of their roles.
+// Assigning a role the user already has changes nothing. Roles of members of
+// organizations cannot be assigned to users.
+func (s *roleService) AssignRole(ctx context.Context, userID, role string) (*model.UserRoles, error) {
+	if err := s.requireUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	found, err := s.roles.FindRole(ctx, role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRoleNotFound
+		}
+
+		return nil, fmt.Errorf("find role: %w", err)
+	}
+
+	if found.Scope != model.RoleScopeGlobal {
+		return nil, ErrRoleScope
+	}
+
+	if err := s.roles.AssignRole(ctx, userID, role); err != nil {
+		return nil, fmt.Errorf("assign role: %w", err)
+	}
+
+	return s.userRoles(ctx, userID)
+}
+
+// UnassignRole takes the role from the user and returns their remaining
+// roles. Taking a role the user does not have changes nothing.
+func (s *roleService) UnassignRole(ctx context.Context, userID, role string) (*model.UserRoles, error) {
+	if err := s.requireUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.roles.UnassignRole(ctx, userID, role); err != nil {
+		return nil, fmt.Errorf("unassign role: %w", err)
+	}
+
+	return s.userRoles(ctx, userID)
+}
+
+func (s *roleService) requireUser(ctx context.Context, userID string) error {
+	if _, err := s.users.FindUserByID(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+
+		return fmt.Errorf("find user by id: %w", err)
+	}
+
+	return nil
+}
+
+func (s *roleService) userRoles(ctx context.Context, userID string) (*model.UserRoles, error) {
+	roles, err := s.roles.ListUserRoles(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user roles: %w", err)
+	}
+
+	return &model.UserRoles{UserID: userID, Roles: roles}, nil
+}
@@ -0,0 +1,92 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+)
+
+const testRole = "editor"
+
+func TestRoleService_AssignRole_Success(t *testing.T) {
+	roles, users, roleService := setupRoleService(t)
+	ctx := context.Background()
+
+	users.EXPECT().FindUserByID(ctx, testID).Return(&model.User{ID: testID}, nil)
+	roles.EXPECT().FindRole(ctx, testRole).Return(&model.Role{Name: testRole, Scope: model.RoleScopeGlobal}, nil)
+	roles.EXPECT().AssignRole(ctx, testID, testRole).Return(nil)
+	roles.EXPECT().ListUserRoles(ctx, testID).Return([]string{testRole}, nil)
+
+	assigned, err := roleService.AssignRole(ctx, testID, testRole)
+
+	assert.NoError(t, err, "assign role should not return an error")
+	assert.Equal(t, &model.UserRoles{UserID: testID, Roles: []string{testRole}}, assigned, "roles should match")
+}
+
+func TestRoleService_AssignRole_NotFound(t *testing.T) {
+	tests := []struct {
+		name    string
+		userErr error
+		roleErr error
+		err     error
+	}{
+		{"unknown user should be rejected", sql.ErrNoRows, nil, service.ErrUserNotFound},
+		{"unknown role should be rejected", nil, sql.ErrNoRows, service.ErrRoleNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles, users, roleService := setupRoleService(t)
+			ctx := context.Background()
+
+			users.EXPECT().FindUserByID(ctx, testID).Return(&model.User{ID: testID}, tt.userErr)
+			roles.EXPECT().FindRole(ctx, testRole).Return(nil, tt.roleErr).AnyTimes()
+			roles.EXPECT().AssignRole(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			_, err := roleService.AssignRole(ctx, testID, testRole)
+
+			assert.ErrorIs(t, err, tt.err, "errors should match")
+		})
+	}
+}
+
+func TestRoleService_AssignRole_OrganizationRole(t *testing.T) {
+	roles, users, roleService := setupRoleService(t)
+	ctx := context.Background()
+
+	users.EXPECT().FindUserByID(ctx, testID).Return(&model.User{ID: testID}, nil)
+	roles.EXPECT().FindRole(ctx, service.RoleOwner).
+		Return(&model.Role{Name: service.RoleOwner, Scope: model.RoleScopeOrganization}, nil)
+	roles.EXPECT().AssignRole(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := roleService.AssignRole(ctx, testID, service.RoleOwner)
+
+	assert.ErrorIs(t, err, service.ErrRoleScope, "errors should match")
+}
+
+func TestRoleService_CreateRole_Exists(t *testing.T) {
+	roles, _, roleService := setupRoleService(t)
+	ctx := context.Background()
+
+	roles.EXPECT().CreateRole(ctx, model.Role{Name: testRole, Scope: model.RoleScopeGlobal}).Return(nil, repo.ErrDuplicate)
+
+	_, err := roleService.CreateRole(ctx, model.RoleParams{Name: testRole})
+
+	assert.ErrorIs(t, err, service.ErrRoleExists, "errors should match")
+}
+
+func setupRoleService(t *testing.T) (*repoMocks.MockRoleRepo, *repoMocks.MockUserRepo, service.RoleService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	roles := repoMocks.NewMockRoleRepo(ctrl)
+	users := repoMocks.NewMockUserRepo(ctrl)
+	return roles, users, service.NewRoleService(roles, users)
+}
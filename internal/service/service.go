@@ -4,3 +4,38 @@ import "errors"
 
 var ErrUserNotFound = errors.New("user does not exists")
 var ErrEmailTaken = errors.New("email is already taken")
+var ErrInvalidSession = errors.New("session is invalid or expired")
+var ErrInvalidToken = errors.New("token is invalid or expired")
+var ErrRefreshTokenReused = errors.New("refresh token has already been used")
+var ErrTokensDisabled = errors.New("token authentication is not enabled")
+var ErrVerificationDisabled = errors.New("email verification is not enabled")
+var ErrEmailNotVerified = errors.New("email is not verified")
+var ErrPasswordResetDisabled = errors.New("password reset is not enabled")
+var ErrTooManyAttempts = errors.New("too many failed sign-in attempts")
+var ErrPasswordBreached = errors.New("password has appeared in a data breach")
+var ErrMFADisabled = errors.New("two-factor authentication is not available")
+var ErrMFARequired = errors.New("a second authentication factor is required")
+var ErrInvalidMFACode = errors.New("authentication code is invalid")
+var ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+var ErrMFANotEnrolled = errors.New("two-factor authentication is not set up")
+var ErrPasskeysDisabled = errors.New("passkeys are not enabled")
+var ErrInvalidPasskey = errors.New("passkey is invalid or was not recognized")
+var ErrPasskeyRegistered = errors.New("passkey is already registered")
+var ErrPasskeyNotFound = errors.New("passkey does not exist")
+var ErrOIDCDisabled = errors.New("sign-in with identity providers is not enabled")
+var ErrUnknownProvider = errors.New("identity provider is not configured")
+var ErrProviderUnavailable = errors.New("identity provider is unavailable")
+var ErrInvalidOIDCSignIn = errors.New("sign-in with the identity provider failed or expired")
+var ErrProviderEmailNotVerified = errors.New("identity provider has not verified the email")
+var ErrIdentityNotLinkable = errors.New("email belongs to an account that has not verified it")
+var ErrMagicLinksDisabled = errors.New("sign-in with emailed links is not enabled")
+var ErrRoleNotFound = errors.New("role does not exist")
+var ErrRoleExists = errors.New("role already exists")
+var ErrRoleScope = errors.New("role cannot be given here")
+var ErrRoleNotGrantable = errors.New("role has permissions the user does not have")
+var ErrOrganizationNotFound = errors.New("organization does not exist")
+var ErrNoOrganization = errors.New("request is not scoped to an organization")
+var ErrMemberNotFound = errors.New("member does not exist")
+var ErrAlreadyMember = errors.New("user is already a member of the organization")
+var ErrLastOwner = errors.New("organization must keep an owner")
+var ErrPasswordRequired = errors.New("a password is required to create the account")
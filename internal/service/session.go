@@ -0,0 +1,104 @@
+//go:generate mockgen -destination=mocks/session_service_mock.go -package=mocks . SessionService
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+)
+
+// SessionTokenLength is the number of random bytes in a session token.
+const SessionTokenLength = 32
+
+type SessionService interface {
+	CreateSession(ctx context.Context, userID string) (string, *model.Session, error)
+	ValidateSession(ctx context.Context, token string) (*model.Session, error)
+	RevokeSession(ctx context.Context, token string) error
+	RevokeUserSessions(ctx context.Context, userID string) error
+}
+
+type sessionService struct {
+	store repo.SessionStore
+	ttl   time.Duration
+}
+
+func NewSessionService(store repo.SessionStore, ttl time.Duration) SessionService {
+	return &sessionService{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+// CreateSession starts a session for the user and returns the opaque token to
+// hand to the client.
+func (s *sessionService) CreateSession(ctx context.Context, userID string) (string, *model.Session, error) {
+	token, err := security.GenerateRandomBytesEncoded(SessionTokenLength)
+	if err != nil {
+		return "", nil, fmt.Errorf("generate session token: %w", err)
+	}
+
+	params := model.Session{
+		ID:        security.HashToken(token),
+		UserID:    userID,
+		ExpiresAt: time.Now().UTC().Add(s.ttl),
+	}
+
+	session, err := s.store.CreateSession(ctx, params)
+	if err != nil {
+		return "", nil, fmt.Errorf("create session: %w", err)
+	}
+
+	return token, session, nil
+}
+
+func (s *sessionService) ValidateSession(ctx context.Context, token string) (*model.Session, error) {
+	if token == "" {
+		return nil, ErrInvalidSession
+	}
+
+	id := security.HashToken(token)
+	session, err := s.store.FindSession(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidSession
+		}
+
+		return nil, fmt.Errorf("find session: %w", err)
+	}
+
+	if !time.Now().Before(session.ExpiresAt) {
+		if err := s.store.DeleteSession(ctx, id); err != nil {
+			return nil, fmt.Errorf("delete expired session: %w", err)
+		}
+
+		return nil, ErrInvalidSession
+	}
+
+	return session, nil
+}
+
+func (s *sessionService) RevokeSession(ctx context.Context, token string) error {
+	if token == "" {
+		return ErrInvalidSession
+	}
+
+	if err := s.store.DeleteSession(ctx, security.HashToken(token)); err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sessionService) RevokeUserSessions(ctx context.Context, userID string) error {
+	if err := s.store.DeleteUserSessions(ctx, userID); err != nil {
+		return fmt.Errorf("delete user sessions: %w", err)
+	}
+
+	return nil
+}
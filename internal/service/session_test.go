@@ -0,0 +1,116 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+)
+
+const (
+	testSessionTTL = time.Hour
+	testToken      = "token"
+)
+
+func TestSessionService_CreateSession_Success(t *testing.T) {
+	mockStore, sessionService := setupSessionMocks(t)
+	ctx := context.Background()
+
+	var stored model.Session
+	mockStore.EXPECT().CreateSession(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, params model.Session) (*model.Session, error) {
+			stored = params
+			params.CreatedAt = time.Now()
+			return &params, nil
+		})
+
+	token, session, err := sessionService.CreateSession(ctx, testID)
+
+	assert.NoError(t, err, "create session should not return an error")
+	assert.NotEmpty(t, token, "token should not be empty")
+	assert.Equal(t, security.HashToken(token), stored.ID, "only the token hash should be stored")
+	assert.NotEqual(t, token, stored.ID, "the raw token should not be stored")
+	assert.Equal(t, testID, session.UserID, "user ID should match")
+	assert.WithinDuration(t, time.Now().Add(testSessionTTL), session.ExpiresAt, time.Minute, "expiry should match the TTL")
+}
+
+func TestSessionService_ValidateSession_Success(t *testing.T) {
+	mockStore, sessionService := setupSessionMocks(t)
+	ctx := context.Background()
+
+	mockStore.EXPECT().FindSession(ctx, security.HashToken(testToken)).Return(&model.Session{
+		ID:        security.HashToken(testToken),
+		UserID:    testID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+
+	session, err := sessionService.ValidateSession(ctx, testToken)
+
+	assert.NoError(t, err, "validate session should not return an error")
+	assert.Equal(t, testID, session.UserID, "user ID should match")
+}
+
+func TestSessionService_ValidateSession_NotFound(t *testing.T) {
+	mockStore, sessionService := setupSessionMocks(t)
+	ctx := context.Background()
+
+	mockStore.EXPECT().FindSession(ctx, security.HashToken(testToken)).Return(nil, sql.ErrNoRows)
+
+	_, err := sessionService.ValidateSession(ctx, testToken)
+
+	assert.ErrorIs(t, err, service.ErrInvalidSession, "errors should match")
+}
+
+func TestSessionService_ValidateSession_Expired(t *testing.T) {
+	mockStore, sessionService := setupSessionMocks(t)
+	ctx := context.Background()
+	id := security.HashToken(testToken)
+
+	mockStore.EXPECT().FindSession(ctx, id).Return(&model.Session{
+		ID:        id,
+		UserID:    testID,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}, nil)
+	mockStore.EXPECT().DeleteSession(ctx, id).Return(nil)
+
+	_, err := sessionService.ValidateSession(ctx, testToken)
+
+	assert.ErrorIs(t, err, service.ErrInvalidSession, "errors should match")
+}
+
+func TestSessionService_ValidateSession_EmptyToken(t *testing.T) {
+	mockStore, sessionService := setupSessionMocks(t)
+	mockStore.EXPECT().FindSession(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := sessionService.ValidateSession(context.Background(), "")
+
+	assert.ErrorIs(t, err, service.ErrInvalidSession, "errors should match")
+}
+
+func TestSessionService_RevokeSession(t *testing.T) {
+	mockStore, sessionService := setupSessionMocks(t)
+	ctx := context.Background()
+
+	mockStore.EXPECT().DeleteSession(ctx, security.HashToken(testToken)).Return(nil)
+
+	err := sessionService.RevokeSession(ctx, testToken)
+
+	assert.NoError(t, err, "revoke session should not return an error")
+}
+
+func setupSessionMocks(t *testing.T) (*repoMocks.MockSessionStore, service.SessionService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := repoMocks.NewMockSessionStore(ctrl)
+	sessionService := service.NewSessionService(mockStore, testSessionTTL)
+
+	return mockStore, sessionService
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+)
+
+// Lengths in random bytes of refresh tokens and token family IDs.
+const (
+	RefreshTokenLength = 32
+	tokenFamilyLength  = 16
+)
+
+// TokenTypeBearer is the token type reported to clients with access tokens.
+const TokenTypeBearer = "Bearer"
+
+type TokenConfig struct {
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+type tokenIssuer struct {
+	repo   repo.RefreshTokenRepo
+	signer security.TokenSigner
+	cfg    TokenConfig
+}
+
+// WithTokens enables the bearer token sign-in flow.
+func WithTokens(tokens repo.RefreshTokenRepo, signer security.TokenSigner, cfg TokenConfig) AuthOption {
+	return func(s *authService) {
+		s.tokens = &tokenIssuer{
+			repo:   tokens,
+			signer: signer,
+			cfg:    cfg,
+		}
+	}
+}
+
+// SignInWithToken verifies the credentials like SignInUser and starts a new
+// refresh token family.
+func (s *authService) SignInWithToken(ctx context.Context, params model.UserSignInParams) (*model.TokenPair, error) {
+	if s.tokens == nil {
+		return nil, ErrTokensDisabled
+	}
+
+	userID, err := s.SignInUser(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tokens.issueFamily(ctx, userID)
+}
+
+// RefreshTokens rotates a refresh token. Presenting a token that was already
+// rotated is treated as theft and revokes every token in its family.
+func (s *authService) RefreshTokens(ctx context.Context, refreshToken string) (*model.TokenPair, error) {
+	if s.tokens == nil {
+		return nil, ErrTokensDisabled
+	}
+
+	current, err := s.tokens.find(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if current.RevokedAt != nil {
+		return nil, s.tokens.revokeFamily(ctx, current.FamilyID, now)
+	}
+
+	if !now.Before(current.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	rotated, err := s.tokens.repo.RevokeRefreshToken(ctx, current.ID, now)
+	if err != nil {
+		return nil, fmt.Errorf("revoke refresh token: %w", err)
+	}
+
+	// Another request rotated this token between our read and update.
+	if !rotated {
+		return nil, s.tokens.revokeFamily(ctx, current.FamilyID, now)
+	}
+
+	return s.tokens.issue(ctx, current.UserID, current.FamilyID)
+}
+
+// RevokeRefreshToken ends the sign-in that issued the token by revoking its family.
+func (s *authService) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	if s.tokens == nil {
+		return ErrTokensDisabled
+	}
+
+	current, err := s.tokens.find(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+
+	if err := s.tokens.repo.RevokeTokenFamily(ctx, current.FamilyID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("revoke token family: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyAccessToken returns the ID of the user the access token was issued to.
+func (s *authService) VerifyAccessToken(_ context.Context, accessToken string) (string, error) {
+	if s.tokens == nil {
+		return "", ErrTokensDisabled
+	}
+
+	claims, err := s.tokens.signer.Verify(accessToken)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	return claims.Subject, nil
+}
+
+func (t *tokenIssuer) issue(ctx context.Context, userID, familyID string) (*model.TokenPair, error) {
+	accessToken, err := t.signer.Sign(userID, t.cfg.AccessTTL)
+	if err != nil {
+		return nil, fmt.Errorf("sign access token: %w", err)
+	}
+
+	refreshToken, err := security.GenerateRandomBytesEncoded(RefreshTokenLength)
+	if err != nil {
+		return nil, fmt.Errorf("generate refresh token: %w", err)
+	}
+
+	params := model.RefreshToken{
+		ID:        security.HashToken(refreshToken),
+		UserID:    userID,
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().UTC().Add(t.cfg.RefreshTTL),
+	}
+
+	if _, err := t.repo.CreateRefreshToken(ctx, params); err != nil {
+		return nil, fmt.Errorf("create refresh token: %w", err)
+	}
+
+	return &model.TokenPair{
+		AccessToken:  accessToken,
+		TokenType:    TokenTypeBearer,
+		ExpiresIn:    int(t.cfg.AccessTTL.Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// issueFamily starts a new refresh token family for a fresh sign-in.
+func (t *tokenIssuer) issueFamily(ctx context.Context, userID string) (*model.TokenPair, error) {
+	familyID, err := security.GenerateRandomBytesEncoded(tokenFamilyLength)
+	if err != nil {
+		return nil, fmt.Errorf("generate token family: %w", err)
+	}
+
+	return t.issue(ctx, userID, familyID)
+}
+
+func (t *tokenIssuer) find(ctx context.Context, refreshToken string) (*model.RefreshToken, error) {
+	if refreshToken == "" {
+		return nil, ErrInvalidToken
+	}
+
+	token, err := t.repo.FindRefreshToken(ctx, security.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+
+		return nil, fmt.Errorf("find refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (t *tokenIssuer) revokeFamily(ctx context.Context, familyID string, now time.Time) error {
+	if err := t.repo.RevokeTokenFamily(ctx, familyID, now); err != nil {
+		return fmt.Errorf("revoke token family: %w", err)
+	}
+
+	return ErrRefreshTokenReused
+}
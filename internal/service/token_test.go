@@ -0,0 +1,240 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	secMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/security/mocks"
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+)
+
+const (
+	testAccessToken  = "access.token.jwt"
+	testRefreshToken = "refresh"
+	testFamilyID     = "family"
+)
+
+var testTokenConfig = service.TokenConfig{
+	AccessTTL:  15 * time.Minute,
+	RefreshTTL: 24 * time.Hour,
+}
+
+type tokenMocks struct {
+	users  *repoMocks.MockUserRepo
+	hasher *secMocks.MockHasher
+	tokens *repoMocks.MockRefreshTokenRepo
+	signer *secMocks.MockTokenSigner
+}
+
+func TestAuthService_SignInWithToken_Success(t *testing.T) {
+	m, authService := setupTokenMocks(t)
+	ctx := context.Background()
+	input := model.UserSignInParams{Email: testEmail, Password: testPassword}
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(true, nil)
+	m.signer.EXPECT().Sign(testID, testTokenConfig.AccessTTL).Return(testAccessToken, nil)
+
+	var stored model.RefreshToken
+	m.tokens.EXPECT().CreateRefreshToken(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, params model.RefreshToken) (*model.RefreshToken, error) {
+			stored = params
+			return &params, nil
+		})
+
+	pair, err := authService.SignInWithToken(ctx, input)
+
+	assert.NoError(t, err, "signin should not return an error")
+	assert.Equal(t, testAccessToken, pair.AccessToken, "access token should match")
+	assert.Equal(t, service.TokenTypeBearer, pair.TokenType, "token type should match")
+	assert.Equal(t, int(testTokenConfig.AccessTTL.Seconds()), pair.ExpiresIn, "expiry should match")
+	assert.NotEmpty(t, pair.RefreshToken, "refresh token should not be empty")
+	assert.Equal(t, security.HashToken(pair.RefreshToken), stored.ID, "only the refresh token hash should be stored")
+	assert.Equal(t, testID, stored.UserID, "user ID should match")
+	assert.NotEmpty(t, stored.FamilyID, "a token family should be started")
+}
+
+func TestAuthService_SignInWithToken_WrongPassword(t *testing.T) {
+	m, authService := setupTokenMocks(t)
+	ctx := context.Background()
+	input := model.UserSignInParams{Email: testEmail, Password: testPassword}
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{ID: testID, PasswordHash: hashedPassword}, nil)
+	m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(false, nil)
+	m.signer.EXPECT().Sign(gomock.Any(), gomock.Any()).Times(0)
+	m.tokens.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := authService.SignInWithToken(ctx, input)
+
+	assert.ErrorIs(t, err, service.ErrPasswordMismatch, "errors should match")
+}
+
+func TestAuthService_RefreshTokens_Rotates(t *testing.T) {
+	m, authService := setupTokenMocks(t)
+	ctx := context.Background()
+	id := security.HashToken(testRefreshToken)
+
+	m.tokens.EXPECT().FindRefreshToken(ctx, id).Return(&model.RefreshToken{
+		ID:        id,
+		UserID:    testID,
+		FamilyID:  testFamilyID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	m.tokens.EXPECT().RevokeRefreshToken(ctx, id, gomock.Any()).Return(true, nil)
+	m.signer.EXPECT().Sign(testID, testTokenConfig.AccessTTL).Return(testAccessToken, nil)
+
+	var stored model.RefreshToken
+	m.tokens.EXPECT().CreateRefreshToken(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, params model.RefreshToken) (*model.RefreshToken, error) {
+			stored = params
+			return &params, nil
+		})
+
+	pair, err := authService.RefreshTokens(ctx, testRefreshToken)
+
+	assert.NoError(t, err, "refresh should not return an error")
+	assert.NotEqual(t, testRefreshToken, pair.RefreshToken, "refresh token should be rotated")
+	assert.Equal(t, testFamilyID, stored.FamilyID, "rotated token should stay in the family")
+}
+
+func TestAuthService_RefreshTokens_ReuseRevokesFamily(t *testing.T) {
+	m, authService := setupTokenMocks(t)
+	ctx := context.Background()
+	id := security.HashToken(testRefreshToken)
+	revokedAt := time.Now().Add(-time.Minute)
+
+	m.tokens.EXPECT().FindRefreshToken(ctx, id).Return(&model.RefreshToken{
+		ID:        id,
+		UserID:    testID,
+		FamilyID:  testFamilyID,
+		ExpiresAt: time.Now().Add(time.Hour),
+		RevokedAt: &revokedAt,
+	}, nil)
+	m.tokens.EXPECT().RevokeTokenFamily(ctx, testFamilyID, gomock.Any()).Return(nil)
+	m.signer.EXPECT().Sign(gomock.Any(), gomock.Any()).Times(0)
+	m.tokens.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := authService.RefreshTokens(ctx, testRefreshToken)
+
+	assert.ErrorIs(t, err, service.ErrRefreshTokenReused, "errors should match")
+}
+
+func TestAuthService_RefreshTokens_ConcurrentReuseRevokesFamily(t *testing.T) {
+	m, authService := setupTokenMocks(t)
+	ctx := context.Background()
+	id := security.HashToken(testRefreshToken)
+
+	m.tokens.EXPECT().FindRefreshToken(ctx, id).Return(&model.RefreshToken{
+		ID:        id,
+		UserID:    testID,
+		FamilyID:  testFamilyID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	m.tokens.EXPECT().RevokeRefreshToken(ctx, id, gomock.Any()).Return(false, nil)
+	m.tokens.EXPECT().RevokeTokenFamily(ctx, testFamilyID, gomock.Any()).Return(nil)
+	m.tokens.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := authService.RefreshTokens(ctx, testRefreshToken)
+
+	assert.ErrorIs(t, err, service.ErrRefreshTokenReused, "errors should match")
+}
+
+func TestAuthService_RefreshTokens_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		token *model.RefreshToken
+		err   error
+	}{
+		{"unknown token", nil, sql.ErrNoRows},
+		{"expired token", &model.RefreshToken{
+			UserID:    testID,
+			FamilyID:  testFamilyID,
+			ExpiresAt: time.Now().Add(-time.Minute),
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, authService := setupTokenMocks(t)
+			ctx := context.Background()
+
+			m.tokens.EXPECT().FindRefreshToken(ctx, security.HashToken(testRefreshToken)).Return(tt.token, tt.err)
+			m.tokens.EXPECT().RevokeRefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			m.tokens.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Times(0)
+
+			_, err := authService.RefreshTokens(ctx, testRefreshToken)
+
+			assert.ErrorIs(t, err, service.ErrInvalidToken, "errors should match")
+		})
+	}
+}
+
+func TestAuthService_RevokeRefreshToken(t *testing.T) {
+	m, authService := setupTokenMocks(t)
+	ctx := context.Background()
+	id := security.HashToken(testRefreshToken)
+
+	m.tokens.EXPECT().FindRefreshToken(ctx, id).Return(&model.RefreshToken{
+		ID:       id,
+		UserID:   testID,
+		FamilyID: testFamilyID,
+	}, nil)
+	m.tokens.EXPECT().RevokeTokenFamily(ctx, testFamilyID, gomock.Any()).Return(nil)
+
+	err := authService.RevokeRefreshToken(ctx, testRefreshToken)
+
+	assert.NoError(t, err, "revoke should not return an error")
+}
+
+func TestAuthService_VerifyAccessToken(t *testing.T) {
+	m, authService := setupTokenMocks(t)
+	m.signer.EXPECT().Verify(testAccessToken).Return(&jwt.RegisteredClaims{Subject: testID}, nil)
+
+	userID, err := authService.VerifyAccessToken(context.Background(), testAccessToken)
+
+	assert.NoError(t, err, "verify should not return an error")
+	assert.Equal(t, testID, userID, "user ID should match")
+}
+
+func TestAuthService_VerifyAccessToken_Invalid(t *testing.T) {
+	m, authService := setupTokenMocks(t)
+	m.signer.EXPECT().Verify(testAccessToken).Return(nil, security.ErrInvalidToken)
+
+	_, err := authService.VerifyAccessToken(context.Background(), testAccessToken)
+
+	assert.ErrorIs(t, err, service.ErrInvalidToken, "errors should match")
+}
+
+func TestAuthService_Tokens_Disabled(t *testing.T) {
+	_, _, authService := setupMocks(t)
+	ctx := context.Background()
+
+	_, err := authService.RefreshTokens(ctx, testRefreshToken)
+	assert.ErrorIs(t, err, service.ErrTokensDisabled, "errors should match")
+
+	_, err = authService.VerifyAccessToken(ctx, testAccessToken)
+	assert.ErrorIs(t, err, service.ErrTokensDisabled, "errors should match")
+}
+
+func setupTokenMocks(t *testing.T) (tokenMocks, service.AuthService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := tokenMocks{
+		users:  repoMocks.NewMockUserRepo(ctrl),
+		hasher: secMocks.NewMockHasher(ctrl),
+		tokens: repoMocks.NewMockRefreshTokenRepo(ctrl),
+		signer: secMocks.NewMockTokenSigner(ctrl),
+	}
+	authService := service.NewAuthService(m.users, m.hasher, service.WithTokens(m.tokens, m.signer, testTokenConfig))
+
+	return m, authService
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/mail"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/repo"
+)
+
+// UserTokenLength is the length in random bytes of emailed tokens.
+const UserTokenLength = 32
+
+type VerificationConfig struct {
+	// BaseURL is the address of the app the verification link points to.
+	BaseURL string
+	TTL     time.Duration
+	// RequireVerified makes SignInUser reject accounts with unverified emails.
+	RequireVerified bool
+}
+
+type emailVerifier struct {
+	tokens repo.UserTokenRepo
+	mailer mail.Mailer
+	cfg    VerificationConfig
+}
+
+// WithEmailVerification emails new users a link to confirm their address.
+func WithEmailVerification(tokens repo.UserTokenRepo, mailer mail.Mailer, cfg VerificationConfig) AuthOption {
+	return func(s *authService) {
+		s.verifier = &emailVerifier{
+			tokens: tokens,
+			mailer: mailer,
+			cfg:    cfg,
+		}
+	}
+}
+
+// VerifyEmail marks the email a verification token was sent to as verified.
+// Tokens are rejected if the user has changed their email since.
+func (s *authService) VerifyEmail(ctx context.Context, token string) error {
+	if s.verifier == nil {
+		return ErrVerificationDisabled
+	}
+
+	userToken, err := consumeUserToken(ctx, s.verifier.tokens, token, model.TokenPurposeVerifyEmail)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.repo.FindUserByID(ctx, userToken.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidToken
+		}
+
+		return fmt.Errorf("find user by id: %w", err)
+	}
+
+	if user.Email != userToken.Email {
+		return ErrInvalidToken
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	if err := s.repo.MarkEmailVerified(ctx, user.ID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("mark email verified: %w", err)
+	}
+
+	return nil
+}
+
+// ResendVerification emails a new verification link. It succeeds without
+// sending anything for unknown or verified emails, and sends in the
+// background otherwise, so that neither its result nor its timing lets
+// callers probe for unverified accounts.
+func (s *authService) ResendVerification(ctx context.Context, email string) error {
+	if s.verifier == nil {
+		return ErrVerificationDisabled
+	}
+
+	user, err := s.repo.FindUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return fmt.Errorf("find user by email: %w", err)
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	s.runInBackground(ctx, "send verification email", func(ctx context.Context) error {
+		return s.verifier.send(ctx, user)
+	})
+
+	return nil
+}
+
+func (v *emailVerifier) send(ctx context.Context, user *model.User) error {
+	token, err := issueUserToken(ctx, v.tokens, user, model.TokenPurposeVerifyEmail, v.cfg.TTL)
+	if err != nil {
+		return err
+	}
+
+	msg := mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Confirm your email address by opening the link below:\n\n%s\n\n"+
+			"The link expires in %s. If you did not create an account, you can ignore this email.\n",
+			tokenLink(v.cfg.BaseURL, "/verify-email", token), v.cfg.TTL),
+	}
+
+	if err := v.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+
+	return nil
+}
+
+// issueUserToken replaces the user's outstanding tokens for the purpose with
+// a new one, returning the raw token to be emailed.
+func issueUserToken(ctx context.Context, tokens repo.UserTokenRepo, user *model.User,
+	purpose model.TokenPurpose, ttl time.Duration) (string, error) {
+	if err := tokens.DeleteUserTokens(ctx, user.ID, purpose); err != nil {
+		return "", fmt.Errorf("delete user tokens: %w", err)
+	}
+
+	token, err := security.GenerateRandomBytesEncoded(UserTokenLength)
+	if err != nil {
+		return "", fmt.Errorf("generate user token: %w", err)
+	}
+
+	params := model.UserToken{
+		ID:        security.HashToken(token),
+		UserID:    user.ID,
+		Purpose:   purpose,
+		Email:     user.Email,
+		ExpiresAt: time.Now().UTC().Add(ttl),
+	}
+
+	if _, err := tokens.CreateUserToken(ctx, params); err != nil {
+		return "", fmt.Errorf("create user token: %w", err)
+	}
+
+	return token, nil
+}
+
+// consumeUserToken redeems an emailed token, mapping unknown and expired
+// tokens to ErrInvalidToken.
+func consumeUserToken(ctx context.Context, tokens repo.UserTokenRepo, token string,
+	purpose model.TokenPurpose) (*model.UserToken, error) {
+	if token == "" {
+		return nil, ErrInvalidToken
+	}
+
+	userToken, err := tokens.ConsumeUserToken(ctx, security.HashToken(token), purpose)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+
+		return nil, fmt.Errorf("consume user token: %w", err)
+	}
+
+	if !time.Now().UTC().Before(userToken.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	return userToken, nil
+}
+
+func tokenLink(baseURL, path, token string) string {
+	return baseURL + path + "?" + url.Values{"token": {token}}.Encode()
+}
@@ -0,0 +1,254 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/fullstackgo/internal/model"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/mail"
+	"github.com/ferdiebergado/fullstackgo/internal/pkg/security"
+	"github.com/ferdiebergado/fullstackgo/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	secMocks "github.com/ferdiebergado/fullstackgo/internal/pkg/security/mocks"
+	repoMocks "github.com/ferdiebergado/fullstackgo/internal/repo/mocks"
+)
+
+const testBaseURL = "https://app.example.com"
+
+var testVerificationConfig = service.VerificationConfig{
+	BaseURL: testBaseURL,
+	TTL:     time.Hour,
+}
+
+type verificationMocks struct {
+	users  *repoMocks.MockUserRepo
+	hasher *secMocks.MockHasher
+	tokens *repoMocks.MockUserTokenRepo
+	mailer *mail.MemoryMailer
+	tasks  *taskQueue
+}
+
+func TestAuthService_SignUpUser_SendsVerification(t *testing.T) {
+	m, authService := setupVerificationMocks(t, testVerificationConfig)
+	ctx := context.Background()
+	created := &model.User{ID: testID, Email: testEmail}
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(nil, sql.ErrNoRows)
+	m.hasher.EXPECT().Hash(testPassword).Return(hashedPassword, nil)
+	m.users.EXPECT().CreateUser(ctx, gomock.Any()).Return(created, nil)
+	m.tokens.EXPECT().DeleteUserTokens(ctx, testID, model.TokenPurposeVerifyEmail).Return(nil)
+
+	var stored model.UserToken
+	m.tokens.EXPECT().CreateUserToken(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, params model.UserToken) (*model.UserToken, error) {
+			stored = params
+			return &params, nil
+		})
+
+	_, err := authService.SignUpUser(ctx, newSignUpParams())
+
+	assert.NoError(t, err, "signup should not return an error")
+	msgs := m.mailer.Messages()
+	if assert.Len(t, msgs, 1, "one email should be sent") {
+		assert.Equal(t, testEmail, msgs[0].To, "recipient should match")
+		token := linkToken(t, msgs[0].Body)
+		assert.Equal(t, security.HashToken(token), stored.ID, "only the token hash should be stored")
+	}
+	assert.Equal(t, testEmail, stored.Email, "token email should match")
+	assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute, "expiry should match")
+}
+
+func TestAuthService_SignUpUser_MailFailureDoesNotFail(t *testing.T) {
+	m, authService := setupVerificationMocks(t, testVerificationConfig)
+	ctx := context.Background()
+
+	m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(nil, sql.ErrNoRows)
+	m.hasher.EXPECT().Hash(testPassword).Return(hashedPassword, nil)
+	m.users.EXPECT().CreateUser(ctx, gomock.Any()).Return(&model.User{ID: testID, Email: testEmail}, nil)
+	m.tokens.EXPECT().DeleteUserTokens(ctx, testID, model.TokenPurposeVerifyEmail).Return(sql.ErrConnDone)
+
+	user, err := authService.SignUpUser(ctx, newSignUpParams())
+
+	assert.NoError(t, err, "signup should not return an error")
+	assert.Equal(t, testID, user.ID, "ID should match")
+}
+
+func TestAuthService_VerifyEmail_Success(t *testing.T) {
+	m, authService := setupVerificationMocks(t, testVerificationConfig)
+	ctx := context.Background()
+	token := "token"
+
+	m.tokens.EXPECT().ConsumeUserToken(ctx, security.HashToken(token), model.TokenPurposeVerifyEmail).
+		Return(&model.UserToken{UserID: testID, Email: testEmail, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	m.users.EXPECT().FindUserByID(ctx, testID).Return(&model.User{ID: testID, Email: testEmail}, nil)
+	m.users.EXPECT().MarkEmailVerified(ctx, testID, gomock.Any()).Return(nil)
+
+	err := authService.VerifyEmail(ctx, token)
+
+	assert.NoError(t, err, "verify email should not return an error")
+}
+
+func TestAuthService_VerifyEmail_Invalid(t *testing.T) {
+	expired := &model.UserToken{UserID: testID, Email: testEmail, ExpiresAt: time.Now().Add(-time.Minute)}
+	stale := &model.UserToken{UserID: testID, Email: "old@example.com", ExpiresAt: time.Now().Add(time.Hour)}
+
+	tests := []struct {
+		name  string
+		token *model.UserToken
+		err   error
+	}{
+		{"unknown token should be rejected", nil, sql.ErrNoRows},
+		{"expired token should be rejected", expired, nil},
+		{"token for a previous email should be rejected", stale, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, authService := setupVerificationMocks(t, testVerificationConfig)
+			ctx := context.Background()
+
+			m.tokens.EXPECT().ConsumeUserToken(ctx, gomock.Any(), model.TokenPurposeVerifyEmail).Return(tt.token, tt.err)
+			m.users.EXPECT().FindUserByID(ctx, testID).Return(&model.User{ID: testID, Email: testEmail}, nil).AnyTimes()
+			m.users.EXPECT().MarkEmailVerified(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			err := authService.VerifyEmail(ctx, "token")
+
+			assert.ErrorIs(t, err, service.ErrInvalidToken, "errors should match")
+		})
+	}
+}
+
+func TestAuthService_VerifyEmail_Disabled(t *testing.T) {
+	_, _, authService := setupMocks(t)
+
+	err := authService.VerifyEmail(context.Background(), "token")
+
+	assert.ErrorIs(t, err, service.ErrVerificationDisabled, "errors should match")
+}
+
+func TestAuthService_ResendVerification(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		user  *model.User
+		err   error
+		sends int
+	}{
+		{"unverified user should be sent an email", &model.User{ID: testID, Email: testEmail}, nil, 1},
+		{"verified user should not be sent an email", &model.User{ID: testID, Email: testEmail, EmailVerifiedAt: &now}, nil, 0},
+		{"unknown email should not be sent an email", nil, sql.ErrNoRows, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, authService := setupVerificationMocks(t, testVerificationConfig)
+			ctx := context.Background()
+
+			m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(tt.user, tt.err)
+			m.tokens.EXPECT().DeleteUserTokens(gomock.Any(), testID, model.TokenPurposeVerifyEmail).Return(nil).
+				Times(tt.sends)
+			m.tokens.EXPECT().CreateUserToken(gomock.Any(), gomock.Any()).Return(&model.UserToken{}, nil).Times(tt.sends)
+
+			err := authService.ResendVerification(ctx, testEmail)
+			m.tasks.run()
+
+			assert.NoError(t, err, "resend should not return an error")
+			assert.Len(t, m.mailer.Messages(), tt.sends, "sent emails should match")
+		})
+	}
+}
+
+func TestAuthService_ResendVerification_SameRequestWork(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		user *model.User
+		err  error
+	}{
+		{"unverified email", &model.User{ID: testID, Email: testEmail}, nil},
+		{"verified email", &model.User{ID: testID, Email: testEmail, EmailVerifiedAt: &now}, nil},
+		{"unknown email", nil, sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, authService := setupVerificationMocks(t, testVerificationConfig)
+			ctx := context.Background()
+
+			// Any other call on the request path fails the test.
+			m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(tt.user, tt.err)
+
+			err := authService.ResendVerification(ctx, testEmail)
+
+			assert.NoError(t, err, "resend should not return an error")
+			assert.Empty(t, m.mailer.Messages(), "no email should be sent before the request returns")
+		})
+	}
+}
+
+func TestAuthService_SignInUser_RequireVerified(t *testing.T) {
+	cfg := testVerificationConfig
+	cfg.RequireVerified = true
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		verifiedAt *time.Time
+		err        error
+	}{
+		{"verified user should sign in", &now, nil},
+		{"unverified user should be rejected", nil, service.ErrEmailNotVerified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, authService := setupVerificationMocks(t, cfg)
+			ctx := context.Background()
+
+			m.users.EXPECT().FindUserByEmail(ctx, testEmail).Return(&model.User{
+				ID: testID, PasswordHash: hashedPassword, EmailVerifiedAt: tt.verifiedAt,
+			}, nil)
+			m.hasher.EXPECT().Verify(testPassword, hashedPassword).Return(true, nil)
+
+			_, err := authService.SignInUser(ctx, model.UserSignInParams{Email: testEmail, Password: testPassword})
+
+			assert.ErrorIs(t, err, tt.err, "errors should match")
+		})
+	}
+}
+
+func setupVerificationMocks(t *testing.T, cfg service.VerificationConfig) (*verificationMocks, service.AuthService) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := &verificationMocks{
+		users:  repoMocks.NewMockUserRepo(ctrl),
+		hasher: secMocks.NewMockHasher(ctrl),
+		tokens: repoMocks.NewMockUserTokenRepo(ctrl),
+		mailer: &mail.MemoryMailer{},
+		tasks:  &taskQueue{},
+	}
+	authService := service.NewAuthService(m.users, m.hasher, service.WithBackground(m.tasks.add),
+		service.WithEmailVerification(m.tokens, m.mailer, cfg))
+
+	return m, authService
+}
+
+var linkPattern = regexp.MustCompile(`https://\S+`)
+
+// linkToken extracts the token from the link in an email body.
+func linkToken(t *testing.T, body string) string {
+	t.Helper()
+	link, err := url.Parse(linkPattern.FindString(body))
+	if err != nil {
+		t.Fatalf("parse link: %v", err)
+	}
+	return link.Query().Get("token")
+}